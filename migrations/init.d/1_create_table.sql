@@ -1,15 +1,135 @@
 USE `microservice-k8s-demo-db`;
 
 DROP TABLE IF EXISTS CatalogItems;
+DROP TABLE IF EXISTS StockReservations;
+DROP TABLE IF EXISTS CatalogItemPrices;
+DROP TABLE IF EXISTS ExchangeRates;
+DROP TABLE IF EXISTS CatalogItemPriceHistory;
+DROP TABLE IF EXISTS Categories;
+DROP TABLE IF EXISTS CategoryClosure;
+DROP TABLE IF EXISTS CatalogItemCategories;
+DROP TABLE IF EXISTS CatalogItemVariants;
 DROP TABLE IF EXISTS Customers;
+DROP TABLE IF EXISTS OrderStatusHistory;
 DROP TABLE IF EXISTS OrderLines;
 DROP TABLE IF EXISTS Orders;
+DROP TABLE IF EXISTS CatalogIdempotencyKeys;
+DROP TABLE IF EXISTS CustomerIdempotencyKeys;
+DROP TABLE IF EXISTS OrderIdempotencyKeys;
+DROP TABLE IF EXISTS OrderSagas;
+DROP TABLE IF EXISTS CatalogOutboxEvents;
+DROP TABLE IF EXISTS CustomerOutboxEvents;
+DROP TABLE IF EXISTS OrderOutboxEvents;
 
 -- CatalogItems Table
 CREATE TABLE CatalogItems (
     id CHAR(36) PRIMARY KEY,
     name VARCHAR(255) NOT NULL,
-    price DECIMAL(10, 2) NOT NULL
+    -- external_key is the key of the item in the systems of its supplier, which imports upsert the items by.
+    external_key VARCHAR(255) NULL,
+    -- Prices are in the minor unit of their ISO 4217 currency, such as cents for USD.
+    price_amount BIGINT NOT NULL,
+    price_currency CHAR(3) NOT NULL,
+    stock INT NOT NULL DEFAULT 0,
+    -- reserved is kept between 0 and stock by the catalog service, as MySQL 5.7 does not enforce CHECK constraints.
+    reserved INT NOT NULL DEFAULT 0,
+    -- version is incremented by every update, which is made only if it is unchanged since the item was read.
+    version BIGINT NOT NULL DEFAULT 1,
+    -- deleted_at is set while the item is in the trash, from which it is purged once it has been there long enough.
+    deleted_at TIMESTAMP NULL,
+    UNIQUE KEY uq_catalog_items_external_key (external_key),
+    INDEX idx_catalog_items_deleted_at (deleted_at)
+);
+
+-- StockReservations Table
+CREATE TABLE StockReservations (
+    reservation_id CHAR(36) NOT NULL,
+    catalog_item_id CHAR(36) NOT NULL,
+    -- The reserved variant of the item, or empty when the item itself is reserved.
+    sku VARCHAR(64) NOT NULL DEFAULT '',
+    quantity INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'reserved',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (reservation_id, catalog_item_id, sku),
+    INDEX idx_stock_reservations_catalog_item_id (catalog_item_id)
+);
+
+-- CatalogItemPrices Table
+-- The explicit prices of the items in other currencies than their base price.
+CREATE TABLE CatalogItemPrices (
+    catalog_item_id CHAR(36) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    PRIMARY KEY (catalog_item_id, currency)
+);
+
+-- ExchangeRates Table
+-- The price in to_currency of one unit of from_currency.
+CREATE TABLE ExchangeRates (
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (from_currency, to_currency)
+);
+
+-- CatalogItemPriceHistory Table
+-- Every base price of the items, in effect from effective_from until the next one of the item.
+-- The rows without applied_at are scheduled price changes, which are made to the items once they are due.
+CREATE TABLE CatalogItemPriceHistory (
+    id CHAR(36) PRIMARY KEY,
+    catalog_item_id CHAR(36) NOT NULL,
+    price_amount BIGINT NOT NULL,
+    price_currency CHAR(3) NOT NULL,
+    effective_from TIMESTAMP NOT NULL,
+    applied_at TIMESTAMP NULL,
+    INDEX idx_catalog_item_price_history_catalog_item_id (catalog_item_id, effective_from),
+    INDEX idx_catalog_item_price_history_applied_at (applied_at, effective_from)
+);
+
+-- Categories Table
+-- The category tree, in which root categories have no parent_id.
+CREATE TABLE Categories (
+    id CHAR(36) PRIMARY KEY,
+    parent_id CHAR(36) NULL,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE KEY uq_categories_slug (slug),
+    INDEX idx_categories_parent_id (parent_id)
+);
+
+-- CategoryClosure Table
+-- A row for every ancestor of each category, including the category itself at depth 0.
+CREATE TABLE CategoryClosure (
+    ancestor_id CHAR(36) NOT NULL,
+    descendant_id CHAR(36) NOT NULL,
+    depth INT NOT NULL,
+    PRIMARY KEY (ancestor_id, descendant_id),
+    INDEX idx_category_closure_descendant_id (descendant_id)
+);
+
+-- CatalogItemCategories Table
+CREATE TABLE CatalogItemCategories (
+    catalog_item_id CHAR(36) NOT NULL,
+    category_id CHAR(36) NOT NULL,
+    PRIMARY KEY (catalog_item_id, category_id),
+    INDEX idx_catalog_item_categories_category_id (category_id)
+);
+
+-- CatalogItemVariants Table
+-- The SKUs of the items, whose price override is NULL when they are sold at the price of their item.
+CREATE TABLE CatalogItemVariants (
+    sku VARCHAR(64) PRIMARY KEY,
+    catalog_item_id CHAR(36) NOT NULL,
+    attributes JSON NOT NULL,
+    price_amount BIGINT NULL,
+    price_currency CHAR(3) NULL,
+    stock INT NOT NULL DEFAULT 0,
+    -- reserved is kept between 0 and stock by the catalog service, as MySQL 5.7 does not enforce CHECK constraints.
+    reserved INT NOT NULL DEFAULT 0,
+    INDEX idx_catalog_item_variants_catalog_item_id (catalog_item_id)
 );
 
 -- Customers Table
@@ -19,21 +139,154 @@ CREATE TABLE Customers (
     email VARCHAR(255) NOT NULL,
     street VARCHAR(255) NOT NULL,
     city VARCHAR(255) NOT NULL,
-    country VARCHAR(255) NOT NULL
+    country VARCHAR(255) NOT NULL,
+    -- version is incremented by every update, which is made only if it is unchanged since the customer was read.
+    version BIGINT NOT NULL DEFAULT 1,
+    -- deleted_at is set while the customer is in the trash, from which it is purged once it has been there long enough.
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_customers_deleted_at (deleted_at)
 );
 
 -- Orders Table
 CREATE TABLE Orders (
     id CHAR(36) PRIMARY KEY,
     customer_id CHAR(36) NOT NULL,
-    order_date TIMESTAMP NOT NULL
+    order_date TIMESTAMP NOT NULL,
+    -- Prices are in the minor unit of their ISO 4217 currency, such as cents for USD.
+    total_price_amount BIGINT NOT NULL DEFAULT 0,
+    total_price_currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    INDEX idx_orders_customer_id_order_date (customer_id, order_date),
+    INDEX idx_orders_order_date (order_date),
+    INDEX idx_orders_total_price (total_price_amount)
 );
 
 -- OrderLines Table
 CREATE TABLE OrderLines (
     order_id CHAR(36) NOT NULL,
+    line_number INT NOT NULL DEFAULT 0,
     catalog_item_id CHAR(36) NOT NULL,
+    -- The variant of the item ordered; empty for items without variants.
+    sku VARCHAR(64) NOT NULL DEFAULT '',
     count INT NOT NULL,
-    PRIMARY KEY (order_id, catalog_item_id),
+    item_name VARCHAR(255) NOT NULL,
+    unit_price_amount BIGINT NOT NULL,
+    unit_price_currency CHAR(3) NOT NULL,
+    -- Set when the unit price was converted from the base price of the item, at exchange_rate to unit_price_currency.
+    base_price_amount BIGINT NULL,
+    base_price_currency CHAR(3) NULL,
+    exchange_rate DECIMAL(18, 8) NULL,
+    exchange_rate_updated_at TIMESTAMP NULL,
+    PRIMARY KEY (order_id, catalog_item_id, sku),
+    FOREIGN KEY (order_id) REFERENCES Orders(id)
+);
+
+-- OrderStatusHistory Table
+CREATE TABLE OrderStatusHistory (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    INDEX idx_order_status_history_order_id (order_id),
     FOREIGN KEY (order_id) REFERENCES Orders(id)
 );
+
+-- CatalogIdempotencyKeys Table
+CREATE TABLE CatalogIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_catalog_idempotency_keys_expires_at (expires_at)
+);
+
+-- CatalogOutboxEvents Table
+CREATE TABLE CatalogOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    -- The relay publishing the event, until claimed_until.
+    claim_id CHAR(36) NULL,
+    claimed_until TIMESTAMP NULL,
+    INDEX idx_catalog_outbox_events_published_at (published_at, sequence),
+    INDEX idx_catalog_outbox_events_claim_id (claim_id)
+);
+
+-- CustomerIdempotencyKeys Table
+CREATE TABLE CustomerIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_customer_idempotency_keys_expires_at (expires_at)
+);
+
+-- CustomerOutboxEvents Table
+CREATE TABLE CustomerOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    -- The relay publishing the event, until claimed_until.
+    claim_id CHAR(36) NULL,
+    claimed_until TIMESTAMP NULL,
+    INDEX idx_customer_outbox_events_published_at (published_at, sequence),
+    INDEX idx_customer_outbox_events_claim_id (claim_id)
+);
+
+-- OrderIdempotencyKeys Table
+CREATE TABLE OrderIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_order_idempotency_keys_expires_at (expires_at)
+);
+
+-- OrderSagas Table
+CREATE TABLE OrderSagas (
+    id CHAR(36) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL,
+    step VARCHAR(32) NOT NULL,
+    payload JSON NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_order_sagas_status_updated_at (status, updated_at)
+);
+
+-- OrderOutboxEvents Table
+CREATE TABLE OrderOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    -- The relay publishing the event, until claimed_until.
+    claim_id CHAR(36) NULL,
+    claimed_until TIMESTAMP NULL,
+    INDEX idx_order_outbox_events_published_at (published_at, sequence),
+    INDEX idx_order_outbox_events_claim_id (claim_id)
+);
@@ -0,0 +1,33 @@
+-- Adds the statuses of the orders and their history, as created by init.d/1_create_table.sql.
+-- The files in init.d only run on an empty database, so the files in this directory are run once
+-- by hand, in the order of their numbers, against the databases created before, after the services
+-- are stopped and before the versions of the services that read the new tables and columns are started:
+--
+--   mysql -u root -p < migrations/upgrade/01_order_status.sql
+--
+-- The existing orders were placed as soon as they were created, so they are taken to be confirmed,
+-- and their history starts with the confirmation at their order date.
+
+USE `microservice-k8s-demo-db`;
+
+-- Orders Table
+ALTER TABLE Orders
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'pending';
+
+UPDATE Orders
+SET status = 'confirmed';
+
+-- OrderStatusHistory Table
+CREATE TABLE OrderStatusHistory (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    INDEX idx_order_status_history_order_id (order_id),
+    FOREIGN KEY (order_id) REFERENCES Orders(id)
+);
+
+INSERT INTO OrderStatusHistory (order_id, from_status, to_status, changed_at)
+SELECT id, NULL, status, order_date
+FROM Orders;
@@ -0,0 +1,36 @@
+-- Adds the names and unit prices of the items on the order lines and the total prices of the orders,
+-- as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 01_order_status.sql:
+--
+--   mysql -u root -p < migrations/upgrade/02_order_line_snapshots.sql
+--
+-- The names and prices at the time of ordering were not recorded, so the lines of the existing orders
+-- take the current ones of their items. The lines of items that no longer exist are left unnamed and free.
+
+USE `microservice-k8s-demo-db`;
+
+-- OrderLines Table
+ALTER TABLE OrderLines
+    ADD COLUMN item_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN unit_price DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+UPDATE OrderLines ol
+JOIN CatalogItems ci ON ci.id = ol.catalog_item_id
+SET ol.item_name = ci.name,
+    ol.unit_price = ci.price;
+
+ALTER TABLE OrderLines
+    ALTER COLUMN item_name DROP DEFAULT,
+    ALTER COLUMN unit_price DROP DEFAULT;
+
+-- Orders Table
+ALTER TABLE Orders
+    ADD COLUMN total_price DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER order_date;
+
+UPDATE Orders o
+JOIN (
+    SELECT order_id, SUM(unit_price * count) AS total_price
+    FROM OrderLines
+    GROUP BY order_id
+) ol ON ol.order_id = o.id
+SET o.total_price = ol.total_price;
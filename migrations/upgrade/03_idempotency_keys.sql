@@ -0,0 +1,45 @@
+-- Adds the idempotency keys of the services, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 02_order_line_snapshots.sql:
+--
+--   mysql -u root -p < migrations/upgrade/03_idempotency_keys.sql
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogIdempotencyKeys Table
+CREATE TABLE CatalogIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_catalog_idempotency_keys_expires_at (expires_at)
+);
+
+-- CustomerIdempotencyKeys Table
+CREATE TABLE CustomerIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_customer_idempotency_keys_expires_at (expires_at)
+);
+
+-- OrderIdempotencyKeys Table
+CREATE TABLE OrderIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_order_idempotency_keys_expires_at (expires_at)
+);
@@ -0,0 +1,12 @@
+-- Adds the indexes the orders are filtered and sorted by, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 03_idempotency_keys.sql:
+--
+--   mysql -u root -p < migrations/upgrade/04_order_indexes.sql
+
+USE `microservice-k8s-demo-db`;
+
+-- Orders Table
+ALTER TABLE Orders
+    ADD INDEX idx_orders_customer_id_order_date (customer_id, order_date),
+    ADD INDEX idx_orders_order_date (order_date),
+    ADD INDEX idx_orders_total_price (total_price);
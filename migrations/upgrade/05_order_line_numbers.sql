@@ -0,0 +1,22 @@
+-- Adds the numbers of the order lines, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 04_order_indexes.sql:
+--
+--   mysql -u root -p < migrations/upgrade/05_order_line_numbers.sql
+--
+-- The order the lines of the existing orders were given in was not recorded, so they are numbered
+-- in the order of their items.
+
+USE `microservice-k8s-demo-db`;
+
+-- OrderLines Table
+ALTER TABLE OrderLines
+    ADD COLUMN line_number INT NOT NULL DEFAULT 0 AFTER order_id;
+
+UPDATE OrderLines ol
+JOIN (
+    SELECT a.order_id, a.catalog_item_id, COUNT(b.catalog_item_id) AS line_number
+    FROM OrderLines a
+    LEFT JOIN OrderLines b ON b.order_id = a.order_id AND b.catalog_item_id < a.catalog_item_id
+    GROUP BY a.order_id, a.catalog_item_id
+) n ON n.order_id = ol.order_id AND n.catalog_item_id = ol.catalog_item_id
+SET ol.line_number = n.line_number;
@@ -0,0 +1,25 @@
+-- Adds the stock of the catalog items and its reservations, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 05_order_line_numbers.sql:
+--
+--   mysql -u root -p < migrations/upgrade/06_stock_reservations.sql
+--
+-- The existing items start out of stock with nothing reserved, and the existing orders hold no reservations.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItems Table
+ALTER TABLE CatalogItems
+    ADD COLUMN stock INT NOT NULL DEFAULT 0,
+    ADD COLUMN reserved INT NOT NULL DEFAULT 0;
+
+-- StockReservations Table
+CREATE TABLE StockReservations (
+    reservation_id CHAR(36) NOT NULL,
+    catalog_item_id CHAR(36) NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'reserved',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (reservation_id, catalog_item_id),
+    INDEX idx_stock_reservations_catalog_item_id (catalog_item_id)
+);
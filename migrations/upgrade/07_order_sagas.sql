@@ -0,0 +1,18 @@
+-- Adds the states of the order placement sagas, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 06_stock_reservations.sql:
+--
+--   mysql -u root -p < migrations/upgrade/07_order_sagas.sql
+
+USE `microservice-k8s-demo-db`;
+
+-- OrderSagas Table
+CREATE TABLE OrderSagas (
+    id CHAR(36) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL,
+    step VARCHAR(32) NOT NULL,
+    payload JSON NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_order_sagas_status_updated_at (status, updated_at)
+);
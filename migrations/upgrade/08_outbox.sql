@@ -0,0 +1,42 @@
+-- Adds the outboxes of the domain events of the services, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 07_order_sagas.sql:
+--
+--   mysql -u root -p < migrations/upgrade/08_outbox.sql
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogOutboxEvents Table
+CREATE TABLE CatalogOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    INDEX idx_catalog_outbox_events_published_at (published_at, sequence)
+);
+
+-- CustomerOutboxEvents Table
+CREATE TABLE CustomerOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    INDEX idx_customer_outbox_events_published_at (published_at, sequence)
+);
+
+-- OrderOutboxEvents Table
+CREATE TABLE OrderOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    INDEX idx_order_outbox_events_published_at (published_at, sequence)
+);
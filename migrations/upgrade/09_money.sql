@@ -0,0 +1,58 @@
+-- Converts the prices of an existing database from DECIMAL(10, 2) to an amount in the minor unit
+-- of the currency, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 08_outbox.sql:
+--
+--   mysql -u root -p < migrations/upgrade/09_money.sql
+--
+-- The prices had no currency and are all taken to be in @currency, the default currency of the
+-- services. The amounts are rounded half away from zero, which leaves them unchanged as DECIMAL(10, 2)
+-- has exactly the two decimal places of the minor unit of USD, EUR and GBP.
+-- Events and saga states stored before still hold prices as numbers, which the services read in the default currency.
+
+USE `microservice-k8s-demo-db`;
+
+SET @currency = 'USD';
+SET @exponent = 2;
+
+-- CatalogItems Table
+ALTER TABLE CatalogItems
+    ADD COLUMN price_amount BIGINT NOT NULL DEFAULT 0 AFTER price,
+    ADD COLUMN price_currency CHAR(3) NOT NULL DEFAULT '' AFTER price_amount;
+
+UPDATE CatalogItems
+SET price_amount = ROUND(price * POW(10, @exponent)),
+    price_currency = @currency;
+
+ALTER TABLE CatalogItems
+    DROP COLUMN price,
+    ALTER COLUMN price_amount DROP DEFAULT,
+    ALTER COLUMN price_currency DROP DEFAULT;
+
+-- Orders Table
+ALTER TABLE Orders
+    ADD COLUMN total_price_amount BIGINT NOT NULL DEFAULT 0 AFTER total_price,
+    ADD COLUMN total_price_currency CHAR(3) NOT NULL DEFAULT '' AFTER total_price_amount;
+
+UPDATE Orders
+SET total_price_amount = ROUND(total_price * POW(10, @exponent)),
+    total_price_currency = @currency;
+
+ALTER TABLE Orders
+    DROP INDEX idx_orders_total_price,
+    DROP COLUMN total_price,
+    ALTER COLUMN total_price_currency DROP DEFAULT,
+    ADD INDEX idx_orders_total_price (total_price_amount);
+
+-- OrderLines Table
+ALTER TABLE OrderLines
+    ADD COLUMN unit_price_amount BIGINT NOT NULL DEFAULT 0 AFTER unit_price,
+    ADD COLUMN unit_price_currency CHAR(3) NOT NULL DEFAULT '' AFTER unit_price_amount;
+
+UPDATE OrderLines
+SET unit_price_amount = ROUND(unit_price * POW(10, @exponent)),
+    unit_price_currency = @currency;
+
+ALTER TABLE OrderLines
+    DROP COLUMN unit_price,
+    ALTER COLUMN unit_price_amount DROP DEFAULT,
+    ALTER COLUMN unit_price_currency DROP DEFAULT;
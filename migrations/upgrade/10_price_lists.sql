@@ -0,0 +1,33 @@
+-- Adds the explicit prices of the catalog items in other currencies, the exchange rates between the
+-- currencies and the conversion of the prices of order lines, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 09_money.sql:
+--
+--   mysql -u root -p < migrations/upgrade/10_price_lists.sql
+--
+-- The existing order lines were all priced at the base price of their items and keep NULL conversions.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItemPrices Table
+CREATE TABLE CatalogItemPrices (
+    catalog_item_id CHAR(36) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    PRIMARY KEY (catalog_item_id, currency)
+);
+
+-- ExchangeRates Table
+CREATE TABLE ExchangeRates (
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (from_currency, to_currency)
+);
+
+-- OrderLines Table
+ALTER TABLE OrderLines
+    ADD COLUMN base_price_amount BIGINT NULL AFTER unit_price_currency,
+    ADD COLUMN base_price_currency CHAR(3) NULL AFTER base_price_amount,
+    ADD COLUMN exchange_rate DECIMAL(18, 8) NULL AFTER base_price_currency,
+    ADD COLUMN exchange_rate_updated_at TIMESTAMP NULL AFTER exchange_rate;
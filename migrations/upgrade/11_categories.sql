@@ -0,0 +1,36 @@
+-- Adds the category tree and the categories of the catalog items, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 10_price_lists.sql:
+--
+--   mysql -u root -p < migrations/upgrade/11_categories.sql
+
+USE `microservice-k8s-demo-db`;
+
+-- Categories Table
+-- The category tree, in which root categories have no parent_id.
+CREATE TABLE Categories (
+    id CHAR(36) PRIMARY KEY,
+    parent_id CHAR(36) NULL,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    UNIQUE KEY uq_categories_slug (slug),
+    INDEX idx_categories_parent_id (parent_id)
+);
+
+-- CategoryClosure Table
+-- A row for every ancestor of each category, including the category itself at depth 0.
+CREATE TABLE CategoryClosure (
+    ancestor_id CHAR(36) NOT NULL,
+    descendant_id CHAR(36) NOT NULL,
+    depth INT NOT NULL,
+    PRIMARY KEY (ancestor_id, descendant_id),
+    INDEX idx_category_closure_descendant_id (descendant_id)
+);
+
+-- CatalogItemCategories Table
+CREATE TABLE CatalogItemCategories (
+    catalog_item_id CHAR(36) NOT NULL,
+    category_id CHAR(36) NOT NULL,
+    PRIMARY KEY (catalog_item_id, category_id),
+    INDEX idx_catalog_item_categories_category_id (category_id)
+);
@@ -0,0 +1,35 @@
+-- Adds the variants of the catalog items and the SKUs of stock reservations and order lines,
+-- as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 11_categories.sql:
+--
+--   mysql -u root -p < migrations/upgrade/12_variants.sql
+--
+-- The existing reservations and order lines are all of items without variants and keep an empty SKU.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItemVariants Table
+-- The SKUs of the items, whose price override is NULL when they are sold at the price of their item.
+CREATE TABLE CatalogItemVariants (
+    sku VARCHAR(64) PRIMARY KEY,
+    catalog_item_id CHAR(36) NOT NULL,
+    attributes JSON NOT NULL,
+    price_amount BIGINT NULL,
+    price_currency CHAR(3) NULL,
+    stock INT NOT NULL DEFAULT 0,
+    -- reserved is kept between 0 and stock by the catalog service, as MySQL 5.7 does not enforce CHECK constraints.
+    reserved INT NOT NULL DEFAULT 0,
+    INDEX idx_catalog_item_variants_catalog_item_id (catalog_item_id)
+);
+
+-- StockReservations Table
+ALTER TABLE StockReservations
+    ADD COLUMN sku VARCHAR(64) NOT NULL DEFAULT '' AFTER catalog_item_id,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (reservation_id, catalog_item_id, sku);
+
+-- OrderLines Table
+ALTER TABLE OrderLines
+    ADD COLUMN sku VARCHAR(64) NOT NULL DEFAULT '' AFTER catalog_item_id,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (order_id, catalog_item_id, sku);
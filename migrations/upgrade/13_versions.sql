@@ -0,0 +1,16 @@
+-- Adds the versions of the catalog items and customers, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 12_variants.sql:
+--
+--   mysql -u root -p < migrations/upgrade/13_versions.sql
+--
+-- The existing items and customers start at version 1.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItems Table
+ALTER TABLE CatalogItems
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER reserved;
+
+-- Customers Table
+ALTER TABLE Customers
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER country;
@@ -0,0 +1,18 @@
+-- Adds the deletion times of the catalog items and customers, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 13_versions.sql:
+--
+--   mysql -u root -p < migrations/upgrade/14_soft_delete.sql
+--
+-- The existing items and customers are not deleted; those deleted before were removed for good.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItems Table
+ALTER TABLE CatalogItems
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER version,
+    ADD INDEX idx_catalog_items_deleted_at (deleted_at);
+
+-- Customers Table
+ALTER TABLE Customers
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER version,
+    ADD INDEX idx_customers_deleted_at (deleted_at);
@@ -0,0 +1,25 @@
+-- Adds the price history of the catalog items, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 14_soft_delete.sql:
+--
+--   mysql -u root -p < migrations/upgrade/15_price_history.sql
+--
+-- The history of the existing items starts with their current prices, effective from the upgrade,
+-- since their earlier prices were not recorded.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItemPriceHistory Table
+CREATE TABLE CatalogItemPriceHistory (
+    id CHAR(36) PRIMARY KEY,
+    catalog_item_id CHAR(36) NOT NULL,
+    price_amount BIGINT NOT NULL,
+    price_currency CHAR(3) NOT NULL,
+    effective_from TIMESTAMP NOT NULL,
+    applied_at TIMESTAMP NULL,
+    INDEX idx_catalog_item_price_history_catalog_item_id (catalog_item_id, effective_from),
+    INDEX idx_catalog_item_price_history_applied_at (applied_at, effective_from)
+);
+
+INSERT INTO CatalogItemPriceHistory (id, catalog_item_id, price_amount, price_currency, effective_from, applied_at)
+SELECT UUID(), id, price_amount, price_currency, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM CatalogItems;
@@ -0,0 +1,13 @@
+-- Adds the external keys of the catalog items, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 15_price_history.sql:
+--
+--   mysql -u root -p < migrations/upgrade/16_external_keys.sql
+--
+-- The existing items have no external key, so that the first import of their supplier creates new items.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogItems Table
+ALTER TABLE CatalogItems
+    ADD COLUMN external_key VARCHAR(255) NULL AFTER name,
+    ADD UNIQUE KEY uq_catalog_items_external_key (external_key);
@@ -0,0 +1,26 @@
+-- Adds the claims of the outbox events, as created by init.d/1_create_table.sql.
+-- It is run once by hand against the databases created before, after 16_external_keys.sql:
+--
+--   mysql -u root -p < migrations/upgrade/17_outbox_claims.sql
+--
+-- The existing events are not claimed, so that the unpublished ones are claimed by the next relay.
+
+USE `microservice-k8s-demo-db`;
+
+-- CatalogOutboxEvents Table
+ALTER TABLE CatalogOutboxEvents
+    ADD COLUMN claim_id CHAR(36) NULL AFTER published_at,
+    ADD COLUMN claimed_until TIMESTAMP NULL AFTER claim_id,
+    ADD INDEX idx_catalog_outbox_events_claim_id (claim_id);
+
+-- CustomerOutboxEvents Table
+ALTER TABLE CustomerOutboxEvents
+    ADD COLUMN claim_id CHAR(36) NULL AFTER published_at,
+    ADD COLUMN claimed_until TIMESTAMP NULL AFTER claim_id,
+    ADD INDEX idx_customer_outbox_events_claim_id (claim_id);
+
+-- OrderOutboxEvents Table
+ALTER TABLE OrderOutboxEvents
+    ADD COLUMN claim_id CHAR(36) NULL AFTER published_at,
+    ADD COLUMN claimed_until TIMESTAMP NULL AFTER claim_id,
+    ADD INDEX idx_order_outbox_events_claim_id (claim_id);
@@ -19,7 +19,10 @@ import (
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/gateway"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mysql"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/publisher"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/search"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 )
@@ -42,13 +45,22 @@ func main() {
 		return
 	}
 
-	err = container.Invoke(func(grpcHandler pb.CatalogServiceServer, config *config.ServerConfig) {
+	err = container.Invoke(func(
+		grpcHandler pb.CatalogServiceServer,
+		config *config.ServerConfig,
+		idempotencyInterceptor grpc.UnaryServerInterceptor,
+		idempotencySweeper *idempotency.Sweeper,
+		outboxRelay *usecase.OutboxRelay,
+		searchIndexer *usecase.SearchIndexer,
+		trashPurger *usecase.TrashPurger,
+		priceScheduler *usecase.PriceScheduler,
+	) {
 		lis, err := net.Listen("tcp", addr) //nolint:govet // This is not a mistake
 		if err != nil {
 			log.Critical("Failed to listen", log.Ferror(err))
 		}
 
-		srv := grpc.NewServer()
+		srv := grpc.NewServer(grpc.UnaryInterceptor(idempotencyInterceptor))
 
 		pb.RegisterCatalogServiceServer(srv, grpcHandler)
 
@@ -56,6 +68,12 @@ func main() {
 
 		log.Info("Server started", log.Fstring("addr", addr))
 
+		go idempotencySweeper.Run(mainCtx)
+		go outboxRelay.Run(mainCtx)
+		go searchIndexer.Run(mainCtx)
+		go trashPurger.Run(mainCtx)
+		go priceScheduler.Run(mainCtx)
+
 		go func() {
 			if err = srv.Serve(lis); err != nil {
 				log.Critical("Failed to serve", log.Ferror(err))
@@ -90,10 +108,31 @@ func BuildContainer(ctx context.Context) (*dig.Container, error) {
 		config.NewServerConfig,
 		config.NewDBConfig,
 		mysql.NewMySQLDB,
+		idempotency.NewConfig,
+		config.NewEventConfig,
+		config.NewSearchConfig,
+		config.NewTrashConfig,
+		config.NewPriceScheduleConfig,
 		mysql.NewTransactionRepository,
+		mysql.NewIdempotencyRepository,
+		mysql.NewOutboxRepository,
+		publisher.NewEventPublisher,
 		mysql.NewCatalogItemRepository,
+		mysql.NewStockRepository,
+		mysql.NewPriceRepository,
+		mysql.NewCategoryRepository,
+		mysql.NewVariantRepository,
+		search.NewSearchIndex,
+		usecase.NewChangeFeed,
+		usecase.NewChangeWatcher,
 		usecase.NewCatalogItemUseCase,
 		gateway.NewCatalogItemHandler,
+		idempotency.NewInterceptor,
+		idempotency.NewSweeper,
+		usecase.NewOutboxRelay,
+		usecase.NewSearchIndexer,
+		usecase.NewTrashPurger,
+		usecase.NewPriceScheduler,
 	}
 
 	for _, provider := range providers {
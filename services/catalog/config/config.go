@@ -10,7 +10,11 @@ import (
 )
 
 const (
-	serverPrefix = "SERVER_"
+	serverPrefix        = "SERVER_"
+	eventPrefix         = "EVENT_"
+	searchPrefix        = "SEARCH_"
+	trashPrefix         = "TRASH_"
+	priceSchedulePrefix = "PRICE_SCHEDULE_"
 )
 
 type DBConfig struct {
@@ -29,6 +33,46 @@ type ServerConfig struct {
 	PreflightCacheDurationSec int           `env:"PREFLIGHT_CACHE_DURATION_SEC,default=300"`
 }
 
+// EventConfig selects the publisher of the events stored in the outbox and how often the outbox is relayed.
+// Publisher is either "log", which writes the events as JSON lines to LogFile or to the standard output,
+// or "memory", which keeps them in memory.
+// The relay claims the events of a batch for RelayLease, which should be longer than publishing a batch.
+// Watchers read the outbox when the relay has published events, and every WatchPollInterval for
+// the events relayed by other replicas. They wait up to WatchGapTimeout for the events of a sequence
+// missing from the outbox, which should be longer than the transactions storing events.
+type EventConfig struct {
+	Publisher         string        `env:"PUBLISHER,default=log"`
+	LogFile           string        `env:"LOG_FILE"`
+	RelayInterval     time.Duration `env:"RELAY_INTERVAL,default=1s"`
+	RelayBatchSize    int           `env:"RELAY_BATCH_SIZE,default=100"`
+	RelayLease        time.Duration `env:"RELAY_LEASE,default=1m"`
+	WatchPollInterval time.Duration `env:"WATCH_POLL_INTERVAL,default=2s"`
+	WatchGapTimeout   time.Duration `env:"WATCH_GAP_TIMEOUT,default=5s"`
+}
+
+// SearchConfig selects the index searching the catalog. Index is "memory", an inverted index kept
+// in the memory of each replica. A word of a query matches the words at most MaxEdits typos away,
+// and none when it is 0.
+type SearchConfig struct {
+	Index    string `env:"INDEX,default=memory"`
+	MaxEdits int    `env:"MAX_EDITS,default=2"`
+}
+
+// TrashConfig controls how long deleted items are kept in the trash, from which they can be restored,
+// before they are purged. The trash is purged every PurgeInterval, PurgeBatchSize items at a time.
+type TrashConfig struct {
+	Retention      time.Duration `env:"RETENTION,default=720h"`
+	PurgeInterval  time.Duration `env:"PURGE_INTERVAL,default=1h"`
+	PurgeBatchSize int           `env:"PURGE_BATCH_SIZE,default=100"`
+}
+
+// PriceScheduleConfig controls how often the scheduled price changes that are due are made,
+// BatchSize changes at a time.
+type PriceScheduleConfig struct {
+	Interval  time.Duration `env:"INTERVAL,default=1m"`
+	BatchSize int           `env:"BATCH_SIZE,default=100"`
+}
+
 func NewDBConfig(ctx context.Context, dbPrefix string) (*DBConfig, error) {
 	conf := &DBConfig{}
 	pl := envconfig.PrefixLookuper(dbPrefix, envconfig.OsLookuper())
@@ -54,3 +98,55 @@ func NewServerConfig(ctx context.Context) (*ServerConfig, error) {
 	}
 	return conf, nil
 }
+
+func NewEventConfig(ctx context.Context) (*EventConfig, error) {
+	conf := &EventConfig{}
+	pl := envconfig.PrefixLookuper(eventPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load event config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewSearchConfig(ctx context.Context) (*SearchConfig, error) {
+	conf := &SearchConfig{}
+	pl := envconfig.PrefixLookuper(searchPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load search config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewTrashConfig(ctx context.Context) (*TrashConfig, error) {
+	conf := &TrashConfig{}
+	pl := envconfig.PrefixLookuper(trashPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load trash config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewPriceScheduleConfig(ctx context.Context) (*PriceScheduleConfig, error) {
+	conf := &PriceScheduleConfig{}
+	pl := envconfig.PrefixLookuper(priceSchedulePrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load price schedule config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
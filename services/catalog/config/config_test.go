@@ -114,3 +114,196 @@ func Test_NewServerConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewEventConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *EventConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &EventConfig{
+				Publisher:         "log",
+				RelayInterval:     time.Second,
+				RelayBatchSize:    100,
+				RelayLease:        time.Minute,
+				WatchPollInterval: 2 * time.Second,
+				WatchGapTimeout:   5 * time.Second,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("EVENT_PUBLISHER", "memory")
+				t.Setenv("EVENT_LOG_FILE", "/var/log/events.jsonl")
+				t.Setenv("EVENT_RELAY_INTERVAL", "500ms")
+				t.Setenv("EVENT_RELAY_BATCH_SIZE", "10")
+				t.Setenv("EVENT_RELAY_LEASE", "30s")
+				t.Setenv("EVENT_WATCH_POLL_INTERVAL", "5s")
+				t.Setenv("EVENT_WATCH_GAP_TIMEOUT", "10s")
+			},
+			want: &EventConfig{
+				Publisher:         "memory",
+				LogFile:           "/var/log/events.jsonl",
+				RelayInterval:     500 * time.Millisecond,
+				RelayBatchSize:    10,
+				RelayLease:        30 * time.Second,
+				WatchPollInterval: 5 * time.Second,
+				WatchGapTimeout:   10 * time.Second,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewEventConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewSearchConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *SearchConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &SearchConfig{
+				Index:    "memory",
+				MaxEdits: 2,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("SEARCH_INDEX", "memory")
+				t.Setenv("SEARCH_MAX_EDITS", "1")
+			},
+			want: &SearchConfig{
+				Index:    "memory",
+				MaxEdits: 1,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewSearchConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewTrashConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *TrashConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &TrashConfig{
+				Retention:      720 * time.Hour,
+				PurgeInterval:  time.Hour,
+				PurgeBatchSize: 100,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("TRASH_RETENTION", "168h")
+				t.Setenv("TRASH_PURGE_INTERVAL", "10m")
+				t.Setenv("TRASH_PURGE_BATCH_SIZE", "10")
+			},
+			want: &TrashConfig{
+				Retention:      168 * time.Hour,
+				PurgeInterval:  10 * time.Minute,
+				PurgeBatchSize: 10,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewTrashConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewPriceScheduleConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *PriceScheduleConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &PriceScheduleConfig{
+				Interval:  time.Minute,
+				BatchSize: 100,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("PRICE_SCHEDULE_INTERVAL", "10s")
+				t.Setenv("PRICE_SCHEDULE_BATCH_SIZE", "10")
+			},
+			want: &PriceScheduleConfig{
+				Interval:  10 * time.Second,
+				BatchSize: 10,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewPriceScheduleConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,30 +1,140 @@
 package entity
 
 import (
-	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CatalogItem struct {
-	ID    string  `json:"id" db:"id"`
-	Name  string  `json:"name" db:"name"`
-	Price float64 `json:"price" db:"price"`
+	ID   string `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	// ExternalKey is the key of the item in the systems of its supplier, which imports upsert the items by.
+	// It is empty for the items that were not imported.
+	ExternalKey string `json:"external_key,omitempty" db:"external_key"`
+	Price       Money  `json:"price" db:"price"`
+	// Prices are the explicit prices of the item in currencies other than that of Price, its base price.
+	Prices []Money `json:"prices,omitempty"`
+	// Conversion is set by Localize when Price was converted from the base price.
+	Conversion *PriceConversion `json:"-"`
+	// CategoryIDs are the categories the item is assigned to.
+	CategoryIDs []string `json:"category_ids,omitempty"`
+	// Variants are the SKUs the item is sold as, if it is not sold as is.
+	Variants []Variant `json:"variants,omitempty"`
+	// Stock is the quantity on hand, of which Reserved is held for orders that are not shipped yet.
+	Stock    int `json:"stock" db:"stock"`
+	Reserved int `json:"reserved" db:"reserved"`
+	// Version is incremented by every update of the item, so that an update made from a stale read is rejected.
+	Version int64 `json:"version" db:"version"`
+	// DeletedAt is when the item was moved to the trash, and nil while it is not there.
+	// An item in the trash can be restored until it is purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
-func NewCatalogItem(id, name string, price float64) (*CatalogItem, error) {
+// The fields of a catalog item an update can be limited to.
+const (
+	CatalogItemFieldName  = "name"
+	CatalogItemFieldPrice = "price"
+	CatalogItemFieldStock = "stock"
+)
+
+func NewCatalogItem(id, name string, price Money) (*CatalogItem, error) {
 	if id == "" {
 		id = uuid.New().String()
 	}
 	if name == "" {
-		return nil, errors.New("name is required")
+		return nil, NewError(ErrInvalidArgument, "name is required")
+	}
+	item := &CatalogItem{
+		ID:      id,
+		Name:    name,
+		Version: 1,
+	}
+	if err := item.SetPrice(price); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// maxExternalKeyLength is the length in bytes an external key can have at most.
+const maxExternalKeyLength = 255
+
+// SetExternalKey sets the key of the item in the systems of its supplier.
+func (item *CatalogItem) SetExternalKey(key string) error {
+	if key == "" {
+		return NewError(ErrInvalidArgument, "external key is required")
+	}
+	if len(key) > maxExternalKeyLength {
+		return NewError(ErrInvalidArgument, fmt.Sprintf("external key must not be longer than %d bytes", maxExternalKeyLength))
+	}
+	item.ExternalKey = key
+	return nil
+}
+
+// SetPrice changes the price, which must be positive and in a supported currency.
+func (item *CatalogItem) SetPrice(price Money) error {
+	if err := price.Validate(); err != nil {
+		return err
+	}
+	if !price.IsPositive() {
+		return NewError(ErrInvalidArgument, "price must be greater than 0")
+	}
+	item.Price = price
+	return nil
+}
+
+// SetPrices replaces the explicit prices, which must be positive and each in a different currency
+// than the others and than the base price.
+func (item *CatalogItem) SetPrices(prices []Money) error {
+	currencies := map[string]bool{item.Price.Currency: true}
+	for _, price := range prices {
+		if err := price.Validate(); err != nil {
+			return err
+		}
+		if !price.IsPositive() {
+			return NewError(ErrInvalidArgument, "price must be greater than 0")
+		}
+		if currencies[price.Currency] {
+			return NewError(ErrInvalidArgument, fmt.Sprintf("item has more than one price in %s", price.Currency))
+		}
+		currencies[price.Currency] = true
+	}
+	item.Prices = prices
+	return nil
+}
+
+// PriceIn returns the price of the item in currency if it has one, either as its base price or an explicit price.
+func (item *CatalogItem) PriceIn(currency string) (Money, bool) {
+	if item.Price.Currency == currency {
+		return item.Price, true
+	}
+	for _, price := range item.Prices {
+		if price.Currency == currency {
+			return price, true
+		}
+	}
+	return Money{}, false
+}
+
+// Localize sets Price to the price of the item in currency. Without a price in currency, the base price
+// is converted at rate, which must then convert from the currency of the base price to currency.
+func (item *CatalogItem) Localize(currency string, rate *ExchangeRate) error {
+	if price, ok := item.PriceIn(currency); ok {
+		item.Price = price
+		return nil
+	}
+	if rate == nil || rate.From != item.Price.Currency || rate.To != currency {
+		return fmt.Errorf("%w from %s to %s", ErrNoExchangeRate, item.Price.Currency, currency)
+	}
+	price, err := rate.Convert(item.Price)
+	if err != nil {
+		return err
 	}
-	if price <= 0 {
-		return nil, errors.New("price must be greater than 0")
+	item.Conversion = &PriceConversion{
+		BasePrice: item.Price,
+		Rate:      *rate,
 	}
-	return &CatalogItem{
-		ID:    id,
-		Name:  name,
-		Price: price,
-	}, nil
+	item.Price = price
+	return nil
 }
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -19,7 +20,7 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 		arg  struct {
 			id    string
 			name  string
-			price float64
+			price Money
 		}
 		want struct {
 			item *CatalogItem
@@ -31,20 +32,21 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				id:    catalogID,
 				name:  "item",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
 				err  error
 			}{
 				item: &CatalogItem{
-					ID:    catalogID,
-					Name:  "item",
-					Price: 100,
+					ID:      catalogID,
+					Name:    "item",
+					Price:   Money{Amount: 10000, Currency: "USD"},
+					Version: 1,
 				},
 				err: nil,
 			},
@@ -54,19 +56,20 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				name:  "item",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
 				err  error
 			}{
 				item: &CatalogItem{
-					ID:    uuid.New().String(),
-					Name:  "item",
-					Price: 100,
+					ID:      uuid.New().String(),
+					Name:    "item",
+					Price:   Money{Amount: 10000, Currency: "USD"},
+					Version: 1,
 				},
 				err: nil,
 			},
@@ -76,11 +79,11 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				id:    catalogID,
 				name:  "",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -95,11 +98,11 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				id:    catalogID,
 				name:  "item",
-				price: -1,
+				price: Money{Amount: -100, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -109,6 +112,25 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 				err:  errors.New("price must be greater than 0"),
 			},
 		},
+		{
+			name: "Fail: currency is not supported",
+			arg: struct {
+				id    string
+				name  string
+				price Money
+			}{
+				id:    catalogID,
+				name:  "item",
+				price: Money{Amount: 10000, Currency: "XXX"},
+			},
+			want: struct {
+				item *CatalogItem
+				err  error
+			}{
+				item: nil,
+				err:  errors.New(`invalid amount of money: unsupported currency "XXX"`),
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -123,6 +145,9 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			} else if err != nil && tt.want.err != nil && err.Error() != tt.want.err.Error() {
 				t.Errorf("NewCatalogItem() error = %v, wantErr %v", err, tt.want.err)
 			}
+			if err != nil && !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("NewCatalogItem() error = %v, want kind %v", err, ErrInvalidArgument)
+			}
 
 			if d := cmp.Diff(item, tt.want.item, cmpopts.IgnoreFields(CatalogItem{}, "ID")); len(d) != 0 {
 				t.Errorf("NewCatalogItem() mismatch (-got +want):\n%s", d)
@@ -130,3 +155,154 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 		})
 	}
 }
+
+func TestEntity_CatalogItem_SetPrices(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name    string
+		prices  []Money
+		wantErr error
+	}{
+		{
+			name:   "success",
+			prices: []Money{{Amount: 900, Currency: "EUR"}, {Amount: 150000, Currency: "JPY"}},
+		},
+		{
+			name:    "Fail: price in the currency of the base price",
+			prices:  []Money{{Amount: 900, Currency: "USD"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: two prices in one currency",
+			prices:  []Money{{Amount: 900, Currency: "EUR"}, {Amount: 950, Currency: "EUR"}},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: price is not positive",
+			prices:  []Money{{Amount: 0, Currency: "EUR"}},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &CatalogItem{Price: Money{Amount: 1000, Currency: "USD"}}
+			err := item.SetPrices(tt.prices)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetPrices() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !cmp.Equal(item.Prices, tt.prices) {
+				t.Errorf("SetPrices() Prices = %v, want %v", item.Prices, tt.prices)
+			}
+		})
+	}
+}
+
+func TestEntity_CatalogItem_SetExternalKey(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{
+			name: "success",
+			key:  "SUP-0001",
+		},
+		{
+			name:    "Fail: key is empty",
+			key:     "",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: key is too long",
+			key:     strings.Repeat("k", 256),
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &CatalogItem{}
+			err := item.SetExternalKey(tt.key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetExternalKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && item.ExternalKey != tt.key {
+				t.Errorf("SetExternalKey() ExternalKey = %v, want %v", item.ExternalKey, tt.key)
+			}
+		})
+	}
+}
+
+func TestEntity_CatalogItem_Localize(t *testing.T) {
+	t.Parallel()
+
+	rate := &ExchangeRate{From: "USD", To: "GBP", Rate: 79000000}
+
+	patterns := []struct {
+		name           string
+		currency       string
+		rate           *ExchangeRate
+		wantPrice      Money
+		wantConversion *PriceConversion
+		wantErr        error
+	}{
+		{
+			name:      "success: base price",
+			currency:  "USD",
+			wantPrice: Money{Amount: 1000, Currency: "USD"},
+		},
+		{
+			name:      "success: explicit price",
+			currency:  "EUR",
+			rate:      &ExchangeRate{From: "USD", To: "EUR", Rate: 108340000},
+			wantPrice: Money{Amount: 900, Currency: "EUR"},
+		},
+		{
+			name:           "success: converted price",
+			currency:       "GBP",
+			rate:           rate,
+			wantPrice:      Money{Amount: 790, Currency: "GBP"},
+			wantConversion: &PriceConversion{BasePrice: Money{Amount: 1000, Currency: "USD"}, Rate: *rate},
+		},
+		{
+			name:     "Fail: no exchange rate",
+			currency: "GBP",
+			wantErr:  ErrNoExchangeRate,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &CatalogItem{
+				Price:  Money{Amount: 1000, Currency: "USD"},
+				Prices: []Money{{Amount: 900, Currency: "EUR"}},
+			}
+			err := item.Localize(tt.currency, tt.rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Localize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if item.Price != tt.wantPrice {
+				t.Errorf("Localize() Price = %v, want %v", item.Price, tt.wantPrice)
+			}
+			if d := cmp.Diff(item.Conversion, tt.wantConversion); len(d) != 0 {
+				t.Errorf("Localize() Conversion mismatch (-got +want):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryCycle       = NewError(ErrFailedPrecondition, "category cannot be moved under itself")
+	ErrCategoryHasChildren = NewError(ErrFailedPrecondition, "category has child categories")
+)
+
+const maxSlugLength = 255
+
+var (
+	slugPattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	nonSlugRunes = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Category is a node of the category tree. A root category has no ParentID. Position orders
+// the children of the same parent, and Depth is the number of ancestors of the category.
+type Category struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Position int    `json:"position"`
+	Depth    int    `json:"depth"`
+}
+
+// NewCategory creates a category. The slug is derived from the name when it is empty.
+func NewCategory(id, parentID, name, slug string, position int) (*Category, error) {
+	if id == "" {
+		id = uuid.New().String()
+	}
+	if parentID == id {
+		return nil, ErrCategoryCycle
+	}
+	category := &Category{
+		ID:       id,
+		ParentID: parentID,
+	}
+	if err := category.Set(name, slug, position); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// Set changes the name, slug and position of the category. The slug is derived from the name when it is empty.
+func (c *Category) Set(name, slug string, position int) error {
+	if name == "" {
+		return NewError(ErrInvalidArgument, "name is required")
+	}
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	if len(slug) > maxSlugLength || !slugPattern.MatchString(slug) {
+		return NewError(ErrInvalidArgument, "slug must be lowercase letters and digits separated by hyphens")
+	}
+	if position < 0 {
+		return NewError(ErrInvalidArgument, "position must be 0 or greater")
+	}
+	c.Name = name
+	c.Slug = slug
+	c.Position = position
+	return nil
+}
+
+// Slugify turns a name into a slug, such as "Home & Garden" into "home-garden".
+func Slugify(name string) string {
+	return strings.Trim(nonSlugRunes.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// SortCategories orders the categories of a tree depth first, so that every category comes right
+// after its parent and before its next sibling, and siblings are ordered by position and name.
+// Categories whose parent is not among them are treated as roots.
+func SortCategories(categories []Category) []Category {
+	ids := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		ids[c.ID] = true
+	}
+	children := make(map[string][]Category)
+	for _, c := range categories {
+		parentID := c.ParentID
+		if !ids[parentID] {
+			parentID = ""
+		}
+		children[parentID] = append(children[parentID], c)
+	}
+	for _, siblings := range children {
+		sort.SliceStable(siblings, func(i, j int) bool {
+			if siblings[i].Position != siblings[j].Position {
+				return siblings[i].Position < siblings[j].Position
+			}
+			return siblings[i].Name < siblings[j].Name
+		})
+	}
+
+	sorted := make([]Category, 0, len(categories))
+	var walk func(parentID string)
+	walk = func(parentID string) {
+		for _, c := range children[parentID] {
+			sorted = append(sorted, c)
+			walk(c.ID)
+		}
+	}
+	walk("")
+	return sorted
+}
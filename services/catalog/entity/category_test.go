@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEntity_NewCategory(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		id       string
+		parentID string
+		catName  string
+		slug     string
+		position int
+		wantSlug string
+		wantErr  error
+	}{
+		{
+			name:     "success",
+			catName:  "Books",
+			slug:     "books",
+			wantSlug: "books",
+		},
+		{
+			name:     "success: slug is derived from the name",
+			catName:  "Home & Garden",
+			wantSlug: "home-garden",
+		},
+		{
+			name:    "Fail: name is empty",
+			slug:    "books",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: slug has upper case letters",
+			catName: "Books",
+			slug:    "Books",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: no slug can be derived from the name",
+			catName: "!!!",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:     "Fail: position is negative",
+			catName:  "Books",
+			position: -1,
+			wantErr:  ErrInvalidArgument,
+		},
+		{
+			name:     "Fail: parent is the category itself",
+			id:       "id",
+			parentID: "id",
+			catName:  "Books",
+			wantErr:  ErrCategoryCycle,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			category, err := NewCategory(tt.id, tt.parentID, tt.catName, tt.slug, tt.position)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && category.Slug != tt.wantSlug {
+				t.Errorf("NewCategory() Slug = %v, want %v", category.Slug, tt.wantSlug)
+			}
+		})
+	}
+}
+
+func TestEntity_SortCategories(t *testing.T) {
+	t.Parallel()
+
+	categories := []Category{
+		{ID: "laptops", ParentID: "computers", Name: "Laptops", Position: 1},
+		{ID: "garden", Name: "Garden", Position: 1},
+		{ID: "computers", ParentID: "electronics", Name: "Computers"},
+		{ID: "desktops", ParentID: "computers", Name: "Desktops", Position: 1},
+		{ID: "electronics", Name: "Electronics"},
+		{ID: "orphan", ParentID: "missing", Name: "Orphan", Position: 2},
+	}
+
+	var got []string
+	for _, c := range SortCategories(categories) {
+		got = append(got, c.ID)
+	}
+	want := []string{"electronics", "computers", "desktops", "laptops", "garden", "orphan"}
+	if d := cmp.Diff(got, want); len(d) != 0 {
+		t.Errorf("SortCategories() mismatch (-got +want):\n%s", d)
+	}
+}
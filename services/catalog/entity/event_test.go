@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_NewEvent(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	now := time.Now()
+
+	patterns := []struct {
+		name string
+		arg  struct {
+			eventType   EventType
+			aggregateID string
+			payload     interface{}
+		}
+		want    string
+		wantErr error
+	}{
+		{
+			name: "success",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeCatalogItemPriceChanged,
+				aggregateID: itemID,
+				payload:     PriceChange{CatalogItemID: itemID, OldPrice: Money{Amount: 10000, Currency: "USD"}, NewPrice: Money{Amount: 12000, Currency: "USD"}},
+			},
+			want:    `{"catalog_item_id":"` + itemID + `","old_price":{"amount":10000,"currency":"USD"},"new_price":{"amount":12000,"currency":"USD"}}`,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: aggregate id is required",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType: EventTypeCatalogItemDeleted,
+				payload:   Deletion{ID: itemID},
+			},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name: "Fail: payload cannot be encoded",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeCatalogItemCreated,
+				aggregateID: itemID,
+				payload:     make(chan int),
+			},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := NewEvent(tt.arg.eventType, tt.arg.aggregateID, tt.arg.payload, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if event.ID == "" || event.Type != tt.arg.eventType || event.AggregateID != tt.arg.aggregateID || !event.OccurredAt.Equal(now) {
+					t.Errorf("NewEvent() = %v", event)
+				}
+				if !json.Valid(event.Payload) || string(event.Payload) != tt.want {
+					t.Errorf("NewEvent() payload = %s, want %s", event.Payload, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// RateExponent is the number of decimal places of exchange rates.
+const RateExponent = 8
+
+var ErrNoExchangeRate = NewError(ErrFailedPrecondition, "no exchange rate")
+
+// ExchangeRate is the price in To of one unit of From, in units of 10^-RateExponent, so that
+// a rate of 1.0834 is 108340000. A rate only converts from From to To; the opposite direction
+// has a rate of its own.
+type ExchangeRate struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Rate      int64     `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewExchangeRate parses a decimal rate, such as "1.0834", from one currency to another.
+func NewExchangeRate(from, to, rate string, updatedAt time.Time) (*ExchangeRate, error) {
+	if err := ValidateCurrency(from); err != nil {
+		return nil, err
+	}
+	if err := ValidateCurrency(to); err != nil {
+		return nil, err
+	}
+	if from == to {
+		return nil, NewError(ErrInvalidArgument, "exchange rate must be between different currencies")
+	}
+	r, err := parseMinorUnits(rate, RateExponent, false)
+	if err != nil {
+		return nil, WrapError(ErrInvalidArgument, fmt.Sprintf("invalid exchange rate: %q", rate), err)
+	}
+	if r <= 0 {
+		return nil, NewError(ErrInvalidArgument, "exchange rate must be greater than 0")
+	}
+	return &ExchangeRate{
+		From:      from,
+		To:        to,
+		Rate:      r,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
+// Decimal formats the rate without trailing zeros, such as "1.0834".
+func (r ExchangeRate) Decimal() string {
+	return strings.TrimSuffix(strings.TrimRight(formatDecimal(r.Rate, RateExponent), "0"), ".")
+}
+
+// Convert converts m, which must be in From, to To. The result is rounded half away from zero to
+// the minor unit of To, which is the only rounding of a converted price.
+func (r ExchangeRate) Convert(m Money) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, fmt.Errorf("%w: cannot convert %s at a rate from %s", ErrCurrencyMismatch, m.Currency, r.From)
+	}
+
+	// amount * rate * 10^exponent(To) / (10^exponent(From) * 10^RateExponent), computed exactly.
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(r.Rate))
+	num.Mul(num, pow10(currencyExponents[r.To]))
+	den := pow10(currencyExponents[r.From] + RateExponent)
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Abs(rem).Lsh(rem, 1).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	if !q.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s converted to %s is out of range", ErrInvalidMoney, m, r.To)
+	}
+	return Money{Amount: q.Int64(), Currency: r.To}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil) //nolint:gomnd // 10 is the base of decimals.
+}
+
+// PriceConversion records the base price a price was converted from and the rate it was converted at,
+// so that the price can be reproduced.
+type PriceConversion struct {
+	BasePrice Money        `json:"base_price"`
+	Rate      ExchangeRate `json:"rate"`
+}
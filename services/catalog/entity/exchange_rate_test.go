@@ -0,0 +1,134 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEntity_NewExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	patterns := []struct {
+		name    string
+		from    string
+		to      string
+		rate    string
+		want    *ExchangeRate
+		wantErr error
+	}{
+		{
+			name: "success",
+			from: "USD",
+			to:   "EUR",
+			rate: "1.0834",
+			want: &ExchangeRate{From: "USD", To: "EUR", Rate: 108340000, UpdatedAt: now},
+		},
+		{
+			name:    "Fail: same currency",
+			from:    "USD",
+			to:      "USD",
+			rate:    "1",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: unsupported currency",
+			from:    "USD",
+			to:      "XXX",
+			rate:    "1",
+			wantErr: ErrInvalidMoney,
+		},
+		{
+			name:    "Fail: more decimal places than a rate has",
+			from:    "USD",
+			to:      "EUR",
+			rate:    "1.000000001",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: rate is zero",
+			from:    "USD",
+			to:      "EUR",
+			rate:    "0",
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewExchangeRate(tt.from, tt.to, tt.rate, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewExchangeRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && *got != *tt.want {
+				t.Errorf("NewExchangeRate() = %v, want %v", got, tt.want)
+			}
+			if tt.want != nil && got.Decimal() != tt.rate {
+				t.Errorf("Decimal() = %v, want %v", got.Decimal(), tt.rate)
+			}
+		})
+	}
+}
+
+func TestEntity_ExchangeRate_Convert(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name    string
+		rate    ExchangeRate
+		money   Money
+		want    Money
+		wantErr error
+	}{
+		{
+			name:  "success: rounded half away from zero to the minor unit",
+			rate:  ExchangeRate{From: "USD", To: "EUR", Rate: 108340000},
+			money: Money{Amount: 1999, Currency: "USD"},
+			want:  Money{Amount: 2166, Currency: "EUR"},
+		},
+		{
+			name:  "success: half is rounded up",
+			rate:  ExchangeRate{From: "USD", To: "EUR", Rate: 50000000},
+			money: Money{Amount: 1, Currency: "USD"},
+			want:  Money{Amount: 1, Currency: "EUR"},
+		},
+		{
+			name:  "success: to a currency without minor unit",
+			rate:  ExchangeRate{From: "USD", To: "JPY", Rate: 14950000000},
+			money: Money{Amount: 1999, Currency: "USD"},
+			want:  Money{Amount: 2989, Currency: "JPY"},
+		},
+		{
+			name:  "success: from a currency without minor unit",
+			rate:  ExchangeRate{From: "JPY", To: "USD", Rate: 668900},
+			money: Money{Amount: 2989, Currency: "JPY"},
+			want:  Money{Amount: 1999, Currency: "USD"},
+		},
+		{
+			name:    "Fail: money is not in the currency of the rate",
+			rate:    ExchangeRate{From: "USD", To: "EUR", Rate: 108340000},
+			money:   Money{Amount: 1999, Currency: "GBP"},
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.rate.Convert(tt.money)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
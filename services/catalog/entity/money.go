@@ -0,0 +1,209 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of the prices stored before prices had a currency.
+const DefaultCurrency = "USD"
+
+// currencyExponents is the number of digits of the minor unit of each supported ISO 4217 currency.
+var currencyExponents = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+}
+
+// Currencies returns the supported currencies in alphabetical order.
+func Currencies() []string {
+	currencies := make([]string, 0, len(currencyExponents))
+	for currency := range currencyExponents {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+var (
+	ErrInvalidMoney     = NewError(ErrInvalidArgument, "invalid amount of money")
+	ErrCurrencyMismatch = NewError(ErrInvalidArgument, "amounts of money have different currencies")
+)
+
+// Money is an amount of money in the minor unit of its currency, such as cents for USD,
+// so that amounts add up exactly. Amounts are only rounded when they are converted from
+// a decimal with more digits than the currency has, and they are then rounded half away from zero.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	m := Money{Amount: amount, Currency: currency}
+	if err := m.Validate(); err != nil {
+		return Money{}, err
+	}
+	return m, nil
+}
+
+// ParseMoney parses a decimal amount in the major unit of currency, such as "12.34" for 12.34 USD.
+// Amounts with more decimal places than the currency has are rejected rather than rounded.
+func ParseMoney(s, currency string) (Money, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, unsupportedCurrency(currency)
+	}
+	amount, err := parseMinorUnits(s, exponent, false)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MoneyFromFloat converts an amount in the major unit of currency, as prices were stored before they
+// were Money, rounding it half away from zero to the minor unit.
+func MoneyFromFloat(f float64, currency string) (Money, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, unsupportedCurrency(currency)
+	}
+	// The shortest decimal representing f is rounded, as f itself is usually not exactly the
+	// decimal it was written as: 1.005 is slightly less than 1.005.
+	amount, err := parseMinorUnits(strconv.FormatFloat(f, 'f', -1, 64), exponent, true)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// parseMinorUnits parses a decimal into an integer number of its 10^-exponent units. Digits past the
+// exponent are rounded half away from zero if round is set, and are an error otherwise.
+func parseMinorUnits(s string, exponent int, round bool) (int64, error) {
+	negative := strings.HasPrefix(s, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("%w: %q is not a decimal", ErrInvalidMoney, s)
+	}
+
+	roundUp := false
+	if len(fraction) > exponent {
+		if !round && strings.Trim(fraction[exponent:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidMoney, s, exponent)
+		}
+		roundUp = fraction[exponent] >= '5'
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	amount, err := strconv.ParseInt("0"+whole+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, s)
+	}
+	if roundUp {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func unsupportedCurrency(currency string) error {
+	return fmt.Errorf("%w: unsupported currency %q", ErrInvalidMoney, currency)
+}
+
+// ValidateCurrency reports an error if currency is not supported.
+func ValidateCurrency(currency string) error {
+	if _, ok := currencyExponents[currency]; !ok {
+		return unsupportedCurrency(currency)
+	}
+	return nil
+}
+
+// Validate reports an error if the currency is not supported.
+func (m Money) Validate() error {
+	return ValidateCurrency(m.Currency)
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Add returns the sum of m and other, which must have the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Mul returns m multiplied by a quantity.
+func (m Money) Mul(quantity int64) Money {
+	return Money{Amount: m.Amount * quantity, Currency: m.Currency}
+}
+
+// Decimal formats the amount in the major unit of the currency, such as "12.34" for 1234 cents.
+func (m Money) Decimal() string {
+	return formatDecimal(m.Amount, currencyExponents[m.Currency])
+}
+
+// formatDecimal formats an integer number of 10^-exponent units as a decimal.
+func formatDecimal(amount int64, exponent int) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+// Float64 returns the amount in the major unit of the currency, for the clients that still read prices as numbers.
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.Decimal(), 64)
+	return f
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// UnmarshalJSON also accepts the plain numbers that prices were encoded as before they were Money,
+// such as in the events stored then, as amounts in DefaultCurrency.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var f float64
+	if err := json.Unmarshal(b, &f); err == nil {
+		money, merr := MoneyFromFloat(f, DefaultCurrency)
+		if merr != nil {
+			return merr
+		}
+		*m = money
+		return nil
+	}
+
+	type money Money
+	var v money
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
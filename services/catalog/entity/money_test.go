@@ -0,0 +1,208 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEntity_ParseMoney(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		s        string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{
+			name:     "success: cents",
+			s:        "12.34",
+			currency: "USD",
+			want:     Money{Amount: 1234, Currency: "USD"},
+		},
+		{
+			name:     "success: fewer decimal places than the currency has",
+			s:        "12.5",
+			currency: "EUR",
+			want:     Money{Amount: 1250, Currency: "EUR"},
+		},
+		{
+			name:     "success: trailing zeros past the minor unit",
+			s:        "12.3400",
+			currency: "USD",
+			want:     Money{Amount: 1234, Currency: "USD"},
+		},
+		{
+			name:     "success: currency without minor unit",
+			s:        "1200",
+			currency: "JPY",
+			want:     Money{Amount: 1200, Currency: "JPY"},
+		},
+		{
+			name:     "success: negative amount",
+			s:        "-0.05",
+			currency: "USD",
+			want:     Money{Amount: -5, Currency: "USD"},
+		},
+		{
+			name:     "Fail: more decimal places than the currency has",
+			s:        "12.345",
+			currency: "USD",
+			wantErr:  ErrInvalidMoney,
+		},
+		{
+			name:     "Fail: not a decimal",
+			s:        "1e3",
+			currency: "USD",
+			wantErr:  ErrInvalidMoney,
+		},
+		{
+			name:     "Fail: unsupported currency",
+			s:        "12.34",
+			currency: "XXX",
+			wantErr:  ErrInvalidMoney,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseMoney(tt.s, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntity_MoneyFromFloat(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		f        float64
+		currency string
+		want     Money
+	}{
+		{
+			name:     "success: exact",
+			f:        19.99,
+			currency: "USD",
+			want:     Money{Amount: 1999, Currency: "USD"},
+		},
+		{
+			name:     "success: half is rounded away from zero",
+			f:        1.005,
+			currency: "USD",
+			want:     Money{Amount: 101, Currency: "USD"},
+		},
+		{
+			name:     "success: negative half is rounded away from zero",
+			f:        -1.005,
+			currency: "USD",
+			want:     Money{Amount: -101, Currency: "USD"},
+		},
+		{
+			name:     "success: below half is rounded down",
+			f:        0.1 + 0.2,
+			currency: "USD",
+			want:     Money{Amount: 30, Currency: "USD"},
+		},
+		{
+			name:     "success: currency without minor unit",
+			f:        99.5,
+			currency: "JPY",
+			want:     Money{Amount: 100, Currency: "JPY"},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MoneyFromFloat(tt.f, tt.currency)
+			if err != nil {
+				t.Fatalf("MoneyFromFloat() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MoneyFromFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntity_Money(t *testing.T) {
+	t.Parallel()
+
+	price := Money{Amount: 1999, Currency: "USD"}
+
+	total, err := price.Mul(3).Add(Money{Amount: 1, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if want := (Money{Amount: 5998, Currency: "USD"}); total != want {
+		t.Errorf("Add() = %v, want %v", total, want)
+	}
+
+	if _, err = price.Add(Money{Amount: 1999, Currency: "EUR"}); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+
+	for _, tt := range []struct {
+		money Money
+		want  string
+	}{
+		{Money{Amount: 1999, Currency: "USD"}, "19.99"},
+		{Money{Amount: 5, Currency: "USD"}, "0.05"},
+		{Money{Amount: -5, Currency: "USD"}, "-0.05"},
+		{Money{Amount: 1200, Currency: "JPY"}, "1200"},
+	} {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("Decimal() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestEntity_MoneyUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name string
+		json string
+		want Money
+	}{
+		{
+			name: "success: money",
+			json: `{"amount":1999,"currency":"EUR"}`,
+			want: Money{Amount: 1999, Currency: "EUR"},
+		},
+		{
+			name: "success: number encoded before prices were money",
+			json: `19.99`,
+			want: Money{Amount: 1999, Currency: DefaultCurrency},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got Money
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
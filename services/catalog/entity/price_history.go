@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceHistoryEntry is a base price of a catalog item, in effect from EffectiveFrom until the EffectiveFrom of
+// the next entry of the item. An entry effective in the future is a scheduled price change, which is made to
+// the item once it is due.
+type PriceHistoryEntry struct {
+	ID            string    `json:"id" db:"id"`
+	CatalogItemID string    `json:"catalog_item_id" db:"catalog_item_id"`
+	Price         Money     `json:"price" db:"price"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+	// AppliedAt is when the price was set on the item, and nil while the change is scheduled.
+	AppliedAt *time.Time `json:"applied_at,omitempty" db:"applied_at"`
+}
+
+// NewPriceHistoryEntry returns a change of the base price of an item to price from effectiveFrom,
+// which is scheduled until it is applied.
+func NewPriceHistoryEntry(catalogItemID string, price Money, effectiveFrom time.Time) (*PriceHistoryEntry, error) {
+	if catalogItemID == "" {
+		return nil, NewError(ErrInvalidArgument, "catalog item id is required")
+	}
+	if err := price.Validate(); err != nil {
+		return nil, err
+	}
+	if !price.IsPositive() {
+		return nil, NewError(ErrInvalidArgument, "price must be greater than 0")
+	}
+	return &PriceHistoryEntry{
+		ID:            uuid.New().String(),
+		CatalogItemID: catalogItemID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+	}, nil
+}
+
+// Scheduled reports whether the price has not been set on the item yet.
+func (e *PriceHistoryEntry) Scheduled() bool {
+	return e.AppliedAt == nil
+}
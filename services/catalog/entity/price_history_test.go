@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEntity_NewPriceHistoryEntry(t *testing.T) {
+	t.Parallel()
+
+	effectiveFrom := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	patterns := []struct {
+		name          string
+		catalogItemID string
+		price         Money
+		wantErr       error
+	}{
+		{
+			name:          "success",
+			catalogItemID: "item-1",
+			price:         Money{Amount: 1250, Currency: "USD"},
+		},
+		{
+			name:    "Fail: catalog item id is empty",
+			price:   Money{Amount: 1250, Currency: "USD"},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:          "Fail: unsupported currency",
+			catalogItemID: "item-1",
+			price:         Money{Amount: 1250, Currency: "XXX"},
+			wantErr:       ErrInvalidMoney,
+		},
+		{
+			name:          "Fail: price is zero",
+			catalogItemID: "item-1",
+			price:         Money{Amount: 0, Currency: "USD"},
+			wantErr:       ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewPriceHistoryEntry(tt.catalogItemID, tt.price, effectiveFrom)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewPriceHistoryEntry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ID == "" || got.CatalogItemID != tt.catalogItemID || got.Price != tt.price || !got.EffectiveFrom.Equal(effectiveFrom) {
+				t.Errorf("NewPriceHistoryEntry() = %v", got)
+			}
+			if !got.Scheduled() {
+				t.Errorf("Scheduled() = false, want true")
+			}
+		})
+	}
+}
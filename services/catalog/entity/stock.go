@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+var ErrInsufficientStock = NewError(ErrFailedPrecondition, "insufficient stock")
+
+// Available returns the quantity that can still be reserved.
+func (item *CatalogItem) Available() int {
+	return item.Stock - item.Reserved
+}
+
+// SetStock changes the quantity on hand. It cannot go below the reserved quantity.
+func (item *CatalogItem) SetStock(stock int) error {
+	if stock < 0 {
+		return NewError(ErrInvalidArgument, "stock must not be negative")
+	}
+	if stock < item.Reserved {
+		return NewError(ErrFailedPrecondition, fmt.Sprintf("stock must not be lower than the reserved quantity %d", item.Reserved))
+	}
+	item.Stock = stock
+	return nil
+}
+
+// Reserve holds quantity of the available stock.
+func (item *CatalogItem) Reserve(quantity int) error {
+	if quantity <= 0 {
+		return NewError(ErrInvalidArgument, "quantity must be greater than 0")
+	}
+	if item.Available() < quantity {
+		return fmt.Errorf("%w: %s has %d available, %d requested", ErrInsufficientStock, item.ID, item.Available(), quantity)
+	}
+	item.Reserved += quantity
+	return nil
+}
+
+// Release returns a reserved quantity to the available stock.
+func (item *CatalogItem) Release(quantity int) {
+	item.Reserved -= quantity
+}
+
+// Commit removes a reserved quantity from the stock on hand once the goods leave.
+func (item *CatalogItem) Commit(quantity int) {
+	item.Reserved -= quantity
+	item.Stock -= quantity
+}
+
+type ReservationStatus string
+
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusCommitted ReservationStatus = "committed"
+)
+
+var ErrReservationClosed = NewError(ErrFailedPrecondition, "stock reservation is already closed")
+
+// StockReservation is the quantity of one catalog item, or of one of its variants when SKU
+// is set, held by a reservation. A reservation, typically made for an order, holds one entry
+// per item and variant.
+type StockReservation struct {
+	ReservationID string            `json:"reservation_id"`
+	CatalogItemID string            `json:"catalog_item_id"`
+	SKU           string            `json:"sku,omitempty"`
+	Quantity      int               `json:"quantity"`
+	Status        ReservationStatus `json:"status"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+func NewStockReservation(reservationID, catalogItemID, sku string, quantity int, now time.Time) (*StockReservation, error) {
+	if reservationID == "" {
+		return nil, NewError(ErrInvalidArgument, "reservationID is required")
+	}
+	if catalogItemID == "" {
+		return nil, NewError(ErrInvalidArgument, "catalogItemID is required")
+	}
+	if quantity <= 0 {
+		return nil, NewError(ErrInvalidArgument, "quantity must be greater than 0")
+	}
+	return &StockReservation{
+		ReservationID: reservationID,
+		CatalogItemID: catalogItemID,
+		SKU:           sku,
+		Quantity:      quantity,
+		Status:        ReservationStatusReserved,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Close moves a reservation to released or committed. Closing a reservation again with the
+// same status is a no-op reported by changed being false, so retried calls are harmless.
+func (r *StockReservation) Close(status ReservationStatus, now time.Time) (changed bool, err error) {
+	if r.Status == status {
+		return false, nil
+	}
+	if r.Status != ReservationStatusReserved {
+		return false, fmt.Errorf("%w: %s is %s", ErrReservationClosed, r.ReservationID, r.Status)
+	}
+	r.Status = status
+	r.UpdatedAt = now
+	return true, nil
+}
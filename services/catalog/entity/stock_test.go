@@ -0,0 +1,196 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_CatalogItem_Reserve(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		quantity int
+		want     struct {
+			reserved int
+			err      error
+		}
+	}{
+		{
+			name:     "success",
+			quantity: 6,
+			want: struct {
+				reserved int
+				err      error
+			}{
+				reserved: 10,
+				err:      nil,
+			},
+		},
+		{
+			name:     "Fail: quantity is 0",
+			quantity: 0,
+			want: struct {
+				reserved int
+				err      error
+			}{
+				reserved: 4,
+				err:      ErrInvalidArgument,
+			},
+		},
+		{
+			name:     "Fail: quantity exceeds the available stock",
+			quantity: 7,
+			want: struct {
+				reserved int
+				err      error
+			}{
+				reserved: 4,
+				err:      ErrInsufficientStock,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &CatalogItem{ID: uuid.New().String(), Stock: 10, Reserved: 4}
+
+			err := item.Reserve(tt.quantity)
+
+			if !errors.Is(err, tt.want.err) {
+				t.Errorf("Reserve() error = %v, wantErr %v", err, tt.want.err)
+			}
+			if item.Reserved != tt.want.reserved || item.Stock != 10 {
+				t.Errorf("Reserve() got = %d/%d, want %d/%d", item.Stock, item.Reserved, 10, tt.want.reserved)
+			}
+		})
+	}
+}
+
+func TestEntity_CatalogItem_SetStock(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		stock int
+		want  error
+	}{
+		{
+			name:  "success",
+			stock: 4,
+			want:  nil,
+		},
+		{
+			name:  "Fail: stock is negative",
+			stock: -1,
+			want:  ErrInvalidArgument,
+		},
+		{
+			name:  "Fail: stock is lower than the reserved quantity",
+			stock: 3,
+			want:  ErrFailedPrecondition,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &CatalogItem{ID: uuid.New().String(), Stock: 10, Reserved: 4}
+
+			err := item.SetStock(tt.stock)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("SetStock() error = %v, wantErr %v", err, tt.want)
+			}
+			if err == nil && item.Stock != tt.stock {
+				t.Errorf("SetStock() got = %d, want %d", item.Stock, tt.stock)
+			}
+		})
+	}
+}
+
+func TestEntity_StockReservation_Close(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	patterns := []struct {
+		name   string
+		status ReservationStatus
+		close  ReservationStatus
+		want   struct {
+			changed bool
+			err     error
+		}
+	}{
+		{
+			name:   "success: release a reservation",
+			status: ReservationStatusReserved,
+			close:  ReservationStatusReleased,
+			want: struct {
+				changed bool
+				err     error
+			}{
+				changed: true,
+				err:     nil,
+			},
+		},
+		{
+			name:   "success: commit a committed reservation again",
+			status: ReservationStatusCommitted,
+			close:  ReservationStatusCommitted,
+			want: struct {
+				changed bool
+				err     error
+			}{
+				changed: false,
+				err:     nil,
+			},
+		},
+		{
+			name:   "Fail: release a committed reservation",
+			status: ReservationStatusCommitted,
+			close:  ReservationStatusReleased,
+			want: struct {
+				changed bool
+				err     error
+			}{
+				changed: false,
+				err:     ErrReservationClosed,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := NewStockReservation(uuid.New().String(), uuid.New().String(), "", 1, now.Add(-time.Hour))
+			if err != nil {
+				t.Fatalf("NewStockReservation() error = %v", err)
+			}
+			r.Status = tt.status
+
+			changed, err := r.Close(tt.close, now)
+
+			if !errors.Is(err, tt.want.err) {
+				t.Errorf("Close() error = %v, wantErr %v", err, tt.want.err)
+			}
+			if changed != tt.want.changed {
+				t.Errorf("Close() changed = %v, want %v", changed, tt.want.changed)
+			}
+			if changed && (r.Status != tt.close || !r.UpdatedAt.Equal(now)) {
+				t.Errorf("Close() got = %v, want status %v", r, tt.close)
+			}
+		})
+	}
+}
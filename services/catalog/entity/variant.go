@@ -0,0 +1,201 @@
+package entity
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const maxSKULength = 64
+
+var skuPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var ErrDuplicateVariant = NewError(ErrAlreadyExists, "item has a variant with the same attributes")
+
+// Variant is a SKU of a catalog item, such as a shirt in one size and colour, identified by
+// its attributes. It has a stock of its own, and is sold at the price of the item unless Price
+// overrides it.
+type Variant struct {
+	SKU           string            `json:"sku"`
+	CatalogItemID string            `json:"catalog_item_id"`
+	Attributes    map[string]string `json:"attributes"`
+	Price         *Money            `json:"price,omitempty"`
+	// Conversion is set by Localize when Price was converted from the price override.
+	Conversion *PriceConversion `json:"-"`
+	Stock      int              `json:"stock"`
+	Reserved   int              `json:"reserved"`
+}
+
+func NewVariant(sku, catalogItemID string, attributes map[string]string, price *Money) (*Variant, error) {
+	if len(sku) > maxSKULength || !skuPattern.MatchString(sku) {
+		return nil, NewError(ErrInvalidArgument, fmt.Sprintf("invalid sku: %q", sku))
+	}
+	if catalogItemID == "" {
+		return nil, NewError(ErrInvalidArgument, "catalogItemID is required")
+	}
+	variant := &Variant{
+		SKU:           sku,
+		CatalogItemID: catalogItemID,
+	}
+	if err := variant.SetAttributes(attributes); err != nil {
+		return nil, err
+	}
+	if err := variant.SetPrice(price); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// SetAttributes replaces the attributes, of which there must be at least one, with neither
+// an empty name nor an empty value.
+func (v *Variant) SetAttributes(attributes map[string]string) error {
+	if len(attributes) == 0 {
+		return NewError(ErrInvalidArgument, "at least one attribute is required")
+	}
+	trimmed := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" || value == "" {
+			return NewError(ErrInvalidArgument, "attribute names and values are required")
+		}
+		trimmed[name] = value
+	}
+	v.Attributes = trimmed
+	return nil
+}
+
+// SetPrice changes the price override, which must be positive and in a supported currency.
+// A nil price removes the override.
+func (v *Variant) SetPrice(price *Money) error {
+	if price == nil {
+		v.Price = nil
+		return nil
+	}
+	if err := price.Validate(); err != nil {
+		return err
+	}
+	if !price.IsPositive() {
+		return NewError(ErrInvalidArgument, "price must be greater than 0")
+	}
+	p := *price
+	v.Price = &p
+	return nil
+}
+
+// Label describes the attributes in the order of their names, such as "color: red, size: M".
+func (v *Variant) Label() string {
+	names := make([]string, 0, len(v.Attributes))
+	for name := range v.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+v.Attributes[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Localize converts the price override to currency at rate, which must then convert from the
+// currency of the override to currency. A variant without an override is sold at the price of
+// its item, which the item localizes.
+func (v *Variant) Localize(currency string, rate *ExchangeRate) error {
+	if v.Price == nil || v.Price.Currency == currency {
+		return nil
+	}
+	if rate == nil || rate.From != v.Price.Currency || rate.To != currency {
+		return fmt.Errorf("%w from %s to %s", ErrNoExchangeRate, v.Price.Currency, currency)
+	}
+	price, err := rate.Convert(*v.Price)
+	if err != nil {
+		return err
+	}
+	v.Conversion = &PriceConversion{
+		BasePrice: *v.Price,
+		Rate:      *rate,
+	}
+	v.Price = &price
+	return nil
+}
+
+// Available returns the quantity that can still be reserved.
+func (v *Variant) Available() int {
+	return v.Stock - v.Reserved
+}
+
+// SetStock changes the quantity on hand. It cannot go below the reserved quantity.
+func (v *Variant) SetStock(stock int) error {
+	if stock < 0 {
+		return NewError(ErrInvalidArgument, "stock must not be negative")
+	}
+	if stock < v.Reserved {
+		return NewError(ErrFailedPrecondition, fmt.Sprintf("stock must not be lower than the reserved quantity %d", v.Reserved))
+	}
+	v.Stock = stock
+	return nil
+}
+
+// Reserve holds quantity of the available stock.
+func (v *Variant) Reserve(quantity int) error {
+	if quantity <= 0 {
+		return NewError(ErrInvalidArgument, "quantity must be greater than 0")
+	}
+	if v.Available() < quantity {
+		return fmt.Errorf("%w: %s has %d available, %d requested", ErrInsufficientStock, v.SKU, v.Available(), quantity)
+	}
+	v.Reserved += quantity
+	return nil
+}
+
+// Release returns a reserved quantity to the available stock.
+func (v *Variant) Release(quantity int) {
+	v.Reserved -= quantity
+}
+
+// Commit removes a reserved quantity from the stock on hand once the goods leave.
+func (v *Variant) Commit(quantity int) {
+	v.Reserved -= quantity
+	v.Stock -= quantity
+}
+
+// Variant returns the variant of the item with sku.
+func (item *CatalogItem) Variant(sku string) (*Variant, bool) {
+	for i := range item.Variants {
+		if item.Variants[i].SKU == sku {
+			return &item.Variants[i], true
+		}
+	}
+	return nil, false
+}
+
+// SetVariant adds v to the variants of the item, or replaces the variant with the same SKU.
+// No two variants of an item have the same attributes.
+func (item *CatalogItem) SetVariant(v Variant) error {
+	if v.CatalogItemID != item.ID {
+		return NewError(ErrInvalidArgument, fmt.Sprintf("variant %s is not of item %s", v.SKU, item.ID))
+	}
+	for _, other := range item.Variants {
+		if other.SKU != v.SKU && reflect.DeepEqual(other.Attributes, v.Attributes) {
+			return fmt.Errorf("%w: %s", ErrDuplicateVariant, other.SKU)
+		}
+	}
+	if existing, ok := item.Variant(v.SKU); ok {
+		*existing = v
+		return nil
+	}
+	item.Variants = append(item.Variants, v)
+	return nil
+}
+
+// RemoveVariant removes the variant with sku from the variants of the item.
+func (item *CatalogItem) RemoveVariant(sku string) {
+	for i := range item.Variants {
+		if item.Variants[i].SKU == sku {
+			item.Variants = append(item.Variants[:i], item.Variants[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package entity
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_NewVariant(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name       string
+		sku        string
+		attributes map[string]string
+		price      *Money
+		wantErr    error
+	}{
+		{
+			name:       "success",
+			sku:        "SHIRT-M-RED",
+			attributes: map[string]string{"size": "M", "color": "red"},
+			wantErr:    nil,
+		},
+		{
+			name:       "success: with a price override",
+			sku:        "SHIRT-XL-RED",
+			attributes: map[string]string{"size": "XL"},
+			price:      &Money{Amount: 1200, Currency: "USD"},
+			wantErr:    nil,
+		},
+		{
+			name:       "Fail: sku has spaces",
+			sku:        "SHIRT M",
+			attributes: map[string]string{"size": "M"},
+			wantErr:    ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: no attributes",
+			sku:     "SHIRT-M",
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:       "Fail: attribute value is empty",
+			sku:        "SHIRT-M",
+			attributes: map[string]string{"size": " "},
+			wantErr:    ErrInvalidArgument,
+		},
+		{
+			name:       "Fail: price override is not positive",
+			sku:        "SHIRT-M",
+			attributes: map[string]string{"size": "M"},
+			price:      &Money{Amount: 0, Currency: "USD"},
+			wantErr:    ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			variant, err := NewVariant(tt.sku, itemID, tt.attributes, tt.price)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewVariant() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && variant.SKU != tt.sku {
+				t.Errorf("NewVariant() SKU = %v, want %v", variant.SKU, tt.sku)
+			}
+		})
+	}
+}
+
+func TestEntity_Variant_Label(t *testing.T) {
+	t.Parallel()
+
+	v := &Variant{Attributes: map[string]string{"size": "M", "color": "red"}}
+	if got, want := v.Label(), "color: red, size: M"; got != want {
+		t.Errorf("Label() = %v, want %v", got, want)
+	}
+}
+
+func TestEntity_Variant_Localize(t *testing.T) {
+	t.Parallel()
+
+	rate := &ExchangeRate{From: "USD", To: "EUR", Rate: 90000000, UpdatedAt: time.Now()}
+
+	patterns := []struct {
+		name      string
+		price     *Money
+		rate      *ExchangeRate
+		wantPrice *Money
+		wantErr   error
+	}{
+		{
+			name:      "success: override is converted",
+			price:     &Money{Amount: 1000, Currency: "USD"},
+			rate:      rate,
+			wantPrice: &Money{Amount: 900, Currency: "EUR"},
+		},
+		{
+			name:      "success: override is in the currency",
+			price:     &Money{Amount: 800, Currency: "EUR"},
+			wantPrice: &Money{Amount: 800, Currency: "EUR"},
+		},
+		{
+			name: "success: no override",
+		},
+		{
+			name:    "Fail: no exchange rate",
+			price:   &Money{Amount: 1000, Currency: "USD"},
+			wantErr: ErrNoExchangeRate,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := &Variant{SKU: "SHIRT-M", Price: tt.price}
+			err := v.Localize("EUR", tt.rate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Localize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(v.Price, tt.wantPrice) {
+				t.Errorf("Localize() Price = %v, want %v", v.Price, tt.wantPrice)
+			}
+		})
+	}
+}
+
+func TestEntity_Variant_Reserve(t *testing.T) {
+	t.Parallel()
+
+	v := &Variant{SKU: "SHIRT-M", Stock: 5, Reserved: 3}
+	if err := v.Reserve(3); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Reserve() error = %v, want %v", err, ErrInsufficientStock)
+	}
+	if err := v.Reserve(2); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	v.Commit(4)
+	if v.Stock != 1 || v.Reserved != 1 {
+		t.Errorf("Commit() got = %d/%d, want %d/%d", v.Stock, v.Reserved, 1, 1)
+	}
+}
+
+func TestEntity_CatalogItem_SetVariant(t *testing.T) {
+	t.Parallel()
+
+	item := &CatalogItem{ID: uuid.New().String(), Name: "shirt"}
+	red := Variant{SKU: "SHIRT-RED", CatalogItemID: item.ID, Attributes: map[string]string{"color": "red"}}
+	if err := item.SetVariant(red); err != nil {
+		t.Fatalf("SetVariant() error = %v", err)
+	}
+
+	duplicate := Variant{SKU: "SHIRT-RED-2", CatalogItemID: item.ID, Attributes: map[string]string{"color": "red"}}
+	if err := item.SetVariant(duplicate); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("SetVariant() error = %v, want %v", err, ErrAlreadyExists)
+	}
+
+	red.Stock = 3
+	if err := item.SetVariant(red); err != nil {
+		t.Fatalf("SetVariant() error = %v", err)
+	}
+	if len(item.Variants) != 1 || item.Variants[0].Stock != 3 {
+		t.Errorf("SetVariant() Variants = %v, want the red variant replaced", item.Variants)
+	}
+
+	item.RemoveVariant(red.SKU)
+	if len(item.Variants) != 0 {
+		t.Errorf("RemoveVariant() Variants = %v, want none", item.Variants)
+	}
+}
@@ -0,0 +1,99 @@
+package gateway
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// exportChunkSize is the number of bytes of the file an export sends in a message at most.
+const exportChunkSize = 64 * 1024
+
+func (ch *catalogItemHandler) ImportCatalogItems(stream pb.CatalogService_ImportCatalogItemsServer) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		log.Warn("Import has no chunk")
+		return status.Errorf(codes.InvalidArgument, "Format is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	r := &importReader{stream: stream, data: first.GetData()}
+	report, err := ch.cuc.ImportCatalogItems(stream.Context(), first.GetFormat(), r, first.GetDryRun())
+	if err != nil {
+		return toStatusError(err, "Failed to import catalog items")
+	}
+
+	return stream.SendAndClose(convertImportReport(report))
+}
+
+// importReader reads the file of an import from the chunks of its stream.
+type importReader struct {
+	stream pb.CatalogService_ImportCatalogItemsServer
+	data   []byte
+}
+
+func (r *importReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.data = req.GetData()
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func (ch *catalogItemHandler) ExportCatalogItems(req *pb.ExportCatalogItemsRequest, stream pb.CatalogService_ExportCatalogItemsServer) error {
+	w := bufio.NewWriterSize(&exportWriter{stream: stream}, exportChunkSize)
+	if err := ch.cuc.ExportCatalogItems(stream.Context(), req.GetFormat(), w); err != nil {
+		return toStatusError(err, "Failed to export catalog items")
+	}
+	if err := w.Flush(); err != nil {
+		log.Error("Failed to send exported catalog items", log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+// exportWriter sends every write of the file of an export as a chunk of its stream.
+type exportWriter struct {
+	stream pb.CatalogService_ExportCatalogItemsServer
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&pb.ExportCatalogItemsResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func convertImportReport(report *usecase.ImportReport) *pb.ImportCatalogItemsResponse {
+	errs := make([]*pb.ImportError, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		errs = append(errs, &pb.ImportError{
+			Row:         int32(e.Row),
+			ExternalKey: e.ExternalKey,
+			Message:     e.Message,
+		})
+	}
+	return &pb.ImportCatalogItemsResponse{
+		Created:   int32(report.Created),
+		Updated:   int32(report.Updated),
+		Unchanged: int32(report.Unchanged),
+		Failed:    int32(report.Failed),
+		Errors:    errs,
+		DryRun:    report.DryRun,
+	}
+}
@@ -0,0 +1,178 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_ImportCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	file := "external_key,name,price,currency,stock\nSUP-0001,item1,10.00,USD,10\n,item2,1.00,USD,1\n"
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		requests   []*pb.ImportCatalogItemsRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ImportCatalogItems(gomock.Any(), usecase.FormatCSV, gomock.Any(), true).DoAndReturn(
+					func(_ context.Context, _ string, r io.Reader, dryRun bool) (*usecase.ImportReport, error) {
+						b, err := io.ReadAll(r)
+						if err != nil || string(b) != file {
+							t.Errorf("unexpected file: %q, %v", b, err)
+						}
+						return &usecase.ImportReport{
+							Created: 1,
+							Failed:  1,
+							Errors:  []usecase.ImportError{{Row: 3, Message: "external key is required"}},
+							DryRun:  dryRun,
+						}, nil
+					})
+			},
+			requests: []*pb.ImportCatalogItemsRequest{
+				{Format: "csv", DryRun: true, Data: []byte(file[:10])},
+				{Data: []byte(file[10:40])},
+				{},
+				{Data: []byte(file[40:])},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: unsupported format",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ImportCatalogItems(gomock.Any(), "xml", gomock.Any(), false).Return(nil, entity.NewError(entity.ErrInvalidArgument, `unsupported format "xml"`))
+			},
+			requests:   []*pb.ImportCatalogItemsRequest{{Format: "xml", Data: []byte("<items/>")}},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid request of no chunk",
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			stream, err := client.ImportCatalogItems(context.Background())
+			if err != nil {
+				t.Fatalf("failed to open stream: %v", err)
+			}
+			for _, req := range tt.requests {
+				if err = stream.Send(req); err != nil {
+					t.Fatalf("failed to send chunk: %v", err)
+				}
+			}
+			resp, err := stream.CloseAndRecv()
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus == codes.OK {
+				if resp.GetCreated() != 1 || resp.GetFailed() != 1 || len(resp.GetErrors()) != 1 || resp.GetErrors()[0].GetRow() != 3 || !resp.GetDryRun() {
+					t.Fatalf("handler returned wrong report: %v", resp)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ExportCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	// The file is larger than a chunk, so that it is sent in several.
+	file := strings.Repeat(`{"external_key":"SUP-0001","id":"id1","name":"item1","price":"10.00","currency":"USD","stock":10}`+"\n", 1000)
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.ExportCatalogItemsRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ExportCatalogItems(gomock.Any(), usecase.FormatJSONL, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, w io.Writer) error {
+						_, err := io.WriteString(w, file)
+						return err
+					})
+			},
+			request:    &pb.ExportCatalogItemsRequest{Format: "jsonl"},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: internal error",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ExportCatalogItems(gomock.Any(), usecase.FormatCSV, gomock.Any()).Return(errors.New("connection refused"))
+			},
+			request:    &pb.ExportCatalogItemsRequest{Format: "csv"},
+			wantStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			stream, err := client.ExportCatalogItems(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("failed to open stream: %v", err)
+			}
+			var got strings.Builder
+			chunks := 0
+			for {
+				resp, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.wantStatus {
+						t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+					}
+					return
+				}
+				if len(resp.GetData()) > exportChunkSize {
+					t.Errorf("chunk is %d bytes, want at most %d", len(resp.GetData()), exportChunkSize)
+				}
+				got.Write(resp.GetData())
+				chunks++
+			}
+			if tt.wantStatus != codes.OK {
+				t.Fatalf("handler returned wrong status code: got %v want %v", codes.OK, tt.wantStatus)
+			}
+			if got.String() != file || chunks < 2 {
+				t.Fatalf("handler returned %d bytes in %d chunks, want %d bytes in several", got.Len(), chunks, len(file))
+			}
+		})
+	}
+}
@@ -2,11 +2,16 @@ package gateway
 
 import (
 	"context"
+	"slices"
+	"strconv"
 
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
@@ -20,6 +25,32 @@ type CatalogItemHandler interface {
 	CreateCatalogItem(ctx context.Context, req *pb.CreateCatalogItemRequest) (*pb.CreateCatalogItemResponse, error)
 	UpdateCatalogItem(ctx context.Context, req *pb.UpdateCatalogItemRequest) (*pb.UpdateCatalogItemResponse, error)
 	DeleteCatalogItem(ctx context.Context, req *pb.DeleteCatalogItemRequest) (*pb.DeleteCatalogItemResponse, error)
+	RestoreCatalogItem(ctx context.Context, req *pb.RestoreCatalogItemRequest) (*pb.RestoreCatalogItemResponse, error)
+	ListDeletedCatalogItems(ctx context.Context, req *pb.ListDeletedCatalogItemsRequest) (*pb.ListDeletedCatalogItemsResponse, error)
+	ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error)
+	ReleaseStock(ctx context.Context, req *pb.ReleaseStockRequest) (*pb.ReleaseStockResponse, error)
+	CommitStock(ctx context.Context, req *pb.CommitStockRequest) (*pb.CommitStockResponse, error)
+	WatchCatalogItems(req *pb.WatchCatalogItemsRequest, stream pb.CatalogService_WatchCatalogItemsServer) error
+	ImportCatalogItems(stream pb.CatalogService_ImportCatalogItemsServer) error
+	ExportCatalogItems(req *pb.ExportCatalogItemsRequest, stream pb.CatalogService_ExportCatalogItemsServer) error
+	SetCatalogItemPrices(ctx context.Context, req *pb.SetCatalogItemPricesRequest) (*pb.SetCatalogItemPricesResponse, error)
+	GetPriceHistory(ctx context.Context, req *pb.GetPriceHistoryRequest) (*pb.GetPriceHistoryResponse, error)
+	SchedulePriceChange(ctx context.Context, req *pb.SchedulePriceChangeRequest) (*pb.SchedulePriceChangeResponse, error)
+	SetExchangeRate(ctx context.Context, req *pb.SetExchangeRateRequest) (*pb.SetExchangeRateResponse, error)
+	ListExchangeRates(ctx context.Context, req *pb.ListExchangeRatesRequest) (*pb.ListExchangeRatesResponse, error)
+	DeleteExchangeRate(ctx context.Context, req *pb.DeleteExchangeRateRequest) (*pb.DeleteExchangeRateResponse, error)
+	GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error)
+	ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error)
+	CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error)
+	UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error)
+	DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error)
+	SetCatalogItemCategories(ctx context.Context, req *pb.SetCatalogItemCategoriesRequest) (*pb.SetCatalogItemCategoriesResponse, error)
+	ListCatalogItemsByCategory(ctx context.Context, req *pb.ListCatalogItemsByCategoryRequest) (*pb.ListCatalogItemsByCategoryResponse, error)
+	GetVariant(ctx context.Context, req *pb.GetVariantRequest) (*pb.GetVariantResponse, error)
+	ListVariants(ctx context.Context, req *pb.ListVariantsRequest) (*pb.ListVariantsResponse, error)
+	CreateVariant(ctx context.Context, req *pb.CreateVariantRequest) (*pb.CreateVariantResponse, error)
+	UpdateVariant(ctx context.Context, req *pb.UpdateVariantRequest) (*pb.UpdateVariantResponse, error)
+	DeleteVariant(ctx context.Context, req *pb.DeleteVariantRequest) (*pb.DeleteVariantResponse, error)
 }
 
 type catalogItemHandler struct {
@@ -40,18 +71,23 @@ func (ch *catalogItemHandler) GetCatalogItem(ctx context.Context, req *pb.GetCat
 		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
 	}
 
-	item, err := ch.cuc.GetCatalogItem(ctx, id)
+	var item *entity.CatalogItem
+	var err error
+	if req.GetAsOf() != nil {
+		if req.GetCurrency() != "" {
+			log.Warn("Currency cannot be set with as of time", log.Fstring("currency", req.GetCurrency()))
+			return nil, status.Errorf(codes.InvalidArgument, "Currency cannot be set with as of time")
+		}
+		item, err = ch.cuc.GetCatalogItemAsOf(ctx, id, req.GetAsOf().AsTime())
+	} else {
+		item, err = ch.cuc.GetCatalogItem(ctx, id, req.GetCurrency())
+	}
 	if err != nil {
-		log.Error("Failed to get catalog item", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to get catalog item")
+		return nil, toStatusError(err, "Failed to get catalog item")
 	}
 
 	return &pb.GetCatalogItemResponse{
-		Item: &pb.CatalogItem{
-			Id:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
-		},
+		Item: convertCatalogItem(item),
 	}, nil
 }
 
@@ -62,19 +98,14 @@ func (ch *catalogItemHandler) ListCatalogItemsByName(ctx context.Context, req *p
 		return nil, status.Errorf(codes.InvalidArgument, "Name is required")
 	}
 
-	items, err := ch.cuc.ListCatalogItemsByName(ctx, name)
+	items, err := ch.cuc.ListCatalogItemsByName(ctx, name, req.GetCurrency())
 	if err != nil {
-		log.Error("Failed to list catalog items by name", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to list catalog items by name")
+		return nil, toStatusError(err, "Failed to list catalog items by name")
 	}
 
 	var res []*pb.CatalogItem
-	for _, item := range items {
-		res = append(res, &pb.CatalogItem{
-			Id:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
-		})
+	for i := range items {
+		res = append(res, convertCatalogItem(&items[i]))
 	}
 
 	return &pb.ListCatalogItemsByNameResponse{
@@ -89,19 +120,14 @@ func (ch *catalogItemHandler) ListCatalogItemsByIDs(ctx context.Context, req *pb
 		return nil, status.Errorf(codes.InvalidArgument, "IDs are required")
 	}
 
-	items, err := ch.cuc.ListCatalogItemsByIDs(ctx, ids)
+	items, err := ch.cuc.ListCatalogItemsByIDs(ctx, ids, req.GetCurrency())
 	if err != nil {
-		log.Error("Failed to list catalog items by IDs", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to list catalog items by IDs")
+		return nil, toStatusError(err, "Failed to list catalog items by IDs")
 	}
 
 	var res []*pb.CatalogItem
-	for _, item := range items {
-		res = append(res, &pb.CatalogItem{
-			Id:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
-		})
+	for i := range items {
+		res = append(res, convertCatalogItem(&items[i]))
 	}
 
 	return &pb.ListCatalogItemsByIDsResponse{
@@ -109,24 +135,29 @@ func (ch *catalogItemHandler) ListCatalogItemsByIDs(ctx context.Context, req *pb
 	}, nil
 }
 
-func (ch *catalogItemHandler) ListCatalogItems(ctx context.Context, _ *pb.ListCatalogItemsRequest) (*pb.ListCatalogItemsResponse, error) {
-	items, err := ch.cuc.ListCatalogItems(ctx)
+func (ch *catalogItemHandler) ListCatalogItems(ctx context.Context, req *pb.ListCatalogItemsRequest) (*pb.ListCatalogItemsResponse, error) {
+	if req.GetPageSize() < 0 {
+		log.Warn("Invalid page size", log.Fint("page_size", int(req.GetPageSize())))
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	}
+
+	items, info, err := ch.cuc.ListCatalogItems(ctx, repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	}, req.GetCurrency())
 	if err != nil {
-		log.Error("Failed to list catalog items by name", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to list catalog items by name")
+		return nil, toStatusError(err, "Failed to list catalog items")
 	}
 
 	var res []*pb.CatalogItem
-	for _, item := range items {
-		res = append(res, &pb.CatalogItem{
-			Id:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
-		})
+	for i := range items {
+		res = append(res, convertCatalogItem(&items[i]))
 	}
 
 	return &pb.ListCatalogItemsResponse{
-		Items: res,
+		Items:         res,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
 	}, nil
 }
 
@@ -134,59 +165,100 @@ func (ch *catalogItemHandler) CreateCatalogItem(ctx context.Context, req *pb.Cre
 	if !ch.isValidCreateCatalogItemRequest(req) {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
 	}
+	price, err := toMoney(req.GetPrice(), req.GetLegacyPrice()) //nolint:staticcheck // legacy_price is read until clients set price
+	if err != nil {
+		return nil, toStatusError(err, "Invalid price")
+	}
+	if !price.IsPositive() {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid price")
+	}
 
-	if err := ch.cuc.CreateCatalogItem(
+	item, err := ch.cuc.CreateCatalogItem(
 		ctx,
 		req.GetName(),
-		req.GetPrice(),
-	); err != nil {
-		log.Error("Failed to create catalog item", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to create catalog item")
+		price,
+		int(req.GetStock()),
+	)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to create catalog item")
 	}
 
-	return &pb.CreateCatalogItemResponse{}, nil
+	return &pb.CreateCatalogItemResponse{
+		Item: convertCatalogItem(item),
+	}, nil
 }
 
 func (ch *catalogItemHandler) isValidCreateCatalogItemRequest(req *pb.CreateCatalogItemRequest) bool {
 	if req.GetName() == "" ||
-		req.GetPrice() <= 0 {
+		req.GetStock() < 0 {
 		log.Warn(
 			"Invalid request",
 			log.Fstring("name", req.GetName()),
-			log.Ffloat64("price", req.GetPrice()),
+			log.Fint("stock", int(req.GetStock())),
 		)
 		return false
 	}
 	return true
 }
 
+// catalogItemPaths maps the paths of an update mask to the fields of an item they update.
+var catalogItemPaths = map[string]string{
+	"name":         entity.CatalogItemFieldName,
+	"price":        entity.CatalogItemFieldPrice,
+	"legacy_price": entity.CatalogItemFieldPrice,
+	"stock":        entity.CatalogItemFieldStock,
+}
+
 func (ch *catalogItemHandler) UpdateCatalogItem(ctx context.Context, req *pb.UpdateCatalogItemRequest) (*pb.UpdateCatalogItemResponse, error) {
-	if !ch.isValidUpdateCatalogItemRequest(req) {
+	fields, err := toUpdateFields(req.GetUpdateMask(), catalogItemPaths)
+	if err != nil {
+		return nil, toStatusError(err, "Invalid update mask")
+	}
+	masked := func(field string) bool {
+		return len(fields) == 0 || slices.Contains(fields, field)
+	}
+	if !ch.isValidUpdateCatalogItemRequest(req, masked) {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
 	}
 
-	if err := ch.cuc.UpdateCatalogItem(
-		ctx,
-		req.GetId(),
-		req.GetName(),
-		req.GetPrice(),
-	); err != nil {
-		log.Error("Failed to update catalog item", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to update catalog item")
+	params := usecase.UpdateCatalogItemParams{
+		ID:      req.GetId(),
+		Name:    req.GetName(),
+		Stock:   int(req.GetStock()),
+		Version: req.GetVersion(),
+		Fields:  fields,
+	}
+	if masked(entity.CatalogItemFieldPrice) {
+		params.Price, err = toMoney(req.GetPrice(), req.GetLegacyPrice()) //nolint:staticcheck // legacy_price is read until clients set price
+		if err != nil {
+			return nil, toStatusError(err, "Invalid price")
+		}
+		if !params.Price.IsPositive() {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid price")
+		}
+	}
+
+	item, err := ch.cuc.UpdateCatalogItem(ctx, params)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update catalog item")
 	}
 
-	return &pb.UpdateCatalogItemResponse{}, nil
+	return &pb.UpdateCatalogItemResponse{
+		Item: convertCatalogItem(item),
+	}, nil
 }
 
-func (ch *catalogItemHandler) isValidUpdateCatalogItemRequest(req *pb.UpdateCatalogItemRequest) bool {
+func (ch *catalogItemHandler) isValidUpdateCatalogItemRequest(req *pb.UpdateCatalogItemRequest, masked func(field string) bool) bool {
 	if req.GetId() == "" ||
-		req.GetName() == "" ||
-		req.GetPrice() <= 0 {
+		(masked(entity.CatalogItemFieldName) && req.GetName() == "") ||
+		req.GetStock() < 0 ||
+		req.GetVersion() < 0 {
 		log.Warn(
 			"Invalid request",
 			log.Fstring("id", req.GetId()),
 			log.Fstring("name", req.GetName()),
-			log.Ffloat64("price", req.GetPrice()),
+			log.Fint("stock", int(req.GetStock())),
+			log.Fint("version", int(req.GetVersion())),
 		)
 		return false
 	}
@@ -201,9 +273,58 @@ func (ch *catalogItemHandler) DeleteCatalogItem(ctx context.Context, req *pb.Del
 	}
 
 	if err := ch.cuc.DeleteCatalogItem(ctx, id); err != nil {
-		log.Error("Failed to delete catalog item", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to delete catalog item")
+		return nil, toStatusError(err, "Failed to delete catalog item")
 	}
 
 	return &pb.DeleteCatalogItemResponse{}, nil
 }
+
+func (ch *catalogItemHandler) WatchCatalogItems(req *pb.WatchCatalogItemsRequest, stream pb.CatalogService_WatchCatalogItemsServer) error {
+	after := int64(-1)
+	if token := req.GetResumeToken(); token != "" {
+		sequence, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || sequence < 0 {
+			log.Warn("Invalid resume token", log.Fstring("resume_token", token))
+			return status.Errorf(codes.InvalidArgument, "Invalid resume token")
+		}
+		after = sequence
+	}
+
+	err := ch.cuc.WatchCatalogItems(stream.Context(), after, func(change usecase.CatalogItemChange) error {
+		res := &pb.WatchCatalogItemsResponse{
+			Type:        string(change.Type),
+			Id:          change.ID,
+			ResumeToken: strconv.FormatInt(change.Sequence, 10),
+		}
+		if item := change.CatalogItem; item != nil {
+			res.CatalogItem = convertCatalogItem(item)
+		}
+		return stream.Send(res)
+	})
+	if err != nil {
+		return toStatusError(err, "Failed to watch catalog items")
+	}
+	return nil
+}
+
+func convertCatalogItem(item *entity.CatalogItem) *pb.CatalogItem {
+	var deletedAt *timestamppb.Timestamp
+	if item.DeletedAt != nil {
+		deletedAt = timestamppb.New(*item.DeletedAt)
+	}
+	return &pb.CatalogItem{
+		Id:          item.ID,
+		Name:        item.Name,
+		Price:       convertMoney(item.Price),
+		LegacyPrice: item.Price.Float64(),
+		Stock:       int32(item.Stock),
+		Available:   int32(item.Available()),
+		Prices:      convertMonies(item.Prices),
+		Conversion:  convertPriceConversion(item.Conversion),
+		CategoryIds: item.CategoryIDs,
+		Variants:    convertVariants(item.Variants),
+		Version:     item.Version,
+		DeletedAt:   deletedAt,
+		ExternalKey: item.ExternalKey,
+	}
+}
@@ -3,8 +3,10 @@ package gateway
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -13,10 +15,15 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
 )
 
@@ -69,8 +76,9 @@ func TestHandler_GetCatalogItem(t *testing.T) {
 	item := entity.CatalogItem{
 		ID:    itemID,
 		Name:  "item1",
-		Price: float64(100),
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
 	}
+	asOf := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	patterns := []struct {
 		name  string
@@ -86,6 +94,7 @@ func TestHandler_GetCatalogItem(t *testing.T) {
 				cuc.EXPECT().GetCatalogItem(
 					gomock.Any(),
 					itemID,
+					"",
 				).Return(&item, nil)
 			},
 			request: &pb.GetCatalogItemRequest{
@@ -98,6 +107,73 @@ func TestHandler_GetCatalogItem(t *testing.T) {
 			request:    &pb.GetCatalogItemRequest{Id: ""},
 			wantStatus: codes.InvalidArgument,
 		},
+		{
+			name: "Fail: catalog item not found",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetCatalogItem(
+					gomock.Any(),
+					itemID,
+					"",
+				).Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found"))
+			},
+			request: &pb.GetCatalogItemRequest{
+				Id: itemID,
+			},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name: "Fail: no exchange rate to the currency",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetCatalogItem(
+					gomock.Any(),
+					itemID,
+					"EUR",
+				).Return(nil, entity.ErrNoExchangeRate)
+			},
+			request: &pb.GetCatalogItemRequest{
+				Id:       itemID,
+				Currency: "EUR",
+			},
+			wantStatus: codes.FailedPrecondition,
+		},
+		{
+			name: "success: price as of a past time",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetCatalogItemAsOf(
+					gomock.Any(),
+					itemID,
+					asOf,
+				).Return(&item, nil)
+			},
+			request: &pb.GetCatalogItemRequest{
+				Id:   itemID,
+				AsOf: timestamppb.New(asOf),
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: invalid request of as of time with currency",
+			request: &pb.GetCatalogItemRequest{
+				Id:       itemID,
+				Currency: "EUR",
+				AsOf:     timestamppb.New(asOf),
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: internal error",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetCatalogItem(
+					gomock.Any(),
+					itemID,
+					"",
+				).Return(nil, errors.New("connection refused"))
+			},
+			request: &pb.GetCatalogItemRequest{
+				Id: itemID,
+			},
+			wantStatus: codes.Internal,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -129,12 +205,12 @@ func TestHandler_ListCatalogItemsByName(t *testing.T) {
 		{
 			ID:    uuid.New().String(),
 			Name:  "item1",
-			Price: float64(100),
+			Price: entity.Money{Amount: 10000, Currency: "USD"},
 		},
 		{
 			ID:    uuid.New().String(),
 			Name:  "item2",
-			Price: float64(200),
+			Price: entity.Money{Amount: 20000, Currency: "USD"},
 		},
 	}
 
@@ -152,6 +228,7 @@ func TestHandler_ListCatalogItemsByName(t *testing.T) {
 				cuc.EXPECT().ListCatalogItemsByName(
 					gomock.Any(),
 					"item",
+					"",
 				).Return(items, nil)
 			},
 			request: &pb.ListCatalogItemsByNameRequest{
@@ -195,12 +272,12 @@ func TestHandler_ListCatalogItemsByIDs(t *testing.T) {
 		{
 			ID:    uuid.New().String(),
 			Name:  "item1",
-			Price: float64(100),
+			Price: entity.Money{Amount: 10000, Currency: "USD"},
 		},
 		{
 			ID:    uuid.New().String(),
 			Name:  "item2",
-			Price: float64(200),
+			Price: entity.Money{Amount: 20000, Currency: "USD"},
 		},
 	}
 
@@ -218,6 +295,7 @@ func TestHandler_ListCatalogItemsByIDs(t *testing.T) {
 				tuc.EXPECT().ListCatalogItemsByIDs(
 					gomock.Any(),
 					[]string{items[0].ID, items[1].ID},
+					"",
 				).Return(items, nil)
 			},
 			request: &pb.ListCatalogItemsByIDsRequest{
@@ -261,12 +339,12 @@ func TestHandler_ListCatalogItems(t *testing.T) {
 		{
 			ID:    uuid.New().String(),
 			Name:  "item1",
-			Price: float64(100),
+			Price: entity.Money{Amount: 10000, Currency: "USD"},
 		},
 		{
 			ID:    uuid.New().String(),
 			Name:  "item2",
-			Price: float64(200),
+			Price: entity.Money{Amount: 20000, Currency: "USD"},
 		},
 	}
 
@@ -283,11 +361,30 @@ func TestHandler_ListCatalogItems(t *testing.T) {
 			setup: func(tuc *mock.MockCatalogItemUseCase) {
 				tuc.EXPECT().ListCatalogItems(
 					gomock.Any(),
-				).Return(items, nil)
+					repository.Page{Size: 2, Token: "token"},
+					"",
+				).Return(items, repository.PageInfo{NextToken: "next", PrevToken: "prev"}, nil)
 			},
-			request:    &pb.ListCatalogItemsRequest{},
+			request:    &pb.ListCatalogItemsRequest{PageSize: 2, PageToken: "token"},
 			wantStatus: codes.OK,
 		},
+		{
+			name:       "Fail: invalid request of negative page size",
+			request:    &pb.ListCatalogItemsRequest{PageSize: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(tuc *mock.MockCatalogItemUseCase) {
+				tuc.EXPECT().ListCatalogItems(
+					gomock.Any(),
+					repository.Page{Token: "invalid"},
+					"",
+				).Return(nil, repository.PageInfo{}, repository.ErrInvalidPageToken)
+			},
+			request:    &pb.ListCatalogItemsRequest{PageToken: "invalid"},
+			wantStatus: codes.InvalidArgument,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -307,6 +404,9 @@ func TestHandler_ListCatalogItems(t *testing.T) {
 				if len(resp.GetItems()) != len(items) {
 					t.Fatalf("handler returned wrong item data")
 				}
+				if resp.GetNextPageToken() != "next" || resp.GetPrevPageToken() != "prev" {
+					t.Fatalf("handler returned wrong page tokens")
+				}
 			}
 		})
 	}
@@ -315,6 +415,12 @@ func TestHandler_ListCatalogItems(t *testing.T) {
 func TestHandler_CreateCatalogItem(t *testing.T) {
 	t.Parallel()
 
+	item := entity.CatalogItem{
+		ID:    uuid.New().String(),
+		Name:  "item1",
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -329,12 +435,31 @@ func TestHandler_CreateCatalogItem(t *testing.T) {
 				tuc.EXPECT().CreateCatalogItem(
 					gomock.Any(),
 					"item1",
-					float64(100),
-				).Return(nil)
+					entity.Money{Amount: 10000, Currency: "USD"},
+					5,
+				).Return(&item, nil)
 			},
 			request: &pb.CreateCatalogItemRequest{
 				Name:  "item1",
-				Price: float64(100),
+				Price: &pb.Money{Amount: 10000, Currency: "USD"},
+				Stock: 5,
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "success: legacy price is in the default currency",
+			setup: func(tuc *mock.MockCatalogItemUseCase) {
+				tuc.EXPECT().CreateCatalogItem(
+					gomock.Any(),
+					"item1",
+					entity.Money{Amount: 1999, Currency: "USD"},
+					5,
+				).Return(&item, nil)
+			},
+			request: &pb.CreateCatalogItemRequest{
+				Name:        "item1",
+				LegacyPrice: 19.99,
+				Stock:       5,
 			},
 			wantStatus: codes.OK,
 		},
@@ -342,7 +467,7 @@ func TestHandler_CreateCatalogItem(t *testing.T) {
 			name: "Fail: invalid request of name is empty",
 			request: &pb.CreateCatalogItemRequest{
 				Name:  "",
-				Price: float64(100),
+				Price: &pb.Money{Amount: 10000, Currency: "USD"},
 			},
 			wantStatus: codes.InvalidArgument,
 		},
@@ -350,7 +475,24 @@ func TestHandler_CreateCatalogItem(t *testing.T) {
 			name: "Fail: invalid request of  price is less than 0",
 			request: &pb.CreateCatalogItemRequest{
 				Name:  "item1",
-				Price: float64(-1),
+				Price: &pb.Money{Amount: -100, Currency: "USD"},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of unsupported currency",
+			request: &pb.CreateCatalogItemRequest{
+				Name:  "item1",
+				Price: &pb.Money{Amount: 10000, Currency: "XXX"},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of stock is less than 0",
+			request: &pb.CreateCatalogItemRequest{
+				Name:  "item1",
+				Price: &pb.Money{Amount: 10000, Currency: "USD"},
+				Stock: -1,
 			},
 			wantStatus: codes.InvalidArgument,
 		},
@@ -364,13 +506,13 @@ func TestHandler_CreateCatalogItem(t *testing.T) {
 			client, cleanup := setupTestServer(t, tt.setup)
 			defer cleanup()
 
-			req, err := client.CreateCatalogItem(context.Background(), tt.request)
+			resp, err := client.CreateCatalogItem(context.Background(), tt.request)
 			if status.Code(err) != tt.wantStatus {
 				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
 			}
 
 			if tt.wantStatus == codes.OK {
-				if req == nil {
+				if resp.GetItem().GetId() != item.ID {
 					t.Fatalf("handler returned wrong item data")
 				}
 			}
@@ -383,6 +525,12 @@ func TestHandler_UpdateCatalogItem(t *testing.T) {
 
 	itemID := uuid.New().String()
 
+	item := entity.CatalogItem{
+		ID:    itemID,
+		Name:  "updated name",
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -396,24 +544,86 @@ func TestHandler_UpdateCatalogItem(t *testing.T) {
 			setup: func(tuc *mock.MockCatalogItemUseCase) {
 				tuc.EXPECT().UpdateCatalogItem(
 					gomock.Any(),
-					itemID,
-					"updated name",
-					float64(100),
-				).Return(nil)
+					usecase.UpdateCatalogItemParams{
+						ID:      itemID,
+						Name:    "updated name",
+						Price:   entity.Money{Amount: 10000, Currency: "USD"},
+						Version: 3,
+					},
+				).Return(&item, nil)
 			},
 			request: &pb.UpdateCatalogItemRequest{
-				Id:    itemID,
-				Name:  "updated name",
-				Price: float64(100),
+				Id:      itemID,
+				Name:    "updated name",
+				Price:   &pb.Money{Amount: 10000, Currency: "USD"},
+				Version: 3,
 			},
 			wantStatus: codes.OK,
 		},
+		{
+			name: "success: only the masked fields are validated and updated",
+			setup: func(tuc *mock.MockCatalogItemUseCase) {
+				tuc.EXPECT().UpdateCatalogItem(
+					gomock.Any(),
+					usecase.UpdateCatalogItemParams{
+						ID:     itemID,
+						Stock:  5,
+						Fields: []string{entity.CatalogItemFieldStock},
+					},
+				).Return(&item, nil)
+			},
+			request: &pb.UpdateCatalogItemRequest{
+				Id:         itemID,
+				Stock:      5,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"stock", "stock"}},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: invalid request of unknown update mask path",
+			request: &pb.UpdateCatalogItemRequest{
+				Id:         itemID,
+				Name:       "updated name",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "reserved"}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of masked name is empty",
+			request: &pb.UpdateCatalogItemRequest{
+				Id:         itemID,
+				Stock:      5,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"name", "stock"}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: item is updated concurrently",
+			setup: func(tuc *mock.MockCatalogItemUseCase) {
+				tuc.EXPECT().UpdateCatalogItem(
+					gomock.Any(),
+					usecase.UpdateCatalogItemParams{
+						ID:      itemID,
+						Name:    "updated name",
+						Price:   entity.Money{Amount: 10000, Currency: "USD"},
+						Version: 2,
+					},
+				).Return(nil, entity.NewError(entity.ErrConflict, "catalog item has been changed concurrently"))
+			},
+			request: &pb.UpdateCatalogItemRequest{
+				Id:      itemID,
+				Name:    "updated name",
+				Price:   &pb.Money{Amount: 10000, Currency: "USD"},
+				Version: 2,
+			},
+			wantStatus: codes.Aborted,
+		},
 		{
 			name: "Fail: invalid request of id is empty",
 			request: &pb.UpdateCatalogItemRequest{
 				Id:    "",
 				Name:  "updated name",
-				Price: float64(100),
+				Price: &pb.Money{Amount: 10000, Currency: "USD"},
 			},
 			wantStatus: codes.InvalidArgument,
 		},
@@ -422,7 +632,7 @@ func TestHandler_UpdateCatalogItem(t *testing.T) {
 			request: &pb.UpdateCatalogItemRequest{
 				Id:    itemID,
 				Name:  "",
-				Price: float64(100),
+				Price: &pb.Money{Amount: 10000, Currency: "USD"},
 			},
 			wantStatus: codes.InvalidArgument,
 		},
@@ -431,7 +641,7 @@ func TestHandler_UpdateCatalogItem(t *testing.T) {
 			request: &pb.UpdateCatalogItemRequest{
 				Id:    itemID,
 				Name:  "updated name",
-				Price: float64(-1),
+				Price: &pb.Money{Amount: -100, Currency: "USD"},
 			},
 			wantStatus: codes.InvalidArgument,
 		},
@@ -445,13 +655,13 @@ func TestHandler_UpdateCatalogItem(t *testing.T) {
 			client, cleanup := setupTestServer(t, tt.setup)
 			defer cleanup()
 
-			req, err := client.UpdateCatalogItem(context.Background(), tt.request)
+			resp, err := client.UpdateCatalogItem(context.Background(), tt.request)
 			if status.Code(err) != tt.wantStatus {
 				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
 			}
 
 			if tt.wantStatus == codes.OK {
-				if req == nil {
+				if resp.GetItem().GetId() != item.ID {
 					t.Fatalf("handler returned wrong item data")
 				}
 			}
@@ -511,3 +721,115 @@ func TestHandler_DeleteCatalogItem(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_WatchCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	item := entity.CatalogItem{
+		ID:    uuid.New().String(),
+		Name:  "item1",
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
+		Stock: 10,
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.WatchCatalogItemsRequest
+		want       []*pb.WatchCatalogItemsResponse
+		wantStatus codes.Code
+	}{
+		{
+			name: "success: changes are streamed from the resume token",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().WatchCatalogItems(
+					gomock.Any(),
+					int64(41),
+					gomock.Any(),
+				).DoAndReturn(func(_ context.Context, _ int64, send func(usecase.CatalogItemChange) error) error {
+					if err := send(usecase.CatalogItemChange{Type: usecase.ChangeTypeUpdated, ID: item.ID, CatalogItem: &item, Sequence: 42}); err != nil {
+						return err
+					}
+					return send(usecase.CatalogItemChange{Type: usecase.ChangeTypeDeleted, ID: item.ID, Sequence: 43})
+				})
+			},
+			request: &pb.WatchCatalogItemsRequest{ResumeToken: "41"},
+			want: []*pb.WatchCatalogItemsResponse{
+				{
+					Type:        "updated",
+					Id:          item.ID,
+					CatalogItem: &pb.CatalogItem{Id: item.ID, Name: item.Name, Price: &pb.Money{Amount: 10000, Currency: "USD"}, LegacyPrice: 100, Stock: 10, Available: 10},
+					ResumeToken: "42",
+				},
+				{Type: "deleted", Id: item.ID, ResumeToken: "43"},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "success: changes are watched from now on without a resume token",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().WatchCatalogItems(gomock.Any(), int64(-1), gomock.Any()).Return(nil)
+			},
+			request:    &pb.WatchCatalogItemsRequest{},
+			want:       nil,
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid resume token",
+			request:    &pb.WatchCatalogItemsRequest{ResumeToken: "abc"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: internal error",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().WatchCatalogItems(gomock.Any(), int64(-1), gomock.Any()).Return(errors.New("connection refused"))
+			},
+			request:    &pb.WatchCatalogItemsRequest{},
+			wantStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			stream, err := client.WatchCatalogItems(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("failed to open stream: %v", err)
+			}
+
+			var got []*pb.WatchCatalogItemsResponse
+			for {
+				res, err := stream.Recv() //nolint:govet // err shadowed
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.wantStatus {
+						t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+					}
+					return
+				}
+				got = append(got, res)
+			}
+			if tt.wantStatus != codes.OK {
+				t.Fatalf("handler returned wrong status code: got %v want %v", codes.OK, tt.wantStatus)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("handler streamed %d changes, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("handler streamed %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	category, err := ch.cuc.GetCategory(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to get category")
+	}
+
+	return &pb.GetCategoryResponse{
+		Category: convertCategory(category),
+	}, nil
+}
+
+func (ch *catalogItemHandler) ListCategories(ctx context.Context, _ *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := ch.cuc.ListCategories(ctx)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list categories")
+	}
+
+	res := make([]*pb.Category, 0, len(categories))
+	for i := range categories {
+		res = append(res, convertCategory(&categories[i]))
+	}
+
+	return &pb.ListCategoriesResponse{
+		Categories: res,
+	}, nil
+}
+
+func (ch *catalogItemHandler) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	if req.GetName() == "" || req.GetPosition() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("name", req.GetName()),
+			log.Fint("position", int(req.GetPosition())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	category, err := ch.cuc.CreateCategory(ctx, req.GetParentId(), req.GetName(), req.GetSlug(), int(req.GetPosition()))
+	if err != nil {
+		return nil, toStatusError(err, "Failed to create category")
+	}
+
+	return &pb.CreateCategoryResponse{
+		Category: convertCategory(category),
+	}, nil
+}
+
+func (ch *catalogItemHandler) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error) {
+	if req.GetId() == "" || req.GetName() == "" || req.GetPosition() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("id", req.GetId()),
+			log.Fstring("name", req.GetName()),
+			log.Fint("position", int(req.GetPosition())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	category, err := ch.cuc.UpdateCategory(ctx, req.GetId(), req.GetParentId(), req.GetName(), req.GetSlug(), int(req.GetPosition()))
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update category")
+	}
+
+	return &pb.UpdateCategoryResponse{
+		Category: convertCategory(category),
+	}, nil
+}
+
+func (ch *catalogItemHandler) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	if err := ch.cuc.DeleteCategory(ctx, id); err != nil {
+		return nil, toStatusError(err, "Failed to delete category")
+	}
+
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+func (ch *catalogItemHandler) SetCatalogItemCategories(ctx context.Context, req *pb.SetCatalogItemCategoriesRequest) (*pb.SetCatalogItemCategoriesResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	item, err := ch.cuc.SetCatalogItemCategories(ctx, id, req.GetCategoryIds())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to set catalog item categories")
+	}
+
+	return &pb.SetCatalogItemCategoriesResponse{
+		Item: convertCatalogItem(item),
+	}, nil
+}
+
+func (ch *catalogItemHandler) ListCatalogItemsByCategory(ctx context.Context, req *pb.ListCatalogItemsByCategoryRequest) (*pb.ListCatalogItemsByCategoryResponse, error) {
+	if req.GetCategoryId() == "" || req.GetPageSize() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("category_id", req.GetCategoryId()),
+			log.Fint("page_size", int(req.GetPageSize())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	items, info, err := ch.cuc.ListCatalogItemsByCategory(ctx, req.GetCategoryId(), repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	}, req.GetCurrency())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list catalog items by category")
+	}
+
+	var res []*pb.CatalogItem
+	for i := range items {
+		res = append(res, convertCatalogItem(&items[i]))
+	}
+
+	return &pb.ListCatalogItemsByCategoryResponse{
+		Items:         res,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
+	}, nil
+}
+
+func convertCategory(category *entity.Category) *pb.Category {
+	return &pb.Category{
+		Id:       category.ID,
+		ParentId: category.ParentID,
+		Name:     category.Name,
+		Slug:     category.Slug,
+		Position: int32(category.Position),
+		Depth:    int32(category.Depth),
+	}
+}
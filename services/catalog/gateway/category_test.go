@@ -0,0 +1,191 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_CreateCategory(t *testing.T) {
+	t.Parallel()
+
+	categoryID := uuid.New().String()
+	parentID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.CreateCategoryRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().CreateCategory(gomock.Any(), parentID, "Laptops", "", 0).Return(&entity.Category{
+					ID:       categoryID,
+					ParentID: parentID,
+					Name:     "Laptops",
+					Slug:     "laptops",
+					Depth:    1,
+				}, nil)
+			},
+			request:    &pb.CreateCategoryRequest{ParentId: parentID, Name: "Laptops"},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: parent not found",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().CreateCategory(gomock.Any(), parentID, "Laptops", "", 0).Return(nil, entity.NewError(entity.ErrNotFound, "category not found"))
+			},
+			request:    &pb.CreateCategoryRequest{ParentId: parentID, Name: "Laptops"},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of name is empty",
+			request:    &pb.CreateCategoryRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.CreateCategory(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK && resp.GetCategory().GetId() != categoryID {
+				t.Fatalf("handler returned wrong category: %v", resp.GetCategory())
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateCategory(t *testing.T) {
+	t.Parallel()
+
+	categoryID := uuid.New().String()
+	childID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.UpdateCategoryRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().UpdateCategory(gomock.Any(), categoryID, "", "Computers", "computers", 1).Return(&entity.Category{
+					ID:       categoryID,
+					Name:     "Computers",
+					Slug:     "computers",
+					Position: 1,
+				}, nil)
+			},
+			request:    &pb.UpdateCategoryRequest{Id: categoryID, Name: "Computers", Slug: "computers", Position: 1},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: category is moved under its child",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().UpdateCategory(gomock.Any(), categoryID, childID, "Computers", "", 0).Return(nil, entity.ErrCategoryCycle)
+			},
+			request:    &pb.UpdateCategoryRequest{Id: categoryID, ParentId: childID, Name: "Computers"},
+			wantStatus: codes.FailedPrecondition,
+		},
+		{
+			name:       "Fail: invalid request of position is negative",
+			request:    &pb.UpdateCategoryRequest{Id: categoryID, Name: "Computers", Position: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			_, err := client.UpdateCategory(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_SetCatalogItemCategories(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	categoryID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.SetCatalogItemCategoriesRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SetCatalogItemCategories(gomock.Any(), itemID, []string{categoryID}).Return(&entity.CatalogItem{
+					ID:          itemID,
+					Name:        "item",
+					Price:       entity.Money{Amount: 1000, Currency: "USD"},
+					CategoryIDs: []string{categoryID},
+				}, nil)
+			},
+			request:    &pb.SetCatalogItemCategoriesRequest{Id: itemID, CategoryIds: []string{categoryID}},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid request of id is empty",
+			request:    &pb.SetCatalogItemCategoriesRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.SetCatalogItemCategories(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK && len(resp.GetItem().GetCategoryIds()) != len(tt.request.GetCategoryIds()) {
+				t.Fatalf("handler returned wrong categories: %v", resp.GetItem().GetCategoryIds())
+			}
+		})
+	}
+}
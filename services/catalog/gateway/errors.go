@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"errors"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+var errorCodes = []struct {
+	kind error
+	code codes.Code
+}{
+	{entity.ErrNotFound, codes.NotFound},
+	{entity.ErrAlreadyExists, codes.AlreadyExists},
+	{entity.ErrInvalidArgument, codes.InvalidArgument},
+	{entity.ErrConflict, codes.Aborted},
+	{entity.ErrFailedPrecondition, codes.FailedPrecondition},
+}
+
+// toStatusError converts a usecase error into a gRPC status error.
+// Domain errors keep their own message; anything else is reported as
+// codes.Internal with msg so that internal details are not leaked to the client.
+func toStatusError(err error, msg string) error {
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.kind) {
+			log.Warn(msg, log.Ferror(err))
+			return status.Error(ec.code, err.Error())
+		}
+	}
+	log.Error(msg, log.Ferror(err))
+	return status.Error(codes.Internal, msg)
+}
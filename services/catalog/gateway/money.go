@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// toMoney converts the price of a request. The clients that do not set it yet send a legacy price,
+// which is in the currency prices had before they had one.
+func toMoney(m *pb.Money, legacy float64) (entity.Money, error) {
+	if m == nil {
+		return entity.MoneyFromFloat(legacy, entity.DefaultCurrency)
+	}
+	return entity.NewMoney(m.GetAmount(), m.GetCurrency())
+}
+
+// toOptionalMoney converts a price that may be left unset, such as a price override.
+func toOptionalMoney(m *pb.Money) (*entity.Money, error) {
+	if m == nil {
+		return nil, nil //nolint:nilnil // an unset price is not an error
+	}
+	money, err := entity.NewMoney(m.GetAmount(), m.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	return &money, nil
+}
+
+func convertMoney(m entity.Money) *pb.Money {
+	return &pb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+func convertMonies(ms []entity.Money) []*pb.Money {
+	if len(ms) == 0 {
+		return nil
+	}
+	res := make([]*pb.Money, 0, len(ms))
+	for _, m := range ms {
+		res = append(res, convertMoney(m))
+	}
+	return res
+}
+
+func convertExchangeRate(rate *entity.ExchangeRate) *pb.ExchangeRate {
+	return &pb.ExchangeRate{
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Decimal(),
+		UpdatedAt: timestamppb.New(rate.UpdatedAt),
+	}
+}
+
+func convertPriceConversion(c *entity.PriceConversion) *pb.PriceConversion {
+	if c == nil {
+		return nil
+	}
+	return &pb.PriceConversion{
+		BasePrice:    convertMoney(c.BasePrice),
+		ExchangeRate: convertExchangeRate(&c.Rate),
+	}
+}
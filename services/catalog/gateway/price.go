@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) SetCatalogItemPrices(ctx context.Context, req *pb.SetCatalogItemPricesRequest) (*pb.SetCatalogItemPricesResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	prices := make([]entity.Money, 0, len(req.GetPrices()))
+	for _, p := range req.GetPrices() {
+		price, err := entity.NewMoney(p.GetAmount(), p.GetCurrency())
+		if err != nil {
+			return nil, toStatusError(err, "Invalid price")
+		}
+		prices = append(prices, price)
+	}
+
+	item, err := ch.cuc.SetCatalogItemPrices(ctx, id, prices, req.GetVersion())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to set catalog item prices")
+	}
+
+	return &pb.SetCatalogItemPricesResponse{
+		Item: convertCatalogItem(item),
+	}, nil
+}
+
+func (ch *catalogItemHandler) SetExchangeRate(ctx context.Context, req *pb.SetExchangeRateRequest) (*pb.SetExchangeRateResponse, error) {
+	if req.GetFrom() == "" || req.GetTo() == "" || req.GetRate() == "" {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("from", req.GetFrom()),
+			log.Fstring("to", req.GetTo()),
+			log.Fstring("rate", req.GetRate()),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	rate, err := ch.cuc.SetExchangeRate(ctx, req.GetFrom(), req.GetTo(), req.GetRate())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to set exchange rate")
+	}
+
+	return &pb.SetExchangeRateResponse{
+		ExchangeRate: convertExchangeRate(rate),
+	}, nil
+}
+
+func (ch *catalogItemHandler) ListExchangeRates(ctx context.Context, _ *pb.ListExchangeRatesRequest) (*pb.ListExchangeRatesResponse, error) {
+	rates, err := ch.cuc.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list exchange rates")
+	}
+
+	res := make([]*pb.ExchangeRate, 0, len(rates))
+	for i := range rates {
+		res = append(res, convertExchangeRate(&rates[i]))
+	}
+
+	return &pb.ListExchangeRatesResponse{
+		ExchangeRates: res,
+	}, nil
+}
+
+func (ch *catalogItemHandler) DeleteExchangeRate(ctx context.Context, req *pb.DeleteExchangeRateRequest) (*pb.DeleteExchangeRateResponse, error) {
+	if req.GetFrom() == "" || req.GetTo() == "" {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("from", req.GetFrom()),
+			log.Fstring("to", req.GetTo()),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	if err := ch.cuc.DeleteExchangeRate(ctx, req.GetFrom(), req.GetTo()); err != nil {
+		return nil, toStatusError(err, "Failed to delete exchange rate")
+	}
+
+	return &pb.DeleteExchangeRateResponse{}, nil
+}
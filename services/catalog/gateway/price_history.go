@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) GetPriceHistory(ctx context.Context, req *pb.GetPriceHistoryRequest) (*pb.GetPriceHistoryResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	history, err := ch.cuc.GetPriceHistory(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to get price history")
+	}
+
+	var res []*pb.PriceHistoryEntry
+	for i := range history {
+		res = append(res, convertPriceHistoryEntry(&history[i]))
+	}
+
+	return &pb.GetPriceHistoryResponse{
+		Entries: res,
+	}, nil
+}
+
+func (ch *catalogItemHandler) SchedulePriceChange(ctx context.Context, req *pb.SchedulePriceChangeRequest) (*pb.SchedulePriceChangeResponse, error) {
+	if req.GetId() == "" || req.GetPrice() == nil || req.GetEffectiveFrom() == nil {
+		log.Warn("Invalid request body: %v", req)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	price, err := entity.NewMoney(req.GetPrice().GetAmount(), req.GetPrice().GetCurrency())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid price")
+	}
+
+	entry, err := ch.cuc.SchedulePriceChange(ctx, req.GetId(), price, req.GetEffectiveFrom().AsTime())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to schedule price change")
+	}
+
+	return &pb.SchedulePriceChangeResponse{
+		Entry: convertPriceHistoryEntry(entry),
+	}, nil
+}
+
+func convertPriceHistoryEntry(entry *entity.PriceHistoryEntry) *pb.PriceHistoryEntry {
+	var appliedAt *timestamppb.Timestamp
+	if entry.AppliedAt != nil {
+		appliedAt = timestamppb.New(*entry.AppliedAt)
+	}
+	return &pb.PriceHistoryEntry{
+		Id:            entry.ID,
+		CatalogItemId: entry.CatalogItemID,
+		Price:         convertMoney(entry.Price),
+		EffectiveFrom: timestamppb.New(entry.EffectiveFrom),
+		AppliedAt:     appliedAt,
+	}
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_GetPriceHistory(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	appliedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.GetPriceHistoryRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetPriceHistory(gomock.Any(), itemID).Return([]entity.PriceHistoryEntry{
+					{
+						ID:            uuid.New().String(),
+						CatalogItemID: itemID,
+						Price:         entity.Money{Amount: 12000, Currency: "USD"},
+						EffectiveFrom: appliedAt.Add(24 * time.Hour),
+					},
+					{
+						ID:            uuid.New().String(),
+						CatalogItemID: itemID,
+						Price:         entity.Money{Amount: 10000, Currency: "USD"},
+						EffectiveFrom: appliedAt,
+						AppliedAt:     &appliedAt,
+					},
+				}, nil)
+			},
+			request:    &pb.GetPriceHistoryRequest{Id: itemID},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: catalog item not found",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().GetPriceHistory(gomock.Any(), itemID).Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found"))
+			},
+			request:    &pb.GetPriceHistoryRequest{Id: itemID},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of id is empty",
+			request:    &pb.GetPriceHistoryRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.GetPriceHistory(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus == codes.OK {
+				entries := resp.GetEntries()
+				if len(entries) != 2 || entries[0].GetAppliedAt() != nil || !entries[1].GetAppliedAt().AsTime().Equal(appliedAt) {
+					t.Fatalf("handler returned wrong price history: %v", entries)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_SchedulePriceChange(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	effectiveFrom := time.Now().Add(24 * time.Hour).UTC()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.SchedulePriceChangeRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				price := entity.Money{Amount: 12000, Currency: "USD"}
+				cuc.EXPECT().SchedulePriceChange(gomock.Any(), itemID, price, effectiveFrom).Return(&entity.PriceHistoryEntry{
+					ID:            uuid.New().String(),
+					CatalogItemID: itemID,
+					Price:         price,
+					EffectiveFrom: effectiveFrom,
+				}, nil)
+			},
+			request: &pb.SchedulePriceChangeRequest{
+				Id:            itemID,
+				Price:         &pb.Money{Amount: 12000, Currency: "USD"},
+				EffectiveFrom: timestamppb.New(effectiveFrom),
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: price change is in the past",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SchedulePriceChange(gomock.Any(), itemID, gomock.Any(), gomock.Any()).Return(nil, entity.NewError(entity.ErrInvalidArgument, "effective time of a price change must be in the future"))
+			},
+			request: &pb.SchedulePriceChangeRequest{
+				Id:            itemID,
+				Price:         &pb.Money{Amount: 12000, Currency: "USD"},
+				EffectiveFrom: timestamppb.New(effectiveFrom.Add(-48 * time.Hour)),
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of price currency",
+			request: &pb.SchedulePriceChangeRequest{
+				Id:            itemID,
+				Price:         &pb.Money{Amount: 12000, Currency: "usd"},
+				EffectiveFrom: timestamppb.New(effectiveFrom),
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of effective time is unset",
+			request: &pb.SchedulePriceChangeRequest{
+				Id:    itemID,
+				Price: &pb.Money{Amount: 12000, Currency: "USD"},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.SchedulePriceChange(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus == codes.OK && (resp.GetEntry().GetCatalogItemId() != itemID || resp.GetEntry().GetAppliedAt() != nil) {
+				t.Fatalf("handler returned wrong price change: %v", resp.GetEntry())
+			}
+		})
+	}
+}
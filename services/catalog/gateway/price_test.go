@@ -0,0 +1,213 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_SetCatalogItemPrices(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.SetCatalogItemPricesRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SetCatalogItemPrices(
+					gomock.Any(),
+					itemID,
+					[]entity.Money{{Amount: 900, Currency: "EUR"}},
+					int64(3),
+				).Return(&entity.CatalogItem{
+					ID:     itemID,
+					Name:   "item",
+					Price:  entity.Money{Amount: 1000, Currency: "USD"},
+					Prices: []entity.Money{{Amount: 900, Currency: "EUR"}},
+				}, nil)
+			},
+			request: &pb.SetCatalogItemPricesRequest{
+				Id:      itemID,
+				Prices:  []*pb.Money{{Amount: 900, Currency: "EUR"}},
+				Version: 3,
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: stale version",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SetCatalogItemPrices(
+					gomock.Any(),
+					itemID,
+					[]entity.Money{{Amount: 900, Currency: "EUR"}},
+					int64(2),
+				).Return(nil, repository.ErrVersionConflict)
+			},
+			request: &pb.SetCatalogItemPricesRequest{
+				Id:      itemID,
+				Prices:  []*pb.Money{{Amount: 900, Currency: "EUR"}},
+				Version: 2,
+			},
+			wantStatus: codes.Aborted,
+		},
+		{
+			name: "Fail: invalid request of unsupported currency",
+			request: &pb.SetCatalogItemPricesRequest{
+				Id:     itemID,
+				Prices: []*pb.Money{{Amount: 900, Currency: "XXX"}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid request of id is empty",
+			request:    &pb.SetCatalogItemPricesRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.SetCatalogItemPrices(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK && len(resp.GetItem().GetPrices()) != len(tt.request.GetPrices()) {
+				t.Fatalf("handler returned wrong prices: %v", resp.GetItem().GetPrices())
+			}
+		})
+	}
+}
+
+func TestHandler_SetExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.SetExchangeRateRequest
+		wantStatus codes.Code
+		wantRate   string
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SetExchangeRate(
+					gomock.Any(),
+					"USD",
+					"EUR",
+					"0.920",
+				).Return(&entity.ExchangeRate{From: "USD", To: "EUR", Rate: 92000000, UpdatedAt: time.Now()}, nil)
+			},
+			request:    &pb.SetExchangeRateRequest{From: "USD", To: "EUR", Rate: "0.920"},
+			wantStatus: codes.OK,
+			wantRate:   "0.92",
+		},
+		{
+			name: "Fail: invalid rate",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SetExchangeRate(
+					gomock.Any(),
+					"USD",
+					"EUR",
+					"-1",
+				).Return(nil, entity.NewError(entity.ErrInvalidArgument, "exchange rate must be greater than 0"))
+			},
+			request:    &pb.SetExchangeRateRequest{From: "USD", To: "EUR", Rate: "-1"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid request of rate is empty",
+			request:    &pb.SetExchangeRateRequest{From: "USD", To: "EUR"},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.SetExchangeRate(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK && resp.GetExchangeRate().GetRate() != tt.wantRate {
+				t.Fatalf("handler returned wrong rate: got %v want %v", resp.GetExchangeRate().GetRate(), tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.DeleteExchangeRateRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().DeleteExchangeRate(gomock.Any(), "USD", "EUR").Return(nil)
+			},
+			request:    &pb.DeleteExchangeRateRequest{From: "USD", To: "EUR"},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid request of to is empty",
+			request:    &pb.DeleteExchangeRateRequest{From: "USD"},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			_, err := client.DeleteExchangeRate(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+		})
+	}
+}
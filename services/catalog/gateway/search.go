@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) SearchCatalogItems(ctx context.Context, req *pb.SearchCatalogItemsRequest) (*pb.SearchCatalogItemsResponse, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" || req.GetPageSize() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("query", req.GetQuery()),
+			log.Fint("page_size", int(req.GetPageSize())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+	minPrice, err := toOptionalMoney(req.GetMinPrice())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid minimum price")
+	}
+	maxPrice, err := toOptionalMoney(req.GetMaxPrice())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid maximum price")
+	}
+
+	results, err := ch.cuc.SearchCatalogItems(ctx, usecase.SearchParams{
+		Query:    req.GetQuery(),
+		MinPrice: minPrice,
+		MaxPrice: maxPrice,
+		Page: repository.Page{
+			Size:  int(req.GetPageSize()),
+			Token: req.GetPageToken(),
+		},
+		Currency: req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, toStatusError(err, "Failed to search catalog items")
+	}
+
+	var res []*pb.SearchResult
+	for i := range results.Results {
+		res = append(res, convertSearchResult(&results.Results[i]))
+	}
+
+	return &pb.SearchCatalogItemsResponse{
+		Results:       res,
+		TotalSize:     int32(results.Total),
+		NextPageToken: results.PageInfo.NextToken,
+		PrevPageToken: results.PageInfo.PrevToken,
+	}, nil
+}
+
+func convertSearchResult(result *usecase.SearchResult) *pb.SearchResult {
+	highlights := make([]*pb.TextRange, 0, len(result.Highlights))
+	for _, h := range result.Highlights {
+		highlights = append(highlights, &pb.TextRange{
+			Start: int32(h.Start),
+			End:   int32(h.End),
+		})
+	}
+	return &pb.SearchResult{
+		Item:       convertCatalogItem(&result.CatalogItem),
+		Score:      result.Score,
+		Highlights: highlights,
+	}
+}
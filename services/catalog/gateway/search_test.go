@@ -0,0 +1,110 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_SearchCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	minPrice := entity.Money{Amount: 1000, Currency: "JPY"}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.SearchCatalogItemsRequest
+		wantStatus codes.Code
+		wantTotal  int32
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SearchCatalogItems(gomock.Any(), usecase.SearchParams{
+					Query:    "shirt",
+					MinPrice: &minPrice,
+					Page:     repository.Page{Size: 10},
+				}).Return(&usecase.SearchResults{
+					Results: []usecase.SearchResult{
+						{
+							CatalogItem: entity.CatalogItem{ID: itemID, Name: "Blue Shirt", Price: entity.Money{Amount: 1500, Currency: "JPY"}},
+							Score:       1.5,
+							Highlights:  []repository.Highlight{{Start: 5, End: 10}},
+						},
+					},
+					Total:    11,
+					PageInfo: repository.PageInfo{NextToken: "10"},
+				}, nil)
+			},
+			request: &pb.SearchCatalogItemsRequest{
+				Query:    "shirt",
+				MinPrice: &pb.Money{Amount: 1000, Currency: "JPY"},
+				PageSize: 10,
+			},
+			wantStatus: codes.OK,
+			wantTotal:  11,
+		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().SearchCatalogItems(gomock.Any(), gomock.Any()).Return(nil, repository.ErrInvalidPageToken)
+			},
+			request:    &pb.SearchCatalogItemsRequest{Query: "shirt", PageToken: "x"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid request of query is empty",
+			request:    &pb.SearchCatalogItemsRequest{Query: " "},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid minimum price",
+			request:    &pb.SearchCatalogItemsRequest{Query: "shirt", MinPrice: &pb.Money{Amount: 1000, Currency: "yen"}},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.SearchCatalogItems(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus != codes.OK {
+				return
+			}
+
+			if resp.GetTotalSize() != tt.wantTotal {
+				t.Fatalf("handler returned wrong total size: got %v want %v", resp.GetTotalSize(), tt.wantTotal)
+			}
+			result := resp.GetResults()[0]
+			if result.GetItem().GetId() != itemID {
+				t.Fatalf("handler returned wrong item: %v", result.GetItem())
+			}
+			if h := result.GetHighlights(); len(h) != 1 || h[0].GetStart() != 5 || h[0].GetEnd() != 10 {
+				t.Fatalf("handler returned wrong highlights: %v", h)
+			}
+		})
+	}
+}
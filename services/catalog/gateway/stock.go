@@ -0,0 +1,98 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReserveStockResponse, error) {
+	if !ch.isValidReserveStockRequest(req) {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+
+	quantities := make([]usecase.StockQuantity, 0, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		quantities = append(quantities, usecase.StockQuantity{
+			CatalogItemID: item.GetCatalogItemId(),
+			SKU:           item.GetSku(),
+			Quantity:      int(item.GetQuantity()),
+		})
+	}
+
+	reservations, err := ch.cuc.ReserveStock(ctx, req.GetReservationId(), quantities)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to reserve stock")
+	}
+
+	var res []*pb.StockReservation
+	for _, reservation := range reservations {
+		res = append(res, &pb.StockReservation{
+			ReservationId: reservation.ReservationID,
+			CatalogItemId: reservation.CatalogItemID,
+			Quantity:      int32(reservation.Quantity),
+			Status:        string(reservation.Status),
+			Sku:           reservation.SKU,
+		})
+	}
+
+	return &pb.ReserveStockResponse{
+		Reservations: res,
+	}, nil
+}
+
+func (ch *catalogItemHandler) isValidReserveStockRequest(req *pb.ReserveStockRequest) bool {
+	if req.GetReservationId() == "" || len(req.GetItems()) == 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("reservation_id", req.GetReservationId()),
+			log.Fint("items", len(req.GetItems())),
+		)
+		return false
+	}
+	for _, item := range req.GetItems() {
+		if item.GetCatalogItemId() == "" || item.GetQuantity() <= 0 {
+			log.Warn(
+				"Invalid request",
+				log.Fstring("catalog_item_id", item.GetCatalogItemId()),
+				log.Fint("quantity", int(item.GetQuantity())),
+			)
+			return false
+		}
+	}
+	return true
+}
+
+func (ch *catalogItemHandler) ReleaseStock(ctx context.Context, req *pb.ReleaseStockRequest) (*pb.ReleaseStockResponse, error) {
+	reservationID := req.GetReservationId()
+	if reservationID == "" {
+		log.Warn("Reservation ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Reservation ID is required")
+	}
+
+	if err := ch.cuc.ReleaseStock(ctx, reservationID); err != nil {
+		return nil, toStatusError(err, "Failed to release stock")
+	}
+
+	return &pb.ReleaseStockResponse{}, nil
+}
+
+func (ch *catalogItemHandler) CommitStock(ctx context.Context, req *pb.CommitStockRequest) (*pb.CommitStockResponse, error) {
+	reservationID := req.GetReservationId()
+	if reservationID == "" {
+		log.Warn("Reservation ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Reservation ID is required")
+	}
+
+	if err := ch.cuc.CommitStock(ctx, reservationID); err != nil {
+		return nil, toStatusError(err, "Failed to commit stock")
+	}
+
+	return &pb.CommitStockResponse{}, nil
+}
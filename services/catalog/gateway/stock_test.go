@@ -0,0 +1,206 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_ReserveStock(t *testing.T) {
+	t.Parallel()
+
+	reservationID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.ReserveStockRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ReserveStock(
+					gomock.Any(),
+					reservationID,
+					[]usecase.StockQuantity{{CatalogItemID: itemID, Quantity: 2}},
+				).Return([]entity.StockReservation{
+					{ReservationID: reservationID, CatalogItemID: itemID, Quantity: 2, Status: entity.ReservationStatusReserved},
+				}, nil)
+			},
+			request: &pb.ReserveStockRequest{
+				ReservationId: reservationID,
+				Items:         []*pb.StockQuantity{{CatalogItemId: itemID, Quantity: 2}},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: insufficient stock",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ReserveStock(
+					gomock.Any(),
+					reservationID,
+					gomock.Any(),
+				).Return(nil, entity.ErrInsufficientStock)
+			},
+			request: &pb.ReserveStockRequest{
+				ReservationId: reservationID,
+				Items:         []*pb.StockQuantity{{CatalogItemId: itemID, Quantity: 2}},
+			},
+			wantStatus: codes.FailedPrecondition,
+		},
+		{
+			name: "Fail: invalid request of reservation id is empty",
+			request: &pb.ReserveStockRequest{
+				Items: []*pb.StockQuantity{{CatalogItemId: itemID, Quantity: 2}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of quantity is 0",
+			request: &pb.ReserveStockRequest{
+				ReservationId: reservationID,
+				Items:         []*pb.StockQuantity{{CatalogItemId: itemID}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.ReserveStock(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				if len(resp.GetReservations()) != 1 || resp.GetReservations()[0].GetStatus() != string(entity.ReservationStatusReserved) {
+					t.Fatalf("handler returned wrong reservations: %v", resp.GetReservations())
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ReleaseStock(t *testing.T) {
+	t.Parallel()
+
+	reservationID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.ReleaseStockRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ReleaseStock(gomock.Any(), reservationID).Return(nil)
+			},
+			request:    &pb.ReleaseStockRequest{ReservationId: reservationID},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: reservation not found",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ReleaseStock(gomock.Any(), reservationID).Return(usecase.ErrStockReservationNotFound)
+			},
+			request:    &pb.ReleaseStockRequest{ReservationId: reservationID},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of reservation id is empty",
+			request:    &pb.ReleaseStockRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			_, err := client.ReleaseStock(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_CommitStock(t *testing.T) {
+	t.Parallel()
+
+	reservationID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.CommitStockRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().CommitStock(gomock.Any(), reservationID).Return(nil)
+			},
+			request:    &pb.CommitStockRequest{ReservationId: reservationID},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: reservation already released",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().CommitStock(gomock.Any(), reservationID).Return(entity.ErrReservationClosed)
+			},
+			request:    &pb.CommitStockRequest{ReservationId: reservationID},
+			wantStatus: codes.FailedPrecondition,
+		},
+		{
+			name:       "Fail: invalid request of reservation id is empty",
+			request:    &pb.CommitStockRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			_, err := client.CommitStock(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+		})
+	}
+}
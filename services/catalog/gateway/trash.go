@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) RestoreCatalogItem(ctx context.Context, req *pb.RestoreCatalogItemRequest) (*pb.RestoreCatalogItemResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	item, err := ch.cuc.RestoreCatalogItem(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to restore catalog item")
+	}
+
+	return &pb.RestoreCatalogItemResponse{
+		Item: convertCatalogItem(item),
+	}, nil
+}
+
+func (ch *catalogItemHandler) ListDeletedCatalogItems(ctx context.Context, req *pb.ListDeletedCatalogItemsRequest) (*pb.ListDeletedCatalogItemsResponse, error) {
+	if req.GetPageSize() < 0 {
+		log.Warn("Invalid page size", log.Fint("page_size", int(req.GetPageSize())))
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	}
+
+	items, info, err := ch.cuc.ListDeletedCatalogItems(ctx, repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list deleted catalog items")
+	}
+
+	var res []*pb.CatalogItem
+	for i := range items {
+		res = append(res, convertCatalogItem(&items[i]))
+	}
+
+	return &pb.ListDeletedCatalogItemsResponse{
+		Items:         res,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
+	}, nil
+}
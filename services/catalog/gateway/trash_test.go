@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_RestoreCatalogItem(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.RestoreCatalogItemRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().RestoreCatalogItem(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:    itemID,
+					Name:  "item",
+					Price: entity.Money{Amount: 10000, Currency: "USD"},
+				}, nil)
+			},
+			request:    &pb.RestoreCatalogItemRequest{Id: itemID},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: item is not in the trash",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().RestoreCatalogItem(gomock.Any(), itemID).Return(nil, entity.NewError(entity.ErrNotFound, "deleted catalog item not found"))
+			},
+			request:    &pb.RestoreCatalogItemRequest{Id: itemID},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of id is empty",
+			request:    &pb.RestoreCatalogItemRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.RestoreCatalogItem(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus == codes.OK && resp.GetItem().GetId() != itemID {
+				t.Fatalf("handler returned wrong item data")
+			}
+		})
+	}
+}
+
+func TestHandler_ListDeletedCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.ListDeletedCatalogItemsRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().ListDeletedCatalogItems(gomock.Any(), repository.Page{Size: 10}).Return([]entity.CatalogItem{
+					{
+						ID:        itemID,
+						Name:      "item",
+						Price:     entity.Money{Amount: 10000, Currency: "USD"},
+						DeletedAt: &deletedAt,
+					},
+				}, repository.PageInfo{}, nil)
+			},
+			request:    &pb.ListDeletedCatalogItemsRequest{PageSize: 10},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid request of page size is negative",
+			request:    &pb.ListDeletedCatalogItemsRequest{PageSize: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.ListDeletedCatalogItems(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus != codes.OK {
+				return
+			}
+			if len(resp.GetItems()) != 1 || !resp.GetItems()[0].GetDeletedAt().AsTime().Equal(deletedAt) {
+				t.Fatalf("handler returned wrong items: %v", resp.GetItems())
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func (ch *catalogItemHandler) GetVariant(ctx context.Context, req *pb.GetVariantRequest) (*pb.GetVariantResponse, error) {
+	sku := req.GetSku()
+	if sku == "" {
+		log.Warn("SKU is required")
+		return nil, status.Errorf(codes.InvalidArgument, "SKU is required")
+	}
+
+	variant, err := ch.cuc.GetVariant(ctx, sku)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to get variant")
+	}
+
+	return &pb.GetVariantResponse{
+		Variant: convertVariant(variant),
+	}, nil
+}
+
+func (ch *catalogItemHandler) ListVariants(ctx context.Context, req *pb.ListVariantsRequest) (*pb.ListVariantsResponse, error) {
+	catalogItemID := req.GetCatalogItemId()
+	if catalogItemID == "" {
+		log.Warn("Catalog item ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Catalog item ID is required")
+	}
+
+	variants, err := ch.cuc.ListVariants(ctx, catalogItemID)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list variants")
+	}
+
+	return &pb.ListVariantsResponse{
+		Variants: convertVariants(variants),
+	}, nil
+}
+
+func (ch *catalogItemHandler) CreateVariant(ctx context.Context, req *pb.CreateVariantRequest) (*pb.CreateVariantResponse, error) {
+	if req.GetCatalogItemId() == "" || req.GetSku() == "" || req.GetStock() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("catalog_item_id", req.GetCatalogItemId()),
+			log.Fstring("sku", req.GetSku()),
+			log.Fint("stock", int(req.GetStock())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+	price, err := toOptionalMoney(req.GetPrice())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid price")
+	}
+
+	variant, err := ch.cuc.CreateVariant(ctx, req.GetCatalogItemId(), req.GetSku(), req.GetAttributes(), price, int(req.GetStock()))
+	if err != nil {
+		return nil, toStatusError(err, "Failed to create variant")
+	}
+
+	return &pb.CreateVariantResponse{
+		Variant: convertVariant(variant),
+	}, nil
+}
+
+func (ch *catalogItemHandler) UpdateVariant(ctx context.Context, req *pb.UpdateVariantRequest) (*pb.UpdateVariantResponse, error) {
+	if req.GetSku() == "" || req.GetStock() < 0 {
+		log.Warn(
+			"Invalid request",
+			log.Fstring("sku", req.GetSku()),
+			log.Fint("stock", int(req.GetStock())),
+		)
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
+	}
+	price, err := toOptionalMoney(req.GetPrice())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid price")
+	}
+
+	variant, err := ch.cuc.UpdateVariant(ctx, req.GetSku(), req.GetAttributes(), price, int(req.GetStock()))
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update variant")
+	}
+
+	return &pb.UpdateVariantResponse{
+		Variant: convertVariant(variant),
+	}, nil
+}
+
+func (ch *catalogItemHandler) DeleteVariant(ctx context.Context, req *pb.DeleteVariantRequest) (*pb.DeleteVariantResponse, error) {
+	sku := req.GetSku()
+	if sku == "" {
+		log.Warn("SKU is required")
+		return nil, status.Errorf(codes.InvalidArgument, "SKU is required")
+	}
+
+	if err := ch.cuc.DeleteVariant(ctx, sku); err != nil {
+		return nil, toStatusError(err, "Failed to delete variant")
+	}
+
+	return &pb.DeleteVariantResponse{}, nil
+}
+
+func convertVariant(variant *entity.Variant) *pb.Variant {
+	res := &pb.Variant{
+		Sku:           variant.SKU,
+		CatalogItemId: variant.CatalogItemID,
+		Attributes:    variant.Attributes,
+		Conversion:    convertPriceConversion(variant.Conversion),
+		Stock:         int32(variant.Stock),
+		Available:     int32(variant.Available()),
+	}
+	if variant.Price != nil {
+		res.Price = convertMoney(*variant.Price)
+	}
+	return res
+}
+
+func convertVariants(variants []entity.Variant) []*pb.Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+	res := make([]*pb.Variant, 0, len(variants))
+	for i := range variants {
+		res = append(res, convertVariant(&variants[i]))
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+func TestHandler_CreateVariant(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	attributes := map[string]string{"size": "M", "color": "red"}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.CreateVariantRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				price := &entity.Money{Amount: 1200, Currency: "USD"}
+				cuc.EXPECT().CreateVariant(gomock.Any(), itemID, "SHIRT-M-RED", attributes, price, 5).Return(&entity.Variant{
+					SKU:           "SHIRT-M-RED",
+					CatalogItemID: itemID,
+					Attributes:    attributes,
+					Price:         price,
+					Stock:         5,
+				}, nil)
+			},
+			request: &pb.CreateVariantRequest{
+				CatalogItemId: itemID,
+				Sku:           "SHIRT-M-RED",
+				Attributes:    attributes,
+				Price:         &pb.Money{Amount: 1200, Currency: "USD"},
+				Stock:         5,
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: sku already exists",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().CreateVariant(gomock.Any(), itemID, "SHIRT-M-RED", attributes, nil, 5).Return(nil, entity.ErrDuplicateVariant)
+			},
+			request: &pb.CreateVariantRequest{
+				CatalogItemId: itemID,
+				Sku:           "SHIRT-M-RED",
+				Attributes:    attributes,
+				Stock:         5,
+			},
+			wantStatus: codes.AlreadyExists,
+		},
+		{
+			name: "Fail: invalid request of price currency is empty",
+			request: &pb.CreateVariantRequest{
+				CatalogItemId: itemID,
+				Sku:           "SHIRT-M-RED",
+				Attributes:    attributes,
+				Price:         &pb.Money{Amount: 1200},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: invalid request of sku is empty",
+			request:    &pb.CreateVariantRequest{CatalogItemId: itemID, Attributes: attributes},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.CreateVariant(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				variant := resp.GetVariant()
+				if variant.GetSku() != "SHIRT-M-RED" || variant.GetPrice().GetAmount() != 1200 || variant.GetAvailable() != 5 {
+					t.Fatalf("handler returned wrong variant: %v", variant)
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteVariant(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemUseCase,
+		)
+		request    *pb.DeleteVariantRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().DeleteVariant(gomock.Any(), "SHIRT-M-RED").Return(nil)
+			},
+			request:    &pb.DeleteVariantRequest{Sku: "SHIRT-M-RED"},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: variant not found",
+			setup: func(cuc *mock.MockCatalogItemUseCase) {
+				cuc.EXPECT().DeleteVariant(gomock.Any(), "SHIRT-M-RED").Return(entity.NewError(entity.ErrNotFound, "variant not found"))
+			},
+			request:    &pb.DeleteVariantRequest{Sku: "SHIRT-M-RED"},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of sku is empty",
+			request:    &pb.DeleteVariantRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			_, err := client.DeleteVariant(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+		})
+	}
+}
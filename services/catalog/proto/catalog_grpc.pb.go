@@ -2,7 +2,7 @@
 // versions:
 // - protoc-gen-go-grpc v1.3.0
 // - protoc             v4.25.3
-// source: proto/catalog.proto
+// source: catalog/proto/catalog.proto
 
 package proto
 
@@ -20,13 +20,40 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CatalogService_GetCatalogItem_FullMethodName         = "/catalog.CatalogService/GetCatalogItem"
-	CatalogService_ListCatalogItems_FullMethodName       = "/catalog.CatalogService/ListCatalogItems"
-	CatalogService_ListCatalogItemsByName_FullMethodName = "/catalog.CatalogService/ListCatalogItemsByName"
-	CatalogService_ListCatalogItemsByIDs_FullMethodName  = "/catalog.CatalogService/ListCatalogItemsByIDs"
-	CatalogService_CreateCatalogItem_FullMethodName      = "/catalog.CatalogService/CreateCatalogItem"
-	CatalogService_UpdateCatalogItem_FullMethodName      = "/catalog.CatalogService/UpdateCatalogItem"
-	CatalogService_DeleteCatalogItem_FullMethodName      = "/catalog.CatalogService/DeleteCatalogItem"
+	CatalogService_GetCatalogItem_FullMethodName             = "/catalog.CatalogService/GetCatalogItem"
+	CatalogService_ListCatalogItems_FullMethodName           = "/catalog.CatalogService/ListCatalogItems"
+	CatalogService_ListCatalogItemsByName_FullMethodName     = "/catalog.CatalogService/ListCatalogItemsByName"
+	CatalogService_ListCatalogItemsByIDs_FullMethodName      = "/catalog.CatalogService/ListCatalogItemsByIDs"
+	CatalogService_CreateCatalogItem_FullMethodName          = "/catalog.CatalogService/CreateCatalogItem"
+	CatalogService_UpdateCatalogItem_FullMethodName          = "/catalog.CatalogService/UpdateCatalogItem"
+	CatalogService_DeleteCatalogItem_FullMethodName          = "/catalog.CatalogService/DeleteCatalogItem"
+	CatalogService_RestoreCatalogItem_FullMethodName         = "/catalog.CatalogService/RestoreCatalogItem"
+	CatalogService_ListDeletedCatalogItems_FullMethodName    = "/catalog.CatalogService/ListDeletedCatalogItems"
+	CatalogService_ReserveStock_FullMethodName               = "/catalog.CatalogService/ReserveStock"
+	CatalogService_ReleaseStock_FullMethodName               = "/catalog.CatalogService/ReleaseStock"
+	CatalogService_CommitStock_FullMethodName                = "/catalog.CatalogService/CommitStock"
+	CatalogService_WatchCatalogItems_FullMethodName          = "/catalog.CatalogService/WatchCatalogItems"
+	CatalogService_ImportCatalogItems_FullMethodName         = "/catalog.CatalogService/ImportCatalogItems"
+	CatalogService_ExportCatalogItems_FullMethodName         = "/catalog.CatalogService/ExportCatalogItems"
+	CatalogService_SetCatalogItemPrices_FullMethodName       = "/catalog.CatalogService/SetCatalogItemPrices"
+	CatalogService_GetPriceHistory_FullMethodName            = "/catalog.CatalogService/GetPriceHistory"
+	CatalogService_SchedulePriceChange_FullMethodName        = "/catalog.CatalogService/SchedulePriceChange"
+	CatalogService_SetExchangeRate_FullMethodName            = "/catalog.CatalogService/SetExchangeRate"
+	CatalogService_ListExchangeRates_FullMethodName          = "/catalog.CatalogService/ListExchangeRates"
+	CatalogService_DeleteExchangeRate_FullMethodName         = "/catalog.CatalogService/DeleteExchangeRate"
+	CatalogService_GetCategory_FullMethodName                = "/catalog.CatalogService/GetCategory"
+	CatalogService_ListCategories_FullMethodName             = "/catalog.CatalogService/ListCategories"
+	CatalogService_CreateCategory_FullMethodName             = "/catalog.CatalogService/CreateCategory"
+	CatalogService_UpdateCategory_FullMethodName             = "/catalog.CatalogService/UpdateCategory"
+	CatalogService_DeleteCategory_FullMethodName             = "/catalog.CatalogService/DeleteCategory"
+	CatalogService_SetCatalogItemCategories_FullMethodName   = "/catalog.CatalogService/SetCatalogItemCategories"
+	CatalogService_ListCatalogItemsByCategory_FullMethodName = "/catalog.CatalogService/ListCatalogItemsByCategory"
+	CatalogService_GetVariant_FullMethodName                 = "/catalog.CatalogService/GetVariant"
+	CatalogService_ListVariants_FullMethodName               = "/catalog.CatalogService/ListVariants"
+	CatalogService_CreateVariant_FullMethodName              = "/catalog.CatalogService/CreateVariant"
+	CatalogService_UpdateVariant_FullMethodName              = "/catalog.CatalogService/UpdateVariant"
+	CatalogService_DeleteVariant_FullMethodName              = "/catalog.CatalogService/DeleteVariant"
+	CatalogService_SearchCatalogItems_FullMethodName         = "/catalog.CatalogService/SearchCatalogItems"
 )
 
 // CatalogServiceClient is the client API for CatalogService service.
@@ -39,7 +66,37 @@ type CatalogServiceClient interface {
 	ListCatalogItemsByIDs(ctx context.Context, in *ListCatalogItemsByIDsRequest, opts ...grpc.CallOption) (*ListCatalogItemsByIDsResponse, error)
 	CreateCatalogItem(ctx context.Context, in *CreateCatalogItemRequest, opts ...grpc.CallOption) (*CreateCatalogItemResponse, error)
 	UpdateCatalogItem(ctx context.Context, in *UpdateCatalogItemRequest, opts ...grpc.CallOption) (*UpdateCatalogItemResponse, error)
+	// DeleteCatalogItem moves the item to the trash, from which it is purged after a retention period.
 	DeleteCatalogItem(ctx context.Context, in *DeleteCatalogItemRequest, opts ...grpc.CallOption) (*DeleteCatalogItemResponse, error)
+	RestoreCatalogItem(ctx context.Context, in *RestoreCatalogItemRequest, opts ...grpc.CallOption) (*RestoreCatalogItemResponse, error)
+	ListDeletedCatalogItems(ctx context.Context, in *ListDeletedCatalogItemsRequest, opts ...grpc.CallOption) (*ListDeletedCatalogItemsResponse, error)
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error)
+	CommitStock(ctx context.Context, in *CommitStockRequest, opts ...grpc.CallOption) (*CommitStockResponse, error)
+	WatchCatalogItems(ctx context.Context, in *WatchCatalogItemsRequest, opts ...grpc.CallOption) (CatalogService_WatchCatalogItemsClient, error)
+	// A file of catalog items is imported and exported in chunks, so that it need not fit in a message.
+	ImportCatalogItems(ctx context.Context, opts ...grpc.CallOption) (CatalogService_ImportCatalogItemsClient, error)
+	ExportCatalogItems(ctx context.Context, in *ExportCatalogItemsRequest, opts ...grpc.CallOption) (CatalogService_ExportCatalogItemsClient, error)
+	SetCatalogItemPrices(ctx context.Context, in *SetCatalogItemPricesRequest, opts ...grpc.CallOption) (*SetCatalogItemPricesResponse, error)
+	GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error)
+	SchedulePriceChange(ctx context.Context, in *SchedulePriceChangeRequest, opts ...grpc.CallOption) (*SchedulePriceChangeResponse, error)
+	// The exchange rates are maintained by administrators.
+	SetExchangeRate(ctx context.Context, in *SetExchangeRateRequest, opts ...grpc.CallOption) (*SetExchangeRateResponse, error)
+	ListExchangeRates(ctx context.Context, in *ListExchangeRatesRequest, opts ...grpc.CallOption) (*ListExchangeRatesResponse, error)
+	DeleteExchangeRate(ctx context.Context, in *DeleteExchangeRateRequest, opts ...grpc.CallOption) (*DeleteExchangeRateResponse, error)
+	GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*GetCategoryResponse, error)
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
+	UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error)
+	DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error)
+	SetCatalogItemCategories(ctx context.Context, in *SetCatalogItemCategoriesRequest, opts ...grpc.CallOption) (*SetCatalogItemCategoriesResponse, error)
+	ListCatalogItemsByCategory(ctx context.Context, in *ListCatalogItemsByCategoryRequest, opts ...grpc.CallOption) (*ListCatalogItemsByCategoryResponse, error)
+	GetVariant(ctx context.Context, in *GetVariantRequest, opts ...grpc.CallOption) (*GetVariantResponse, error)
+	ListVariants(ctx context.Context, in *ListVariantsRequest, opts ...grpc.CallOption) (*ListVariantsResponse, error)
+	CreateVariant(ctx context.Context, in *CreateVariantRequest, opts ...grpc.CallOption) (*CreateVariantResponse, error)
+	UpdateVariant(ctx context.Context, in *UpdateVariantRequest, opts ...grpc.CallOption) (*UpdateVariantResponse, error)
+	DeleteVariant(ctx context.Context, in *DeleteVariantRequest, opts ...grpc.CallOption) (*DeleteVariantResponse, error)
+	SearchCatalogItems(ctx context.Context, in *SearchCatalogItemsRequest, opts ...grpc.CallOption) (*SearchCatalogItemsResponse, error)
 }
 
 type catalogServiceClient struct {
@@ -113,6 +170,320 @@ func (c *catalogServiceClient) DeleteCatalogItem(ctx context.Context, in *Delete
 	return out, nil
 }
 
+func (c *catalogServiceClient) RestoreCatalogItem(ctx context.Context, in *RestoreCatalogItemRequest, opts ...grpc.CallOption) (*RestoreCatalogItemResponse, error) {
+	out := new(RestoreCatalogItemResponse)
+	err := c.cc.Invoke(ctx, CatalogService_RestoreCatalogItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListDeletedCatalogItems(ctx context.Context, in *ListDeletedCatalogItemsRequest, opts ...grpc.CallOption) (*ListDeletedCatalogItemsResponse, error) {
+	out := new(ListDeletedCatalogItemsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListDeletedCatalogItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ReserveStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ReleaseStock(ctx context.Context, in *ReleaseStockRequest, opts ...grpc.CallOption) (*ReleaseStockResponse, error) {
+	out := new(ReleaseStockResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ReleaseStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CommitStock(ctx context.Context, in *CommitStockRequest, opts ...grpc.CallOption) (*CommitStockResponse, error) {
+	out := new(CommitStockResponse)
+	err := c.cc.Invoke(ctx, CatalogService_CommitStock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) WatchCatalogItems(ctx context.Context, in *WatchCatalogItemsRequest, opts ...grpc.CallOption) (CatalogService_WatchCatalogItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CatalogService_ServiceDesc.Streams[0], CatalogService_WatchCatalogItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogServiceWatchCatalogItemsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CatalogService_WatchCatalogItemsClient interface {
+	Recv() (*WatchCatalogItemsResponse, error)
+	grpc.ClientStream
+}
+
+type catalogServiceWatchCatalogItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogServiceWatchCatalogItemsClient) Recv() (*WatchCatalogItemsResponse, error) {
+	m := new(WatchCatalogItemsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogServiceClient) ImportCatalogItems(ctx context.Context, opts ...grpc.CallOption) (CatalogService_ImportCatalogItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CatalogService_ServiceDesc.Streams[1], CatalogService_ImportCatalogItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogServiceImportCatalogItemsClient{stream}
+	return x, nil
+}
+
+type CatalogService_ImportCatalogItemsClient interface {
+	Send(*ImportCatalogItemsRequest) error
+	CloseAndRecv() (*ImportCatalogItemsResponse, error)
+	grpc.ClientStream
+}
+
+type catalogServiceImportCatalogItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogServiceImportCatalogItemsClient) Send(m *ImportCatalogItemsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *catalogServiceImportCatalogItemsClient) CloseAndRecv() (*ImportCatalogItemsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportCatalogItemsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogServiceClient) ExportCatalogItems(ctx context.Context, in *ExportCatalogItemsRequest, opts ...grpc.CallOption) (CatalogService_ExportCatalogItemsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CatalogService_ServiceDesc.Streams[2], CatalogService_ExportCatalogItems_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &catalogServiceExportCatalogItemsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CatalogService_ExportCatalogItemsClient interface {
+	Recv() (*ExportCatalogItemsResponse, error)
+	grpc.ClientStream
+}
+
+type catalogServiceExportCatalogItemsClient struct {
+	grpc.ClientStream
+}
+
+func (x *catalogServiceExportCatalogItemsClient) Recv() (*ExportCatalogItemsResponse, error) {
+	m := new(ExportCatalogItemsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *catalogServiceClient) SetCatalogItemPrices(ctx context.Context, in *SetCatalogItemPricesRequest, opts ...grpc.CallOption) (*SetCatalogItemPricesResponse, error) {
+	out := new(SetCatalogItemPricesResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SetCatalogItemPrices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error) {
+	out := new(GetPriceHistoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_GetPriceHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) SchedulePriceChange(ctx context.Context, in *SchedulePriceChangeRequest, opts ...grpc.CallOption) (*SchedulePriceChangeResponse, error) {
+	out := new(SchedulePriceChangeResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SchedulePriceChange_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) SetExchangeRate(ctx context.Context, in *SetExchangeRateRequest, opts ...grpc.CallOption) (*SetExchangeRateResponse, error) {
+	out := new(SetExchangeRateResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SetExchangeRate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListExchangeRates(ctx context.Context, in *ListExchangeRatesRequest, opts ...grpc.CallOption) (*ListExchangeRatesResponse, error) {
+	out := new(ListExchangeRatesResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListExchangeRates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DeleteExchangeRate(ctx context.Context, in *DeleteExchangeRateRequest, opts ...grpc.CallOption) (*DeleteExchangeRateResponse, error) {
+	out := new(DeleteExchangeRateResponse)
+	err := c.cc.Invoke(ctx, CatalogService_DeleteExchangeRate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetCategory(ctx context.Context, in *GetCategoryRequest, opts ...grpc.CallOption) (*GetCategoryResponse, error) {
+	out := new(GetCategoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_GetCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListCategories_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error) {
+	out := new(CreateCategoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_CreateCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) UpdateCategory(ctx context.Context, in *UpdateCategoryRequest, opts ...grpc.CallOption) (*UpdateCategoryResponse, error) {
+	out := new(UpdateCategoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_UpdateCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DeleteCategory(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*DeleteCategoryResponse, error) {
+	out := new(DeleteCategoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_DeleteCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) SetCatalogItemCategories(ctx context.Context, in *SetCatalogItemCategoriesRequest, opts ...grpc.CallOption) (*SetCatalogItemCategoriesResponse, error) {
+	out := new(SetCatalogItemCategoriesResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SetCatalogItemCategories_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListCatalogItemsByCategory(ctx context.Context, in *ListCatalogItemsByCategoryRequest, opts ...grpc.CallOption) (*ListCatalogItemsByCategoryResponse, error) {
+	out := new(ListCatalogItemsByCategoryResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListCatalogItemsByCategory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetVariant(ctx context.Context, in *GetVariantRequest, opts ...grpc.CallOption) (*GetVariantResponse, error) {
+	out := new(GetVariantResponse)
+	err := c.cc.Invoke(ctx, CatalogService_GetVariant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ListVariants(ctx context.Context, in *ListVariantsRequest, opts ...grpc.CallOption) (*ListVariantsResponse, error) {
+	out := new(ListVariantsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ListVariants_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CreateVariant(ctx context.Context, in *CreateVariantRequest, opts ...grpc.CallOption) (*CreateVariantResponse, error) {
+	out := new(CreateVariantResponse)
+	err := c.cc.Invoke(ctx, CatalogService_CreateVariant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) UpdateVariant(ctx context.Context, in *UpdateVariantRequest, opts ...grpc.CallOption) (*UpdateVariantResponse, error) {
+	out := new(UpdateVariantResponse)
+	err := c.cc.Invoke(ctx, CatalogService_UpdateVariant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) DeleteVariant(ctx context.Context, in *DeleteVariantRequest, opts ...grpc.CallOption) (*DeleteVariantResponse, error) {
+	out := new(DeleteVariantResponse)
+	err := c.cc.Invoke(ctx, CatalogService_DeleteVariant_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) SearchCatalogItems(ctx context.Context, in *SearchCatalogItemsRequest, opts ...grpc.CallOption) (*SearchCatalogItemsResponse, error) {
+	out := new(SearchCatalogItemsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SearchCatalogItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CatalogServiceServer is the server API for CatalogService service.
 // All implementations must embed UnimplementedCatalogServiceServer
 // for forward compatibility
@@ -123,7 +494,37 @@ type CatalogServiceServer interface {
 	ListCatalogItemsByIDs(context.Context, *ListCatalogItemsByIDsRequest) (*ListCatalogItemsByIDsResponse, error)
 	CreateCatalogItem(context.Context, *CreateCatalogItemRequest) (*CreateCatalogItemResponse, error)
 	UpdateCatalogItem(context.Context, *UpdateCatalogItemRequest) (*UpdateCatalogItemResponse, error)
+	// DeleteCatalogItem moves the item to the trash, from which it is purged after a retention period.
 	DeleteCatalogItem(context.Context, *DeleteCatalogItemRequest) (*DeleteCatalogItemResponse, error)
+	RestoreCatalogItem(context.Context, *RestoreCatalogItemRequest) (*RestoreCatalogItemResponse, error)
+	ListDeletedCatalogItems(context.Context, *ListDeletedCatalogItemsRequest) (*ListDeletedCatalogItemsResponse, error)
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error)
+	CommitStock(context.Context, *CommitStockRequest) (*CommitStockResponse, error)
+	WatchCatalogItems(*WatchCatalogItemsRequest, CatalogService_WatchCatalogItemsServer) error
+	// A file of catalog items is imported and exported in chunks, so that it need not fit in a message.
+	ImportCatalogItems(CatalogService_ImportCatalogItemsServer) error
+	ExportCatalogItems(*ExportCatalogItemsRequest, CatalogService_ExportCatalogItemsServer) error
+	SetCatalogItemPrices(context.Context, *SetCatalogItemPricesRequest) (*SetCatalogItemPricesResponse, error)
+	GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error)
+	SchedulePriceChange(context.Context, *SchedulePriceChangeRequest) (*SchedulePriceChangeResponse, error)
+	// The exchange rates are maintained by administrators.
+	SetExchangeRate(context.Context, *SetExchangeRateRequest) (*SetExchangeRateResponse, error)
+	ListExchangeRates(context.Context, *ListExchangeRatesRequest) (*ListExchangeRatesResponse, error)
+	DeleteExchangeRate(context.Context, *DeleteExchangeRateRequest) (*DeleteExchangeRateResponse, error)
+	GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error)
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
+	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
+	UpdateCategory(context.Context, *UpdateCategoryRequest) (*UpdateCategoryResponse, error)
+	DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteCategoryResponse, error)
+	SetCatalogItemCategories(context.Context, *SetCatalogItemCategoriesRequest) (*SetCatalogItemCategoriesResponse, error)
+	ListCatalogItemsByCategory(context.Context, *ListCatalogItemsByCategoryRequest) (*ListCatalogItemsByCategoryResponse, error)
+	GetVariant(context.Context, *GetVariantRequest) (*GetVariantResponse, error)
+	ListVariants(context.Context, *ListVariantsRequest) (*ListVariantsResponse, error)
+	CreateVariant(context.Context, *CreateVariantRequest) (*CreateVariantResponse, error)
+	UpdateVariant(context.Context, *UpdateVariantRequest) (*UpdateVariantResponse, error)
+	DeleteVariant(context.Context, *DeleteVariantRequest) (*DeleteVariantResponse, error)
+	SearchCatalogItems(context.Context, *SearchCatalogItemsRequest) (*SearchCatalogItemsResponse, error)
 	mustEmbedUnimplementedCatalogServiceServer()
 }
 
@@ -157,6 +558,114 @@ func (UnimplementedCatalogServiceServer) UpdateCatalogItem(context.Context, *Upd
 func (UnimplementedCatalogServiceServer) DeleteCatalogItem(context.Context, *DeleteCatalogItemRequest) (*DeleteCatalogItemResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCatalogItem not implemented")
 }
+
+func (UnimplementedCatalogServiceServer) RestoreCatalogItem(context.Context, *RestoreCatalogItemRequest) (*RestoreCatalogItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCatalogItem not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ListDeletedCatalogItems(context.Context, *ListDeletedCatalogItemsRequest) (*ListDeletedCatalogItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedCatalogItems not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ReleaseStock(context.Context, *ReleaseStockRequest) (*ReleaseStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseStock not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) CommitStock(context.Context, *CommitStockRequest) (*CommitStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitStock not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) WatchCatalogItems(*WatchCatalogItemsRequest, CatalogService_WatchCatalogItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCatalogItems not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ImportCatalogItems(CatalogService_ImportCatalogItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportCatalogItems not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ExportCatalogItems(*ExportCatalogItemsRequest, CatalogService_ExportCatalogItemsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportCatalogItems not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) SetCatalogItemPrices(context.Context, *SetCatalogItemPricesRequest) (*SetCatalogItemPricesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCatalogItemPrices not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) SchedulePriceChange(context.Context, *SchedulePriceChangeRequest) (*SchedulePriceChangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SchedulePriceChange not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) SetExchangeRate(context.Context, *SetExchangeRateRequest) (*SetExchangeRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetExchangeRate not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ListExchangeRates(context.Context, *ListExchangeRatesRequest) (*ListExchangeRatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExchangeRates not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) DeleteExchangeRate(context.Context, *DeleteExchangeRateRequest) (*DeleteExchangeRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteExchangeRate not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) GetCategory(context.Context, *GetCategoryRequest) (*GetCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) UpdateCategory(context.Context, *UpdateCategoryRequest) (*UpdateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCategory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) DeleteCategory(context.Context, *DeleteCategoryRequest) (*DeleteCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) SetCatalogItemCategories(context.Context, *SetCatalogItemCategoriesRequest) (*SetCatalogItemCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetCatalogItemCategories not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ListCatalogItemsByCategory(context.Context, *ListCatalogItemsByCategoryRequest) (*ListCatalogItemsByCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCatalogItemsByCategory not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) GetVariant(context.Context, *GetVariantRequest) (*GetVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVariant not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) ListVariants(context.Context, *ListVariantsRequest) (*ListVariantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVariants not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) CreateVariant(context.Context, *CreateVariantRequest) (*CreateVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVariant not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) UpdateVariant(context.Context, *UpdateVariantRequest) (*UpdateVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVariant not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) DeleteVariant(context.Context, *DeleteVariantRequest) (*DeleteVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVariant not implemented")
+}
+
+func (UnimplementedCatalogServiceServer) SearchCatalogItems(context.Context, *SearchCatalogItemsRequest) (*SearchCatalogItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCatalogItems not implemented")
+}
 func (UnimplementedCatalogServiceServer) mustEmbedUnimplementedCatalogServiceServer() {}
 
 // UnsafeCatalogServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -296,13 +805,513 @@ func _CatalogService_DeleteCatalogItem_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
-// CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var CatalogService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "catalog.CatalogService",
-	HandlerType: (*CatalogServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
+func _CatalogService_RestoreCatalogItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCatalogItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).RestoreCatalogItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_RestoreCatalogItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).RestoreCatalogItem(ctx, req.(*RestoreCatalogItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListDeletedCatalogItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedCatalogItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListDeletedCatalogItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListDeletedCatalogItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListDeletedCatalogItems(ctx, req.(*ListDeletedCatalogItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ReleaseStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ReleaseStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ReleaseStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ReleaseStock(ctx, req.(*ReleaseStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CommitStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CommitStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CommitStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CommitStock(ctx, req.(*CommitStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_WatchCatalogItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCatalogItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServiceServer).WatchCatalogItems(m, &catalogServiceWatchCatalogItemsServer{stream})
+}
+
+type CatalogService_WatchCatalogItemsServer interface {
+	Send(*WatchCatalogItemsResponse) error
+	grpc.ServerStream
+}
+
+type catalogServiceWatchCatalogItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogServiceWatchCatalogItemsServer) Send(m *WatchCatalogItemsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CatalogService_ImportCatalogItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CatalogServiceServer).ImportCatalogItems(&catalogServiceImportCatalogItemsServer{stream})
+}
+
+type CatalogService_ImportCatalogItemsServer interface {
+	SendAndClose(*ImportCatalogItemsResponse) error
+	Recv() (*ImportCatalogItemsRequest, error)
+	grpc.ServerStream
+}
+
+type catalogServiceImportCatalogItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogServiceImportCatalogItemsServer) SendAndClose(m *ImportCatalogItemsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *catalogServiceImportCatalogItemsServer) Recv() (*ImportCatalogItemsRequest, error) {
+	m := new(ImportCatalogItemsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CatalogService_ExportCatalogItems_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportCatalogItemsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatalogServiceServer).ExportCatalogItems(m, &catalogServiceExportCatalogItemsServer{stream})
+}
+
+type CatalogService_ExportCatalogItemsServer interface {
+	Send(*ExportCatalogItemsResponse) error
+	grpc.ServerStream
+}
+
+type catalogServiceExportCatalogItemsServer struct {
+	grpc.ServerStream
+}
+
+func (x *catalogServiceExportCatalogItemsServer) Send(m *ExportCatalogItemsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CatalogService_SetCatalogItemPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCatalogItemPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SetCatalogItemPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SetCatalogItemPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SetCatalogItemPrices(ctx, req.(*SetCatalogItemPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetPriceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetPriceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetPriceHistory(ctx, req.(*GetPriceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_SchedulePriceChange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SchedulePriceChangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SchedulePriceChange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SchedulePriceChange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SchedulePriceChange(ctx, req.(*SchedulePriceChangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_SetExchangeRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetExchangeRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SetExchangeRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SetExchangeRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SetExchangeRate(ctx, req.(*SetExchangeRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListExchangeRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExchangeRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListExchangeRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListExchangeRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListExchangeRates(ctx, req.(*ListExchangeRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DeleteExchangeRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteExchangeRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DeleteExchangeRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_DeleteExchangeRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DeleteExchangeRate(ctx, req.(*DeleteExchangeRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetCategory(ctx, req.(*GetCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_UpdateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).UpdateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_UpdateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).UpdateCategory(ctx, req.(*UpdateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DeleteCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DeleteCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_DeleteCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DeleteCategory(ctx, req.(*DeleteCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_SetCatalogItemCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCatalogItemCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SetCatalogItemCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SetCatalogItemCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SetCatalogItemCategories(ctx, req.(*SetCatalogItemCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListCatalogItemsByCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCatalogItemsByCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListCatalogItemsByCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListCatalogItemsByCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListCatalogItemsByCategory(ctx, req.(*ListCatalogItemsByCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetVariant(ctx, req.(*GetVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ListVariants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVariantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ListVariants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ListVariants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ListVariants(ctx, req.(*ListVariantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CreateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CreateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CreateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CreateVariant(ctx, req.(*CreateVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_UpdateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).UpdateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_UpdateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).UpdateVariant(ctx, req.(*UpdateVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_DeleteVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).DeleteVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_DeleteVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).DeleteVariant(ctx, req.(*DeleteVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_SearchCatalogItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCatalogItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SearchCatalogItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SearchCatalogItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SearchCatalogItems(ctx, req.(*SearchCatalogItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CatalogService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "catalog.CatalogService",
+	HandlerType: (*CatalogServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "GetCatalogItem",
 			Handler:    _CatalogService_GetCatalogItem_Handler,
@@ -331,7 +1340,119 @@ var CatalogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCatalogItem",
 			Handler:    _CatalogService_DeleteCatalogItem_Handler,
 		},
+		{
+			MethodName: "RestoreCatalogItem",
+			Handler:    _CatalogService_RestoreCatalogItem_Handler,
+		},
+		{
+			MethodName: "ListDeletedCatalogItems",
+			Handler:    _CatalogService_ListDeletedCatalogItems_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _CatalogService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "ReleaseStock",
+			Handler:    _CatalogService_ReleaseStock_Handler,
+		},
+		{
+			MethodName: "CommitStock",
+			Handler:    _CatalogService_CommitStock_Handler,
+		},
+		{
+			MethodName: "SetCatalogItemPrices",
+			Handler:    _CatalogService_SetCatalogItemPrices_Handler,
+		},
+		{
+			MethodName: "GetPriceHistory",
+			Handler:    _CatalogService_GetPriceHistory_Handler,
+		},
+		{
+			MethodName: "SchedulePriceChange",
+			Handler:    _CatalogService_SchedulePriceChange_Handler,
+		},
+		{
+			MethodName: "SetExchangeRate",
+			Handler:    _CatalogService_SetExchangeRate_Handler,
+		},
+		{
+			MethodName: "ListExchangeRates",
+			Handler:    _CatalogService_ListExchangeRates_Handler,
+		},
+		{
+			MethodName: "DeleteExchangeRate",
+			Handler:    _CatalogService_DeleteExchangeRate_Handler,
+		},
+		{
+			MethodName: "GetCategory",
+			Handler:    _CatalogService_GetCategory_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _CatalogService_ListCategories_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _CatalogService_CreateCategory_Handler,
+		},
+		{
+			MethodName: "UpdateCategory",
+			Handler:    _CatalogService_UpdateCategory_Handler,
+		},
+		{
+			MethodName: "DeleteCategory",
+			Handler:    _CatalogService_DeleteCategory_Handler,
+		},
+		{
+			MethodName: "SetCatalogItemCategories",
+			Handler:    _CatalogService_SetCatalogItemCategories_Handler,
+		},
+		{
+			MethodName: "ListCatalogItemsByCategory",
+			Handler:    _CatalogService_ListCatalogItemsByCategory_Handler,
+		},
+		{
+			MethodName: "GetVariant",
+			Handler:    _CatalogService_GetVariant_Handler,
+		},
+		{
+			MethodName: "ListVariants",
+			Handler:    _CatalogService_ListVariants_Handler,
+		},
+		{
+			MethodName: "CreateVariant",
+			Handler:    _CatalogService_CreateVariant_Handler,
+		},
+		{
+			MethodName: "UpdateVariant",
+			Handler:    _CatalogService_UpdateVariant_Handler,
+		},
+		{
+			MethodName: "DeleteVariant",
+			Handler:    _CatalogService_DeleteVariant_Handler,
+		},
+		{
+			MethodName: "SearchCatalogItems",
+			Handler:    _CatalogService_SearchCatalogItems_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchCatalogItems",
+			Handler:       _CatalogService_WatchCatalogItems_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportCatalogItems",
+			Handler:       _CatalogService_ImportCatalogItems_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportCatalogItems",
+			Handler:       _CatalogService_ExportCatalogItems_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/catalog.proto",
+	Metadata: "catalog/proto/catalog.proto",
 }
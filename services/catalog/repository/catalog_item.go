@@ -3,16 +3,43 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
 )
 
+// ErrVersionConflict is returned by Update when the stored version of the item no longer
+// matches the version the change was computed from.
+var ErrVersionConflict = entity.NewError(entity.ErrConflict, "catalog item has been changed concurrently")
+
+// CatalogItemRepository stores the catalog items. The items in the trash are hidden from all the methods
+// but GetByExternalKey, ListDeleted, ListDeletedBefore, Restore and Purge. The external keys are unique
+// among all the items, including those in the trash.
 type CatalogItemRepository interface {
 	Get(ctx context.Context, id string) (*entity.CatalogItem, error)
-	List(ctx context.Context) ([]entity.CatalogItem, error)
+	// GetByExternalKey gets the item with the key of its supplier, even if it is in the trash,
+	// in which case its DeletedAt is set, since the key cannot be given to another item.
+	GetByExternalKey(ctx context.Context, key string) (*entity.CatalogItem, error)
+	List(ctx context.Context, page Page) ([]entity.CatalogItem, PageInfo, error)
 	ListByName(ctx context.Context, name string) ([]entity.CatalogItem, error)
 	ListByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error)
+	// ListByCategory lists the items in the category or any category under it.
+	ListByCategory(ctx context.Context, categoryID string, page Page) ([]entity.CatalogItem, PageInfo, error)
 	Create(ctx context.Context, item entity.CatalogItem) error
-	Update(ctx context.Context, item entity.CatalogItem) error
-	Delete(ctx context.Context, id string) error
+	// Update stores the fields of the item, or all of them when none is given, if its stored version is still
+	// item.Version, and increments the stored version.
+	Update(ctx context.Context, item entity.CatalogItem, fields ...string) error
+	// IncrementVersion increments the stored version of the item if it is still item.Version, when the item
+	// is changed in the tables of its prices rather than in its own.
+	IncrementVersion(ctx context.Context, item entity.CatalogItem) error
+	// Delete moves the item to the trash at deletedAt, and increments its version.
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	// Restore takes the item out of the trash, and increments its version.
+	Restore(ctx context.Context, id string) error
+	// ListDeleted lists the items in the trash.
+	ListDeleted(ctx context.Context, page Page) ([]entity.CatalogItem, PageInfo, error)
+	// ListDeletedBefore returns the IDs of at most limit items moved to the trash before before, oldest first.
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// Purge deletes the item for good if it was moved to the trash before before.
+	Purge(ctx context.Context, id string, before time.Time) error
 }
@@ -0,0 +1,25 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// CategoryRepository reads and writes the category tree and the categories of catalog items.
+// Create and Update keep the ancestors of every category, so that the descendants of a category
+// are read without walking the tree. Update moves the subtree of a category whose parent changed.
+type CategoryRepository interface {
+	Get(ctx context.Context, id string) (*entity.Category, error)
+	List(ctx context.Context) ([]entity.Category, error)
+	Create(ctx context.Context, category entity.Category) error
+	Update(ctx context.Context, category entity.Category) error
+	// Delete deletes a category without children, along with the assignments of items to it.
+	Delete(ctx context.Context, id string) error
+	// ListDescendantIDs returns the IDs of the category and all categories under it.
+	ListDescendantIDs(ctx context.Context, id string) ([]string, error)
+	// ListItemCategoryIDs returns the categories of the items, keyed by item ID.
+	ListItemCategoryIDs(ctx context.Context, itemIDs []string) (map[string][]string, error)
+	ReplaceItemCategories(ctx context.Context, itemID string, categoryIDs []string) error
+}
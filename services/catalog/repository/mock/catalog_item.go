@@ -7,9 +7,11 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
 )
 
 // MockCatalogItemRepository is a mock of CatalogItemRepository interface.
@@ -50,17 +52,17 @@ func (mr *MockCatalogItemRepositoryMockRecorder) Create(ctx, item interface{}) *
 }
 
 // Delete mocks base method.
-func (m *MockCatalogItemRepository) Delete(ctx context.Context, id string) error {
+func (m *MockCatalogItemRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCatalogItemRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockCatalogItemRepositoryMockRecorder) Delete(ctx, id, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCatalogItemRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCatalogItemRepository)(nil).Delete), ctx, id, deletedAt)
 }
 
 // Get mocks base method.
@@ -78,19 +80,65 @@ func (mr *MockCatalogItemRepositoryMockRecorder) Get(ctx, id interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCatalogItemRepository)(nil).Get), ctx, id)
 }
 
-// List mocks base method.
-func (m *MockCatalogItemRepository) List(ctx context.Context) ([]entity.CatalogItem, error) {
+// GetByExternalKey mocks base method.
+func (m *MockCatalogItemRepository) GetByExternalKey(ctx context.Context, key string) (*entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret := m.ctrl.Call(m, "GetByExternalKey", ctx, key)
+	ret0, _ := ret[0].(*entity.CatalogItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetByExternalKey indicates an expected call of GetByExternalKey.
+func (mr *MockCatalogItemRepositoryMockRecorder) GetByExternalKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByExternalKey", reflect.TypeOf((*MockCatalogItemRepository)(nil).GetByExternalKey), ctx, key)
+}
+
+// IncrementVersion mocks base method.
+func (m *MockCatalogItemRepository) IncrementVersion(ctx context.Context, item entity.CatalogItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementVersion", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementVersion indicates an expected call of IncrementVersion.
+func (mr *MockCatalogItemRepositoryMockRecorder) IncrementVersion(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementVersion", reflect.TypeOf((*MockCatalogItemRepository)(nil).IncrementVersion), ctx, item)
+}
+
+// List mocks base method.
+func (m *MockCatalogItemRepository) List(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, page)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // List indicates an expected call of List.
-func (mr *MockCatalogItemRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockCatalogItemRepositoryMockRecorder) List(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCatalogItemRepository)(nil).List), ctx, page)
+}
+
+// ListByCategory mocks base method.
+func (m *MockCatalogItemRepository) ListByCategory(ctx context.Context, categoryID string, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCategory", ctx, categoryID, page)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByCategory indicates an expected call of ListByCategory.
+func (mr *MockCatalogItemRepositoryMockRecorder) ListByCategory(ctx, categoryID, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCatalogItemRepository)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCategory", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListByCategory), ctx, categoryID, page)
 }
 
 // ListByIDs mocks base method.
@@ -123,16 +171,80 @@ func (mr *MockCatalogItemRepositoryMockRecorder) ListByName(ctx, name interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByName", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListByName), ctx, name)
 }
 
+// ListDeleted mocks base method.
+func (m *MockCatalogItemRepository) ListDeleted(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, page)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockCatalogItemRepositoryMockRecorder) ListDeleted(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListDeleted), ctx, page)
+}
+
+// ListDeletedBefore mocks base method.
+func (m *MockCatalogItemRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBefore", ctx, before, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedBefore indicates an expected call of ListDeletedBefore.
+func (mr *MockCatalogItemRepositoryMockRecorder) ListDeletedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBefore", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListDeletedBefore), ctx, before, limit)
+}
+
+// Purge mocks base method.
+func (m *MockCatalogItemRepository) Purge(ctx context.Context, id string, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockCatalogItemRepositoryMockRecorder) Purge(ctx, id, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockCatalogItemRepository)(nil).Purge), ctx, id, before)
+}
+
+// Restore mocks base method.
+func (m *MockCatalogItemRepository) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCatalogItemRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCatalogItemRepository)(nil).Restore), ctx, id)
+}
+
 // Update mocks base method.
-func (m *MockCatalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) error {
+func (m *MockCatalogItemRepository) Update(ctx context.Context, item entity.CatalogItem, fields ...string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, item)
+	varargs := []interface{}{ctx, item}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockCatalogItemRepositoryMockRecorder) Update(ctx, item interface{}) *gomock.Call {
+func (mr *MockCatalogItemRepositoryMockRecorder) Update(ctx, item interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCatalogItemRepository)(nil).Update), ctx, item)
+	varargs := append([]interface{}{ctx, item}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCatalogItemRepository)(nil).Update), varargs...)
 }
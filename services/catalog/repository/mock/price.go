@@ -0,0 +1,225 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: price.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// MockPriceRepository is a mock of PriceRepository interface.
+type MockPriceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceRepositoryMockRecorder
+}
+
+// MockPriceRepositoryMockRecorder is the mock recorder for MockPriceRepository.
+type MockPriceRepositoryMockRecorder struct {
+	mock *MockPriceRepository
+}
+
+// NewMockPriceRepository creates a new mock instance.
+func NewMockPriceRepository(ctrl *gomock.Controller) *MockPriceRepository {
+	mock := &MockPriceRepository{ctrl: ctrl}
+	mock.recorder = &MockPriceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceRepository) EXPECT() *MockPriceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddPriceHistoryEntry mocks base method.
+func (m *MockPriceRepository) AddPriceHistoryEntry(ctx context.Context, entry entity.PriceHistoryEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPriceHistoryEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPriceHistoryEntry indicates an expected call of AddPriceHistoryEntry.
+func (mr *MockPriceRepositoryMockRecorder) AddPriceHistoryEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPriceHistoryEntry", reflect.TypeOf((*MockPriceRepository)(nil).AddPriceHistoryEntry), ctx, entry)
+}
+
+// DeleteExchangeRate mocks base method.
+func (m *MockPriceRepository) DeleteExchangeRate(ctx context.Context, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExchangeRate", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExchangeRate indicates an expected call of DeleteExchangeRate.
+func (mr *MockPriceRepositoryMockRecorder) DeleteExchangeRate(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExchangeRate", reflect.TypeOf((*MockPriceRepository)(nil).DeleteExchangeRate), ctx, from, to)
+}
+
+// DeletePriceHistory mocks base method.
+func (m *MockPriceRepository) DeletePriceHistory(ctx context.Context, itemID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePriceHistory", ctx, itemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePriceHistory indicates an expected call of DeletePriceHistory.
+func (mr *MockPriceRepositoryMockRecorder) DeletePriceHistory(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePriceHistory", reflect.TypeOf((*MockPriceRepository)(nil).DeletePriceHistory), ctx, itemID)
+}
+
+// DeletePriceHistoryEntry mocks base method.
+func (m *MockPriceRepository) DeletePriceHistoryEntry(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePriceHistoryEntry", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePriceHistoryEntry indicates an expected call of DeletePriceHistoryEntry.
+func (mr *MockPriceRepositoryMockRecorder) DeletePriceHistoryEntry(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePriceHistoryEntry", reflect.TypeOf((*MockPriceRepository)(nil).DeletePriceHistoryEntry), ctx, id)
+}
+
+// GetExchangeRate mocks base method.
+func (m *MockPriceRepository) GetExchangeRate(ctx context.Context, from, to string) (*entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRate", ctx, from, to)
+	ret0, _ := ret[0].(*entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchangeRate indicates an expected call of GetExchangeRate.
+func (mr *MockPriceRepositoryMockRecorder) GetExchangeRate(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRate", reflect.TypeOf((*MockPriceRepository)(nil).GetExchangeRate), ctx, from, to)
+}
+
+// GetPriceAsOf mocks base method.
+func (m *MockPriceRepository) GetPriceAsOf(ctx context.Context, itemID string, asOf time.Time) (*entity.PriceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceAsOf", ctx, itemID, asOf)
+	ret0, _ := ret[0].(*entity.PriceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceAsOf indicates an expected call of GetPriceAsOf.
+func (mr *MockPriceRepositoryMockRecorder) GetPriceAsOf(ctx, itemID, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceAsOf", reflect.TypeOf((*MockPriceRepository)(nil).GetPriceAsOf), ctx, itemID, asOf)
+}
+
+// ListDuePriceChanges mocks base method.
+func (m *MockPriceRepository) ListDuePriceChanges(ctx context.Context, now time.Time, limit int) ([]entity.PriceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuePriceChanges", ctx, now, limit)
+	ret0, _ := ret[0].([]entity.PriceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuePriceChanges indicates an expected call of ListDuePriceChanges.
+func (mr *MockPriceRepositoryMockRecorder) ListDuePriceChanges(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuePriceChanges", reflect.TypeOf((*MockPriceRepository)(nil).ListDuePriceChanges), ctx, now, limit)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockPriceRepository) ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", ctx)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockPriceRepositoryMockRecorder) ListExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockPriceRepository)(nil).ListExchangeRates), ctx)
+}
+
+// ListPriceHistory mocks base method.
+func (m *MockPriceRepository) ListPriceHistory(ctx context.Context, itemID string) ([]entity.PriceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceHistory", ctx, itemID)
+	ret0, _ := ret[0].([]entity.PriceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPriceHistory indicates an expected call of ListPriceHistory.
+func (mr *MockPriceRepositoryMockRecorder) ListPriceHistory(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceHistory", reflect.TypeOf((*MockPriceRepository)(nil).ListPriceHistory), ctx, itemID)
+}
+
+// ListPrices mocks base method.
+func (m *MockPriceRepository) ListPrices(ctx context.Context, itemIDs []string) (map[string][]entity.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPrices", ctx, itemIDs)
+	ret0, _ := ret[0].(map[string][]entity.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPrices indicates an expected call of ListPrices.
+func (mr *MockPriceRepositoryMockRecorder) ListPrices(ctx, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrices", reflect.TypeOf((*MockPriceRepository)(nil).ListPrices), ctx, itemIDs)
+}
+
+// MarkPriceChangeApplied mocks base method.
+func (m *MockPriceRepository) MarkPriceChangeApplied(ctx context.Context, id string, appliedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPriceChangeApplied", ctx, id, appliedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPriceChangeApplied indicates an expected call of MarkPriceChangeApplied.
+func (mr *MockPriceRepositoryMockRecorder) MarkPriceChangeApplied(ctx, id, appliedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPriceChangeApplied", reflect.TypeOf((*MockPriceRepository)(nil).MarkPriceChangeApplied), ctx, id, appliedAt)
+}
+
+// ReplacePrices mocks base method.
+func (m *MockPriceRepository) ReplacePrices(ctx context.Context, itemID string, prices []entity.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePrices", ctx, itemID, prices)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePrices indicates an expected call of ReplacePrices.
+func (mr *MockPriceRepositoryMockRecorder) ReplacePrices(ctx, itemID, prices interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePrices", reflect.TypeOf((*MockPriceRepository)(nil).ReplacePrices), ctx, itemID, prices)
+}
+
+// UpsertExchangeRate mocks base method.
+func (m *MockPriceRepository) UpsertExchangeRate(ctx context.Context, rate entity.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertExchangeRate", ctx, rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertExchangeRate indicates an expected call of UpsertExchangeRate.
+func (mr *MockPriceRepositoryMockRecorder) UpsertExchangeRate(ctx, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertExchangeRate", reflect.TypeOf((*MockPriceRepository)(nil).UpsertExchangeRate), ctx, rate)
+}
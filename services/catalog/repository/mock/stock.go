@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: stock.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// MockStockRepository is a mock of StockRepository interface.
+type MockStockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockRepositoryMockRecorder
+}
+
+// MockStockRepositoryMockRecorder is the mock recorder for MockStockRepository.
+type MockStockRepositoryMockRecorder struct {
+	mock *MockStockRepository
+}
+
+// NewMockStockRepository creates a new mock instance.
+func NewMockStockRepository(ctrl *gomock.Controller) *MockStockRepository {
+	mock := &MockStockRepository{ctrl: ctrl}
+	mock.recorder = &MockStockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockRepository) EXPECT() *MockStockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateReservations mocks base method.
+func (m *MockStockRepository) CreateReservations(ctx context.Context, reservations []entity.StockReservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReservations", ctx, reservations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReservations indicates an expected call of CreateReservations.
+func (mr *MockStockRepositoryMockRecorder) CreateReservations(ctx, reservations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReservations", reflect.TypeOf((*MockStockRepository)(nil).CreateReservations), ctx, reservations)
+}
+
+// LockItems mocks base method.
+func (m *MockStockRepository) LockItems(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockItems", ctx, ids)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockItems indicates an expected call of LockItems.
+func (mr *MockStockRepositoryMockRecorder) LockItems(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockItems", reflect.TypeOf((*MockStockRepository)(nil).LockItems), ctx, ids)
+}
+
+// LockReservations mocks base method.
+func (m *MockStockRepository) LockReservations(ctx context.Context, reservationID string) ([]entity.StockReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockReservations", ctx, reservationID)
+	ret0, _ := ret[0].([]entity.StockReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockReservations indicates an expected call of LockReservations.
+func (mr *MockStockRepositoryMockRecorder) LockReservations(ctx, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockReservations", reflect.TypeOf((*MockStockRepository)(nil).LockReservations), ctx, reservationID)
+}
+
+// LockVariants mocks base method.
+func (m *MockStockRepository) LockVariants(ctx context.Context, skus []string) ([]entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockVariants", ctx, skus)
+	ret0, _ := ret[0].([]entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockVariants indicates an expected call of LockVariants.
+func (mr *MockStockRepositoryMockRecorder) LockVariants(ctx, skus interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockVariants", reflect.TypeOf((*MockStockRepository)(nil).LockVariants), ctx, skus)
+}
+
+// UpdateReservationStatus mocks base method.
+func (m *MockStockRepository) UpdateReservationStatus(ctx context.Context, reservationID string, status entity.ReservationStatus, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReservationStatus", ctx, reservationID, status, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReservationStatus indicates an expected call of UpdateReservationStatus.
+func (mr *MockStockRepositoryMockRecorder) UpdateReservationStatus(ctx, reservationID, status, updatedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReservationStatus", reflect.TypeOf((*MockStockRepository)(nil).UpdateReservationStatus), ctx, reservationID, status, updatedAt)
+}
+
+// UpdateStock mocks base method.
+func (m *MockStockRepository) UpdateStock(ctx context.Context, item entity.CatalogItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockStockRepositoryMockRecorder) UpdateStock(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockStockRepository)(nil).UpdateStock), ctx, item)
+}
+
+// UpdateVariantStock mocks base method.
+func (m *MockStockRepository) UpdateVariantStock(ctx context.Context, variant entity.Variant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariantStock", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVariantStock indicates an expected call of UpdateVariantStock.
+func (mr *MockStockRepositoryMockRecorder) UpdateVariantStock(ctx, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariantStock", reflect.TypeOf((*MockStockRepository)(nil).UpdateVariantStock), ctx, variant)
+}
@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: variant.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// MockVariantRepository is a mock of VariantRepository interface.
+type MockVariantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVariantRepositoryMockRecorder
+}
+
+// MockVariantRepositoryMockRecorder is the mock recorder for MockVariantRepository.
+type MockVariantRepositoryMockRecorder struct {
+	mock *MockVariantRepository
+}
+
+// NewMockVariantRepository creates a new mock instance.
+func NewMockVariantRepository(ctrl *gomock.Controller) *MockVariantRepository {
+	mock := &MockVariantRepository{ctrl: ctrl}
+	mock.recorder = &MockVariantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVariantRepository) EXPECT() *MockVariantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockVariantRepository) Create(ctx context.Context, variant entity.Variant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockVariantRepositoryMockRecorder) Create(ctx, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVariantRepository)(nil).Create), ctx, variant)
+}
+
+// Delete mocks base method.
+func (m *MockVariantRepository) Delete(ctx context.Context, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockVariantRepositoryMockRecorder) Delete(ctx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockVariantRepository)(nil).Delete), ctx, sku)
+}
+
+// DeleteByCatalogItemID mocks base method.
+func (m *MockVariantRepository) DeleteByCatalogItemID(ctx context.Context, itemID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByCatalogItemID", ctx, itemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByCatalogItemID indicates an expected call of DeleteByCatalogItemID.
+func (mr *MockVariantRepositoryMockRecorder) DeleteByCatalogItemID(ctx, itemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByCatalogItemID", reflect.TypeOf((*MockVariantRepository)(nil).DeleteByCatalogItemID), ctx, itemID)
+}
+
+// Get mocks base method.
+func (m *MockVariantRepository) Get(ctx context.Context, sku string) (*entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, sku)
+	ret0, _ := ret[0].(*entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockVariantRepositoryMockRecorder) Get(ctx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockVariantRepository)(nil).Get), ctx, sku)
+}
+
+// ListByCatalogItemIDs mocks base method.
+func (m *MockVariantRepository) ListByCatalogItemIDs(ctx context.Context, itemIDs []string) (map[string][]entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCatalogItemIDs", ctx, itemIDs)
+	ret0, _ := ret[0].(map[string][]entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCatalogItemIDs indicates an expected call of ListByCatalogItemIDs.
+func (mr *MockVariantRepositoryMockRecorder) ListByCatalogItemIDs(ctx, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCatalogItemIDs", reflect.TypeOf((*MockVariantRepository)(nil).ListByCatalogItemIDs), ctx, itemIDs)
+}
+
+// Update mocks base method.
+func (m *MockVariantRepository) Update(ctx context.Context, variant entity.Variant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockVariantRepositoryMockRecorder) Update(ctx, variant interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVariantRepository)(nil).Update), ctx, variant)
+}
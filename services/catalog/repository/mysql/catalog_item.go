@@ -3,7 +3,10 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
@@ -26,9 +29,9 @@ func (cr *catalogItemRepository) Get(ctx context.Context, id string) (*entity.Ca
 	}
 
 	query := `
-	SELECT id, name, price
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version
 	FROM CatalogItems
-	WHERE id = ?
+	WHERE id = ? AND deleted_at IS NULL
 	LIMIT 1
 	`
 
@@ -37,27 +40,87 @@ func (cr *catalogItemRepository) Get(ctx context.Context, id string) (*entity.Ca
 	if err := row.Scan(
 		&item.ID,
 		&item.Name,
-		&item.Price,
+		&item.ExternalKey,
+		&item.Price.Amount,
+		&item.Price.Currency,
+		&item.Stock,
+		&item.Reserved,
+		&item.Version,
 	); err != nil {
-		return nil, err
+		return nil, translateError(err, "catalog item not found")
 	}
 	return &item, nil
 }
 
-func (cr *catalogItemRepository) List(ctx context.Context) ([]entity.CatalogItem, error) {
+func (cr *catalogItemRepository) GetByExternalKey(ctx context.Context, key string) (*entity.CatalogItem, error) {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
 	}
 
 	query := `
-	SELECT id, name, price
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version, deleted_at
 	FROM CatalogItems
+	WHERE external_key = ?
+	LIMIT 1
 	`
 
-	rows, err := executor.QueryContext(ctx, query)
+	row := executor.QueryRowContext(ctx, query, key)
+	var item entity.CatalogItem
+	var deletedAt sql.NullTime
+	if err := row.Scan(
+		&item.ID,
+		&item.Name,
+		&item.ExternalKey,
+		&item.Price.Amount,
+		&item.Price.Currency,
+		&item.Stock,
+		&item.Reserved,
+		&item.Version,
+		&deletedAt,
+	); err != nil {
+		return nil, translateError(err, "catalog item not found")
+	}
+	if deletedAt.Valid {
+		item.DeletedAt = &deletedAt.Time
+	}
+	return &item, nil
+}
+
+func (cr *catalogItemRepository) List(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	c, err := decodeCursor(page.Token)
 	if err != nil {
-		return nil, err
+		return nil, repository.PageInfo{}, err
+	}
+	limit := page.Limit()
+
+	query := `
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version
+	FROM CatalogItems
+	WHERE deleted_at IS NULL
+	`
+	args := make([]interface{}, 0, 2) //nolint:gomnd // cursor and limit
+	switch {
+	case c == nil:
+		query += `ORDER BY id ASC`
+	case c.Backward:
+		query += `AND id < ? ORDER BY id DESC`
+		args = append(args, c.ID)
+	default:
+		query += `AND id > ? ORDER BY id ASC`
+		args = append(args, c.ID)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
 	}
 	defer rows.Close()
 
@@ -67,17 +130,89 @@ func (cr *catalogItemRepository) List(ctx context.Context) ([]entity.CatalogItem
 		if err = rows.Scan(
 			&item.ID,
 			&item.Name,
-			&item.Price,
+			&item.ExternalKey,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&item.Version,
 		); err != nil {
-			return nil, err
+			return nil, repository.PageInfo{}, err
 		}
 		items = append(items, item)
 	}
 	if err = rows.Err(); err != nil {
-		return nil, err
+		return nil, repository.PageInfo{}, err
 	}
 
-	return items, nil
+	items, info := paginate(items, limit, c, func(item entity.CatalogItem) string { return item.ID })
+	return items, info, nil
+}
+
+func (cr *catalogItemRepository) ListByCategory(ctx context.Context, categoryID string, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	c, err := decodeCursor(page.Token)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	limit := page.Limit()
+
+	// An item in several categories under the category is listed once.
+	query := `
+	SELECT DISTINCT ci.id, ci.name, COALESCE(ci.external_key, ''), ci.price_amount, ci.price_currency, ci.stock, ci.reserved, ci.version
+	FROM CatalogItems ci
+	JOIN CatalogItemCategories cic ON cic.catalog_item_id = ci.id
+	JOIN CategoryClosure cc ON cc.descendant_id = cic.category_id
+	WHERE cc.ancestor_id = ? AND ci.deleted_at IS NULL
+	`
+	args := make([]interface{}, 0, 3) //nolint:gomnd // category, cursor and limit
+	args = append(args, categoryID)
+	switch {
+	case c == nil:
+		query += `ORDER BY ci.id ASC`
+	case c.Backward:
+		query += `AND ci.id < ? ORDER BY ci.id DESC`
+		args = append(args, c.ID)
+	default:
+		query += `AND ci.id > ? ORDER BY ci.id ASC`
+		args = append(args, c.ID)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var items []entity.CatalogItem
+	for rows.Next() {
+		var item entity.CatalogItem
+		if err = rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.ExternalKey,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&item.Version,
+		); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	items, info := paginate(items, limit, c, func(item entity.CatalogItem) string { return item.ID })
+	return items, info, nil
 }
 
 func (cr *catalogItemRepository) ListByName(ctx context.Context, name string) ([]entity.CatalogItem, error) {
@@ -87,9 +222,9 @@ func (cr *catalogItemRepository) ListByName(ctx context.Context, name string) ([
 	}
 
 	query := `
-	SELECT id, name, price
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version
 	FROM CatalogItems
-	WHERE name LIKE ?
+	WHERE name LIKE ? AND deleted_at IS NULL
 	`
 
 	rows, err := executor.QueryContext(ctx, query, "%"+name+"%")
@@ -104,7 +239,12 @@ func (cr *catalogItemRepository) ListByName(ctx context.Context, name string) ([
 		if err = rows.Scan(
 			&item.ID,
 			&item.Name,
-			&item.Price,
+			&item.ExternalKey,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&item.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -131,9 +271,9 @@ func (cr *catalogItemRepository) ListByIDs(ctx context.Context, ids []string) ([
 	}
 
 	query := `
-	SELECT id, name, price
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version
 	FROM CatalogItems
-	WHERE id IN (` + strings.Join(placeholders, ",") + `)
+	WHERE id IN (` + strings.Join(placeholders, ",") + `) AND deleted_at IS NULL
 	`
 
 	rows, err := executor.QueryContext(ctx, query, args...)
@@ -148,7 +288,12 @@ func (cr *catalogItemRepository) ListByIDs(ctx context.Context, ids []string) ([
 		if err = rows.Scan(
 			&item.ID,
 			&item.Name,
-			&item.Price,
+			&item.ExternalKey,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&item.Version,
 		); err != nil {
 			return nil, err
 		}
@@ -169,9 +314,9 @@ func (cr *catalogItemRepository) Create(ctx context.Context, item entity.Catalog
 
 	query := `
 	INSERT INTO CatalogItems (
-	id, name, price
+	id, name, external_key, price_amount, price_currency, stock, version
 	)
-	VALUES (?, ?, ?)
+	VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?)
 	`
 
 	if _, err := executor.ExecContext(
@@ -179,14 +324,84 @@ func (cr *catalogItemRepository) Create(ctx context.Context, item entity.Catalog
 		query,
 		item.ID,
 		item.Name,
-		item.Price,
+		item.ExternalKey,
+		item.Price.Amount,
+		item.Price.Currency,
+		item.Stock,
+		item.Version,
 	); err != nil {
+		return translateError(err, "catalog item not found")
+	}
+	return nil
+}
+
+// catalogItemColumn is the columns an update of a field of an item sets.
+type catalogItemColumn struct {
+	field  string
+	set    string
+	values func(item entity.CatalogItem) []interface{}
+}
+
+// catalogItemColumns are the fields of an item an update can set, in the order they are set.
+var catalogItemColumns = []catalogItemColumn{
+	{entity.CatalogItemFieldName, "name = ?", func(item entity.CatalogItem) []interface{} {
+		return []interface{}{item.Name}
+	}},
+	{entity.CatalogItemFieldPrice, "price_amount = ?, price_currency = ?", func(item entity.CatalogItem) []interface{} {
+		return []interface{}{item.Price.Amount, item.Price.Currency}
+	}},
+	{entity.CatalogItemFieldStock, "stock = ?", func(item entity.CatalogItem) []interface{} {
+		return []interface{}{item.Stock}
+	}},
+}
+
+func (cr *catalogItemRepository) Update(ctx context.Context, item entity.CatalogItem, fields ...string) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	updated := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !slices.ContainsFunc(catalogItemColumns, func(c catalogItemColumn) bool { return c.field == field }) {
+			return entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unknown catalog item field %q", field))
+		}
+		updated[field] = true
+	}
+	var sets []string
+	var args []interface{}
+	for _, column := range catalogItemColumns {
+		if len(fields) > 0 && !updated[column.field] {
+			continue
+		}
+		sets = append(sets, column.set)
+		args = append(args, column.values(item)...)
+	}
+
+	// The version is part of the condition so that a concurrent change made after
+	// the item was read is not silently overwritten.
+	query := `
+	UPDATE CatalogItems
+	SET ` + strings.Join(sets, ", ") + `, version = version + 1
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
+	`
+	args = append(args, item.ID, item.Version)
+
+	res, err := executor.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "catalog item not found")
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return repository.ErrVersionConflict
+	}
 	return nil
 }
 
-func (cr *catalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) error {
+func (cr *catalogItemRepository) IncrementVersion(ctx context.Context, item entity.CatalogItem) error {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
@@ -194,23 +409,163 @@ func (cr *catalogItemRepository) Update(ctx context.Context, item entity.Catalog
 
 	query := `
 	UPDATE CatalogItems
-	SET name = ?, price = ?
-	WHERE id = ?
+	SET version = version + 1
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
 	`
 
-	if _, err := executor.ExecContext(
-		ctx,
-		query,
-		item.Name,
-		item.Price,
-		item.ID,
-	); err != nil {
+	res, err := executor.ExecContext(ctx, query, item.ID, item.Version)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return repository.ErrVersionConflict
+	}
 	return nil
 }
 
-func (cr *catalogItemRepository) Delete(ctx context.Context, id string) error {
+func (cr *catalogItemRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogItems
+	SET deleted_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NULL
+	`
+
+	res, err := executor.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "catalog item not found")
+}
+
+func (cr *catalogItemRepository) Restore(ctx context.Context, id string) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogItems
+	SET deleted_at = NULL, version = version + 1
+	WHERE id = ? AND deleted_at IS NOT NULL
+	`
+
+	res, err := executor.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "deleted catalog item not found")
+}
+
+func (cr *catalogItemRepository) ListDeleted(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	c, err := decodeCursor(page.Token)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	limit := page.Limit()
+
+	query := `
+	SELECT id, name, COALESCE(external_key, ''), price_amount, price_currency, stock, reserved, version, deleted_at
+	FROM CatalogItems
+	WHERE deleted_at IS NOT NULL
+	`
+	args := make([]interface{}, 0, 2) //nolint:gomnd // cursor and limit
+	switch {
+	case c == nil:
+		query += `ORDER BY id ASC`
+	case c.Backward:
+		query += `AND id < ? ORDER BY id DESC`
+		args = append(args, c.ID)
+	default:
+		query += `AND id > ? ORDER BY id ASC`
+		args = append(args, c.ID)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var items []entity.CatalogItem
+	for rows.Next() {
+		var item entity.CatalogItem
+		var deletedAt time.Time
+		if err = rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.ExternalKey,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&item.Version,
+			&deletedAt,
+		); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		item.DeletedAt = &deletedAt
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	items, info := paginate(items, limit, c, func(item entity.CatalogItem) string { return item.ID })
+	return items, info, nil
+}
+
+func (cr *catalogItemRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT id
+	FROM CatalogItems
+	WHERE deleted_at < ?
+	ORDER BY deleted_at ASC, id ASC
+	LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (cr *catalogItemRepository) Purge(ctx context.Context, id string, before time.Time) error {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
@@ -218,11 +573,12 @@ func (cr *catalogItemRepository) Delete(ctx context.Context, id string) error {
 
 	query := `
 	DELETE FROM CatalogItems
-	WHERE id = ?
+	WHERE id = ? AND deleted_at < ?
 	`
 
-	if _, err := executor.ExecContext(ctx, query, id); err != nil {
+	res, err := executor.ExecContext(ctx, query, id, before)
+	if err != nil {
 		return err
 	}
-	return nil
+	return expectAffected(res, "deleted catalog item not found")
 }
@@ -2,10 +2,13 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
 )
 
 func Test_CatalogItemRepository(t *testing.T) {
@@ -15,15 +18,19 @@ func Test_CatalogItemRepository(t *testing.T) {
 	item1, err := entity.NewCatalogItem(
 		"",
 		"item1",
-		100,
+		entity.Money{Amount: 10000, Currency: "USD"},
 	)
 	ValidateErr(t, err, nil)
+	err = item1.SetExternalKey("SUP-0003")
+	ValidateErr(t, err, nil)
 	item2, err := entity.NewCatalogItem(
 		"",
 		"item2",
-		200,
+		entity.Money{Amount: 20000, Currency: "EUR"},
 	)
 	ValidateErr(t, err, nil)
+	err = item2.SetExternalKey("SUP-0002")
+	ValidateErr(t, err, nil)
 
 	// Create
 	err = repo.Create(ctx, *item1)
@@ -38,13 +45,54 @@ func Test_CatalogItemRepository(t *testing.T) {
 		t.Errorf("want: %v, got: %v", item1, gotItem)
 	}
 
+	// GetByExternalKey
+	gotItem, err = repo.GetByExternalKey(ctx, "SUP-0002")
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(gotItem, item2) {
+		t.Errorf("want: %v, got: %v", item2, gotItem)
+	}
+	_, err = repo.GetByExternalKey(ctx, "SUP-0001")
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	duplicate, err := entity.NewCatalogItem("", "duplicate", entity.Money{Amount: 100, Currency: "USD"})
+	ValidateErr(t, err, nil)
+	duplicate.ExternalKey = item2.ExternalKey
+	if err = repo.Create(ctx, *duplicate); !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
 	// List
-	gotItems, err := repo.List(ctx)
+	gotItems, _, err := repo.List(ctx, repository.Page{})
 	ValidateErr(t, err, nil)
 	if len(gotItems) != 2 {
 		t.Errorf("want: 2, got: %d", len(gotItems))
 	}
 
+	// List with pagination
+	firstPage, firstInfo, err := repo.List(ctx, repository.Page{Size: 1})
+	ValidateErr(t, err, nil)
+	if len(firstPage) != 1 || firstInfo.NextToken == "" || firstInfo.PrevToken != "" {
+		t.Errorf("unexpected first page: %v, %+v", firstPage, firstInfo)
+	}
+	secondPage, secondInfo, err := repo.List(ctx, repository.Page{Size: 1, Token: firstInfo.NextToken})
+	ValidateErr(t, err, nil)
+	if len(secondPage) != 1 || secondInfo.NextToken != "" || secondInfo.PrevToken == "" {
+		t.Errorf("unexpected second page: %v, %+v", secondPage, secondInfo)
+	}
+	if firstPage[0].ID == secondPage[0].ID {
+		t.Errorf("pages overlap: %s", firstPage[0].ID)
+	}
+	prevPage, _, err := repo.List(ctx, repository.Page{Size: 1, Token: secondInfo.PrevToken})
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(prevPage, firstPage) {
+		t.Errorf("want: %v, got: %v", firstPage, prevPage)
+	}
+
+	_, _, err = repo.List(ctx, repository.Page{Token: "invalid"})
+	ValidateErr(t, err, repository.ErrInvalidPageToken)
+
 	// ListByName
 	gotItems, err = repo.ListByName(ctx, "item")
 	ValidateErr(t, err, nil)
@@ -61,9 +109,25 @@ func Test_CatalogItemRepository(t *testing.T) {
 
 	// Update
 	item1.Name = "item1-updated"
-	item1.Price = 150
+	item1.Price = entity.Money{Amount: 15050, Currency: "USD"}
 	err = repo.Update(ctx, *item1)
 	ValidateErr(t, err, nil)
+	item1.Version++
+
+	gotItem, err = repo.Get(ctx, item1.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(gotItem, item1) {
+		t.Errorf("want: %v, got: %v", item1, gotItem)
+	}
+
+	// Update of only some fields
+	partial := *item1
+	partial.Name = "item1-partial"
+	partial.Stock = 99
+	err = repo.Update(ctx, partial, entity.CatalogItemFieldName)
+	ValidateErr(t, err, nil)
+	item1.Name = partial.Name
+	item1.Version++
 
 	gotItem, err = repo.Get(ctx, item1.ID)
 	ValidateErr(t, err, nil)
@@ -71,12 +135,105 @@ func Test_CatalogItemRepository(t *testing.T) {
 		t.Errorf("want: %v, got: %v", item1, gotItem)
 	}
 
+	if err = repo.Update(ctx, *item1, "reserved"); !errors.Is(err, entity.ErrInvalidArgument) {
+		t.Errorf("want: %v, got: %v", entity.ErrInvalidArgument, err)
+	}
+
+	// Update from a stale version
+	stale := *item1
+	stale.Version--
+	stale.Name = "item1-stale"
+	err = repo.Update(ctx, stale)
+	ValidateErr(t, err, repository.ErrVersionConflict)
+
+	// IncrementVersion
+	err = repo.IncrementVersion(ctx, *item1)
+	ValidateErr(t, err, nil)
+	item1.Version++
+
+	gotItem, err = repo.Get(ctx, item1.ID)
+	ValidateErr(t, err, nil)
+	if gotItem.Version != item1.Version {
+		t.Errorf("want: %d, got: %d", item1.Version, gotItem.Version)
+	}
+
+	err = repo.IncrementVersion(ctx, stale)
+	ValidateErr(t, err, repository.ErrVersionConflict)
+
 	// Delete
-	err = repo.Delete(ctx, item1.ID)
+	deletedAt := time.Now().Truncate(time.Second)
+	err = repo.Delete(ctx, item1.ID, deletedAt)
 	ValidateErr(t, err, nil)
+	item1.Version++
 
 	_, err = repo.Get(ctx, item1.ID)
 	if err == nil {
 		t.Errorf("want: error, got: nil")
 	}
+	gotItem, err = repo.GetByExternalKey(ctx, item1.ExternalKey)
+	ValidateErr(t, err, nil)
+	if gotItem.ID != item1.ID || gotItem.DeletedAt == nil || !gotItem.DeletedAt.Equal(deletedAt) {
+		t.Errorf("want: %v deleted at %v, got: %v", item1.ID, deletedAt, gotItem)
+	}
+	gotItems, _, err = repo.List(ctx, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotItems) != 1 || gotItems[0].ID != item2.ID {
+		t.Errorf("want: %v, got: %v", []entity.CatalogItem{*item2}, gotItems)
+	}
+
+	err = repo.Delete(ctx, item1.ID, deletedAt)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// ListDeleted
+	gotItems, _, err = repo.ListDeleted(ctx, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotItems) != 1 || gotItems[0].ID != item1.ID || gotItems[0].DeletedAt == nil || !gotItems[0].DeletedAt.Equal(deletedAt) {
+		t.Errorf("want: %v deleted at %v, got: %v", item1.ID, deletedAt, gotItems)
+	}
+
+	// Restore
+	err = repo.Restore(ctx, item1.ID)
+	ValidateErr(t, err, nil)
+	item1.Version++
+
+	gotItem, err = repo.Get(ctx, item1.ID)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(gotItem, item1) {
+		t.Errorf("want: %v, got: %v", item1, gotItem)
+	}
+
+	err = repo.Restore(ctx, item1.ID)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// Purge
+	err = repo.Delete(ctx, item1.ID, deletedAt)
+	ValidateErr(t, err, nil)
+
+	ids, err := repo.ListDeletedBefore(ctx, deletedAt, 10)
+	ValidateErr(t, err, nil)
+	if len(ids) != 0 {
+		t.Errorf("want: none, got: %v", ids)
+	}
+	err = repo.Purge(ctx, item1.ID, deletedAt)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	before := deletedAt.Add(time.Second)
+	ids, err = repo.ListDeletedBefore(ctx, before, 10)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(ids, []string{item1.ID}) {
+		t.Errorf("want: %v, got: %v", []string{item1.ID}, ids)
+	}
+	err = repo.Purge(ctx, item1.ID, before)
+	ValidateErr(t, err, nil)
+
+	err = repo.Restore(ctx, item1.ID)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
 }
@@ -0,0 +1,321 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// The tree is stored as a closure table: CategoryClosure has a row for every ancestor of each
+// category, including the category itself at depth 0, so that the descendants and the depth of a
+// category are read with a single query.
+
+type categoryRepository struct {
+	db SQLExecutor
+}
+
+func NewCategoryRepository(db *sql.DB) repository.CategoryRepository {
+	return &categoryRepository{
+		db: db,
+	}
+}
+
+// selectCategories selects the columns read by scanCategories. The depth is the number of ancestors.
+const selectCategories = `
+	SELECT c.id, c.parent_id, c.name, c.slug, c.position,
+		(SELECT COUNT(*) - 1 FROM CategoryClosure cc WHERE cc.descendant_id = c.id)
+	FROM Categories c
+	`
+
+func (cr *categoryRepository) Get(ctx context.Context, id string) (*entity.Category, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	rows, err := executor.QueryContext(ctx, selectCategories+`WHERE c.id = ? LIMIT 1`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories, err := scanCategories(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 {
+		return nil, translateError(sql.ErrNoRows, "category not found")
+	}
+	return &categories[0], nil
+}
+
+func (cr *categoryRepository) List(ctx context.Context) ([]entity.Category, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	rows, err := executor.QueryContext(ctx, selectCategories+`ORDER BY c.position, c.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCategories(rows)
+}
+
+func scanCategories(rows *sql.Rows) ([]entity.Category, error) {
+	var categories []entity.Category
+	for rows.Next() {
+		var category entity.Category
+		var parentID sql.NullString
+		if err := rows.Scan(
+			&category.ID,
+			&parentID,
+			&category.Name,
+			&category.Slug,
+			&category.Position,
+			&category.Depth,
+		); err != nil {
+			return nil, err
+		}
+		category.ParentID = parentID.String
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (cr *categoryRepository) Create(ctx context.Context, category entity.Category) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	INSERT INTO Categories (id, parent_id, name, slug, position)
+	VALUES (?, ?, ?, ?, ?)
+	`
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		category.ID,
+		nullString(category.ParentID),
+		category.Name,
+		category.Slug,
+		category.Position,
+	); err != nil {
+		return translateError(err, "category not found")
+	}
+
+	// The category is a descendant of each ancestor of its parent, and of itself.
+	query = `
+	INSERT INTO CategoryClosure (ancestor_id, descendant_id, depth)
+	SELECT ancestor_id, ?, depth + 1 FROM CategoryClosure WHERE descendant_id = ?
+	UNION ALL
+	SELECT ?, ?, 0
+	`
+	if _, err := executor.ExecContext(ctx, query, category.ID, category.ParentID, category.ID, category.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) Update(ctx context.Context, category entity.Category) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	var parentID sql.NullString
+	if err := executor.QueryRowContext(ctx, `SELECT parent_id FROM Categories WHERE id = ?`, category.ID).Scan(&parentID); err != nil {
+		return translateError(err, "category not found")
+	}
+
+	query := `
+	UPDATE Categories
+	SET parent_id = ?, name = ?, slug = ?, position = ?
+	WHERE id = ?
+	`
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		nullString(category.ParentID),
+		category.Name,
+		category.Slug,
+		category.Position,
+		category.ID,
+	); err != nil {
+		return translateError(err, "category not found")
+	}
+	if parentID.String == category.ParentID {
+		return nil
+	}
+
+	// The subtree of the category is detached from the ancestors of its old parent,
+	// and attached to those of the new parent.
+	query = `
+	DELETE a FROM CategoryClosure a
+	JOIN CategoryClosure d ON d.descendant_id = a.descendant_id
+	LEFT JOIN CategoryClosure x ON x.ancestor_id = d.ancestor_id AND x.descendant_id = a.ancestor_id
+	WHERE d.ancestor_id = ? AND x.ancestor_id IS NULL
+	`
+	if _, err := executor.ExecContext(ctx, query, category.ID); err != nil {
+		return err
+	}
+	if category.ParentID == "" {
+		return nil
+	}
+
+	query = `
+	INSERT INTO CategoryClosure (ancestor_id, descendant_id, depth)
+	SELECT supertree.ancestor_id, subtree.descendant_id, supertree.depth + subtree.depth + 1
+	FROM CategoryClosure supertree
+	CROSS JOIN CategoryClosure subtree
+	WHERE supertree.descendant_id = ? AND subtree.ancestor_id = ?
+	`
+	if _, err := executor.ExecContext(ctx, query, category.ParentID, category.ID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) Delete(ctx context.Context, id string) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CatalogItemCategories WHERE category_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CategoryClosure WHERE descendant_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := executor.ExecContext(ctx, `DELETE FROM Categories WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (cr *categoryRepository) ListDescendantIDs(ctx context.Context, id string) ([]string, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT descendant_id
+	FROM CategoryClosure
+	WHERE ancestor_id = ?
+	ORDER BY depth, descendant_id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var descendantID string
+		if err = rows.Scan(&descendantID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, descendantID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (cr *categoryRepository) ListItemCategoryIDs(ctx context.Context, itemIDs []string) (map[string][]string, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	categoryIDs := make(map[string][]string, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return categoryIDs, nil
+	}
+
+	placeholders := make([]string, len(itemIDs))
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+	SELECT catalog_item_id, category_id
+	FROM CatalogItemCategories
+	WHERE catalog_item_id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY catalog_item_id, category_id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID, categoryID string
+		if err = rows.Scan(
+			&itemID,
+			&categoryID,
+		); err != nil {
+			return nil, err
+		}
+		categoryIDs[itemID] = append(categoryIDs[itemID], categoryID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categoryIDs, nil
+}
+
+func (cr *categoryRepository) ReplaceItemCategories(ctx context.Context, itemID string, categoryIDs []string) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CatalogItemCategories WHERE catalog_item_id = ?`, itemID); err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO CatalogItemCategories (catalog_item_id, category_id)
+	VALUES `
+	values := make([]interface{}, 0, len(categoryIDs)*2) //nolint:gomnd // 2 is the number of columns.
+	for i, categoryID := range categoryIDs {
+		if i > 0 {
+			query += ", "
+		}
+		query += "(?, ?)"
+		values = append(values, itemID, categoryID)
+	}
+
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return translateError(err, "category not found")
+	}
+	return nil
+}
+
+// nullString stores an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+func Test_CategoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCategoryRepository(db)
+	itemRepo := NewCatalogItemRepository(db)
+
+	newCategory := func(parentID string) entity.Category {
+		id := uuid.New().String()
+		category, err := entity.NewCategory(id, parentID, "category", "category-"+id, 0)
+		ValidateErr(t, err, nil)
+		return *category
+	}
+
+	// Create: electronics > computers > laptops, and garden
+	electronics := newCategory("")
+	computers := newCategory(electronics.ID)
+	laptops := newCategory(computers.ID)
+	garden := newCategory("")
+	for _, category := range []entity.Category{electronics, computers, laptops, garden} {
+		err := repo.Create(ctx, category)
+		ValidateErr(t, err, nil)
+	}
+
+	// Get
+	gotCategory, err := repo.Get(ctx, laptops.ID)
+	ValidateErr(t, err, nil)
+	laptops.Depth = 2
+	if d := cmp.Diff(laptops, *gotCategory); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	_, err = repo.Get(ctx, uuid.New().String())
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// A slug is unique.
+	duplicate := newCategory("")
+	duplicate.Slug = electronics.Slug
+	err = repo.Create(ctx, duplicate)
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
+	// ListDescendantIDs
+	gotIDs, err := repo.ListDescendantIDs(ctx, electronics.ID)
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff([]string{electronics.ID, computers.ID, laptops.ID}, gotIDs); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// Items are listed by the categories they are in and those above them.
+	item := entity.CatalogItem{
+		ID:    uuid.New().String(),
+		Name:  "laptop",
+		Price: entity.Money{Amount: 100000, Currency: "USD"},
+	}
+	err = itemRepo.Create(ctx, item)
+	ValidateErr(t, err, nil)
+	err = repo.ReplaceItemCategories(ctx, item.ID, []string{computers.ID, laptops.ID})
+	ValidateErr(t, err, nil)
+
+	gotCategoryIDs, err := repo.ListItemCategoryIDs(ctx, []string{item.ID})
+	ValidateErr(t, err, nil)
+	wantCategoryIDs := []string{computers.ID, laptops.ID}
+	if d := cmp.Diff(map[string][]string{item.ID: wantCategoryIDs}, gotCategoryIDs, cmpopts.SortSlices(func(a, b string) bool { return a < b })); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	gotItems, _, err := itemRepo.ListByCategory(ctx, electronics.ID, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotItems) != 1 || gotItems[0].ID != item.ID {
+		t.Errorf("got %v, want the item once", gotItems)
+	}
+
+	// Update moves computers and laptops under garden.
+	computers.ParentID = garden.ID
+	computers.Name = "moved"
+	err = repo.Update(ctx, computers)
+	ValidateErr(t, err, nil)
+
+	gotIDs, err = repo.ListDescendantIDs(ctx, electronics.ID)
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff([]string{electronics.ID}, gotIDs); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+	gotCategory, err = repo.Get(ctx, laptops.ID)
+	ValidateErr(t, err, nil)
+	if gotCategory.Depth != 2 {
+		t.Errorf("got depth %d, want 2", gotCategory.Depth)
+	}
+	gotItems, _, err = itemRepo.ListByCategory(ctx, garden.ID, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotItems) != 1 {
+		t.Errorf("got %d items, want 1", len(gotItems))
+	}
+
+	// Update moves computers to the root.
+	computers.ParentID = ""
+	err = repo.Update(ctx, computers)
+	ValidateErr(t, err, nil)
+	gotCategory, err = repo.Get(ctx, laptops.ID)
+	ValidateErr(t, err, nil)
+	if gotCategory.Depth != 1 {
+		t.Errorf("got depth %d, want 1", gotCategory.Depth)
+	}
+
+	// Delete
+	err = repo.Delete(ctx, laptops.ID)
+	ValidateErr(t, err, nil)
+	_, err = repo.Get(ctx, laptops.ID)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+	gotCategoryIDs, err = repo.ListItemCategoryIDs(ctx, []string{item.ID})
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff(map[string][]string{item.ID: {computers.ID}}, gotCategoryIDs); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// List
+	gotCategories, err := repo.List(ctx)
+	ValidateErr(t, err, nil)
+	if len(gotCategories) < 3 {
+		t.Errorf("got %d categories, want at least 3", len(gotCategories))
+	}
+}
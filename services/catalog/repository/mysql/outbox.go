@@ -0,0 +1,184 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+type outboxRepository struct {
+	db SQLExecutor
+}
+
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (or *outboxRepository) Add(ctx context.Context, events ...entity.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// The sequence is assigned by the auto increment column, so that events are published
+	// in the order their transactions stored them.
+	placeholders := make([]string, 0, len(events))
+	values := make([]interface{}, 0, len(events)*5) //nolint:gomnd // 5 is the number of columns.
+	for _, event := range events {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		values = append(values, event.ID, string(event.Type), event.AggregateID, []byte(event.Payload), event.OccurredAt)
+	}
+
+	query := `
+	INSERT INTO CatalogOutboxEvents (id, event_type, aggregate_id, payload, occurred_at)
+	VALUES ` + strings.Join(placeholders, ", ")
+
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return translateInsertError(err, "event already exists")
+	}
+	return nil
+}
+
+func (or *outboxRepository) ClaimUnpublished(ctx context.Context, claimID string, limit int, now, until time.Time) ([]entity.Event, error) {
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// The events are claimed by a single statement, so that relays claiming at the same time never
+	// claim the same event. MySQL 5.7 has no SKIP LOCKED to lock them while they are published instead.
+	query := `
+	UPDATE CatalogOutboxEvents SET claim_id = ?, claimed_until = ?
+	WHERE published_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?)
+	ORDER BY sequence
+	LIMIT ?
+	`
+	if _, err := executor.ExecContext(ctx, query, claimID, until, now, limit); err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT sequence, id, event_type, aggregate_id, payload, occurred_at
+	FROM CatalogOutboxEvents
+	WHERE claim_id = ? AND published_at IS NULL
+	ORDER BY sequence
+	`
+
+	rows, err := executor.QueryContext(ctx, query, claimID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (or *outboxRepository) ListAfter(ctx context.Context, sequence int64, limit int) ([]entity.Event, error) {
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT sequence, id, event_type, aggregate_id, payload, occurred_at
+	FROM CatalogOutboxEvents
+	WHERE sequence > ?
+	ORDER BY sequence
+	LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, sequence, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+func (or *outboxRepository) LastSequence(ctx context.Context) (int64, error) {
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT COALESCE(MAX(sequence), 0) FROM CatalogOutboxEvents
+	`
+
+	var sequence int64
+	if err := executor.QueryRowContext(ctx, query).Scan(&sequence); err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func scanEvents(rows *sql.Rows) ([]entity.Event, error) {
+	var events []entity.Event
+	for rows.Next() {
+		var event entity.Event
+		var eventType string
+		var payload []byte
+		if err := rows.Scan(
+			&event.Sequence,
+			&event.ID,
+			&eventType,
+			&event.AggregateID,
+			&payload,
+			&event.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		event.Type = entity.EventType(eventType)
+		event.Payload = payload
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (or *outboxRepository) MarkPublished(ctx context.Context, id string, now time.Time) error {
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogOutboxEvents SET published_at = ? WHERE id = ?
+	`
+
+	if _, err := executor.ExecContext(ctx, query, now, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (or *outboxRepository) ReleaseClaim(ctx context.Context, claimID string) error {
+	executor := or.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogOutboxEvents SET claim_id = NULL, claimed_until = NULL
+	WHERE claim_id = ? AND published_at IS NULL
+	`
+
+	if _, err := executor.ExecContext(ctx, query, claimID); err != nil {
+		return err
+	}
+	return nil
+}
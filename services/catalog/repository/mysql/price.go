@@ -0,0 +1,221 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+type priceRepository struct {
+	db SQLExecutor
+}
+
+func NewPriceRepository(db *sql.DB) repository.PriceRepository {
+	return &priceRepository{
+		db: db,
+	}
+}
+
+func (pr *priceRepository) ListPrices(ctx context.Context, itemIDs []string) (map[string][]entity.Money, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	prices := make(map[string][]entity.Money, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return prices, nil
+	}
+
+	placeholders := make([]string, len(itemIDs))
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	query := `
+	SELECT catalog_item_id, amount, currency
+	FROM CatalogItemPrices
+	WHERE catalog_item_id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY catalog_item_id, currency
+	`
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var itemID string
+		var price entity.Money
+		if err = rows.Scan(
+			&itemID,
+			&price.Amount,
+			&price.Currency,
+		); err != nil {
+			return nil, err
+		}
+		prices[itemID] = append(prices[itemID], price)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return prices, nil
+}
+
+func (pr *priceRepository) ReplacePrices(ctx context.Context, itemID string, prices []entity.Money) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CatalogItemPrices WHERE catalog_item_id = ?`, itemID); err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO CatalogItemPrices (catalog_item_id, amount, currency)
+	VALUES `
+	values := make([]interface{}, 0, len(prices)*3) //nolint:gomnd // 3 is the number of columns.
+	for i, price := range prices {
+		if i > 0 {
+			query += ", "
+		}
+		query += "(?, ?, ?)"
+		values = append(values, itemID, price.Amount, price.Currency)
+	}
+
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return translateError(err, "catalog item price not found")
+	}
+	return nil
+}
+
+func (pr *priceRepository) GetExchangeRate(ctx context.Context, from, to string) (*entity.ExchangeRate, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT from_currency, to_currency, rate, updated_at
+	FROM ExchangeRates
+	WHERE from_currency = ? AND to_currency = ?
+	LIMIT 1
+	`
+
+	rows, err := executor.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates, err := scanExchangeRates(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, translateError(sql.ErrNoRows, "exchange rate not found")
+	}
+	return &rates[0], nil
+}
+
+func (pr *priceRepository) ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT from_currency, to_currency, rate, updated_at
+	FROM ExchangeRates
+	ORDER BY from_currency, to_currency
+	`
+
+	rows, err := executor.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanExchangeRates(rows)
+}
+
+// scanExchangeRates reads rows of from_currency, to_currency, rate and updated_at.
+// Rates are read as decimals, as they are stored.
+func scanExchangeRates(rows *sql.Rows) ([]entity.ExchangeRate, error) {
+	var rates []entity.ExchangeRate
+	for rows.Next() {
+		var from, to, rate string
+		var updatedAt time.Time
+		if err := rows.Scan(
+			&from,
+			&to,
+			&rate,
+			&updatedAt,
+		); err != nil {
+			return nil, err
+		}
+		r, err := entity.NewExchangeRate(from, to, rate, updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (pr *priceRepository) UpsertExchangeRate(ctx context.Context, rate entity.ExchangeRate) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	INSERT INTO ExchangeRates (from_currency, to_currency, rate, updated_at)
+	VALUES (?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE rate = VALUES(rate), updated_at = VALUES(updated_at)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		rate.From,
+		rate.To,
+		rate.Decimal(),
+		rate.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *priceRepository) DeleteExchangeRate(ctx context.Context, from, to string) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	DELETE FROM ExchangeRates
+	WHERE from_currency = ? AND to_currency = ?
+	`
+
+	if _, err := executor.ExecContext(ctx, query, from, to); err != nil {
+		return err
+	}
+	return nil
+}
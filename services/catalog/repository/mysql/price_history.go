@@ -0,0 +1,193 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func (pr *priceRepository) AddPriceHistoryEntry(ctx context.Context, entry entity.PriceHistoryEntry) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	INSERT INTO CatalogItemPriceHistory (
+	id, catalog_item_id, price_amount, price_currency, effective_from, applied_at
+	)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.CatalogItemID,
+		entry.Price.Amount,
+		entry.Price.Currency,
+		entry.EffectiveFrom,
+		entry.AppliedAt,
+	); err != nil {
+		return translateError(err, "price history entry not found")
+	}
+	return nil
+}
+
+func (pr *priceRepository) ListPriceHistory(ctx context.Context, itemID string) ([]entity.PriceHistoryEntry, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT id, catalog_item_id, price_amount, price_currency, effective_from, applied_at
+	FROM CatalogItemPriceHistory
+	WHERE catalog_item_id = ?
+	ORDER BY effective_from DESC, id ASC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPriceHistory(rows)
+}
+
+func (pr *priceRepository) GetPriceAsOf(ctx context.Context, itemID string, asOf time.Time) (*entity.PriceHistoryEntry, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT id, catalog_item_id, price_amount, price_currency, effective_from, applied_at
+	FROM CatalogItemPriceHistory
+	WHERE catalog_item_id = ? AND effective_from <= ?
+	ORDER BY effective_from DESC
+	LIMIT 1
+	`
+
+	rows, err := executor.QueryContext(ctx, query, itemID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries, err := scanPriceHistory(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, translateError(sql.ErrNoRows, "no price of the catalog item as of that time")
+	}
+	return &entries[0], nil
+}
+
+func (pr *priceRepository) ListDuePriceChanges(ctx context.Context, now time.Time, limit int) ([]entity.PriceHistoryEntry, error) {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT h.id, h.catalog_item_id, h.price_amount, h.price_currency, h.effective_from, h.applied_at
+	FROM CatalogItemPriceHistory h
+	JOIN CatalogItems ci ON ci.id = h.catalog_item_id
+	WHERE h.applied_at IS NULL AND h.effective_from <= ? AND ci.deleted_at IS NULL
+	ORDER BY h.effective_from ASC, h.id ASC
+	LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPriceHistory(rows)
+}
+
+// scanPriceHistory reads rows of id, catalog_item_id, price_amount, price_currency, effective_from and applied_at.
+func scanPriceHistory(rows *sql.Rows) ([]entity.PriceHistoryEntry, error) {
+	var entries []entity.PriceHistoryEntry
+	for rows.Next() {
+		var entry entity.PriceHistoryEntry
+		var appliedAt sql.NullTime
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CatalogItemID,
+			&entry.Price.Amount,
+			&entry.Price.Currency,
+			&entry.EffectiveFrom,
+			&appliedAt,
+		); err != nil {
+			return nil, err
+		}
+		if appliedAt.Valid {
+			entry.AppliedAt = &appliedAt.Time
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (pr *priceRepository) MarkPriceChangeApplied(ctx context.Context, id string, appliedAt time.Time) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogItemPriceHistory
+	SET applied_at = ?, effective_from = ?
+	WHERE id = ? AND applied_at IS NULL
+	`
+
+	res, err := executor.ExecContext(ctx, query, appliedAt, appliedAt, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "scheduled price change not found")
+}
+
+func (pr *priceRepository) DeletePriceHistoryEntry(ctx context.Context, id string) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	DELETE FROM CatalogItemPriceHistory
+	WHERE id = ?
+	`
+
+	if _, err := executor.ExecContext(ctx, query, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (pr *priceRepository) DeletePriceHistory(ctx context.Context, itemID string) error {
+	executor := pr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	DELETE FROM CatalogItemPriceHistory
+	WHERE catalog_item_id = ?
+	`
+
+	if _, err := executor.ExecContext(ctx, query, itemID); err != nil {
+		return err
+	}
+	return nil
+}
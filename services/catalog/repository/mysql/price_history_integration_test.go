@@ -0,0 +1,124 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func Test_PriceHistory(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPriceRepository(db)
+	itemRepo := NewCatalogItemRepository(db)
+
+	item, err := entity.NewCatalogItem("", "item", entity.Money{Amount: 1000, Currency: "USD"})
+	ValidateErr(t, err, nil)
+	err = itemRepo.Create(ctx, *item)
+	ValidateErr(t, err, nil)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	created := now.Add(-48 * time.Hour)
+	changed := now.Add(-24 * time.Hour)
+	scheduled := now.Add(24 * time.Hour)
+
+	entry1, err := entity.NewPriceHistoryEntry(item.ID, entity.Money{Amount: 1000, Currency: "USD"}, created)
+	ValidateErr(t, err, nil)
+	entry1.AppliedAt = &created
+	entry2, err := entity.NewPriceHistoryEntry(item.ID, entity.Money{Amount: 1200, Currency: "USD"}, changed)
+	ValidateErr(t, err, nil)
+	entry3, err := entity.NewPriceHistoryEntry(item.ID, entity.Money{Amount: 900, Currency: "USD"}, scheduled)
+	ValidateErr(t, err, nil)
+
+	// AddPriceHistoryEntry and ListPriceHistory
+	for _, entry := range []*entity.PriceHistoryEntry{entry1, entry2, entry3} {
+		err = repo.AddPriceHistoryEntry(ctx, *entry)
+		ValidateErr(t, err, nil)
+	}
+
+	history, err := repo.ListPriceHistory(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff([]entity.PriceHistoryEntry{*entry3, *entry2, *entry1}, history); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// GetPriceAsOf
+	for _, tt := range []struct {
+		asOf time.Time
+		want *entity.PriceHistoryEntry
+	}{
+		{asOf: created, want: entry1},
+		{asOf: changed.Add(-time.Second), want: entry1},
+		{asOf: now, want: entry2},
+		{asOf: scheduled.Add(time.Hour), want: entry3},
+	} {
+		got, err := repo.GetPriceAsOf(ctx, item.ID, tt.asOf)
+		ValidateErr(t, err, nil)
+		if got.ID != tt.want.ID {
+			t.Errorf("as of %v want: %v, got: %v", tt.asOf, tt.want, got)
+		}
+	}
+	_, err = repo.GetPriceAsOf(ctx, item.ID, created.Add(-time.Second))
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// ListDuePriceChanges and MarkPriceChangeApplied
+	due, err := repo.ListDuePriceChanges(ctx, now, 10)
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff([]entity.PriceHistoryEntry{*entry2}, due); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	err = itemRepo.Delete(ctx, item.ID, now)
+	ValidateErr(t, err, nil)
+	due, err = repo.ListDuePriceChanges(ctx, now, 10)
+	ValidateErr(t, err, nil)
+	if len(due) != 0 {
+		t.Errorf("want: none, got: %v", due)
+	}
+	err = itemRepo.Restore(ctx, item.ID)
+	ValidateErr(t, err, nil)
+
+	// The change is made late, once the item is restored, and is effective from then
+	appliedAt := now.Add(time.Minute)
+	err = repo.MarkPriceChangeApplied(ctx, entry2.ID, appliedAt)
+	ValidateErr(t, err, nil)
+	history, err = repo.ListPriceHistory(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	for _, entry := range history {
+		if entry.ID == entry2.ID && (!entry.EffectiveFrom.Equal(appliedAt) || entry.AppliedAt == nil || !entry.AppliedAt.Equal(appliedAt)) {
+			t.Errorf("want: effective from and applied at %v, got: %v", appliedAt, entry)
+		}
+	}
+	err = repo.MarkPriceChangeApplied(ctx, entry2.ID, now)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+	due, err = repo.ListDuePriceChanges(ctx, now, 10)
+	ValidateErr(t, err, nil)
+	if len(due) != 0 {
+		t.Errorf("want: none, got: %v", due)
+	}
+
+	// DeletePriceHistoryEntry and DeletePriceHistory
+	err = repo.DeletePriceHistoryEntry(ctx, entry3.ID)
+	ValidateErr(t, err, nil)
+	history, err = repo.ListPriceHistory(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	if len(history) != 2 || history[0].ID != entry2.ID || history[0].Scheduled() {
+		t.Errorf("want: %v applied, got: %v", entry2.ID, history)
+	}
+
+	err = repo.DeletePriceHistory(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	history, err = repo.ListPriceHistory(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	if len(history) != 0 {
+		t.Errorf("want: none, got: %v", history)
+	}
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func Test_PriceRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPriceRepository(db)
+
+	itemID := uuid.New().String()
+	prices := []entity.Money{
+		{Amount: 900, Currency: "EUR"},
+		{Amount: 150000, Currency: "JPY"},
+	}
+
+	// ReplacePrices and ListPrices
+	err := repo.ReplacePrices(ctx, itemID, prices)
+	ValidateErr(t, err, nil)
+
+	gotPrices, err := repo.ListPrices(ctx, []string{itemID, uuid.New().String()})
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff(map[string][]entity.Money{itemID: prices}, gotPrices); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	err = repo.ReplacePrices(ctx, itemID, prices[:1])
+	ValidateErr(t, err, nil)
+	gotPrices, err = repo.ListPrices(ctx, []string{itemID})
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff(map[string][]entity.Money{itemID: prices[:1]}, gotPrices); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	err = repo.ReplacePrices(ctx, itemID, nil)
+	ValidateErr(t, err, nil)
+	gotPrices, err = repo.ListPrices(ctx, []string{itemID})
+	ValidateErr(t, err, nil)
+	if len(gotPrices) != 0 {
+		t.Errorf("want: 0, got: %d", len(gotPrices))
+	}
+
+	// UpsertExchangeRate, GetExchangeRate and ListExchangeRates
+	updatedAt := time.Now().UTC().Truncate(time.Second)
+	rate, err := entity.NewExchangeRate("USD", "EUR", "1.0834", updatedAt)
+	ValidateErr(t, err, nil)
+	err = repo.UpsertExchangeRate(ctx, *rate)
+	ValidateErr(t, err, nil)
+
+	rate.Rate = 109000000
+	err = repo.UpsertExchangeRate(ctx, *rate)
+	ValidateErr(t, err, nil)
+
+	gotRate, err := repo.GetExchangeRate(ctx, "USD", "EUR")
+	ValidateErr(t, err, nil)
+	if gotRate.Rate != rate.Rate || !gotRate.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("want: %v, got: %v", rate, gotRate)
+	}
+
+	rates, err := repo.ListExchangeRates(ctx)
+	ValidateErr(t, err, nil)
+	if len(rates) != 1 || rates[0].From != "USD" || rates[0].To != "EUR" {
+		t.Errorf("unexpected rates: %v", rates)
+	}
+
+	// DeleteExchangeRate
+	err = repo.DeleteExchangeRate(ctx, "USD", "EUR")
+	ValidateErr(t, err, nil)
+
+	_, err = repo.GetExchangeRate(ctx, "USD", "EUR")
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+}
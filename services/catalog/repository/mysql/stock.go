@@ -0,0 +1,250 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+type stockRepository struct {
+	db SQLExecutor
+}
+
+func NewStockRepository(db *sql.DB) repository.StockRepository {
+	return &stockRepository{
+		db: db,
+	}
+}
+
+func (sr *stockRepository) LockItems(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	// Rows are locked in primary key order so that concurrent reservations of
+	// overlapping items wait for each other instead of deadlocking.
+	query := `
+	SELECT id, name, price_amount, price_currency, stock, reserved, deleted_at
+	FROM CatalogItems
+	WHERE id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY id
+	FOR UPDATE
+	`
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []entity.CatalogItem
+	for rows.Next() {
+		var item entity.CatalogItem
+		var deletedAt sql.NullTime
+		if err = rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.Price.Amount,
+			&item.Price.Currency,
+			&item.Stock,
+			&item.Reserved,
+			&deletedAt,
+		); err != nil {
+			return nil, err
+		}
+		if deletedAt.Valid {
+			item.DeletedAt = &deletedAt.Time
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (sr *stockRepository) UpdateStock(ctx context.Context, item entity.CatalogItem) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// The version is left alone, as reservations are not edits of the item. The item is locked
+	// by LockItems, as is done by the updates of its stock, so that the reserved quantity is
+	// always checked against the stock it is written with.
+	query := `
+	UPDATE CatalogItems
+	SET stock = ?, reserved = ?
+	WHERE id = ?
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		item.Stock,
+		item.Reserved,
+		item.ID,
+	); err != nil {
+		return translateError(err, "catalog item not found")
+	}
+	return nil
+}
+
+func (sr *stockRepository) LockVariants(ctx context.Context, skus []string) ([]entity.Variant, error) {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	placeholders := make([]string, len(skus))
+	args := make([]interface{}, len(skus))
+	for i, sku := range skus {
+		placeholders[i] = "?"
+		args[i] = sku
+	}
+
+	// Rows are locked in primary key order, after the items, for the same reason as in LockItems.
+	rows, err := executor.QueryContext(
+		ctx,
+		selectVariants+`WHERE sku IN (`+strings.Join(placeholders, ",")+`) ORDER BY sku FOR UPDATE`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanVariants(rows)
+}
+
+func (sr *stockRepository) UpdateVariantStock(ctx context.Context, variant entity.Variant) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE CatalogItemVariants
+	SET stock = ?, reserved = ?
+	WHERE sku = ?
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		variant.Stock,
+		variant.Reserved,
+		variant.SKU,
+	); err != nil {
+		return translateError(err, "variant not found")
+	}
+	return nil
+}
+
+func (sr *stockRepository) CreateReservations(ctx context.Context, reservations []entity.StockReservation) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	INSERT INTO StockReservations (
+	reservation_id, catalog_item_id, sku, quantity, status, created_at, updated_at
+	)
+	VALUES `
+	values := make([]interface{}, 0, len(reservations)*7) //nolint:gomnd // 7 is the number of columns.
+	for i, r := range reservations {
+		if i > 0 {
+			query += ", "
+		}
+		query += "(?, ?, ?, ?, ?, ?, ?)"
+		values = append(values, r.ReservationID, r.CatalogItemID, r.SKU, r.Quantity, string(r.Status), r.CreatedAt, r.UpdatedAt)
+	}
+
+	if _, err := executor.ExecContext(ctx, query, values...); err != nil {
+		return translateError(err, "stock reservation not found")
+	}
+	return nil
+}
+
+func (sr *stockRepository) LockReservations(ctx context.Context, reservationID string) ([]entity.StockReservation, error) {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT reservation_id, catalog_item_id, sku, quantity, status, created_at, updated_at
+	FROM StockReservations
+	WHERE reservation_id = ?
+	ORDER BY catalog_item_id, sku
+	FOR UPDATE
+	`
+
+	rows, err := executor.QueryContext(ctx, query, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []entity.StockReservation
+	for rows.Next() {
+		var r entity.StockReservation
+		var status string
+		if err = rows.Scan(
+			&r.ReservationID,
+			&r.CatalogItemID,
+			&r.SKU,
+			&r.Quantity,
+			&status,
+			&r.CreatedAt,
+			&r.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		r.Status = entity.ReservationStatus(status)
+		reservations = append(reservations, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+func (sr *stockRepository) UpdateReservationStatus(ctx context.Context, reservationID string, status entity.ReservationStatus, updatedAt time.Time) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE StockReservations
+	SET status = ?, updated_at = ?
+	WHERE reservation_id = ?
+	`
+
+	if _, err := executor.ExecContext(
+		ctx,
+		query,
+		string(status),
+		updatedAt,
+		reservationID,
+	); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func Test_StockRepository(t *testing.T) {
+	ctx := context.Background()
+	itemRepo := NewCatalogItemRepository(db)
+	repo := NewStockRepository(db)
+	tr := NewTransactionRepository(db)
+
+	item, err := entity.NewCatalogItem(
+		"",
+		"stocked item",
+		entity.Money{Amount: 10000, Currency: "USD"},
+	)
+	ValidateErr(t, err, nil)
+	err = item.SetStock(10)
+	ValidateErr(t, err, nil)
+	err = itemRepo.Create(ctx, *item)
+	ValidateErr(t, err, nil)
+
+	reservationID := uuid.New().String()
+	now := time.Now()
+	reservation, err := entity.NewStockReservation(reservationID, item.ID, "", 3, now)
+	ValidateErr(t, err, nil)
+
+	// LockItems, UpdateStock and CreateReservations
+	err = tr.Transaction(ctx, func(ctx context.Context) error {
+		items, err := repo.LockItems(ctx, []string{item.ID, uuid.New().String()}) //nolint:govet // shadow
+		if err != nil {
+			return err
+		}
+		if len(items) != 1 || items[0].Stock != 10 || items[0].Reserved != 0 {
+			t.Errorf("unexpected locked items: %v", items)
+		}
+		if err = items[0].Reserve(reservation.Quantity); err != nil {
+			return err
+		}
+		if err = repo.UpdateStock(ctx, items[0]); err != nil {
+			return err
+		}
+		return repo.CreateReservations(ctx, []entity.StockReservation{*reservation})
+	})
+	ValidateErr(t, err, nil)
+
+	gotItem, err := itemRepo.Get(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	if gotItem.Stock != 10 || gotItem.Reserved != 3 {
+		t.Errorf("want: 10/3, got: %d/%d", gotItem.Stock, gotItem.Reserved)
+	}
+
+	// A reservation can be created only once
+	err = repo.CreateReservations(ctx, []entity.StockReservation{*reservation})
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
+	// A reservation leaves the version alone, so that an edit of the item read before it is not rejected
+	if gotItem.Version != item.Version {
+		t.Errorf("want: version %d, got: %d", item.Version, gotItem.Version)
+	}
+	item.Name = "renamed item"
+	err = itemRepo.Update(ctx, *item, entity.CatalogItemFieldName)
+	ValidateErr(t, err, nil)
+	gotItem, err = itemRepo.Get(ctx, item.ID)
+	ValidateErr(t, err, nil)
+	if gotItem.Name != item.Name || gotItem.Stock != 10 || gotItem.Reserved != 3 {
+		t.Errorf("want: %s 10/3, got: %s %d/%d", item.Name, gotItem.Name, gotItem.Stock, gotItem.Reserved)
+	}
+
+	// LockReservations and UpdateReservationStatus
+	err = tr.Transaction(ctx, func(ctx context.Context) error {
+		reservations, err := repo.LockReservations(ctx, reservationID) //nolint:govet // shadow
+		if err != nil {
+			return err
+		}
+		if len(reservations) != 1 ||
+			reservations[0].CatalogItemID != item.ID ||
+			reservations[0].Quantity != 3 ||
+			reservations[0].Status != entity.ReservationStatusReserved {
+			t.Errorf("unexpected reservations: %v", reservations)
+		}
+		return repo.UpdateReservationStatus(ctx, reservationID, entity.ReservationStatusCommitted, now)
+	})
+	ValidateErr(t, err, nil)
+
+	reservations, err := repo.LockReservations(ctx, reservationID)
+	ValidateErr(t, err, nil)
+	if len(reservations) != 1 || reservations[0].Status != entity.ReservationStatusCommitted {
+		t.Errorf("unexpected reservations: %v", reservations)
+	}
+
+	reservations, err = repo.LockReservations(ctx, uuid.New().String())
+	ValidateErr(t, err, nil)
+	if len(reservations) != 0 {
+		t.Errorf("want: 0, got: %d", len(reservations))
+	}
+}
@@ -0,0 +1,248 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+type variantRepository struct {
+	db SQLExecutor
+}
+
+func NewVariantRepository(db *sql.DB) repository.VariantRepository {
+	return &variantRepository{
+		db: db,
+	}
+}
+
+// variantModel is a row of CatalogItemVariants, whose attributes are stored as a JSON object
+// and whose price override is NULL when the variant is sold at the price of its item.
+type variantModel struct {
+	SKU           string
+	CatalogItemID string
+	Attributes    []byte
+	PriceAmount   sql.NullInt64
+	PriceCurrency sql.NullString
+	Stock         int
+	Reserved      int
+}
+
+func newVariantModel(variant entity.Variant) (variantModel, error) {
+	attributes, err := json.Marshal(variant.Attributes)
+	if err != nil {
+		return variantModel{}, err
+	}
+	vm := variantModel{
+		SKU:           variant.SKU,
+		CatalogItemID: variant.CatalogItemID,
+		Attributes:    attributes,
+		Stock:         variant.Stock,
+		Reserved:      variant.Reserved,
+	}
+	if variant.Price != nil {
+		vm.PriceAmount = sql.NullInt64{Int64: variant.Price.Amount, Valid: true}
+		vm.PriceCurrency = sql.NullString{String: variant.Price.Currency, Valid: true}
+	}
+	return vm, nil
+}
+
+func (vm variantModel) toEntity() (*entity.Variant, error) {
+	variant := &entity.Variant{
+		SKU:           vm.SKU,
+		CatalogItemID: vm.CatalogItemID,
+		Stock:         vm.Stock,
+		Reserved:      vm.Reserved,
+	}
+	if err := json.Unmarshal(vm.Attributes, &variant.Attributes); err != nil {
+		return nil, err
+	}
+	if vm.PriceAmount.Valid {
+		variant.Price = &entity.Money{Amount: vm.PriceAmount.Int64, Currency: vm.PriceCurrency.String}
+	}
+	return variant, nil
+}
+
+// selectVariants selects the columns read by scanVariants.
+const selectVariants = `
+	SELECT sku, catalog_item_id, attributes, price_amount, price_currency, stock, reserved
+	FROM CatalogItemVariants
+	`
+
+func scanVariants(rows *sql.Rows) ([]entity.Variant, error) {
+	var variants []entity.Variant
+	for rows.Next() {
+		var vm variantModel
+		if err := rows.Scan(
+			&vm.SKU,
+			&vm.CatalogItemID,
+			&vm.Attributes,
+			&vm.PriceAmount,
+			&vm.PriceCurrency,
+			&vm.Stock,
+			&vm.Reserved,
+		); err != nil {
+			return nil, err
+		}
+		variant, err := vm.toEntity()
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, *variant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+func (vr *variantRepository) Get(ctx context.Context, sku string) (*entity.Variant, error) {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	rows, err := executor.QueryContext(ctx, selectVariants+`WHERE sku = ? LIMIT 1`, sku)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants, err := scanVariants(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, translateError(sql.ErrNoRows, "variant not found")
+	}
+	return &variants[0], nil
+}
+
+func (vr *variantRepository) ListByCatalogItemIDs(ctx context.Context, itemIDs []string) (map[string][]entity.Variant, error) {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	variants := make(map[string][]entity.Variant, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return variants, nil
+	}
+
+	placeholders := make([]string, len(itemIDs))
+	args := make([]interface{}, len(itemIDs))
+	for i, id := range itemIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := executor.QueryContext(
+		ctx,
+		selectVariants+`WHERE catalog_item_id IN (`+strings.Join(placeholders, ",")+`) ORDER BY catalog_item_id, sku`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list, err := scanVariants(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range list {
+		variants[variant.CatalogItemID] = append(variants[variant.CatalogItemID], variant)
+	}
+	return variants, nil
+}
+
+func (vr *variantRepository) Create(ctx context.Context, variant entity.Variant) error {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	vm, err := newVariantModel(variant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO CatalogItemVariants (
+	sku, catalog_item_id, attributes, price_amount, price_currency, stock, reserved
+	)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err = executor.ExecContext(
+		ctx,
+		query,
+		vm.SKU,
+		vm.CatalogItemID,
+		vm.Attributes,
+		vm.PriceAmount,
+		vm.PriceCurrency,
+		vm.Stock,
+		vm.Reserved,
+	); err != nil {
+		return translateError(err, "variant not found")
+	}
+	return nil
+}
+
+func (vr *variantRepository) Update(ctx context.Context, variant entity.Variant) error {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	vm, err := newVariantModel(variant)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE CatalogItemVariants
+	SET attributes = ?, price_amount = ?, price_currency = ?, stock = ?
+	WHERE sku = ?
+	`
+	if _, err = executor.ExecContext(
+		ctx,
+		query,
+		vm.Attributes,
+		vm.PriceAmount,
+		vm.PriceCurrency,
+		vm.Stock,
+		vm.SKU,
+	); err != nil {
+		return translateError(err, "variant not found")
+	}
+	return nil
+}
+
+func (vr *variantRepository) Delete(ctx context.Context, sku string) error {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CatalogItemVariants WHERE sku = ?`, sku); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (vr *variantRepository) DeleteByCatalogItemID(ctx context.Context, itemID string) error {
+	executor := vr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM CatalogItemVariants WHERE catalog_item_id = ?`, itemID); err != nil {
+		return err
+	}
+	return nil
+}
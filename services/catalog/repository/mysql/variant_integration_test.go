@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func Test_VariantRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewVariantRepository(db)
+
+	itemID := uuid.New().String()
+	red := entity.Variant{
+		SKU:           "SHIRT-M-RED",
+		CatalogItemID: itemID,
+		Attributes:    map[string]string{"size": "M", "color": "red"},
+		Stock:         5,
+	}
+	blue := entity.Variant{
+		SKU:           "SHIRT-M-BLUE",
+		CatalogItemID: itemID,
+		Attributes:    map[string]string{"size": "M", "color": "blue"},
+		Price:         &entity.Money{Amount: 1200, Currency: "USD"},
+		Stock:         3,
+	}
+
+	// Create and Get
+	err := repo.Create(ctx, red)
+	ValidateErr(t, err, nil)
+	err = repo.Create(ctx, blue)
+	ValidateErr(t, err, nil)
+
+	err = repo.Create(ctx, red)
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
+	gotVariant, err := repo.Get(ctx, blue.SKU)
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff(blue, *gotVariant); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// Update
+	red.Price = &entity.Money{Amount: 900, Currency: "EUR"}
+	red.Stock = 8
+	err = repo.Update(ctx, red)
+	ValidateErr(t, err, nil)
+
+	// ListByCatalogItemIDs
+	gotVariants, err := repo.ListByCatalogItemIDs(ctx, []string{itemID, uuid.New().String()})
+	ValidateErr(t, err, nil)
+	if d := cmp.Diff(map[string][]entity.Variant{itemID: {blue, red}}, gotVariants); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+
+	// Delete
+	err = repo.Delete(ctx, red.SKU)
+	ValidateErr(t, err, nil)
+	_, err = repo.Get(ctx, red.SKU)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("want: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// DeleteByCatalogItemID
+	err = repo.DeleteByCatalogItemID(ctx, itemID)
+	ValidateErr(t, err, nil)
+	gotVariants, err = repo.ListByCatalogItemIDs(ctx, []string{itemID})
+	ValidateErr(t, err, nil)
+	if len(gotVariants) != 0 {
+		t.Errorf("want: 0, got: %d", len(gotVariants))
+	}
+}
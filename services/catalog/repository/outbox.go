@@ -0,0 +1,33 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// OutboxRepository stores the events to be published. Events added inside
+// TransactionRepository.Transaction are stored only if the transaction commits.
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...entity.Event) error
+	// ClaimUnpublished claims up to limit unpublished events for claimID until until, and returns the
+	// unpublished events of claimID in the order they were added. The events of other claims are
+	// skipped until their claim expires at now or is released.
+	ClaimUnpublished(ctx context.Context, claimID string, limit int, now, until time.Time) ([]entity.Event, error)
+	// ReleaseClaim releases the unpublished events of claimID, so that they can be claimed again.
+	ReleaseClaim(ctx context.Context, claimID string) error
+	MarkPublished(ctx context.Context, id string, now time.Time) error
+	// ListAfter returns up to limit events with a sequence greater than sequence, in the order they were added.
+	// The sequences are assigned as the events are added, so an event of a transaction that commits after
+	// a later one is returned after the later one was, and the sequences of rolled back events are missing.
+	ListAfter(ctx context.Context, sequence int64, limit int) ([]entity.Event, error)
+	// LastSequence returns the sequence of the last event added, or 0 if there is none.
+	LastSequence(ctx context.Context) (int64, error)
+}
+
+// EventPublisher delivers events to their consumers.
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.Event) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var ErrInvalidPageToken = entity.NewError(entity.ErrInvalidArgument, "invalid page token")
+
+// Page is a request for one page of a keyset-paginated list.
+// Token is an opaque cursor previously returned in PageInfo; an empty token means the first page.
+type Page struct {
+	Size  int
+	Token string
+}
+
+// Limit returns the page size clamped to [1, MaxPageSize], using DefaultPageSize when unset.
+func (p Page) Limit() int {
+	if p.Size <= 0 {
+		return DefaultPageSize
+	}
+	if p.Size > MaxPageSize {
+		return MaxPageSize
+	}
+	return p.Size
+}
+
+// PageInfo carries the cursors of the pages adjacent to the returned one.
+// An empty token means there is no page in that direction.
+type PageInfo struct {
+	NextToken string
+	PrevToken string
+}
@@ -0,0 +1,36 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// PriceRepository reads and writes the explicit prices of catalog items in other currencies than
+// their base price, the exchange rates that convert base prices to the currencies without one,
+// and the history of the base prices, including their scheduled changes.
+type PriceRepository interface {
+	// ListPrices returns the explicit prices of the items, keyed by item ID.
+	ListPrices(ctx context.Context, itemIDs []string) (map[string][]entity.Money, error)
+	ReplacePrices(ctx context.Context, itemID string, prices []entity.Money) error
+	GetExchangeRate(ctx context.Context, from, to string) (*entity.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	UpsertExchangeRate(ctx context.Context, rate entity.ExchangeRate) error
+	DeleteExchangeRate(ctx context.Context, from, to string) error
+	AddPriceHistoryEntry(ctx context.Context, entry entity.PriceHistoryEntry) error
+	// ListPriceHistory returns the price history of the item, the latest effective first.
+	ListPriceHistory(ctx context.Context, itemID string) ([]entity.PriceHistoryEntry, error)
+	// GetPriceAsOf returns the entry of the base price of the item in effect at asOf.
+	GetPriceAsOf(ctx context.Context, itemID string, asOf time.Time) (*entity.PriceHistoryEntry, error)
+	// ListDuePriceChanges returns at most limit scheduled price changes effective at now, the earliest first.
+	// The changes of the items in the trash are not due until the items are restored.
+	ListDuePriceChanges(ctx context.Context, now time.Time, limit int) ([]entity.PriceHistoryEntry, error)
+	// MarkPriceChangeApplied records that a scheduled price change was made at appliedAt, from which it is
+	// effective, so that a change made late, such as that of an item restored from the trash, is not recorded
+	// as in effect before it was made. It fails with a not found error if the change is not scheduled.
+	MarkPriceChangeApplied(ctx context.Context, id string, appliedAt time.Time) error
+	DeletePriceHistoryEntry(ctx context.Context, id string) error
+	DeletePriceHistory(ctx context.Context, itemID string) error
+}
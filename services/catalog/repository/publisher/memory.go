@@ -0,0 +1,36 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// MemoryPublisher keeps the published events in memory.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []entity.Event
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (mp *MemoryPublisher) Publish(_ context.Context, event entity.Event) error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	mp.events = append(mp.events, event)
+	return nil
+}
+
+// Events returns the events published so far, in the order they were published.
+func (mp *MemoryPublisher) Events() []entity.Event {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	events := make([]entity.Event, len(mp.events))
+	copy(events, mp.events)
+	return events
+}
@@ -0,0 +1,38 @@
+package publisher
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+const (
+	KindLog    = "log"
+	KindMemory = "memory"
+)
+
+// NewEventPublisher returns the publisher selected by conf.
+func NewEventPublisher(conf *config.EventConfig) (repository.EventPublisher, error) {
+	switch conf.Publisher {
+	case KindLog:
+		var w io.Writer = os.Stdout
+		if conf.LogFile != "" {
+			f, err := os.OpenFile(conf.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gomnd // file mode
+			if err != nil {
+				log.Error("Failed to open event log file", log.Fstring("path", conf.LogFile), log.Ferror(err))
+				return nil, err
+			}
+			w = f
+		}
+		return NewLogPublisher(w), nil
+	case KindMemory:
+		return NewMemoryPublisher(), nil
+	default:
+		return nil, fmt.Errorf("unknown event publisher: %s", conf.Publisher)
+	}
+}
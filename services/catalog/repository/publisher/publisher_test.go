@@ -0,0 +1,118 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func newTestEvents(t *testing.T) []entity.Event {
+	t.Helper()
+
+	itemID := uuid.New().String()
+	now := time.Now().UTC()
+	created, err := entity.NewEvent(entity.EventTypeCatalogItemCreated, itemID, entity.CatalogItem{ID: itemID, Name: "item", Price: entity.Money{Amount: 10000, Currency: "USD"}}, now)
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	deleted, err := entity.NewEvent(entity.EventTypeCatalogItemDeleted, itemID, entity.Deletion{ID: itemID}, now)
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	return []entity.Event{*created, *deleted}
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	t.Parallel()
+
+	events := newTestEvents(t)
+	mp := NewMemoryPublisher()
+	for _, event := range events {
+		if err := mp.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	if got := mp.Events(); !reflect.DeepEqual(got, events) {
+		t.Errorf("Events() = %v, want %v", got, events)
+	}
+}
+
+func TestLogPublisher(t *testing.T) {
+	t.Parallel()
+
+	events := newTestEvents(t)
+	var buf bytes.Buffer
+	lp := NewLogPublisher(&buf)
+	for _, event := range events {
+		if err := lp.Publish(context.Background(), event); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(events) {
+		t.Fatalf("got %d lines, want %d", len(lines), len(events))
+	}
+	for i, line := range lines {
+		var got entity.Event
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("Unmarshal() error = %v", err)
+		}
+		if got.ID != events[i].ID || got.Type != events[i].Type || string(got.Payload) != string(events[i].Payload) {
+			t.Errorf("line %d = %v, want %v", i, got, events[i])
+		}
+	}
+}
+
+func TestNewEventPublisher(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name    string
+		conf    *config.EventConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "success: log",
+			conf: &config.EventConfig{Publisher: KindLog, LogFile: filepath.Join(t.TempDir(), "events.jsonl")},
+			want: "*publisher.LogPublisher",
+		},
+		{
+			name: "success: memory",
+			conf: &config.EventConfig{Publisher: KindMemory},
+			want: "*publisher.MemoryPublisher",
+		},
+		{
+			name:    "Fail: unknown publisher",
+			conf:    &config.EventConfig{Publisher: "kafka"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewEventPublisher(tt.conf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewEventPublisher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && reflect.TypeOf(got).String() != tt.want {
+				t.Errorf("NewEventPublisher() = %T, want %v", got, tt.want)
+			}
+		})
+	}
+}
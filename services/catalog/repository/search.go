@@ -0,0 +1,50 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// SearchIndex finds catalog items by the words of their names and the attributes of their variants,
+// ranked by relevance. It is kept up to date by the writes of the catalog, and may lag behind them.
+type SearchIndex interface {
+	// Put adds the items to the index, replacing those already in it.
+	Put(ctx context.Context, items ...entity.CatalogItem) error
+	Delete(ctx context.Context, ids ...string) error
+	Search(ctx context.Context, query SearchQuery) (*SearchResult, error)
+}
+
+// SearchQuery is a search of the index. Every word of Text must match a word of an item, exactly,
+// as a prefix or with a typo. MinPrice and MaxPrice, when set, bound the price of the items in
+// their currency: the base price or the explicit price of each item in that currency. Items without
+// a price in that currency are not found.
+type SearchQuery struct {
+	Text     string
+	MinPrice *entity.Money
+	MaxPrice *entity.Money
+	Offset   int
+	Limit    int
+}
+
+// SearchResult is a page of the hits of a search, the most relevant first. Total is the number of
+// hits on all pages.
+type SearchResult struct {
+	Hits  []SearchHit
+	Total int
+}
+
+// SearchHit is an item found by a search. Highlights are the words of the name of the item that
+// matched the query.
+type SearchHit struct {
+	ID         string
+	Score      float64
+	Highlights []Highlight
+}
+
+// Highlight is the range of bytes [Start, End) of a text.
+type Highlight struct {
+	Start int
+	End   int
+}
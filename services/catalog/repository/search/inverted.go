@@ -0,0 +1,310 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// The parameters of BM25: k1 is how quickly the score of a term saturates with its frequency in an item,
+// and b how much the length of an item discounts it.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// The weights of the matches of a query word, relative to an exact match. A typo weighs less the more
+// edits it takes.
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.75
+	typoWeight   = 0.5
+)
+
+// minPrefixLength is the shortest query word matching the words it is a prefix of.
+const minPrefixLength = 2
+
+// minTypoLength is the shortest query word matching the words a typo away; shorter words match
+// too many others. A word matches with one edit from this length, and with two from twice it.
+const minTypoLength = 4
+
+// document is an item in the index. Name is kept to highlight the words of a hit, and prices are the
+// base price and the explicit prices of the item, which price filters are applied to.
+type document struct {
+	id     string
+	name   string
+	terms  map[string]int
+	length int
+	prices []entity.Money
+}
+
+// InvertedIndex is a SearchIndex kept in memory. It maps each term to the items containing it,
+// and scores the items found with BM25.
+type InvertedIndex struct {
+	mu       sync.RWMutex
+	maxEdits int
+	docs     map[string]*document
+	postings map[string]map[string]int
+	// totalLength is the sum of the lengths of the documents, for their average length.
+	totalLength int
+	// vocabulary is the terms of the index in sorted order, for prefix matching.
+	vocabulary []string
+}
+
+func NewInvertedIndex(maxEdits int) *InvertedIndex {
+	return &InvertedIndex{
+		maxEdits: maxEdits,
+		docs:     make(map[string]*document),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+func (ii *InvertedIndex) Put(_ context.Context, items ...entity.CatalogItem) error {
+	ii.mu.Lock()
+	defer ii.mu.Unlock()
+
+	for _, item := range items {
+		ii.remove(item.ID)
+		ii.add(newDocument(item))
+	}
+	return nil
+}
+
+func (ii *InvertedIndex) Delete(_ context.Context, ids ...string) error {
+	ii.mu.Lock()
+	defer ii.mu.Unlock()
+
+	for _, id := range ids {
+		ii.remove(id)
+	}
+	return nil
+}
+
+// newDocument indexes the name of the item and the attributes of its variants, such as their colours.
+func newDocument(item entity.CatalogItem) *document {
+	text := []string{item.Name}
+	for _, variant := range item.Variants {
+		for _, value := range variant.Attributes {
+			text = append(text, value)
+		}
+	}
+
+	doc := &document{
+		id:     item.ID,
+		name:   item.Name,
+		terms:  make(map[string]int),
+		prices: append([]entity.Money{item.Price}, item.Prices...),
+	}
+	for _, t := range tokenize(strings.Join(text, " ")) {
+		doc.terms[t.term]++
+		doc.length++
+	}
+	return doc
+}
+
+func (ii *InvertedIndex) add(doc *document) {
+	ii.docs[doc.id] = doc
+	ii.totalLength += doc.length
+	for term, freq := range doc.terms {
+		if _, ok := ii.postings[term]; !ok {
+			ii.postings[term] = make(map[string]int)
+			i := sort.SearchStrings(ii.vocabulary, term)
+			ii.vocabulary = append(ii.vocabulary, "")
+			copy(ii.vocabulary[i+1:], ii.vocabulary[i:])
+			ii.vocabulary[i] = term
+		}
+		ii.postings[term][doc.id] = freq
+	}
+}
+
+func (ii *InvertedIndex) remove(id string) {
+	doc, ok := ii.docs[id]
+	if !ok {
+		return
+	}
+	delete(ii.docs, id)
+	ii.totalLength -= doc.length
+	for term := range doc.terms {
+		delete(ii.postings[term], id)
+		if len(ii.postings[term]) == 0 {
+			delete(ii.postings, term)
+			i := sort.SearchStrings(ii.vocabulary, term)
+			ii.vocabulary = append(ii.vocabulary[:i], ii.vocabulary[i+1:]...)
+		}
+	}
+}
+
+func (ii *InvertedIndex) Search(_ context.Context, query repository.SearchQuery) (*repository.SearchResult, error) {
+	queryTerms := terms(query.Text)
+	if len(queryTerms) == 0 {
+		return nil, entity.NewError(entity.ErrInvalidArgument, "query has no words")
+	}
+	if err := validatePriceRange(query.MinPrice, query.MaxPrice); err != nil {
+		return nil, err
+	}
+
+	ii.mu.RLock()
+	defer ii.mu.RUnlock()
+
+	// An item is found when every word of the query matches one of its terms. Its score is the sum over
+	// the words of the query of the best weighted score of the terms they match.
+	scores := make(map[string]float64)
+	matched := make(map[string]map[string]bool)
+	for i, qt := range queryTerms {
+		best := make(map[string]float64)
+		for term, weight := range ii.expand(qt) {
+			idf := ii.idf(term)
+			for id, freq := range ii.postings[term] {
+				if i > 0 && matched[id] == nil {
+					continue
+				}
+				if score := weight * ii.bm25(idf, freq, ii.docs[id].length); score > best[id] {
+					best[id] = score
+				}
+				if matched[id] == nil {
+					matched[id] = make(map[string]bool)
+				}
+				matched[id][term] = true
+			}
+		}
+		for id := range matched {
+			if _, ok := best[id]; !ok {
+				delete(matched, id)
+				delete(scores, id)
+				continue
+			}
+			scores[id] += best[id]
+		}
+	}
+
+	hits := make([]repository.SearchHit, 0, len(scores))
+	for id, score := range scores {
+		doc := ii.docs[id]
+		if !doc.inPriceRange(query.MinPrice, query.MaxPrice) {
+			continue
+		}
+		hits = append(hits, repository.SearchHit{
+			ID:         id,
+			Score:      score,
+			Highlights: highlight(doc.name, matched[id]),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if ni, nj := ii.docs[hits[i].ID].name, ii.docs[hits[j].ID].name; ni != nj {
+			return ni < nj
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	result := &repository.SearchResult{Total: len(hits)}
+	if query.Offset < len(hits) {
+		end := len(hits)
+		if query.Limit > 0 && query.Offset+query.Limit < end {
+			end = query.Offset + query.Limit
+		}
+		result.Hits = hits[query.Offset:end]
+	}
+	return result, nil
+}
+
+// expand returns the terms of the index a word of a query matches, with the weight of the match:
+// the word itself, the terms it is a prefix of, and the terms a few typos away.
+func (ii *InvertedIndex) expand(word string) map[string]float64 {
+	expansions := make(map[string]float64)
+	if _, ok := ii.postings[word]; ok {
+		expansions[word] = exactWeight
+	}
+
+	if runeCount(word) >= minPrefixLength {
+		for i := sort.SearchStrings(ii.vocabulary, word); i < len(ii.vocabulary) && strings.HasPrefix(ii.vocabulary[i], word); i++ {
+			if _, ok := expansions[ii.vocabulary[i]]; !ok {
+				expansions[ii.vocabulary[i]] = prefixWeight
+			}
+		}
+	}
+
+	edits := min(runeCount(word)/minTypoLength, ii.maxEdits)
+	if edits > 0 {
+		for _, term := range ii.vocabulary {
+			if _, ok := expansions[term]; ok {
+				continue
+			}
+			if d := editDistance(word, term, edits); d <= edits {
+				expansions[term] = typoWeight / float64(d)
+			}
+		}
+	}
+	return expansions
+}
+
+// idf is the inverse document frequency of term, which weighs the terms found in fewer items higher.
+func (ii *InvertedIndex) idf(term string) float64 {
+	n := float64(len(ii.docs))
+	df := float64(len(ii.postings[term]))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// bm25 scores a term found freq times in an item of length terms.
+func (ii *InvertedIndex) bm25(idf float64, freq, length int) float64 {
+	avgLength := float64(ii.totalLength) / float64(len(ii.docs))
+	tf := float64(freq)
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(length)/avgLength))
+}
+
+func validatePriceRange(minPrice, maxPrice *entity.Money) error {
+	for _, price := range []*entity.Money{minPrice, maxPrice} {
+		if price == nil {
+			continue
+		}
+		if err := price.Validate(); err != nil {
+			return err
+		}
+	}
+	if minPrice != nil && maxPrice != nil {
+		if minPrice.Currency != maxPrice.Currency {
+			return entity.NewError(entity.ErrInvalidArgument, "price range must be in one currency")
+		}
+		if minPrice.Amount > maxPrice.Amount {
+			return entity.NewError(entity.ErrInvalidArgument, "minimum price must not be greater than maximum price")
+		}
+	}
+	return nil
+}
+
+// inPriceRange reports whether the price of the item in the currency of the range is within it.
+func (doc *document) inPriceRange(minPrice, maxPrice *entity.Money) bool {
+	bound := minPrice
+	if bound == nil {
+		bound = maxPrice
+	}
+	if bound == nil {
+		return true
+	}
+	for _, price := range doc.prices {
+		if price.Currency != bound.Currency {
+			continue
+		}
+		return (minPrice == nil || price.Amount >= minPrice.Amount) &&
+			(maxPrice == nil || price.Amount <= maxPrice.Amount)
+	}
+	return false
+}
+
+// highlight returns the ranges of the words of name whose terms are in matched.
+func highlight(name string, matched map[string]bool) []repository.Highlight {
+	var highlights []repository.Highlight
+	for _, t := range tokenize(name) {
+		if matched[t.term] {
+			highlights = append(highlights, repository.Highlight{Start: t.start, End: t.end})
+		}
+	}
+	return highlights
+}
@@ -0,0 +1,195 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+func TestInvertedIndex_Search(t *testing.T) {
+	t.Parallel()
+
+	items := []entity.CatalogItem{
+		{ID: "1", Name: "Red Cotton Shirt", Price: entity.Money{Amount: 2000, Currency: "USD"}},
+		{ID: "2", Name: "Blue Denim Shirt", Price: entity.Money{Amount: 4500, Currency: "USD"}, Prices: []entity.Money{{Amount: 4000, Currency: "EUR"}}},
+		{ID: "3", Name: "Shirt Shirt Hanger", Price: entity.Money{Amount: 500, Currency: "USD"}},
+		{ID: "4", Name: "Mechanical Keyboard", Price: entity.Money{Amount: 9000, Currency: "EUR"}},
+		{
+			ID: "5", Name: "Polo", Price: entity.Money{Amount: 3000, Currency: "USD"},
+			Variants: []entity.Variant{{SKU: "POLO-GREEN", Attributes: map[string]string{"color": "green"}}},
+		},
+	}
+
+	patterns := []struct {
+		name      string
+		query     repository.SearchQuery
+		wantIDs   []string
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name:      "success: items are ranked by relevance",
+			query:     repository.SearchQuery{Text: "shirts"},
+			wantIDs:   []string{"3", "2", "1"},
+			wantTotal: 3,
+		},
+		{
+			name:      "success: every word must match",
+			query:     repository.SearchQuery{Text: "red shirt"},
+			wantIDs:   []string{"1"},
+			wantTotal: 1,
+		},
+		{
+			name:      "success: prefix",
+			query:     repository.SearchQuery{Text: "keyb"},
+			wantIDs:   []string{"4"},
+			wantTotal: 1,
+		},
+		{
+			name:      "success: typo",
+			query:     repository.SearchQuery{Text: "keybaord"},
+			wantIDs:   []string{"4"},
+			wantTotal: 1,
+		},
+		{
+			name:      "success: attributes of variants",
+			query:     repository.SearchQuery{Text: "green"},
+			wantIDs:   []string{"5"},
+			wantTotal: 1,
+		},
+		{
+			name: "success: price range in the base price",
+			query: repository.SearchQuery{
+				Text:     "shirt",
+				MinPrice: &entity.Money{Amount: 1000, Currency: "USD"},
+				MaxPrice: &entity.Money{Amount: 3000, Currency: "USD"},
+			},
+			wantIDs:   []string{"1"},
+			wantTotal: 1,
+		},
+		{
+			name: "success: price range in an explicit price",
+			query: repository.SearchQuery{
+				Text:     "shirt",
+				MaxPrice: &entity.Money{Amount: 5000, Currency: "EUR"},
+			},
+			wantIDs:   []string{"2"},
+			wantTotal: 1,
+		},
+		{
+			name:      "success: page",
+			query:     repository.SearchQuery{Text: "shirt", Offset: 1, Limit: 1},
+			wantIDs:   []string{"2"},
+			wantTotal: 3,
+		},
+		{
+			name:    "Fail: no words",
+			query:   repository.SearchQuery{Text: " ! "},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: price range in two currencies",
+			query: repository.SearchQuery{
+				Text:     "shirt",
+				MinPrice: &entity.Money{Amount: 1000, Currency: "USD"},
+				MaxPrice: &entity.Money{Amount: 3000, Currency: "EUR"},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	ii := NewInvertedIndex(2)
+	if err := ii.Put(context.Background(), items...); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result, err := ii.Search(context.Background(), tt.query)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Search() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var ids []string
+			for _, hit := range result.Hits {
+				ids = append(ids, hit.ID)
+			}
+			if d := cmp.Diff(tt.wantIDs, ids); len(d) != 0 {
+				t.Errorf("differs: (-want +got)\n%s", d)
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("Search() Total = %d, want %d", result.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestInvertedIndex_Highlights(t *testing.T) {
+	t.Parallel()
+
+	ii := NewInvertedIndex(2)
+	ctx := context.Background()
+	if err := ii.Put(ctx, entity.CatalogItem{ID: "1", Name: "Red Cotton Shirts", Price: entity.Money{Amount: 2000, Currency: "USD"}}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	result, err := ii.Search(ctx, repository.SearchQuery{Text: "shirt cot"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	want := []repository.Highlight{{Start: 4, End: 10}, {Start: 11, End: 17}}
+	if len(result.Hits) != 1 {
+		t.Fatalf("Search() Hits = %v, want 1 hit", result.Hits)
+	}
+	if d := cmp.Diff(want, result.Hits[0].Highlights); len(d) != 0 {
+		t.Errorf("differs: (-want +got)\n%s", d)
+	}
+}
+
+func TestInvertedIndex_PutAndDelete(t *testing.T) {
+	t.Parallel()
+
+	ii := NewInvertedIndex(2)
+	ctx := context.Background()
+	item := entity.CatalogItem{ID: "1", Name: "Red Shirt", Price: entity.Money{Amount: 2000, Currency: "USD"}}
+	if err := ii.Put(ctx, item); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	// A renamed item is no longer found by its old name.
+	item.Name = "Blue Shirt"
+	if err := ii.Put(ctx, item); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	result, err := ii.Search(ctx, repository.SearchQuery{Text: "red"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 0 {
+		t.Errorf("Search() Total = %d, want 0", result.Total)
+	}
+	if len(ii.vocabulary) != 2 {
+		t.Errorf("vocabulary = %v, want the terms of the new name", ii.vocabulary)
+	}
+
+	if err = ii.Delete(ctx, item.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	result, err = ii.Search(ctx, repository.SearchQuery{Text: "shirt"})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 0 || len(ii.vocabulary) != 0 {
+		t.Errorf("Search() Total = %d, vocabulary = %v, want none", result.Total, ii.vocabulary)
+	}
+}
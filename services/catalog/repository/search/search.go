@@ -0,0 +1,22 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+const (
+	KindMemory = "memory"
+)
+
+// NewSearchIndex returns the search index selected by conf.
+func NewSearchIndex(conf *config.SearchConfig) (repository.SearchIndex, error) {
+	switch conf.Index {
+	case KindMemory:
+		return NewInvertedIndex(conf.MaxEdits), nil
+	default:
+		return nil, fmt.Errorf("unknown search index: %s", conf.Index)
+	}
+}
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a word of a text, normalized to the term it is indexed under.
+// Start and End are the range of bytes of the word in the text.
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits text into its words, the runs of letters and digits, and normalizes each word
+// to a term: lower case and stemmed, so that "Shirts" and "shirt" are the same term.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+	return tokens
+}
+
+func newToken(text string, start, end int) token {
+	return token{
+		term:  stem(strings.ToLower(text[start:end])),
+		start: start,
+		end:   end,
+	}
+}
+
+// terms returns the distinct terms of text, in the order they first appear.
+func terms(text string) []string {
+	var ts []string
+	seen := make(map[string]bool)
+	for _, t := range tokenize(text) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			ts = append(ts, t.term)
+		}
+	}
+	return ts
+}
+
+// minStemLength is the shortest stem a suffix is removed down to, which keeps short words such as
+// "red" or "bus" whole.
+const minStemLength = 3
+
+// stem removes the common English inflections of a lower case word: plurals and the -ing and -ed
+// forms of verbs. It is deliberately light, and leaves words it does not recognize as they are.
+func stem(word string) string {
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "ies") && runeCount(word) > minStemLength+1:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		return word
+	case strings.HasSuffix(word, "s") && runeCount(word) > minStemLength:
+		return word[:len(word)-1]
+	case strings.HasSuffix(word, "ing") && runeCount(word) >= minStemLength+3:
+		return word[:len(word)-3]
+	case strings.HasSuffix(word, "ed") && runeCount(word) >= minStemLength+2:
+		return word[:len(word)-2]
+	default:
+		return word
+	}
+}
+
+func runeCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// editDistance returns the Damerau-Levenshtein distance between a and b, in runes: the number of
+// insertions, deletions, substitutions and transpositions of adjacent runes turning a into b.
+// It stops counting at limit, and returns limit+1 for the words further apart.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > limit || -d > limit {
+		return limit + 1
+	}
+
+	// Only the last three rows of the matrix are kept.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(rb)] > limit {
+		return limit + 1
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_tokenize(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name string
+		text string
+		want []token
+	}{
+		{
+			name: "words are lower cased and stemmed",
+			text: "Red Running-Shoes",
+			want: []token{
+				{term: "red", start: 0, end: 3},
+				{term: "runn", start: 4, end: 11},
+				{term: "shoe", start: 12, end: 17},
+			},
+		},
+		{
+			name: "offsets are in bytes",
+			text: "Café Crème 2",
+			want: []token{
+				{term: "café", start: 0, end: 5},
+				{term: "crème", start: 6, end: 12},
+				{term: "2", start: 13, end: 14},
+			},
+		},
+		{
+			name: "no words",
+			text: " - ",
+			want: nil,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tokenize(tt.text)
+			if d := cmp.Diff(tt.want, got, cmp.AllowUnexported(token{})); len(d) != 0 {
+				t.Errorf("differs: (-want +got)\n%s", d)
+			}
+		})
+	}
+}
+
+func Test_stem(t *testing.T) {
+	t.Parallel()
+
+	patterns := map[string]string{
+		"shirts":    "shirt",
+		"dresses":   "dress",
+		"batteries": "battery",
+		"glass":     "glass",
+		"bus":       "bus",
+		"red":       "red",
+		"printed":   "print",
+		"ring":      "ring",
+	}
+	for word, want := range patterns {
+		if got := stem(word); got != want {
+			t.Errorf("stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{a: "shirt", b: "shirt", limit: 2, want: 0},
+		{a: "shrit", b: "shirt", limit: 2, want: 1},
+		{a: "shit", b: "shirt", limit: 2, want: 1},
+		{a: "keyboard", b: "keybaord", limit: 2, want: 1},
+		{a: "monitor", b: "mentor", limit: 2, want: 2},
+		{a: "laptop", b: "desktop", limit: 2, want: 3},
+		{a: "mug", b: "keyboard", limit: 1, want: 2},
+	}
+	for _, tt := range patterns {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
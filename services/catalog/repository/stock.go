@@ -0,0 +1,23 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// StockRepository reads and writes stock levels and reservations. The Lock methods lock the
+// rows they return until the transaction in ctx ends, so they must be called inside
+// TransactionRepository.Transaction.
+type StockRepository interface {
+	// LockItems locks the items, including those in the trash, whose stock is still released and committed.
+	LockItems(ctx context.Context, ids []string) ([]entity.CatalogItem, error)
+	UpdateStock(ctx context.Context, item entity.CatalogItem) error
+	LockVariants(ctx context.Context, skus []string) ([]entity.Variant, error)
+	UpdateVariantStock(ctx context.Context, variant entity.Variant) error
+	CreateReservations(ctx context.Context, reservations []entity.StockReservation) error
+	LockReservations(ctx context.Context, reservationID string) ([]entity.StockReservation, error)
+	UpdateReservationStatus(ctx context.Context, reservationID string, status entity.ReservationStatus, updatedAt time.Time) error
+}
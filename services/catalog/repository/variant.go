@@ -0,0 +1,20 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// VariantRepository reads and writes the variants of catalog items, which are identified by
+// their SKU. The stock of the variants is reserved through StockRepository.
+type VariantRepository interface {
+	Get(ctx context.Context, sku string) (*entity.Variant, error)
+	// ListByCatalogItemIDs returns the variants of the items in the order of their SKU, keyed by item ID.
+	ListByCatalogItemIDs(ctx context.Context, itemIDs []string) (map[string][]entity.Variant, error)
+	Create(ctx context.Context, variant entity.Variant) error
+	Update(ctx context.Context, variant entity.Variant) error
+	Delete(ctx context.Context, sku string) error
+	DeleteByCatalogItemID(ctx context.Context, itemID string) error
+}
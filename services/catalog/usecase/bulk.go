@@ -0,0 +1,393 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// The formats the catalog items are imported and exported in.
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+// MaxImportErrors is the number of failed rows an import reports at most.
+const MaxImportErrors = 1000
+
+// maxRecordLength is the length in bytes a line of a JSON Lines file can have at most.
+const maxRecordLength = 64 * 1024
+
+// CatalogItemRecord is a catalog item as a row of a file, with its base price as a decimal amount in the major
+// unit of its currency. The columns of a CSV file are named after the JSON fields, and can be in any order.
+// ID is exported for reference only: an import matches the items by ExternalKey, and ignores ID.
+type CatalogItemRecord struct {
+	ExternalKey string `json:"external_key"`
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Price       string `json:"price"`
+	Currency    string `json:"currency"`
+	Stock       int    `json:"stock"`
+}
+
+// recordColumns are the columns of a CSV file, in the order they are exported in.
+var recordColumns = []string{"external_key", "id", "name", "price", "currency", "stock"}
+
+// ImportReport is the outcome of an import. In a dry run, the counts are of the changes the import would make.
+type ImportReport struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	Failed    int
+	// Errors are the first MaxImportErrors of the rows that failed, in the order of the file.
+	Errors []ImportError
+	DryRun bool
+}
+
+// ImportError is a row that failed to import. Row is the line it starts on in the file.
+type ImportError struct {
+	Row         int
+	ExternalKey string
+	Message     string
+}
+
+func (r *ImportReport) fail(row int, key string, err error) {
+	r.Failed++
+	if len(r.Errors) < MaxImportErrors {
+		r.Errors = append(r.Errors, ImportError{Row: row, ExternalKey: key, Message: err.Error()})
+	}
+}
+
+func (cu *catalogItemUseCase) ImportCatalogItems(ctx context.Context, format string, r io.Reader, dryRun bool) (*ImportReport, error) {
+	reader, err := newRecordReader(format, r)
+	if err != nil {
+		log.Warn("Invalid import", log.Fstring("format", format), log.Ferror(err))
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: dryRun}
+	seen := make(map[string]int)
+	for {
+		record, row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			if first, ok := seen[record.ExternalKey]; ok && record.ExternalKey != "" {
+				err = entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("external key is already in row %d", first))
+			} else {
+				seen[record.ExternalKey] = row
+				err = cu.importRecord(ctx, record, dryRun, report)
+			}
+		}
+		var domainErr *entity.Error
+		if errors.As(err, &domainErr) {
+			report.fail(row, record.ExternalKey, err)
+			continue
+		}
+		if err != nil {
+			log.Error("Failed to import catalog items", log.Fint("row", row), log.Ferror(err))
+			return nil, err
+		}
+	}
+
+	log.Info(
+		"Imported catalog items",
+		log.Fint("created", report.Created),
+		log.Fint("updated", report.Updated),
+		log.Fint("failed", report.Failed),
+		log.Fbool("dry_run", dryRun),
+	)
+	return report, nil
+}
+
+// importRecord creates the item of a record, or updates the item with its external key, and counts it in report.
+// The item is validated as NewCatalogItem creates it, whether or not it exists. A key of an item in the trash
+// fails the record, as the item has to be restored before it is updated.
+func (cu *catalogItemUseCase) importRecord(ctx context.Context, record CatalogItemRecord, dryRun bool, report *ImportReport) error {
+	price, err := entity.ParseMoney(record.Price, record.Currency)
+	if err != nil {
+		return err
+	}
+	item, err := entity.NewCatalogItem("", record.Name, price)
+	if err != nil {
+		return err
+	}
+	if err = item.SetExternalKey(record.ExternalKey); err != nil {
+		return err
+	}
+	if err = item.SetStock(record.Stock); err != nil {
+		return err
+	}
+
+	existing, err := cu.cr.GetByExternalKey(ctx, item.ExternalKey)
+	if errors.Is(err, entity.ErrNotFound) {
+		if !dryRun {
+			if err = cu.createItem(ctx, item); err != nil {
+				return err
+			}
+		}
+		report.Created++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.DeletedAt != nil {
+		return entity.NewError(entity.ErrFailedPrecondition, "catalog item with this external key is in the trash")
+	}
+
+	items := []entity.CatalogItem{*existing}
+	if err = cu.load(ctx, items, ""); err != nil {
+		return err
+	}
+	existing = &items[0]
+	oldPrice := existing.Price
+	changed, err := setFields(existing, UpdateCatalogItemParams{
+		Name:  item.Name,
+		Price: item.Price,
+		Stock: item.Stock,
+	}, map[string]bool{
+		entity.CatalogItemFieldName:  true,
+		entity.CatalogItemFieldPrice: true,
+		entity.CatalogItemFieldStock: true,
+	})
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		report.Unchanged++
+		return nil
+	}
+	if !dryRun {
+		if err = cu.saveItem(ctx, existing, oldPrice, changed); err != nil {
+			return err
+		}
+	}
+	report.Updated++
+	return nil
+}
+
+// exportPageSize is the number of items an export reads at a time.
+const exportPageSize = repository.MaxPageSize
+
+func (cu *catalogItemUseCase) ExportCatalogItems(ctx context.Context, format string, w io.Writer) error {
+	writer, err := newRecordWriter(format, w)
+	if err != nil {
+		log.Warn("Invalid export", log.Fstring("format", format), log.Ferror(err))
+		return err
+	}
+
+	page := repository.Page{Size: exportPageSize}
+	for {
+		items, info, err := cu.cr.List(ctx, page)
+		if err != nil {
+			log.Error("Failed to list catalog items", log.Ferror(err))
+			return err
+		}
+		for _, item := range items {
+			if err = writer.Write(CatalogItemRecord{
+				ExternalKey: item.ExternalKey,
+				ID:          item.ID,
+				Name:        item.Name,
+				Price:       item.Price.Decimal(),
+				Currency:    item.Price.Currency,
+				Stock:       item.Stock,
+			}); err != nil {
+				log.Error("Failed to export catalog items", log.Ferror(err))
+				return err
+			}
+		}
+		if info.NextToken == "" {
+			break
+		}
+		page.Token = info.NextToken
+	}
+	return writer.Flush()
+}
+
+// recordReader reads the records of a file one at a time, along with the row each starts on.
+// A record that cannot be read is reported as a domain error, and the following ones are still read.
+type recordReader interface {
+	Read() (CatalogItemRecord, int, error)
+}
+
+func newRecordReader(format string, r io.Reader) (recordReader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVRecordReader(r)
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxRecordLength)
+		return &jsonlRecordReader{scanner: scanner}, nil
+	default:
+		return nil, unsupportedFormat(format)
+	}
+}
+
+type csvRecordReader struct {
+	reader *csv.Reader
+	// columns are the positions of the columns in the rows, by name.
+	columns map[string]int
+}
+
+func newCSVRecordReader(r io.Reader) (*csvRecordReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, entity.NewError(entity.ErrInvalidArgument, "file is empty")
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, entity.WrapError(entity.ErrInvalidArgument, fmt.Sprintf("invalid header: %v", parseErr.Err), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range recordColumns {
+		if _, ok := columns[name]; !ok && name != "id" {
+			return nil, entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("header has no %s column", name))
+		}
+	}
+	return &csvRecordReader{reader: reader, columns: columns}, nil
+}
+
+func (cr *csvRecordReader) Read() (CatalogItemRecord, int, error) {
+	row, err := cr.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return CatalogItemRecord{}, parseErr.StartLine, entity.WrapError(entity.ErrInvalidArgument, parseErr.Err.Error(), err)
+	}
+	if err != nil {
+		return CatalogItemRecord{}, 0, err
+	}
+	line, _ := cr.reader.FieldPos(0)
+
+	field := func(name string) string {
+		if i, ok := cr.columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	record := CatalogItemRecord{
+		ExternalKey: field("external_key"),
+		ID:          field("id"),
+		Name:        field("name"),
+		Price:       field("price"),
+		Currency:    field("currency"),
+	}
+	if stock := field("stock"); stock != "" {
+		if record.Stock, err = strconv.Atoi(stock); err != nil {
+			return record, line, entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("invalid stock %q", stock))
+		}
+	}
+	return record, line, nil
+}
+
+type jsonlRecordReader struct {
+	scanner *bufio.Scanner
+	line    int
+	// stopped is set once a line is too long to read, as no line after it can be read either.
+	stopped bool
+}
+
+func (jr *jsonlRecordReader) Read() (CatalogItemRecord, int, error) {
+	if jr.stopped {
+		return CatalogItemRecord{}, jr.line, io.EOF
+	}
+	for jr.scanner.Scan() {
+		jr.line++
+		b := jr.scanner.Bytes()
+		if len(b) == 0 {
+			continue
+		}
+		var record CatalogItemRecord
+		if err := json.Unmarshal(b, &record); err != nil {
+			return record, jr.line, entity.WrapError(entity.ErrInvalidArgument, fmt.Sprintf("invalid JSON: %v", err), err)
+		}
+		return record, jr.line, nil
+	}
+	if errors.Is(jr.scanner.Err(), bufio.ErrTooLong) {
+		jr.stopped = true
+		msg := fmt.Sprintf("line is longer than %d bytes, and the lines after it were not read", maxRecordLength)
+		return CatalogItemRecord{}, jr.line + 1, entity.NewError(entity.ErrInvalidArgument, msg)
+	}
+	if err := jr.scanner.Err(); err != nil {
+		return CatalogItemRecord{}, jr.line, err
+	}
+	return CatalogItemRecord{}, jr.line, io.EOF
+}
+
+// recordWriter writes the records of a file one at a time. Flush must be called after the last one.
+type recordWriter interface {
+	Write(record CatalogItemRecord) error
+	Flush() error
+}
+
+func newRecordWriter(format string, w io.Writer) (recordWriter, error) {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(recordColumns); err != nil {
+			return nil, err
+		}
+		return &csvRecordWriter{writer: writer}, nil
+	case FormatJSONL:
+		return &jsonlRecordWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, unsupportedFormat(format)
+	}
+}
+
+type csvRecordWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvRecordWriter) Write(record CatalogItemRecord) error {
+	return cw.writer.Write([]string{
+		record.ExternalKey,
+		record.ID,
+		record.Name,
+		record.Price,
+		record.Currency,
+		strconv.Itoa(record.Stock),
+	})
+}
+
+func (cw *csvRecordWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+type jsonlRecordWriter struct {
+	encoder *json.Encoder
+}
+
+// Write writes the record on a line of its own, as json.Encoder ends every value with a newline.
+func (jw *jsonlRecordWriter) Write(record CatalogItemRecord) error {
+	return jw.encoder.Encode(record)
+}
+
+func (jw *jsonlRecordWriter) Flush() error {
+	return nil
+}
+
+func unsupportedFormat(format string) error {
+	return entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unsupported format %q, want %q or %q", format, FormatCSV, FormatJSONL))
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_ImportCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	existingID := uuid.New().String()
+	existing := func() *entity.CatalogItem {
+		return &entity.CatalogItem{
+			ID:          existingID,
+			Name:        "item2",
+			ExternalKey: "SUP-0002",
+			Price:       entity.Money{Amount: 2000, Currency: "USD"},
+			Stock:       5,
+			Reserved:    2,
+			Version:     3,
+		}
+	}
+
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	trashed := func() *entity.CatalogItem {
+		return &entity.CatalogItem{
+			ID:          uuid.New().String(),
+			Name:        "item8",
+			ExternalKey: "SUP-0008",
+			Price:       entity.Money{Amount: 100, Currency: "USD"},
+			Stock:       1,
+			DeletedAt:   &deletedAt,
+		}
+	}
+
+	errDB := errors.New("db error")
+
+	csvFile := "name,external_key,price,currency,stock,note\n" +
+		"item1,SUP-0001,10.00,USD,10,new\n" +
+		"item2 renamed,SUP-0002,25.00,USD,5,changed\n" +
+		",SUP-0003,10.00,USD,1,no name\n" +
+		"item1 again,SUP-0001,10.00,USD,1,duplicate\n" +
+		"item4,SUP-0004,10.00,USD,many,bad stock\n"
+	wantErrors := []ImportError{
+		{Row: 4, ExternalKey: "SUP-0003", Message: "name is required"},
+		{Row: 5, ExternalKey: "SUP-0001", Message: "external key is already in row 2"},
+		{Row: 6, ExternalKey: "SUP-0004", Message: `invalid stock "many"`},
+	}
+
+	patterns := []struct {
+		name    string
+		format  string
+		file    string
+		dryRun  bool
+		setup   func(m *mock.MockCatalogItemRepository, m1 *mock.MockPriceRepository, m2 *mock.MockOutboxRepository)
+		want    *ImportReport
+		wantErr error
+	}{
+		{
+			name:   "success: csv",
+			format: FormatCSV,
+			file:   csvFile,
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0001").Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found"))
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, item entity.CatalogItem) {
+					if item.ExternalKey != "SUP-0001" || item.Name != "item1" || item.Price != (entity.Money{Amount: 1000, Currency: "USD"}) || item.Stock != 10 {
+						t.Errorf("unexpected item created: %v", item)
+					}
+				}).Return(nil)
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0002").Return(existing(), nil)
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), entity.CatalogItemFieldName, entity.CatalogItemFieldPrice).Do(func(_ context.Context, item entity.CatalogItem, _ ...string) {
+					if item.ID != existingID || item.Version != 3 {
+						t.Errorf("unexpected item updated: %v", item)
+					}
+				}).Return(nil)
+				pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+			},
+			want: &ImportReport{
+				Created: 1,
+				Updated: 1,
+				Failed:  3,
+				Errors:  wantErrors,
+			},
+		},
+		{
+			name:   "success: dry run makes no change",
+			format: FormatCSV,
+			file:   csvFile,
+			dryRun: true,
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0001").Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found"))
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0002").Return(existing(), nil)
+			},
+			want: &ImportReport{
+				Created: 1,
+				Updated: 1,
+				Failed:  3,
+				Errors:  wantErrors,
+				DryRun:  true,
+			},
+		},
+		{
+			name:   "success: jsonl",
+			format: FormatJSONL,
+			file: `{"external_key":"SUP-0002","name":"item2","price":"20.00","currency":"USD","stock":5}` + "\n" +
+				"\n" +
+				`{"external_key":"SUP-0005","name":` + "\n" +
+				`{"external_key":"SUP-0006","name":"item6","price":"1.5","currency":"JPY","stock":1}` + "\n" +
+				`{"external_key":"SUP-0007","name":"item7","price":"3.00","currency":"USD","stock":1}` + "\n",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0002").Return(existing(), nil)
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0007").Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found"))
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			want: &ImportReport{
+				Created:   1,
+				Unchanged: 1,
+				Failed:    2,
+				Errors: []ImportError{
+					{Row: 3, Message: "invalid JSON: unexpected end of JSON input"},
+					{Row: 4, ExternalKey: "SUP-0006", Message: `invalid amount of money: "1.5" has more than 0 decimal places`},
+				},
+			},
+		},
+		{
+			name:   "Fail: item key is in the trash",
+			format: FormatCSV,
+			file:   "external_key,name,price,currency,stock\nSUP-0008,item8,1.00,USD,1\n",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0008").Return(trashed(), nil)
+			},
+			want: &ImportReport{
+				Failed: 1,
+				Errors: []ImportError{{Row: 2, ExternalKey: "SUP-0008", Message: "catalog item with this external key is in the trash"}},
+			},
+		},
+		{
+			name:   "Fail: item key is in the trash in a dry run",
+			format: FormatCSV,
+			file:   "external_key,name,price,currency,stock\nSUP-0008,item8,1.00,USD,1\n",
+			dryRun: true,
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0008").Return(trashed(), nil)
+			},
+			want: &ImportReport{
+				Failed: 1,
+				Errors: []ImportError{{Row: 2, ExternalKey: "SUP-0008", Message: "catalog item with this external key is in the trash"}},
+				DryRun: true,
+			},
+		},
+		{
+			name:   "Fail: item cannot be read",
+			format: FormatCSV,
+			file:   "external_key,name,price,currency,stock\nSUP-0001,item1,1.00,USD,1\n",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().GetByExternalKey(gomock.Any(), "SUP-0001").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "Fail: header has no price column",
+			format:  FormatCSV,
+			file:    "external_key,name,currency,stock\nSUP-0001,item1,USD,1\n",
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: unsupported format",
+			format:  "xml",
+			file:    "<items/>",
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			pr.EXPECT().ListPrices(gomock.Any(), []string{existingID}).Return(nil, nil).AnyTimes()
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{existingID}).Return(nil, nil).AnyTimes()
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{existingID}).Return(nil, nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(cr, pr, obr)
+			}
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			report, err := tuc.ImportCatalogItems(context.Background(), tt.format, strings.NewReader(tt.file), tt.dryRun)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(report, tt.want); err == nil && len(d) != 0 {
+				t.Errorf("ImportCatalogItems() mismatch (-got +want):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestUseCase_ExportCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	page1 := []entity.CatalogItem{
+		{ID: "id1", Name: "item1", ExternalKey: "SUP-0001", Price: entity.Money{Amount: 1000, Currency: "USD"}, Stock: 10},
+	}
+	page2 := []entity.CatalogItem{
+		{ID: "id2", Name: "item, two", Price: entity.Money{Amount: 1500, Currency: "JPY"}, Stock: 0},
+	}
+
+	patterns := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "success: csv",
+			format: FormatCSV,
+			want: "external_key,id,name,price,currency,stock\n" +
+				"SUP-0001,id1,item1,10.00,USD,10\n" +
+				",id2,\"item, two\",1500,JPY,0\n",
+		},
+		{
+			name:   "success: jsonl",
+			format: FormatJSONL,
+			want: `{"external_key":"SUP-0001","id":"id1","name":"item1","price":"10.00","currency":"USD","stock":10}` + "\n" +
+				`{"external_key":"","id":"id2","name":"item, two","price":"1500","currency":"JPY","stock":0}` + "\n",
+		},
+		{
+			name:    "Fail: unsupported format",
+			format:  "xml",
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			if tt.wantErr == nil {
+				cr.EXPECT().List(gomock.Any(), repository.Page{Size: repository.MaxPageSize}).Return(page1, repository.PageInfo{NextToken: "next"}, nil)
+				cr.EXPECT().List(gomock.Any(), repository.Page{Size: repository.MaxPageSize, Token: "next"}).Return(page2, repository.PageInfo{PrevToken: "prev"}, nil)
+			}
+
+			tuc := NewCatalogItemUseCase(cr, nil, nil, nil, nil, nil, nil, nil, nil)
+
+			var buf bytes.Buffer
+			err := tuc.ExportCatalogItems(context.Background(), tt.format, &buf)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(buf.String(), tt.want); err == nil && len(d) != 0 {
+				t.Errorf("ExportCatalogItems() mismatch (-got +want):\n%s", d)
+			}
+		})
+	}
+}
@@ -3,6 +3,11 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
 
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 
@@ -10,96 +15,394 @@ import (
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
 )
 
+// CatalogItemUseCase manages the catalog. The methods reading items take a currency to return their prices in,
+// and return them at their base price when it is empty.
 type CatalogItemUseCase interface {
-	GetCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error)
-	ListCatalogItems(ctx context.Context) ([]entity.CatalogItem, error)
-	ListCatalogItemsByName(ctx context.Context, name string) ([]entity.CatalogItem, error)
-	ListCatalogItemsByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error)
-	CreateCatalogItem(ctx context.Context, name string, price float64) error
-	UpdateCatalogItem(ctx context.Context, id, name string, price float64) error
+	GetCatalogItem(ctx context.Context, id, currency string) (*entity.CatalogItem, error)
+	// GetCatalogItemAsOf gets an item at the base price it had at asOf, or is scheduled to have when asOf is in
+	// the future. Only the base prices have a history, so the other prices of the item are its current ones.
+	GetCatalogItemAsOf(ctx context.Context, id string, asOf time.Time) (*entity.CatalogItem, error)
+	ListCatalogItems(ctx context.Context, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error)
+	ListCatalogItemsByName(ctx context.Context, name, currency string) ([]entity.CatalogItem, error)
+	ListCatalogItemsByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error)
+	// SearchCatalogItems finds the items matching the words of a query, the most relevant first.
+	SearchCatalogItems(ctx context.Context, params SearchParams) (*SearchResults, error)
+	CreateCatalogItem(ctx context.Context, name string, price entity.Money, stock int) (*entity.CatalogItem, error)
+	UpdateCatalogItem(ctx context.Context, params UpdateCatalogItemParams) (*entity.CatalogItem, error)
+	// DeleteCatalogItem moves the item to the trash, from which it can be restored until it is purged.
 	DeleteCatalogItem(ctx context.Context, id string) error
+	RestoreCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error)
+	// ListDeletedCatalogItems lists the items in the trash at their base prices.
+	ListDeletedCatalogItems(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error)
+	// PurgeCatalogItems deletes for good at most limit items moved to the trash before before,
+	// along with their prices, price history, categories and variants, and returns how many were purged.
+	PurgeCatalogItems(ctx context.Context, before time.Time, limit int) (int, error)
+	SetCatalogItemPrices(ctx context.Context, id string, prices []entity.Money, version int64) (*entity.CatalogItem, error)
+	// GetPriceHistory returns the history of the base price of an item, including its scheduled changes,
+	// the latest effective first.
+	GetPriceHistory(ctx context.Context, id string) ([]entity.PriceHistoryEntry, error)
+	// SchedulePriceChange schedules a change of the base price of an item, which is made once effectiveFrom has come.
+	SchedulePriceChange(ctx context.Context, id string, price entity.Money, effectiveFrom time.Time) (*entity.PriceHistoryEntry, error)
+	// ApplyPriceChanges makes at most limit scheduled price changes due at now, and returns how many were made.
+	ApplyPriceChanges(ctx context.Context, now time.Time, limit int) (int, error)
+	SetExchangeRate(ctx context.Context, from, to, rate string) (*entity.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, from, to string) error
+	GetCategory(ctx context.Context, id string) (*entity.Category, error)
+	// ListCategories returns the category tree depth first, each category right after its parent.
+	ListCategories(ctx context.Context) ([]entity.Category, error)
+	CreateCategory(ctx context.Context, parentID, name, slug string, position int) (*entity.Category, error)
+	UpdateCategory(ctx context.Context, id, parentID, name, slug string, position int) (*entity.Category, error)
+	DeleteCategory(ctx context.Context, id string) error
+	SetCatalogItemCategories(ctx context.Context, id string, categoryIDs []string) (*entity.CatalogItem, error)
+	// ListCatalogItemsByCategory lists the items in the category or any category under it.
+	ListCatalogItemsByCategory(ctx context.Context, categoryID string, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error)
+	GetVariant(ctx context.Context, sku string) (*entity.Variant, error)
+	ListVariants(ctx context.Context, catalogItemID string) ([]entity.Variant, error)
+	CreateVariant(ctx context.Context, catalogItemID, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error)
+	UpdateVariant(ctx context.Context, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error)
+	DeleteVariant(ctx context.Context, sku string) error
+	ReserveStock(ctx context.Context, reservationID string, quantities []StockQuantity) ([]entity.StockReservation, error)
+	ReleaseStock(ctx context.Context, reservationID string) error
+	CommitStock(ctx context.Context, reservationID string) error
+	// ImportCatalogItems reads the records of a file in format from r, and creates an item for each of them or,
+	// when an item has the external key of the record, updates its name, base price and stock. The rows that fail
+	// are reported rather than failing the import, and those before and after them are still imported.
+	// In a dry run, the records are validated and reported on without any change being made.
+	ImportCatalogItems(ctx context.Context, format string, r io.Reader, dryRun bool) (*ImportReport, error)
+	// ExportCatalogItems writes all the items to w in format, at their base prices, in a file that can be imported.
+	ExportCatalogItems(ctx context.Context, format string, w io.Writer) error
+	// WatchCatalogItems calls send with the changes of the catalog made after the change with sequence after,
+	// in order, until ctx is cancelled or send fails. A negative after watches the changes made from now on.
+	WatchCatalogItems(ctx context.Context, after int64, send func(CatalogItemChange) error) error
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeCreated ChangeType = "created"
+	ChangeTypeUpdated ChangeType = "updated"
+	ChangeTypeDeleted ChangeType = "deleted"
+)
+
+// CatalogItemChange is a change of a catalog item. CatalogItem is the item as changed, and is nil when it is deleted.
+// Sequence orders the changes, and is where watching resumes from.
+type CatalogItemChange struct {
+	Type        ChangeType
+	ID          string
+	CatalogItem *entity.CatalogItem
+	Sequence    int64
 }
 
 type catalogItemUseCase struct {
-	cr repository.CatalogItemRepository
+	cr  repository.CatalogItemRepository
+	sr  repository.StockRepository
+	pr  repository.PriceRepository
+	cgr repository.CategoryRepository
+	vr  repository.VariantRepository
+	si  repository.SearchIndex
+	tr  repository.TransactionRepository
+	obr repository.OutboxRepository
+	cw  ChangeWatcher
 }
 
-func NewCatalogItemUseCase(cr repository.CatalogItemRepository) CatalogItemUseCase {
+func NewCatalogItemUseCase(
+	cr repository.CatalogItemRepository,
+	sr repository.StockRepository,
+	pr repository.PriceRepository,
+	cgr repository.CategoryRepository,
+	vr repository.VariantRepository,
+	si repository.SearchIndex,
+	tr repository.TransactionRepository,
+	obr repository.OutboxRepository,
+	cw ChangeWatcher,
+) CatalogItemUseCase {
 	return &catalogItemUseCase{
-		cr: cr,
+		cr:  cr,
+		sr:  sr,
+		pr:  pr,
+		cgr: cgr,
+		vr:  vr,
+		si:  si,
+		tr:  tr,
+		obr: obr,
+		cw:  cw,
 	}
 }
 
-func (cu *catalogItemUseCase) GetCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error) {
+func (cu *catalogItemUseCase) GetCatalogItem(ctx context.Context, id, currency string) (*entity.CatalogItem, error) {
 	item, err := cu.cr.Get(ctx, id)
 	if err != nil {
 		log.Error("Failed to get catalog item", log.Ferror(err))
 		return nil, err
 	}
-	return item, nil
+	items := []entity.CatalogItem{*item}
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, err
+	}
+	return &items[0], nil
 }
 
-func (cu *catalogItemUseCase) ListCatalogItems(ctx context.Context) ([]entity.CatalogItem, error) {
-	items, err := cu.cr.List(ctx)
+func (cu *catalogItemUseCase) ListCatalogItems(ctx context.Context, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error) {
+	items, info, err := cu.cr.List(ctx, page)
 	if err != nil {
 		log.Error("Failed to list catalog items", log.Ferror(err))
-		return nil, err
+		return nil, repository.PageInfo{}, err
 	}
-	return items, nil
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	return items, info, nil
 }
 
-func (cu *catalogItemUseCase) ListCatalogItemsByName(ctx context.Context, name string) ([]entity.CatalogItem, error) {
+func (cu *catalogItemUseCase) ListCatalogItemsByName(ctx context.Context, name, currency string) ([]entity.CatalogItem, error) {
 	items, err := cu.cr.ListByName(ctx, name)
 	if err != nil {
 		log.Error("Failed to list catalog items by name", log.Ferror(err))
 		return nil, err
 	}
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
-func (cu *catalogItemUseCase) ListCatalogItemsByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
+func (cu *catalogItemUseCase) ListCatalogItemsByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error) {
 	items, err := cu.cr.ListByIDs(ctx, ids)
 	if err != nil {
 		log.Error("Failed to list catalog items by ids", log.Ferror(err))
 		return nil, err
 	}
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
-func (cu *catalogItemUseCase) CreateCatalogItem(ctx context.Context, name string, price float64) error {
+func (cu *catalogItemUseCase) CreateCatalogItem(ctx context.Context, name string, price entity.Money, stock int) (*entity.CatalogItem, error) {
 	item, err := entity.NewCatalogItem("", name, price)
 	if err != nil {
 		log.Error("Failed to create catalog item", log.Ferror(err))
-		return err
+		return nil, err
+	}
+	if err = item.SetStock(stock); err != nil {
+		log.Warn("Invalid stock", log.Ferror(err))
+		return nil, err
 	}
-	if err = cu.cr.Create(ctx, *item); err != nil {
+	if err = cu.createItem(ctx, item); err != nil {
 		log.Error("Failed to create catalog item", log.Ferror(err))
-		return err
+		return nil, err
 	}
-	return nil
+	return item, nil
 }
 
-func (cu *catalogItemUseCase) UpdateCatalogItem(ctx context.Context, id, name string, price float64) error {
-	item, err := cu.cr.Get(ctx, id)
+// createItem stores a new item along with its first price.
+func (cu *catalogItemUseCase) createItem(ctx context.Context, item *entity.CatalogItem) error {
+	return cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cu.cr.Create(ctx, *item); err != nil {
+			return err
+		}
+		if err := cu.recordPrice(ctx, item.ID, item.Price, time.Now()); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemCreated, item.ID, item)
+	})
+}
+
+// UpdateCatalogItemParams are the new values of a catalog item. Fields are the fields to update, all of them
+// when it is empty, and the others are left as they are. Version is the version of the item the values were
+// made from; the update is rejected if the item has been updated since, unless it is 0.
+type UpdateCatalogItemParams struct {
+	ID      string
+	Name    string
+	Price   entity.Money
+	Stock   int
+	Version int64
+	Fields  []string
+}
+
+// catalogItemFields are the fields of an item an update can be limited to.
+var catalogItemFields = []string{
+	entity.CatalogItemFieldName,
+	entity.CatalogItemFieldPrice,
+	entity.CatalogItemFieldStock,
+}
+
+func (cu *catalogItemUseCase) UpdateCatalogItem(ctx context.Context, params UpdateCatalogItemParams) (*entity.CatalogItem, error) {
+	masked, err := maskFields(params.Fields, catalogItemFields)
+	if err != nil {
+		log.Warn("Invalid update fields", log.Ferror(err))
+		return nil, err
+	}
+
+	item, err := cu.cr.Get(ctx, params.ID)
 	if err != nil {
 		log.Error("Failed to get catalog item", log.Ferror(err))
-		return err
+		return nil, err
+	}
+	if params.Version != 0 && item.Version != params.Version {
+		log.Warn("Stale catalog item version", log.Fstring("id", params.ID), log.Fint("version", int(params.Version)))
+		return nil, repository.ErrVersionConflict
 	}
 
-	item.Name = name
-	item.Price = price
+	items := []entity.CatalogItem{*item}
+	if err = cu.load(ctx, items, ""); err != nil {
+		return nil, err
+	}
+	item = &items[0]
 
-	if err = cu.cr.Update(ctx, *item); err != nil {
+	oldPrice := item.Price
+	changed, err := setFields(item, params, masked)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return item, nil
+	}
+
+	if err = cu.saveItem(ctx, item, oldPrice, changed); err != nil {
 		log.Error("Failed to update catalog item", log.Ferror(err))
-		return err
+		return nil, err
 	}
-	return nil
+	return item, nil
+}
+
+// setFields sets the masked fields of an item to their values in params, and returns the fields
+// whose values changed, which are the only ones written.
+func setFields(item *entity.CatalogItem, params UpdateCatalogItemParams, masked map[string]bool) ([]string, error) {
+	var changed []string
+	if masked[entity.CatalogItemFieldName] && params.Name != item.Name {
+		if params.Name == "" {
+			log.Warn("Name is required")
+			return nil, entity.NewError(entity.ErrInvalidArgument, "name is required")
+		}
+		item.Name = params.Name
+		changed = append(changed, entity.CatalogItemFieldName)
+	}
+	if masked[entity.CatalogItemFieldPrice] && params.Price != item.Price {
+		if err := setBasePrice(item, params.Price); err != nil {
+			log.Warn("Invalid price", log.Ferror(err))
+			return nil, err
+		}
+		changed = append(changed, entity.CatalogItemFieldPrice)
+	}
+	if masked[entity.CatalogItemFieldStock] && params.Stock != item.Stock {
+		if err := item.SetStock(params.Stock); err != nil {
+			log.Warn("Invalid stock", log.Ferror(err))
+			return nil, err
+		}
+		changed = append(changed, entity.CatalogItemFieldStock)
+	}
+	return changed, nil
+}
+
+// saveItem stores the changed fields of an item, recording its price if it changed from oldPrice.
+func (cu *catalogItemUseCase) saveItem(ctx context.Context, item *entity.CatalogItem, oldPrice entity.Money, changed []string) error {
+	return cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		if slices.Contains(changed, entity.CatalogItemFieldStock) {
+			// The item is locked so that a concurrent reservation cannot raise the reserved
+			// quantity above the stock it is checked against.
+			locked, err := cu.sr.LockItems(ctx, []string{item.ID})
+			if err != nil {
+				return err
+			}
+			if len(locked) == 0 {
+				return ErrCatalogItemNotFound
+			}
+			item.Reserved = locked[0].Reserved
+			if err = item.SetStock(item.Stock); err != nil {
+				return err
+			}
+		}
+		if err := cu.cr.Update(ctx, *item, changed...); err != nil {
+			return err
+		}
+		item.Version++
+		if err := cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item); err != nil {
+			return err
+		}
+		if item.Price == oldPrice {
+			return nil
+		}
+		if err := cu.recordPrice(ctx, item.ID, item.Price, time.Now()); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemPriceChanged, item.ID, entity.PriceChange{
+			CatalogItemID: item.ID,
+			OldPrice:      oldPrice,
+			NewPrice:      item.Price,
+		})
+	})
+}
+
+// maskFields returns the set of fields an update is limited to: fields, or all of them when it is empty.
+func maskFields(fields, all []string) (map[string]bool, error) {
+	if len(fields) == 0 {
+		fields = all
+	}
+	masked := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !slices.Contains(all, field) {
+			return nil, entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unknown field %q", field))
+		}
+		masked[field] = true
+	}
+	return masked, nil
 }
 
 func (cu *catalogItemUseCase) DeleteCatalogItem(ctx context.Context, id string) error {
-	if err := cu.cr.Delete(ctx, id); err != nil {
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		// The prices, categories and variants of the item are kept until it is purged, so that it can be restored.
+		if err := cu.cr.Delete(ctx, id, time.Now()); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemDeleted, id, entity.Deletion{ID: id})
+	}); err != nil {
 		log.Error("Failed to delete catalog item", log.Ferror(err))
 		return err
 	}
 	return nil
 }
+
+func (cu *catalogItemUseCase) WatchCatalogItems(ctx context.Context, after int64, send func(CatalogItemChange) error) error {
+	err := cu.cw.Watch(ctx, after, func(event entity.Event) error {
+		change := CatalogItemChange{
+			ID:       event.AggregateID,
+			Sequence: event.Sequence,
+		}
+		switch event.Type {
+		case entity.EventTypeCatalogItemCreated, entity.EventTypeCatalogItemRestored:
+			// A restored item is created again for the watchers, which saw it deleted.
+			change.Type = ChangeTypeCreated
+		case entity.EventTypeCatalogItemUpdated:
+			change.Type = ChangeTypeUpdated
+		case entity.EventTypeCatalogItemDeleted:
+			change.Type = ChangeTypeDeleted
+			return send(change)
+		default:
+			// A price change is also stored as an update of the item.
+			return nil
+		}
+
+		var item entity.CatalogItem
+		if err := json.Unmarshal(event.Payload, &item); err != nil {
+			return err
+		}
+		change.CatalogItem = &item
+		return send(change)
+	})
+	if err != nil {
+		log.Error("Failed to watch catalog items", log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+// addEvent stores an event in the outbox, within the transaction of ctx.
+func (cu *catalogItemUseCase) addEvent(ctx context.Context, eventType entity.EventType, aggregateID string, payload interface{}) error {
+	event, err := entity.NewEvent(eventType, aggregateID, payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return cu.obr.Add(ctx, *event)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
 )
 
@@ -21,7 +24,7 @@ func TestUseCase_GetCatalogItem(t *testing.T) {
 	item := &entity.CatalogItem{
 		ID:    itemID,
 		Name:  "item",
-		Price: 100,
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
 	}
 
 	patterns := []struct {
@@ -67,14 +70,20 @@ func TestUseCase_GetCatalogItem(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			tr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(tr)
 			}
+			pr.EXPECT().ListPrices(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(tr, nil, pr, cgr, vr, nil, nil, nil, nil)
 
-			getCatalogItem, err := tuc.GetCatalogItem(tt.arg.ctx, tt.arg.id)
+			getCatalogItem, err := tuc.GetCatalogItem(tt.arg.ctx, tt.arg.id, "")
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("GetCatalogItem() error = %v, wantErr %v", err, tt.want.err)
@@ -96,12 +105,12 @@ func TestUseCase_ListCatalogItems(t *testing.T) {
 		{
 			ID:    uuid.New().String(),
 			Name:  "item1",
-			Price: 100,
+			Price: entity.Money{Amount: 10000, Currency: "USD"},
 		},
 		{
 			ID:    uuid.New().String(),
 			Name:  "item2",
-			Price: 200,
+			Price: entity.Money{Amount: 20000, Currency: "USD"},
 		},
 	}
 
@@ -111,31 +120,58 @@ func TestUseCase_ListCatalogItems(t *testing.T) {
 			m *mock.MockCatalogItemRepository,
 		)
 		arg struct {
-			ctx context.Context
+			ctx  context.Context
+			page repository.Page
 		}
 		want struct {
 			items []entity.CatalogItem
+			info  repository.PageInfo
 			err   error
 		}
 	}{
 		{
 			name: "success",
 			setup: func(tr *mock.MockCatalogItemRepository) {
-				tr.EXPECT().List(gomock.Any()).Return(items, nil)
+				tr.EXPECT().List(gomock.Any(), repository.Page{Size: 2}).Return(items, repository.PageInfo{NextToken: "next"}, nil)
 			},
 			arg: struct {
-				ctx context.Context
+				ctx  context.Context
+				page repository.Page
 			}{
-				ctx: context.Background(),
+				ctx:  context.Background(),
+				page: repository.Page{Size: 2},
 			},
 			want: struct {
 				items []entity.CatalogItem
+				info  repository.PageInfo
 				err   error
 			}{
 				items: items,
+				info:  repository.PageInfo{NextToken: "next"},
 				err:   nil,
 			},
 		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(tr *mock.MockCatalogItemRepository) {
+				tr.EXPECT().List(gomock.Any(), repository.Page{Token: "invalid"}).Return(nil, repository.PageInfo{}, repository.ErrInvalidPageToken)
+			},
+			arg: struct {
+				ctx  context.Context
+				page repository.Page
+			}{
+				ctx:  context.Background(),
+				page: repository.Page{Token: "invalid"},
+			},
+			want: struct {
+				items []entity.CatalogItem
+				info  repository.PageInfo
+				err   error
+			}{
+				items: nil,
+				err:   repository.ErrInvalidPageToken,
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -145,14 +181,20 @@ func TestUseCase_ListCatalogItems(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			tr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(tr)
 			}
+			pr.EXPECT().ListPrices(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(tr, nil, pr, cgr, vr, nil, nil, nil, nil)
 
-			getCatalogItems, err := tuc.ListCatalogItems(tt.arg.ctx)
+			getCatalogItems, getInfo, err := tuc.ListCatalogItems(tt.arg.ctx, tt.arg.page, "")
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("ListCatalogItems() error = %v, wantErr %v", err, tt.want.err)
@@ -163,6 +205,9 @@ func TestUseCase_ListCatalogItems(t *testing.T) {
 			if !reflect.DeepEqual(getCatalogItems, tt.want.items) {
 				t.Errorf("ListCatalogItems() got = %v, want %v", getCatalogItems, tt.want.items)
 			}
+			if getInfo != tt.want.info {
+				t.Errorf("ListCatalogItems() got = %v, want %v", getInfo, tt.want.info)
+			}
 		})
 	}
 }
@@ -173,7 +218,7 @@ func TestUseCase_ListCatalogItemsByName(t *testing.T) {
 	item1 := entity.CatalogItem{
 		ID:    uuid.New().String(),
 		Name:  "item1",
-		Price: 100,
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
 	}
 
 	patterns := []struct {
@@ -222,14 +267,20 @@ func TestUseCase_ListCatalogItemsByName(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			tr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(tr)
 			}
+			pr.EXPECT().ListPrices(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(tr, nil, pr, cgr, vr, nil, nil, nil, nil)
 
-			getCatalogItems, err := tuc.ListCatalogItemsByName(tt.arg.ctx, tt.arg.name)
+			getCatalogItems, err := tuc.ListCatalogItemsByName(tt.arg.ctx, tt.arg.name, "")
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("ListCatalogItemsByName() error = %v, wantErr %v", err, tt.want.err)
@@ -250,12 +301,12 @@ func TestUseCase_ListCatalogItemsByIDs(t *testing.T) {
 	item1 := entity.CatalogItem{
 		ID:    uuid.New().String(),
 		Name:  "item1",
-		Price: 100,
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
 	}
 	item2 := entity.CatalogItem{
 		ID:    uuid.New().String(),
 		Name:  "item2",
-		Price: 200,
+		Price: entity.Money{Amount: 20000, Currency: "USD"},
 	}
 
 	patterns := []struct {
@@ -304,14 +355,20 @@ func TestUseCase_ListCatalogItemsByIDs(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			tr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
 				tt.setup(tr)
 			}
+			pr.EXPECT().ListPrices(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(tr, nil, pr, cgr, vr, nil, nil, nil, nil)
 
-			getCatalogItems, err := tuc.ListCatalogItemsByIDs(tt.arg.ctx, tt.arg.ids)
+			getCatalogItems, err := tuc.ListCatalogItemsByIDs(tt.arg.ctx, tt.arg.ids, "")
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("ListCatalogItemsByIDs() error = %v, wantErr %v", err, tt.want.err)
@@ -329,44 +386,106 @@ func TestUseCase_ListCatalogItemsByIDs(t *testing.T) {
 func TestUseCase_CreateCatalogItem(t *testing.T) {
 	t.Parallel()
 
+	errOutbox := errors.New("connection refused")
+
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
+			m3 *mock.MockPriceRepository,
 		)
 		arg struct {
 			ctx   context.Context
 			name  string
-			price float64
+			price entity.Money
+			stock int
 		}
 		wantErr error
 	}{
 		{
 			name: "success",
-			setup: func(tr *mock.MockCatalogItemRepository) {
-				tr.EXPECT().Create(
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, pr *mock.MockPriceRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Create(
 					gomock.Any(),
 					gomock.Any(),
 				).Do(func(_ context.Context, item entity.CatalogItem) {
 					if item.Name != "item" {
 						t.Errorf("unexpected Name: got %v, want %v", item.Name, "item")
 					}
-					if item.Price != 100 {
-						t.Errorf("unexpected Price: got %v, want %v", item.Price, 100)
+					if item.Price != (entity.Money{Amount: 10000, Currency: "USD"}) {
+						t.Errorf("unexpected Price: got %v, want %v", item.Price, entity.Money{Amount: 10000, Currency: "USD"})
+					}
+					if item.Stock != 10 {
+						t.Errorf("unexpected Stock: got %v, want %v", item.Stock, 10)
+					}
+				}).Return(nil)
+				pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Do(func(_ context.Context, entry entity.PriceHistoryEntry) {
+					if entry.CatalogItemID == "" || entry.Price != (entity.Money{Amount: 10000, Currency: "USD"}) || entry.Scheduled() {
+						t.Errorf("unexpected price history entry: %v", entry)
+					}
+				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemCreated {
+						t.Errorf("unexpected events: %v", events)
 					}
 				}).Return(nil)
 			},
 			arg: struct {
 				ctx   context.Context
 				name  string
-				price float64
+				price entity.Money
+				stock int
 			}{
 				ctx:   context.Background(),
 				name:  "item",
-				price: 100,
+				price: entity.Money{Amount: 10000, Currency: "USD"},
+				stock: 10,
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Fail: event cannot be stored",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, pr *mock.MockPriceRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errOutbox)
+			},
+			arg: struct {
+				ctx   context.Context
+				name  string
+				price entity.Money
+				stock int
+			}{
+				ctx:   context.Background(),
+				name:  "item",
+				price: entity.Money{Amount: 10000, Currency: "USD"},
+				stock: 10,
+			},
+			wantErr: errOutbox,
+		},
+		{
+			name: "Fail: negative stock",
+			arg: struct {
+				ctx   context.Context
+				name  string
+				price entity.Money
+				stock int
+			}{
+				ctx:   context.Background(),
+				name:  "item",
+				price: entity.Money{Amount: 10000, Currency: "USD"},
+				stock: -1,
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -375,19 +494,30 @@ func TestUseCase_CreateCatalogItem(t *testing.T) {
 			t.Parallel()
 
 			ctrl := gomock.NewController(t)
-			tr := mock.NewMockCatalogItemRepository(ctrl)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(tr)
+				tt.setup(cr, tr, obr, pr)
 			}
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(cr, nil, pr, nil, nil, nil, tr, obr, nil)
 
-			err := tuc.CreateCatalogItem(tt.arg.ctx, tt.arg.name, tt.arg.price)
+			item, err := tuc.CreateCatalogItem(tt.arg.ctx, tt.arg.name, tt.arg.price, tt.arg.stock)
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("want: %v, got: %v", tt.wantErr, err)
 			}
+			if err == nil {
+				if item.ID == "" {
+					t.Errorf("CreateCatalogItem() returned an item without ID")
+				}
+				if item.Name != tt.arg.name || item.Price != tt.arg.price {
+					t.Errorf("CreateCatalogItem() got = %v, want name %v and price %v", item, tt.arg.name, tt.arg.price)
+				}
+			}
 		})
 	}
 }
@@ -397,57 +527,250 @@ func TestUseCase_UpdateCatalogItem(t *testing.T) {
 
 	itemID := uuid.New().String()
 
-	item := &entity.CatalogItem{
-		ID:    itemID,
-		Name:  "item",
-		Price: 100,
+	newItem := func() *entity.CatalogItem {
+		return &entity.CatalogItem{
+			ID:       itemID,
+			Name:     "item",
+			Price:    entity.Money{Amount: 10000, Currency: "USD"},
+			Stock:    10,
+			Reserved: 4,
+			Version:  3,
+		}
 	}
 
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
+			m3 *mock.MockStockRepository,
 		)
-		arg struct {
-			ctx   context.Context
-			id    string
-			name  string
-			price float64
-		}
-		wantErr error
+		arg        UpdateCatalogItemParams
+		want       entity.CatalogItem
+		wantEvents []entity.EventType
+		wantErr    error
 	}{
 		{
 			name: "success",
-			setup: func(tr *mock.MockCatalogItemRepository) {
-				tr.EXPECT().Get(
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
 					gomock.Any(),
 					itemID,
-				).Return(item, nil)
-				tr.EXPECT().Update(
+				).Return(newItem(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID}).Return([]entity.CatalogItem{*newItem()}, nil)
+				cr.EXPECT().Update(
 					gomock.Any(),
 					gomock.Any(),
-				).Do(func(_ context.Context, item entity.CatalogItem) {
+					entity.CatalogItemFieldName,
+					entity.CatalogItemFieldPrice,
+					entity.CatalogItemFieldStock,
+				).Do(func(_ context.Context, item entity.CatalogItem, _ ...string) {
 					if item.Name != "updated item" {
 						t.Errorf("unexpected Name: got %v, want %v", item.Name, "updated item")
 					}
-					if item.Price != 200 {
-						t.Errorf("unexpected Price: got %v, want %v", item.Price, 200)
+					if item.Price != (entity.Money{Amount: 20000, Currency: "USD"}) {
+						t.Errorf("unexpected Price: got %v, want %v", item.Price, entity.Money{Amount: 20000, Currency: "USD"})
+					}
+					if item.Stock != 20 || item.Reserved != 4 {
+						t.Errorf("unexpected Stock: got %v/%v, want %v/%v", item.Stock, item.Reserved, 20, 4)
+					}
+					if item.Version != 3 {
+						t.Errorf("unexpected Version: got %v, want %v", item.Version, 3)
 					}
 				}).Return(nil)
 			},
-			arg: struct {
-				ctx   context.Context
-				id    string
-				name  string
-				price float64
-			}{
-				ctx:   context.Background(),
-				id:    itemID,
-				name:  "updated item",
-				price: 200,
+			arg: UpdateCatalogItemParams{
+				ID:      itemID,
+				Name:    "updated item",
+				Price:   entity.Money{Amount: 20000, Currency: "USD"},
+				Stock:   20,
+				Version: 3,
+			},
+			want: entity.CatalogItem{
+				Name:    "updated item",
+				Price:   entity.Money{Amount: 20000, Currency: "USD"},
+				Version: 4,
+			},
+			wantEvents: []entity.EventType{entity.EventTypeCatalogItemUpdated, entity.EventTypeCatalogItemPriceChanged},
+			wantErr:    nil,
+		},
+		{
+			name: "success: price is unchanged",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID}).Return([]entity.CatalogItem{*newItem()}, nil)
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), entity.CatalogItemFieldName, entity.CatalogItemFieldStock).Return(nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:    itemID,
+				Name:  "updated item",
+				Price: entity.Money{Amount: 10000, Currency: "USD"},
+				Stock: 20,
+			},
+			want: entity.CatalogItem{
+				Name:    "updated item",
+				Price:   entity.Money{Amount: 10000, Currency: "USD"},
+				Version: 4,
+			},
+			wantEvents: []entity.EventType{entity.EventTypeCatalogItemUpdated},
+			wantErr:    nil,
+		},
+		{
+			name: "success: only the masked fields are updated",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), entity.CatalogItemFieldName).Do(func(_ context.Context, item entity.CatalogItem, _ ...string) {
+					if item.Stock != 10 {
+						t.Errorf("unexpected Stock: got %v, want %v", item.Stock, 10)
+					}
+				}).Return(nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Name:   "updated item",
+				Fields: []string{entity.CatalogItemFieldName},
+			},
+			want: entity.CatalogItem{
+				Name:    "updated item",
+				Price:   entity.Money{Amount: 10000, Currency: "USD"},
+				Version: 4,
+			},
+			wantEvents: []entity.EventType{entity.EventTypeCatalogItemUpdated},
+			wantErr:    nil,
+		},
+		{
+			name: "success: nothing changes",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Name:   "item",
+				Stock:  10,
+				Fields: []string{entity.CatalogItemFieldName, entity.CatalogItemFieldStock},
+			},
+			want: entity.CatalogItem{
+				Name:    "item",
+				Price:   entity.Money{Amount: 10000, Currency: "USD"},
+				Version: 3,
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Fail: unknown field",
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Name:   "updated item",
+				Fields: []string{"reserved"},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: masked name is empty",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Fields: []string{entity.CatalogItemFieldName},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: stock below the reserved quantity",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:    itemID,
+				Name:  "updated item",
+				Price: entity.Money{Amount: 20000, Currency: "USD"},
+				Stock: 3,
+			},
+			wantErr: entity.ErrFailedPrecondition,
+		},
+		{
+			name: "Fail: stock below the quantity reserved since the item was read",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				locked := newItem()
+				locked.Reserved = 8
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID}).Return([]entity.CatalogItem{*locked}, nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Stock:  6,
+				Fields: []string{entity.CatalogItemFieldStock},
+			},
+			wantErr: entity.ErrFailedPrecondition,
+		},
+		{
+			name: "Fail: version is stale",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:      itemID,
+				Name:    "updated item",
+				Price:   entity.Money{Amount: 20000, Currency: "USD"},
+				Stock:   20,
+				Version: 2,
+			},
+			wantErr: entity.ErrConflict,
+		},
+		{
+			name: "Fail: item is updated concurrently",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, sr *mock.MockStockRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					itemID,
+				).Return(newItem(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+			},
+			arg: UpdateCatalogItemParams{
+				ID:     itemID,
+				Name:   "updated item",
+				Fields: []string{entity.CatalogItemFieldName},
+			},
+			wantErr: entity.ErrConflict,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -456,19 +779,56 @@ func TestUseCase_UpdateCatalogItem(t *testing.T) {
 			t.Parallel()
 
 			ctrl := gomock.NewController(t)
-			tr := mock.NewMockCatalogItemRepository(ctrl)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+			sr := mock.NewMockStockRepository(ctrl)
+
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(tr)
+				tt.setup(cr, tr, obr, sr)
+			}
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+
+			// Every price change is recorded in the price history.
+			var wantHistory int
+			if slices.Contains(tt.wantEvents, entity.EventTypeCatalogItemPriceChanged) {
+				wantHistory = 1
 			}
+			pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Do(func(_ context.Context, entry entity.PriceHistoryEntry) {
+				if entry.CatalogItemID != itemID || entry.Price != tt.want.Price || entry.Scheduled() {
+					t.Errorf("unexpected price history entry: %v", entry)
+				}
+			}).Return(nil).Times(wantHistory)
 
-			tuc := NewCatalogItemUseCase(tr)
+			var gotEvents []entity.EventType
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				for _, event := range events {
+					gotEvents = append(gotEvents, event.Type)
+				}
+			}).Return(nil).Times(len(tt.wantEvents))
 
-			err := tuc.UpdateCatalogItem(tt.arg.ctx, tt.arg.id, tt.arg.name, tt.arg.price)
+			tuc := NewCatalogItemUseCase(cr, sr, pr, cgr, vr, nil, tr, obr, nil)
+
+			item, err := tuc.UpdateCatalogItem(context.Background(), tt.arg)
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("want: %v, got: %v", tt.wantErr, err)
 			}
+			if err == nil && (item.ID != tt.arg.ID || item.Name != tt.want.Name || item.Price != tt.want.Price) {
+				t.Errorf("UpdateCatalogItem() got = %v, want id %v, name %v and price %v", item, tt.arg.ID, tt.want.Name, tt.want.Price)
+			}
+			if err == nil && item.Version != tt.want.Version {
+				t.Errorf("UpdateCatalogItem() version = %v, want %v", item.Version, tt.want.Version)
+			}
+			if !reflect.DeepEqual(gotEvents, tt.wantEvents) {
+				t.Errorf("UpdateCatalogItem() events = %v, want %v", gotEvents, tt.wantEvents)
+			}
 		})
 	}
 }
@@ -477,11 +837,14 @@ func TestUsaCase_DeleteCatalogItem(t *testing.T) {
 	t.Parallel()
 
 	itemID := uuid.New().String()
+	errOutbox := errors.New("connection refused")
 
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
 		)
 		arg struct {
 			ctx context.Context
@@ -491,8 +854,16 @@ func TestUsaCase_DeleteCatalogItem(t *testing.T) {
 	}{
 		{
 			name: "success",
-			setup: func(tr *mock.MockCatalogItemRepository) {
-				tr.EXPECT().Delete(gomock.Any(), itemID).Return(nil)
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Delete(gomock.Any(), itemID, gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemDeleted || events[0].AggregateID != itemID {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
 			},
 			arg: struct {
 				ctx context.Context
@@ -503,6 +874,24 @@ func TestUsaCase_DeleteCatalogItem(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Fail: event cannot be stored",
+			setup: func(cr *mock.MockCatalogItemRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Delete(gomock.Any(), itemID, gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errOutbox)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  itemID,
+			},
+			wantErr: errOutbox,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -511,13 +900,20 @@ func TestUsaCase_DeleteCatalogItem(t *testing.T) {
 			t.Parallel()
 
 			ctrl := gomock.NewController(t)
-			tr := mock.NewMockCatalogItemRepository(ctrl)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			pr := mock.NewMockPriceRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(tr)
+				tt.setup(cr, tr, obr)
 			}
+			// The prices, categories and variants are kept for the item to be restored.
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
 
-			tuc := NewCatalogItemUseCase(tr)
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
 
 			err := tuc.DeleteCatalogItem(tt.arg.ctx, tt.arg.id)
 
@@ -527,3 +923,108 @@ func TestUsaCase_DeleteCatalogItem(t *testing.T) {
 		})
 	}
 }
+
+// fakeChangeWatcher sends its events and returns.
+type fakeChangeWatcher struct {
+	events []entity.Event
+}
+
+func (w *fakeChangeWatcher) Watch(_ context.Context, after int64, send func(entity.Event) error) error {
+	for _, event := range w.events {
+		if event.Sequence <= after {
+			continue
+		}
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestUseCase_WatchCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	item := entity.CatalogItem{
+		ID:    uuid.New().String(),
+		Name:  "item",
+		Price: entity.Money{Amount: 10000, Currency: "USD"},
+		Stock: 10,
+	}
+	updated := item
+	updated.Price = entity.Money{Amount: 20000, Currency: "USD"}
+
+	newEvent := func(sequence int64, eventType entity.EventType, payload interface{}) entity.Event {
+		event, err := entity.NewEvent(eventType, item.ID, payload, time.Now())
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		event.Sequence = sequence
+		return *event
+	}
+	events := []entity.Event{
+		newEvent(1, entity.EventTypeCatalogItemCreated, item),
+		newEvent(2, entity.EventTypeCatalogItemUpdated, updated),
+		newEvent(3, entity.EventTypeCatalogItemPriceChanged, entity.PriceChange{CatalogItemID: item.ID, OldPrice: entity.Money{Amount: 10000, Currency: "USD"}, NewPrice: entity.Money{Amount: 20000, Currency: "USD"}}),
+		newEvent(4, entity.EventTypeCatalogItemDeleted, entity.Deletion{ID: item.ID}),
+	}
+	errSend := errors.New("stream closed")
+
+	patterns := []struct {
+		name    string
+		after   int64
+		sendErr error
+		want    []CatalogItemChange
+		wantErr error
+	}{
+		{
+			name:  "success: price changes are sent as updates only",
+			after: 0,
+			want: []CatalogItemChange{
+				{Type: ChangeTypeCreated, ID: item.ID, CatalogItem: &item, Sequence: 1},
+				{Type: ChangeTypeUpdated, ID: item.ID, CatalogItem: &updated, Sequence: 2},
+				{Type: ChangeTypeDeleted, ID: item.ID, Sequence: 4},
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "success: watching resumes after the given change",
+			after: 2,
+			want: []CatalogItemChange{
+				{Type: ChangeTypeDeleted, ID: item.ID, Sequence: 4},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "Fail: a change cannot be sent",
+			after:   0,
+			sendErr: errSend,
+			want:    nil,
+			wantErr: errSend,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tuc := NewCatalogItemUseCase(nil, nil, nil, nil, nil, nil, nil, nil, &fakeChangeWatcher{events: events})
+
+			var got []CatalogItemChange
+			err := tuc.WatchCatalogItems(context.Background(), tt.after, func(change CatalogItemChange) error {
+				if tt.sendErr != nil {
+					return tt.sendErr
+				}
+				got = append(got, change)
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WatchCatalogItems() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
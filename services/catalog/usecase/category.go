@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// load attaches their categories, variants and explicit prices to items, and localizes their prices to currency.
+func (cu *catalogItemUseCase) load(ctx context.Context, items []entity.CatalogItem, currency string) error {
+	if err := cu.attachCategories(ctx, items); err != nil {
+		return err
+	}
+	if err := cu.attachVariants(ctx, items); err != nil {
+		return err
+	}
+	return cu.localize(ctx, items, currency)
+}
+
+func (cu *catalogItemUseCase) attachCategories(ctx context.Context, items []entity.CatalogItem) error {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	categoryIDs, err := cu.cgr.ListItemCategoryIDs(ctx, ids)
+	if err != nil {
+		log.Error("Failed to list catalog item categories", log.Ferror(err))
+		return err
+	}
+	for i := range items {
+		items[i].CategoryIDs = categoryIDs[items[i].ID]
+	}
+	return nil
+}
+
+func (cu *catalogItemUseCase) GetCategory(ctx context.Context, id string) (*entity.Category, error) {
+	category, err := cu.cgr.Get(ctx, id)
+	if err != nil {
+		log.Error("Failed to get category", log.Ferror(err))
+		return nil, err
+	}
+	return category, nil
+}
+
+func (cu *catalogItemUseCase) ListCategories(ctx context.Context) ([]entity.Category, error) {
+	categories, err := cu.cgr.List(ctx)
+	if err != nil {
+		log.Error("Failed to list categories", log.Ferror(err))
+		return nil, err
+	}
+	return entity.SortCategories(categories), nil
+}
+
+func (cu *catalogItemUseCase) CreateCategory(ctx context.Context, parentID, name, slug string, position int) (*entity.Category, error) {
+	category, err := entity.NewCategory("", parentID, name, slug, position)
+	if err != nil {
+		log.Warn("Invalid category", log.Ferror(err))
+		return nil, err
+	}
+
+	if err = cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		if parentID != "" {
+			parent, err := cu.cgr.Get(ctx, parentID)
+			if err != nil {
+				return err
+			}
+			category.Depth = parent.Depth + 1
+		}
+		return cu.cgr.Create(ctx, *category)
+	}); err != nil {
+		log.Error("Failed to create category", log.Ferror(err))
+		return nil, err
+	}
+	return category, nil
+}
+
+func (cu *catalogItemUseCase) UpdateCategory(ctx context.Context, id, parentID, name, slug string, position int) (*entity.Category, error) {
+	var category *entity.Category
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if category, err = cu.cgr.Get(ctx, id); err != nil {
+			return err
+		}
+		if err = category.Set(name, slug, position); err != nil {
+			return err
+		}
+
+		if parentID != category.ParentID {
+			// A category cannot be moved under itself or any category under it.
+			descendantIDs, err := cu.cgr.ListDescendantIDs(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, descendantID := range descendantIDs {
+				if descendantID == parentID {
+					return entity.ErrCategoryCycle
+				}
+			}
+			category.ParentID = parentID
+			category.Depth = 0
+			if parentID != "" {
+				parent, err := cu.cgr.Get(ctx, parentID)
+				if err != nil {
+					return err
+				}
+				category.Depth = parent.Depth + 1
+			}
+		}
+		return cu.cgr.Update(ctx, *category)
+	}); err != nil {
+		log.Error("Failed to update category", log.Ferror(err))
+		return nil, err
+	}
+	return category, nil
+}
+
+func (cu *catalogItemUseCase) DeleteCategory(ctx context.Context, id string) error {
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		descendantIDs, err := cu.cgr.ListDescendantIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(descendantIDs) == 0 {
+			return entity.NewError(entity.ErrNotFound, "category not found")
+		}
+		if len(descendantIDs) > 1 {
+			return entity.ErrCategoryHasChildren
+		}
+		return cu.cgr.Delete(ctx, id)
+	}); err != nil {
+		log.Error("Failed to delete category", log.Ferror(err))
+		return err
+	}
+	return nil
+}
+
+func (cu *catalogItemUseCase) SetCatalogItemCategories(ctx context.Context, id string, categoryIDs []string) (*entity.CatalogItem, error) {
+	item, err := cu.cr.Get(ctx, id)
+	if err != nil {
+		log.Error("Failed to get catalog item", log.Ferror(err))
+		return nil, err
+	}
+	items := []entity.CatalogItem{*item}
+	if err = cu.load(ctx, items, ""); err != nil {
+		return nil, err
+	}
+	item = &items[0]
+
+	// Each category is assigned once, in the order first given.
+	seen := make(map[string]bool, len(categoryIDs))
+	item.CategoryIDs = nil
+	for _, categoryID := range categoryIDs {
+		if categoryID == "" || seen[categoryID] {
+			continue
+		}
+		seen[categoryID] = true
+		item.CategoryIDs = append(item.CategoryIDs, categoryID)
+	}
+
+	if err = cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		for _, categoryID := range item.CategoryIDs {
+			if _, err := cu.cgr.Get(ctx, categoryID); err != nil {
+				return err
+			}
+		}
+		if err := cu.cgr.ReplaceItemCategories(ctx, item.ID, item.CategoryIDs); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item)
+	}); err != nil {
+		log.Error("Failed to set catalog item categories", log.Ferror(err))
+		return nil, err
+	}
+	return item, nil
+}
+
+func (cu *catalogItemUseCase) ListCatalogItemsByCategory(ctx context.Context, categoryID string, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error) {
+	if _, err := cu.cgr.Get(ctx, categoryID); err != nil {
+		log.Error("Failed to get category", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+	items, info, err := cu.cr.ListByCategory(ctx, categoryID, page)
+	if err != nil {
+		log.Error("Failed to list catalog items by category", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	return items, info, nil
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_CreateCategory(t *testing.T) {
+	t.Parallel()
+
+	parentID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCategoryRepository,
+			m1 *mock.MockTransactionRepository,
+		)
+		parentID  string
+		catName   string
+		wantDepth int
+		wantErr   error
+	}{
+		{
+			name: "success: root category",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			catName:   "Electronics",
+			wantDepth: 0,
+			wantErr:   nil,
+		},
+		{
+			name: "success: depth is one below the parent",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Get(gomock.Any(), parentID).Return(&entity.Category{ID: parentID, Name: "Computers", Slug: "computers", Depth: 1}, nil)
+				cgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			parentID:  parentID,
+			catName:   "Laptops",
+			wantDepth: 2,
+			wantErr:   nil,
+		},
+		{
+			name: "Fail: parent not found",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Get(gomock.Any(), parentID).Return(nil, entity.NewError(entity.ErrNotFound, "category not found"))
+			},
+			parentID: parentID,
+			catName:  "Laptops",
+			wantErr:  entity.ErrNotFound,
+		},
+		{
+			name:    "Fail: name is empty",
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cgr, tr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, nil, nil, cgr, nil, nil, tr, nil, nil)
+
+			category, err := cuc.CreateCategory(context.Background(), tt.parentID, tt.catName, "", 0)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && category.Depth != tt.wantDepth {
+				t.Errorf("CreateCategory() Depth = %v, want %v", category.Depth, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestUseCase_UpdateCategory(t *testing.T) {
+	t.Parallel()
+
+	categoryID := uuid.New().String()
+	childID := uuid.New().String()
+	parentID := uuid.New().String()
+
+	category := func() *entity.Category {
+		return &entity.Category{ID: categoryID, Name: "Computers", Slug: "computers"}
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCategoryRepository,
+			m1 *mock.MockTransactionRepository,
+		)
+		parentID  string
+		wantDepth int
+		wantErr   error
+	}{
+		{
+			name: "success: category is moved under another",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Get(gomock.Any(), categoryID).Return(category(), nil)
+				cgr.EXPECT().ListDescendantIDs(gomock.Any(), categoryID).Return([]string{categoryID, childID}, nil)
+				cgr.EXPECT().Get(gomock.Any(), parentID).Return(&entity.Category{ID: parentID, Name: "Electronics", Slug: "electronics"}, nil)
+				cgr.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			parentID:  parentID,
+			wantDepth: 1,
+			wantErr:   nil,
+		},
+		{
+			name: "success: parent is unchanged",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Get(gomock.Any(), categoryID).Return(category(), nil)
+				cgr.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantDepth: 0,
+			wantErr:   nil,
+		},
+		{
+			name: "Fail: category is moved under its child",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().Get(gomock.Any(), categoryID).Return(category(), nil)
+				cgr.EXPECT().ListDescendantIDs(gomock.Any(), categoryID).Return([]string{categoryID, childID}, nil)
+			},
+			parentID: childID,
+			wantErr:  entity.ErrCategoryCycle,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cgr, tr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, nil, nil, cgr, nil, nil, tr, nil, nil)
+
+			category, err := cuc.UpdateCategory(context.Background(), categoryID, tt.parentID, "Computers", "", 0)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && category.Depth != tt.wantDepth {
+				t.Errorf("UpdateCategory() Depth = %v, want %v", category.Depth, tt.wantDepth)
+			}
+		})
+	}
+}
+
+func TestUseCase_DeleteCategory(t *testing.T) {
+	t.Parallel()
+
+	categoryID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCategoryRepository,
+			m1 *mock.MockTransactionRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().ListDescendantIDs(gomock.Any(), categoryID).Return([]string{categoryID}, nil)
+				cgr.EXPECT().Delete(gomock.Any(), categoryID).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: category has children",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().ListDescendantIDs(gomock.Any(), categoryID).Return([]string{categoryID, uuid.New().String()}, nil)
+			},
+			wantErr: entity.ErrCategoryHasChildren,
+		},
+		{
+			name: "Fail: category not found",
+			setup: func(cgr *mock.MockCategoryRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cgr.EXPECT().ListDescendantIDs(gomock.Any(), categoryID).Return(nil, nil)
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cgr, tr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, nil, nil, cgr, nil, nil, tr, nil, nil)
+
+			err := cuc.DeleteCategory(context.Background(), categoryID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestUseCase_ListCatalogItemsByCategory(t *testing.T) {
+	t.Parallel()
+
+	categoryID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockCategoryRepository,
+			m2 *mock.MockPriceRepository,
+			m3 *mock.MockVariantRepository,
+		)
+		wantCategoryIDs []string
+		wantErr         error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCatalogItemRepository, cgr *mock.MockCategoryRepository, pr *mock.MockPriceRepository, vr *mock.MockVariantRepository) {
+				cgr.EXPECT().Get(gomock.Any(), categoryID).Return(&entity.Category{ID: categoryID}, nil)
+				cr.EXPECT().ListByCategory(gomock.Any(), categoryID, repository.Page{Size: 10}).Return([]entity.CatalogItem{
+					{ID: itemID, Name: "item", Price: entity.Money{Amount: 1000, Currency: "USD"}},
+				}, repository.PageInfo{}, nil)
+				cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(map[string][]string{itemID: {categoryID}}, nil)
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil)
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil)
+			},
+			wantCategoryIDs: []string{categoryID},
+			wantErr:         nil,
+		},
+		{
+			name: "Fail: category not found",
+			setup: func(_ *mock.MockCatalogItemRepository, cgr *mock.MockCategoryRepository, _ *mock.MockPriceRepository, _ *mock.MockVariantRepository) {
+				cgr.EXPECT().Get(gomock.Any(), categoryID).Return(nil, entity.NewError(entity.ErrNotFound, "category not found"))
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr, cgr, pr, vr)
+			}
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, nil, nil, nil)
+
+			items, _, err := cuc.ListCatalogItemsByCategory(context.Background(), categoryID, repository.Page{Size: 10}, "")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && (len(items) != 1 || len(items[0].CategoryIDs) != len(tt.wantCategoryIDs)) {
+				t.Errorf("ListCatalogItemsByCategory() got = %v, want one item in %v", items, tt.wantCategoryIDs)
+			}
+		})
+	}
+}
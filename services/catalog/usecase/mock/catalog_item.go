@@ -6,10 +6,14 @@ package mock
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	usecase "github.com/tusmasoma/go-microservice-k8s/services/catalog/usecase"
 )
 
 // MockCatalogItemUseCase is a mock of CatalogItemUseCase interface.
@@ -35,18 +39,78 @@ func (m *MockCatalogItemUseCase) EXPECT() *MockCatalogItemUseCaseMockRecorder {
 	return m.recorder
 }
 
-// CreateCatalogItem mocks base method.
-func (m *MockCatalogItemUseCase) CreateCatalogItem(ctx context.Context, name string, price float64) error {
+// ApplyPriceChanges mocks base method.
+func (m *MockCatalogItemUseCase) ApplyPriceChanges(ctx context.Context, now time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyPriceChanges", ctx, now, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyPriceChanges indicates an expected call of ApplyPriceChanges.
+func (mr *MockCatalogItemUseCaseMockRecorder) ApplyPriceChanges(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyPriceChanges", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ApplyPriceChanges), ctx, now, limit)
+}
+
+// CommitStock mocks base method.
+func (m *MockCatalogItemUseCase) CommitStock(ctx context.Context, reservationID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCatalogItem", ctx, name, price)
+	ret := m.ctrl.Call(m, "CommitStock", ctx, reservationID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// CommitStock indicates an expected call of CommitStock.
+func (mr *MockCatalogItemUseCaseMockRecorder) CommitStock(ctx, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStock", reflect.TypeOf((*MockCatalogItemUseCase)(nil).CommitStock), ctx, reservationID)
+}
+
+// CreateCatalogItem mocks base method.
+func (m *MockCatalogItemUseCase) CreateCatalogItem(ctx context.Context, name string, price entity.Money, stock int) (*entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCatalogItem", ctx, name, price, stock)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // CreateCatalogItem indicates an expected call of CreateCatalogItem.
-func (mr *MockCatalogItemUseCaseMockRecorder) CreateCatalogItem(ctx, name, price interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) CreateCatalogItem(ctx, name, price, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).CreateCatalogItem), ctx, name, price, stock)
+}
+
+// CreateCategory mocks base method.
+func (m *MockCatalogItemUseCase) CreateCategory(ctx context.Context, parentID, name, slug string, position int) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, parentID, name, slug, position)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockCatalogItemUseCaseMockRecorder) CreateCategory(ctx, parentID, name, slug, position interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).CreateCatalogItem), ctx, name, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).CreateCategory), ctx, parentID, name, slug, position)
+}
+
+// CreateVariant mocks base method.
+func (m *MockCatalogItemUseCase) CreateVariant(ctx context.Context, catalogItemID, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariant", ctx, catalogItemID, sku, attributes, price, stock)
+	ret0, _ := ret[0].(*entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVariant indicates an expected call of CreateVariant.
+func (mr *MockCatalogItemUseCaseMockRecorder) CreateVariant(ctx, catalogItemID, sku, attributes, price, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariant", reflect.TypeOf((*MockCatalogItemUseCase)(nil).CreateVariant), ctx, catalogItemID, sku, attributes, price, stock)
 }
 
 // DeleteCatalogItem mocks base method.
@@ -63,76 +127,464 @@ func (mr *MockCatalogItemUseCaseMockRecorder) DeleteCatalogItem(ctx, id interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).DeleteCatalogItem), ctx, id)
 }
 
+// DeleteCategory mocks base method.
+func (m *MockCatalogItemUseCase) DeleteCategory(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockCatalogItemUseCaseMockRecorder) DeleteCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).DeleteCategory), ctx, id)
+}
+
+// DeleteExchangeRate mocks base method.
+func (m *MockCatalogItemUseCase) DeleteExchangeRate(ctx context.Context, from, to string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExchangeRate", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExchangeRate indicates an expected call of DeleteExchangeRate.
+func (mr *MockCatalogItemUseCaseMockRecorder) DeleteExchangeRate(ctx, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExchangeRate", reflect.TypeOf((*MockCatalogItemUseCase)(nil).DeleteExchangeRate), ctx, from, to)
+}
+
+// DeleteVariant mocks base method.
+func (m *MockCatalogItemUseCase) DeleteVariant(ctx context.Context, sku string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariant", ctx, sku)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariant indicates an expected call of DeleteVariant.
+func (mr *MockCatalogItemUseCaseMockRecorder) DeleteVariant(ctx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariant", reflect.TypeOf((*MockCatalogItemUseCase)(nil).DeleteVariant), ctx, sku)
+}
+
+// ExportCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) ExportCatalogItems(ctx context.Context, format string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCatalogItems", ctx, format, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCatalogItems indicates an expected call of ExportCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) ExportCatalogItems(ctx, format, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ExportCatalogItems), ctx, format, w)
+}
+
 // GetCatalogItem mocks base method.
-func (m *MockCatalogItemUseCase) GetCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error) {
+func (m *MockCatalogItemUseCase) GetCatalogItem(ctx context.Context, id, currency string) (*entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCatalogItem", ctx, id)
+	ret := m.ctrl.Call(m, "GetCatalogItem", ctx, id, currency)
 	ret0, _ := ret[0].(*entity.CatalogItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCatalogItem indicates an expected call of GetCatalogItem.
-func (mr *MockCatalogItemUseCaseMockRecorder) GetCatalogItem(ctx, id interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) GetCatalogItem(ctx, id, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetCatalogItem), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetCatalogItem), ctx, id, currency)
 }
 
-// ListCatalogItems mocks base method.
-func (m *MockCatalogItemUseCase) ListCatalogItems(ctx context.Context) ([]entity.CatalogItem, error) {
+// GetCatalogItemAsOf mocks base method.
+func (m *MockCatalogItemUseCase) GetCatalogItemAsOf(ctx context.Context, id string, asOf time.Time) (*entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCatalogItems", ctx)
-	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret := m.ctrl.Call(m, "GetCatalogItemAsOf", ctx, id, asOf)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCatalogItemAsOf indicates an expected call of GetCatalogItemAsOf.
+func (mr *MockCatalogItemUseCaseMockRecorder) GetCatalogItemAsOf(ctx, id, asOf interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCatalogItemAsOf", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetCatalogItemAsOf), ctx, id, asOf)
+}
+
+// GetCategory mocks base method.
+func (m *MockCatalogItemUseCase) GetCategory(ctx context.Context, id string) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, id)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockCatalogItemUseCaseMockRecorder) GetCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetCategory), ctx, id)
+}
+
+// GetPriceHistory mocks base method.
+func (m *MockCatalogItemUseCase) GetPriceHistory(ctx context.Context, id string) ([]entity.PriceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, id)
+	ret0, _ := ret[0].([]entity.PriceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockCatalogItemUseCaseMockRecorder) GetPriceHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetPriceHistory), ctx, id)
+}
+
+// GetVariant mocks base method.
+func (m *MockCatalogItemUseCase) GetVariant(ctx context.Context, sku string) (*entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariant", ctx, sku)
+	ret0, _ := ret[0].(*entity.Variant)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// GetVariant indicates an expected call of GetVariant.
+func (mr *MockCatalogItemUseCaseMockRecorder) GetVariant(ctx, sku interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariant", reflect.TypeOf((*MockCatalogItemUseCase)(nil).GetVariant), ctx, sku)
+}
+
+// ImportCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) ImportCatalogItems(ctx context.Context, format string, r io.Reader, dryRun bool) (*usecase.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCatalogItems", ctx, format, r, dryRun)
+	ret0, _ := ret[0].(*usecase.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCatalogItems indicates an expected call of ImportCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) ImportCatalogItems(ctx, format, r, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ImportCatalogItems), ctx, format, r, dryRun)
+}
+
+// ListCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) ListCatalogItems(ctx context.Context, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCatalogItems", ctx, page, currency)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // ListCatalogItems indicates an expected call of ListCatalogItems.
-func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItems(ctx interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItems(ctx, page, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItems), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItems), ctx, page, currency)
+}
+
+// ListCatalogItemsByCategory mocks base method.
+func (m *MockCatalogItemUseCase) ListCatalogItemsByCategory(ctx context.Context, categoryID string, page repository.Page, currency string) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCatalogItemsByCategory", ctx, categoryID, page, currency)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCatalogItemsByCategory indicates an expected call of ListCatalogItemsByCategory.
+func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItemsByCategory(ctx, categoryID, page, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItemsByCategory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItemsByCategory), ctx, categoryID, page, currency)
 }
 
 // ListCatalogItemsByIDs mocks base method.
-func (m *MockCatalogItemUseCase) ListCatalogItemsByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
+func (m *MockCatalogItemUseCase) ListCatalogItemsByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCatalogItemsByIDs", ctx, ids)
+	ret := m.ctrl.Call(m, "ListCatalogItemsByIDs", ctx, ids, currency)
 	ret0, _ := ret[0].([]entity.CatalogItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListCatalogItemsByIDs indicates an expected call of ListCatalogItemsByIDs.
-func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItemsByIDs(ctx, ids interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItemsByIDs(ctx, ids, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItemsByIDs", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItemsByIDs), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItemsByIDs", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItemsByIDs), ctx, ids, currency)
 }
 
 // ListCatalogItemsByName mocks base method.
-func (m *MockCatalogItemUseCase) ListCatalogItemsByName(ctx context.Context, name string) ([]entity.CatalogItem, error) {
+func (m *MockCatalogItemUseCase) ListCatalogItemsByName(ctx context.Context, name, currency string) ([]entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCatalogItemsByName", ctx, name)
+	ret := m.ctrl.Call(m, "ListCatalogItemsByName", ctx, name, currency)
 	ret0, _ := ret[0].([]entity.CatalogItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListCatalogItemsByName indicates an expected call of ListCatalogItemsByName.
-func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItemsByName(ctx, name interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) ListCatalogItemsByName(ctx, name, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItemsByName", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItemsByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCatalogItemsByName", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCatalogItemsByName), ctx, name, currency)
 }
 
-// UpdateCatalogItem mocks base method.
-func (m *MockCatalogItemUseCase) UpdateCatalogItem(ctx context.Context, id, name string, price float64) error {
+// ListCategories mocks base method.
+func (m *MockCatalogItemUseCase) ListCategories(ctx context.Context) ([]entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCatalogItemUseCaseMockRecorder) ListCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListCategories), ctx)
+}
+
+// ListDeletedCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) ListDeletedCatalogItems(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedCatalogItems", ctx, page)
+	ret0, _ := ret[0].([]entity.CatalogItem)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeletedCatalogItems indicates an expected call of ListDeletedCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) ListDeletedCatalogItems(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListDeletedCatalogItems), ctx, page)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockCatalogItemUseCase) ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", ctx)
+	ret0, _ := ret[0].([]entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockCatalogItemUseCaseMockRecorder) ListExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListExchangeRates), ctx)
+}
+
+// ListVariants mocks base method.
+func (m *MockCatalogItemUseCase) ListVariants(ctx context.Context, catalogItemID string) ([]entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVariants", ctx, catalogItemID)
+	ret0, _ := ret[0].([]entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVariants indicates an expected call of ListVariants.
+func (mr *MockCatalogItemUseCaseMockRecorder) ListVariants(ctx, catalogItemID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVariants", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ListVariants), ctx, catalogItemID)
+}
+
+// PurgeCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) PurgeCatalogItems(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCatalogItems", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeCatalogItems indicates an expected call of PurgeCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) PurgeCatalogItems(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).PurgeCatalogItems), ctx, before, limit)
+}
+
+// ReleaseStock mocks base method.
+func (m *MockCatalogItemUseCase) ReleaseStock(ctx context.Context, reservationID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateCatalogItem", ctx, id, name, price)
+	ret := m.ctrl.Call(m, "ReleaseStock", ctx, reservationID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// ReleaseStock indicates an expected call of ReleaseStock.
+func (mr *MockCatalogItemUseCaseMockRecorder) ReleaseStock(ctx, reservationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseStock", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ReleaseStock), ctx, reservationID)
+}
+
+// ReserveStock mocks base method.
+func (m *MockCatalogItemUseCase) ReserveStock(ctx context.Context, reservationID string, quantities []usecase.StockQuantity) ([]entity.StockReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveStock", ctx, reservationID, quantities)
+	ret0, _ := ret[0].([]entity.StockReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveStock indicates an expected call of ReserveStock.
+func (mr *MockCatalogItemUseCaseMockRecorder) ReserveStock(ctx, reservationID, quantities interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveStock", reflect.TypeOf((*MockCatalogItemUseCase)(nil).ReserveStock), ctx, reservationID, quantities)
+}
+
+// RestoreCatalogItem mocks base method.
+func (m *MockCatalogItemUseCase) RestoreCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCatalogItem", ctx, id)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCatalogItem indicates an expected call of RestoreCatalogItem.
+func (mr *MockCatalogItemUseCaseMockRecorder) RestoreCatalogItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).RestoreCatalogItem), ctx, id)
+}
+
+// SchedulePriceChange mocks base method.
+func (m *MockCatalogItemUseCase) SchedulePriceChange(ctx context.Context, id string, price entity.Money, effectiveFrom time.Time) (*entity.PriceHistoryEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchedulePriceChange", ctx, id, price, effectiveFrom)
+	ret0, _ := ret[0].(*entity.PriceHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchedulePriceChange indicates an expected call of SchedulePriceChange.
+func (mr *MockCatalogItemUseCaseMockRecorder) SchedulePriceChange(ctx, id, price, effectiveFrom interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchedulePriceChange", reflect.TypeOf((*MockCatalogItemUseCase)(nil).SchedulePriceChange), ctx, id, price, effectiveFrom)
+}
+
+// SearchCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) SearchCatalogItems(ctx context.Context, params usecase.SearchParams) (*usecase.SearchResults, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCatalogItems", ctx, params)
+	ret0, _ := ret[0].(*usecase.SearchResults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCatalogItems indicates an expected call of SearchCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) SearchCatalogItems(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).SearchCatalogItems), ctx, params)
+}
+
+// SetCatalogItemCategories mocks base method.
+func (m *MockCatalogItemUseCase) SetCatalogItemCategories(ctx context.Context, id string, categoryIDs []string) (*entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCatalogItemCategories", ctx, id, categoryIDs)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCatalogItemCategories indicates an expected call of SetCatalogItemCategories.
+func (mr *MockCatalogItemUseCaseMockRecorder) SetCatalogItemCategories(ctx, id, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCatalogItemCategories", reflect.TypeOf((*MockCatalogItemUseCase)(nil).SetCatalogItemCategories), ctx, id, categoryIDs)
+}
+
+// SetCatalogItemPrices mocks base method.
+func (m *MockCatalogItemUseCase) SetCatalogItemPrices(ctx context.Context, id string, prices []entity.Money, version int64) (*entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCatalogItemPrices", ctx, id, prices, version)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCatalogItemPrices indicates an expected call of SetCatalogItemPrices.
+func (mr *MockCatalogItemUseCaseMockRecorder) SetCatalogItemPrices(ctx, id, prices, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCatalogItemPrices", reflect.TypeOf((*MockCatalogItemUseCase)(nil).SetCatalogItemPrices), ctx, id, prices, version)
+}
+
+// SetExchangeRate mocks base method.
+func (m *MockCatalogItemUseCase) SetExchangeRate(ctx context.Context, from, to, rate string) (*entity.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetExchangeRate", ctx, from, to, rate)
+	ret0, _ := ret[0].(*entity.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetExchangeRate indicates an expected call of SetExchangeRate.
+func (mr *MockCatalogItemUseCaseMockRecorder) SetExchangeRate(ctx, from, to, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetExchangeRate", reflect.TypeOf((*MockCatalogItemUseCase)(nil).SetExchangeRate), ctx, from, to, rate)
+}
+
+// UpdateCatalogItem mocks base method.
+func (m *MockCatalogItemUseCase) UpdateCatalogItem(ctx context.Context, params usecase.UpdateCatalogItemParams) (*entity.CatalogItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCatalogItem", ctx, params)
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateCatalogItem indicates an expected call of UpdateCatalogItem.
-func (mr *MockCatalogItemUseCaseMockRecorder) UpdateCatalogItem(ctx, id, name, price interface{}) *gomock.Call {
+func (mr *MockCatalogItemUseCaseMockRecorder) UpdateCatalogItem(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).UpdateCatalogItem), ctx, params)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockCatalogItemUseCase) UpdateCategory(ctx context.Context, id, parentID, name, slug string, position int) (*entity.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, id, parentID, name, slug, position)
+	ret0, _ := ret[0].(*entity.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockCatalogItemUseCaseMockRecorder) UpdateCategory(ctx, id, parentID, name, slug, position interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCatalogItemUseCase)(nil).UpdateCategory), ctx, id, parentID, name, slug, position)
+}
+
+// UpdateVariant mocks base method.
+func (m *MockCatalogItemUseCase) UpdateVariant(ctx context.Context, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVariant", ctx, sku, attributes, price, stock)
+	ret0, _ := ret[0].(*entity.Variant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateVariant indicates an expected call of UpdateVariant.
+func (mr *MockCatalogItemUseCaseMockRecorder) UpdateVariant(ctx, sku, attributes, price, stock interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVariant", reflect.TypeOf((*MockCatalogItemUseCase)(nil).UpdateVariant), ctx, sku, attributes, price, stock)
+}
+
+// WatchCatalogItems mocks base method.
+func (m *MockCatalogItemUseCase) WatchCatalogItems(ctx context.Context, after int64, send func(usecase.CatalogItemChange) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchCatalogItems", ctx, after, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchCatalogItems indicates an expected call of WatchCatalogItems.
+func (mr *MockCatalogItemUseCaseMockRecorder) WatchCatalogItems(ctx, after, send interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCatalogItem", reflect.TypeOf((*MockCatalogItemUseCase)(nil).UpdateCatalogItem), ctx, id, name, price)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchCatalogItems", reflect.TypeOf((*MockCatalogItemUseCase)(nil).WatchCatalogItems), ctx, after, send)
 }
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: watch.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+// MockChangeWatcher is a mock of ChangeWatcher interface.
+type MockChangeWatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockChangeWatcherMockRecorder
+}
+
+// MockChangeWatcherMockRecorder is the mock recorder for MockChangeWatcher.
+type MockChangeWatcherMockRecorder struct {
+	mock *MockChangeWatcher
+}
+
+// NewMockChangeWatcher creates a new mock instance.
+func NewMockChangeWatcher(ctrl *gomock.Controller) *MockChangeWatcher {
+	mock := &MockChangeWatcher{ctrl: ctrl}
+	mock.recorder = &MockChangeWatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChangeWatcher) EXPECT() *MockChangeWatcherMockRecorder {
+	return m.recorder
+}
+
+// Watch mocks base method.
+func (m *MockChangeWatcher) Watch(ctx context.Context, after int64, send func(entity.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Watch", ctx, after, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockChangeWatcherMockRecorder) Watch(ctx, after, send interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockChangeWatcher)(nil).Watch), ctx, after, send)
+}
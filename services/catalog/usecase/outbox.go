@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// OutboxRelay publishes the events stored in the outbox. The relays of all the replicas run at the
+// same time, each claiming a batch of events for the lease, so that an event is published by one relay
+// at a time. An event is marked as published after it has been published, so it is published again if
+// the relay stops in between or outlasts the lease: delivery is at least once, and consumers detect
+// duplicates by the event ID. The events of a batch are published in the order they were added, but
+// batches are not: those of the replicas are published concurrently, and the rest of a batch that
+// failed is published after the batches claimed in the meantime.
+type OutboxRelay struct {
+	obr       repository.OutboxRepository
+	ep        repository.EventPublisher
+	feed      *ChangeFeed
+	interval  time.Duration
+	batchSize int
+	lease     time.Duration
+}
+
+func NewOutboxRelay(obr repository.OutboxRepository, ep repository.EventPublisher, feed *ChangeFeed, conf *config.EventConfig) *OutboxRelay {
+	return &OutboxRelay{
+		obr:       obr,
+		ep:        ep,
+		feed:      feed,
+		interval:  conf.RelayInterval,
+		batchSize: conf.RelayBatchSize,
+		lease:     conf.RelayLease,
+	}
+}
+
+// Run relays the outbox until ctx is cancelled, notifying the watchers whenever events are published.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			published, err := r.Relay(ctx)
+			if err != nil {
+				log.Error("Failed to relay outbox", log.Ferror(err))
+			}
+			if published > 0 {
+				r.feed.Notify()
+			}
+		}
+	}
+}
+
+// Relay publishes the unpublished events until the outbox is drained and returns how many were published.
+// It stops at the first event that cannot be published and releases the rest of its batch, so that it is
+// claimed again by the next relay rather than once the lease expires.
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		claimID := uuid.New().String()
+		now := time.Now()
+		events, err := r.obr.ClaimUnpublished(ctx, claimID, r.batchSize, now, now.Add(r.lease))
+		if err != nil {
+			return published, err
+		}
+
+		for _, event := range events {
+			if err = r.publish(ctx, event); err != nil {
+				if rerr := r.obr.ReleaseClaim(ctx, claimID); rerr != nil {
+					log.Warn("Failed to release claimed events", log.Fstring("claimID", claimID), log.Ferror(rerr))
+				}
+				return published, err
+			}
+			published++
+		}
+
+		if len(events) < r.batchSize {
+			return published, nil
+		}
+	}
+}
+
+// publish publishes event and marks it as published.
+func (r *OutboxRelay) publish(ctx context.Context, event entity.Event) error {
+	if err := r.ep.Publish(ctx, event); err != nil {
+		log.Warn("Failed to publish event", log.Fstring("eventID", event.ID), log.Fstring("type", string(event.Type)), log.Ferror(err))
+		return err
+	}
+	return r.obr.MarkPublished(ctx, event.ID, time.Now())
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	t.Parallel()
+
+	events := make([]entity.Event, 3)
+	for i := range events {
+		itemID := uuid.New().String()
+		event, err := entity.NewEvent(entity.EventTypeCatalogItemDeleted, itemID, entity.Deletion{ID: itemID}, time.Now())
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		event.Sequence = int64(i + 1)
+		events[i] = *event
+	}
+	errPublish := errors.New("broker unavailable")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockOutboxRepository,
+			m1 *mock.MockEventPublisher,
+		)
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success: the outbox is drained batch by batch",
+			setup: func(obr *mock.MockOutboxRepository, ep *mock.MockEventPublisher) {
+				gomock.InOrder(
+					obr.EXPECT().ClaimUnpublished(gomock.Any(), gomock.Any(), 2, gomock.Any(), gomock.Any()).Return(events[:2], nil),
+					ep.EXPECT().Publish(gomock.Any(), events[0]).Return(nil),
+					obr.EXPECT().MarkPublished(gomock.Any(), events[0].ID, gomock.Any()).Return(nil),
+					ep.EXPECT().Publish(gomock.Any(), events[1]).Return(nil),
+					obr.EXPECT().MarkPublished(gomock.Any(), events[1].ID, gomock.Any()).Return(nil),
+					obr.EXPECT().ClaimUnpublished(gomock.Any(), gomock.Any(), 2, gomock.Any(), gomock.Any()).Return(events[2:], nil),
+					ep.EXPECT().Publish(gomock.Any(), events[2]).Return(nil),
+					obr.EXPECT().MarkPublished(gomock.Any(), events[2].ID, gomock.Any()).Return(nil),
+				)
+			},
+			want:    3,
+			wantErr: nil,
+		},
+		{
+			name: "success: nothing to publish",
+			setup: func(obr *mock.MockOutboxRepository, ep *mock.MockEventPublisher) {
+				obr.EXPECT().ClaimUnpublished(gomock.Any(), gomock.Any(), 2, gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			want:    0,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: publishing stops at the first event that cannot be published and releases the batch",
+			setup: func(obr *mock.MockOutboxRepository, ep *mock.MockEventPublisher) {
+				var claimID string
+				gomock.InOrder(
+					obr.EXPECT().ClaimUnpublished(gomock.Any(), gomock.Any(), 2, gomock.Any(), gomock.Any()).DoAndReturn(
+						func(_ context.Context, id string, _ int, now, until time.Time) ([]entity.Event, error) {
+							if until.Sub(now) != time.Minute {
+								t.Errorf("unexpected lease: %v", until.Sub(now))
+							}
+							claimID = id
+							return events[:2], nil
+						},
+					),
+					ep.EXPECT().Publish(gomock.Any(), events[0]).Return(errPublish),
+					obr.EXPECT().ReleaseClaim(gomock.Any(), gomock.Any()).Do(func(_ context.Context, id string) {
+						if id != claimID {
+							t.Errorf("unexpected claim: got %v, want %v", id, claimID)
+						}
+					}).Return(nil),
+				)
+			},
+			want:    0,
+			wantErr: errPublish,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			obr := mock.NewMockOutboxRepository(ctrl)
+			ep := mock.NewMockEventPublisher(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(obr, ep)
+			}
+
+			r := NewOutboxRelay(obr, ep, NewChangeFeed(), &config.EventConfig{RelayInterval: time.Second, RelayBatchSize: 2, RelayLease: time.Minute})
+
+			got, err := r.Relay(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Relay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Relay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
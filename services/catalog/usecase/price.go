@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// localize attaches their explicit prices to items and, unless currency is empty, sets their prices and
+// the price overrides of their variants to their prices in currency. Prices without an explicit price in
+// currency are converted at the current exchange rates.
+func (cu *catalogItemUseCase) localize(ctx context.Context, items []entity.CatalogItem, currency string) error {
+	if currency != "" {
+		if err := entity.ValidateCurrency(currency); err != nil {
+			log.Warn("Invalid currency", log.Fstring("currency", currency), log.Ferror(err))
+			return err
+		}
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	prices, err := cu.pr.ListPrices(ctx, ids)
+	if err != nil {
+		log.Error("Failed to list catalog item prices", log.Ferror(err))
+		return err
+	}
+
+	// The rate from each currency is read once; a nil rate is a currency without one.
+	rates := make(map[string]*entity.ExchangeRate)
+	rateFrom := func(from string) (*entity.ExchangeRate, error) {
+		if rate, ok := rates[from]; ok {
+			return rate, nil
+		}
+		rate, err := cu.pr.GetExchangeRate(ctx, from, currency)
+		if err != nil && !errors.Is(err, entity.ErrNotFound) {
+			log.Error("Failed to get exchange rate", log.Ferror(err))
+			return nil, err
+		}
+		rates[from] = rate
+		return rate, nil
+	}
+
+	for i := range items {
+		item := &items[i]
+		item.Prices = prices[item.ID]
+		if currency == "" {
+			continue
+		}
+
+		var rate *entity.ExchangeRate
+		if _, ok := item.PriceIn(currency); !ok {
+			if rate, err = rateFrom(item.Price.Currency); err != nil {
+				return err
+			}
+		}
+		if err = item.Localize(currency, rate); err != nil {
+			log.Warn("Failed to localize price", log.Fstring("itemID", item.ID), log.Ferror(err))
+			return err
+		}
+
+		for j := range item.Variants {
+			variant := &item.Variants[j]
+			if variant.Price == nil || variant.Price.Currency == currency {
+				continue
+			}
+			if rate, err = rateFrom(variant.Price.Currency); err != nil {
+				return err
+			}
+			if err = variant.Localize(currency, rate); err != nil {
+				log.Warn("Failed to localize price", log.Fstring("sku", variant.SKU), log.Ferror(err))
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetCatalogItemPrices replaces the explicit prices of an item. Version is the version of the item the prices
+// were set from; they are rejected if the item has been updated since, unless it is 0.
+func (cu *catalogItemUseCase) SetCatalogItemPrices(ctx context.Context, id string, prices []entity.Money, version int64) (*entity.CatalogItem, error) {
+	item, err := cu.cr.Get(ctx, id)
+	if err != nil {
+		log.Error("Failed to get catalog item", log.Ferror(err))
+		return nil, err
+	}
+	if version != 0 && item.Version != version {
+		log.Warn("Stale catalog item version", log.Fstring("id", id), log.Fint("version", int(version)))
+		return nil, repository.ErrVersionConflict
+	}
+	items := []entity.CatalogItem{*item}
+	if err = cu.attachCategories(ctx, items); err != nil {
+		return nil, err
+	}
+	if err = cu.attachVariants(ctx, items); err != nil {
+		return nil, err
+	}
+	item = &items[0]
+	if err = item.SetPrices(prices); err != nil {
+		log.Warn("Invalid prices", log.Ferror(err))
+		return nil, err
+	}
+
+	if err = cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		// The version is incremented first, so that prices set concurrently from the same version are rejected.
+		if err := cu.cr.IncrementVersion(ctx, *item); err != nil {
+			return err
+		}
+		item.Version++
+		if err := cu.pr.ReplacePrices(ctx, item.ID, item.Prices); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item)
+	}); err != nil {
+		log.Error("Failed to set catalog item prices", log.Ferror(err))
+		return nil, err
+	}
+	return item, nil
+}
+
+func (cu *catalogItemUseCase) SetExchangeRate(ctx context.Context, from, to, rate string) (*entity.ExchangeRate, error) {
+	exchangeRate, err := entity.NewExchangeRate(from, to, rate, time.Now())
+	if err != nil {
+		log.Warn("Invalid exchange rate", log.Ferror(err))
+		return nil, err
+	}
+	if err = cu.pr.UpsertExchangeRate(ctx, *exchangeRate); err != nil {
+		log.Error("Failed to set exchange rate", log.Ferror(err))
+		return nil, err
+	}
+	return exchangeRate, nil
+}
+
+func (cu *catalogItemUseCase) ListExchangeRates(ctx context.Context) ([]entity.ExchangeRate, error) {
+	rates, err := cu.pr.ListExchangeRates(ctx)
+	if err != nil {
+		log.Error("Failed to list exchange rates", log.Ferror(err))
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (cu *catalogItemUseCase) DeleteExchangeRate(ctx context.Context, from, to string) error {
+	if err := cu.pr.DeleteExchangeRate(ctx, from, to); err != nil {
+		log.Error("Failed to delete exchange rate", log.Ferror(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func (cu *catalogItemUseCase) GetCatalogItemAsOf(ctx context.Context, id string, asOf time.Time) (*entity.CatalogItem, error) {
+	item, err := cu.GetCatalogItem(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	entry, err := cu.pr.GetPriceAsOf(ctx, id, asOf)
+	if err != nil {
+		log.Error("Failed to get catalog item price", log.Fstring("id", id), log.Ferror(err))
+		return nil, err
+	}
+	item.Price = entry.Price
+	return item, nil
+}
+
+func (cu *catalogItemUseCase) GetPriceHistory(ctx context.Context, id string) ([]entity.PriceHistoryEntry, error) {
+	if _, err := cu.cr.Get(ctx, id); err != nil {
+		log.Error("Failed to get catalog item", log.Ferror(err))
+		return nil, err
+	}
+	history, err := cu.pr.ListPriceHistory(ctx, id)
+	if err != nil {
+		log.Error("Failed to list catalog item price history", log.Ferror(err))
+		return nil, err
+	}
+	return history, nil
+}
+
+func (cu *catalogItemUseCase) SchedulePriceChange(ctx context.Context, id string, price entity.Money, effectiveFrom time.Time) (*entity.PriceHistoryEntry, error) {
+	if !effectiveFrom.After(time.Now()) {
+		log.Warn("Price change is not in the future", log.Fstring("id", id))
+		return nil, entity.NewError(entity.ErrInvalidArgument, "effective time of a price change must be in the future")
+	}
+	entry, err := entity.NewPriceHistoryEntry(id, price, effectiveFrom)
+	if err != nil {
+		log.Warn("Invalid price change", log.Ferror(err))
+		return nil, err
+	}
+
+	item, err := cu.GetCatalogItem(ctx, id, "")
+	if err != nil {
+		return nil, err
+	}
+	if err = setBasePrice(item, price); err != nil {
+		log.Warn("Invalid price", log.Ferror(err))
+		return nil, err
+	}
+
+	if err = cu.pr.AddPriceHistoryEntry(ctx, *entry); err != nil {
+		log.Error("Failed to schedule price change", log.Ferror(err))
+		return nil, err
+	}
+	return entry, nil
+}
+
+// errPriceChangeDeferred is returned by applyPriceChange for a change of an item moved to the trash since
+// the change was listed. The change is left scheduled, and made once the item is restored.
+var errPriceChangeDeferred = errors.New("price change deferred until the catalog item is restored")
+
+func (cu *catalogItemUseCase) ApplyPriceChanges(ctx context.Context, now time.Time, limit int) (int, error) {
+	changes, err := cu.pr.ListDuePriceChanges(ctx, now, limit)
+	if err != nil {
+		log.Error("Failed to list due price changes", log.Ferror(err))
+		return 0, err
+	}
+
+	applied := 0
+	for _, change := range changes {
+		err = cu.applyPriceChange(ctx, change, now)
+		if errors.Is(err, errPriceChangeDeferred) {
+			log.Info("Deferred price change of catalog item in the trash", log.Fstring("id", change.ID), log.Fstring("itemID", change.CatalogItemID))
+			continue
+		}
+		if errors.Is(err, entity.ErrNotFound) {
+			// The change was made by another scheduler since it was listed.
+			continue
+		}
+		if err != nil {
+			log.Error("Failed to apply price change", log.Fstring("id", change.ID), log.Ferror(err))
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyPriceChange sets the price of a scheduled change on its item at now, which becomes the time the
+// change is effective from. A change that no longer applies, because the item was given an explicit price
+// in its currency since it was scheduled, is dropped.
+func (cu *catalogItemUseCase) applyPriceChange(ctx context.Context, change entity.PriceHistoryEntry, now time.Time) error {
+	return cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		// The change is marked first, so that schedulers running concurrently make it once.
+		if err := cu.pr.MarkPriceChangeApplied(ctx, change.ID, now); err != nil {
+			return err
+		}
+		item, err := cu.cr.Get(ctx, change.CatalogItemID)
+		if errors.Is(err, entity.ErrNotFound) {
+			// Returning an error rolls back the mark, so that the change is still scheduled.
+			return errPriceChangeDeferred
+		}
+		if err != nil {
+			return err
+		}
+		items := []entity.CatalogItem{*item}
+		if err = cu.load(ctx, items, ""); err != nil {
+			return err
+		}
+		item = &items[0]
+
+		oldPrice := item.Price
+		if err = setBasePrice(item, change.Price); err != nil {
+			log.Warn("Dropping price change that no longer applies", log.Fstring("id", change.ID), log.Ferror(err))
+			return cu.pr.DeletePriceHistoryEntry(ctx, change.ID)
+		}
+		if item.Price == oldPrice {
+			return nil
+		}
+
+		if err = cu.cr.Update(ctx, *item, entity.CatalogItemFieldPrice); err != nil {
+			return err
+		}
+		item.Version++
+		if err = cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemPriceChanged, item.ID, entity.PriceChange{
+			CatalogItemID: item.ID,
+			OldPrice:      oldPrice,
+			NewPrice:      item.Price,
+		})
+	})
+}
+
+// setBasePrice changes the base price of an item, which cannot move to a currency the item has an explicit price in.
+func setBasePrice(item *entity.CatalogItem, price entity.Money) error {
+	if err := item.SetPrice(price); err != nil {
+		return err
+	}
+	return item.SetPrices(item.Prices)
+}
+
+// recordPrice adds the base price of an item set at now to its price history.
+func (cu *catalogItemUseCase) recordPrice(ctx context.Context, itemID string, price entity.Money, now time.Time) error {
+	entry, err := entity.NewPriceHistoryEntry(itemID, price, now)
+	if err != nil {
+		return err
+	}
+	entry.AppliedAt = &now
+	return cu.pr.AddPriceHistoryEntry(ctx, *entry)
+}
+
+// PriceScheduler periodically makes the scheduled price changes that are due.
+type PriceScheduler struct {
+	cuc       CatalogItemUseCase
+	interval  time.Duration
+	batchSize int
+}
+
+func NewPriceScheduler(cuc CatalogItemUseCase, conf *config.PriceScheduleConfig) *PriceScheduler {
+	return &PriceScheduler{
+		cuc:       cuc,
+		interval:  conf.Interval,
+		batchSize: conf.BatchSize,
+	}
+}
+
+// Run makes the price changes as they fall due until ctx is cancelled.
+func (s *PriceScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Apply(ctx, now)
+		}
+	}
+}
+
+// Apply makes the price changes due at now, a batch at a time.
+func (s *PriceScheduler) Apply(ctx context.Context, now time.Time) {
+	total := 0
+	for {
+		applied, err := s.cuc.ApplyPriceChanges(ctx, now, s.batchSize)
+		total += applied
+		if err != nil {
+			log.Error("Failed to apply scheduled price changes", log.Ferror(err))
+			break
+		}
+		if applied < s.batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info("Applied scheduled price changes", log.Fint("applied", total))
+	}
+}
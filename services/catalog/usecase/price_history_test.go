@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_GetCatalogItemAsOf(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	asOf := time.Now().Add(-24 * time.Hour)
+
+	patterns := []struct {
+		name    string
+		setup   func(m *mock.MockPriceRepository)
+		want    entity.Money
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().GetPriceAsOf(gomock.Any(), itemID, asOf).Return(&entity.PriceHistoryEntry{
+					CatalogItemID: itemID,
+					Price:         entity.Money{Amount: 8000, Currency: "USD"},
+					EffectiveFrom: asOf.Add(-time.Hour),
+				}, nil)
+			},
+			want:    entity.Money{Amount: 8000, Currency: "USD"},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: item had no price yet",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().GetPriceAsOf(gomock.Any(), itemID, asOf).Return(nil, entity.NewError(entity.ErrNotFound, "no price of the catalog item as of that time"))
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+
+			cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+				ID:    itemID,
+				Name:  "item",
+				Price: entity.Money{Amount: 10000, Currency: "USD"},
+			}, nil)
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil)
+			tt.setup(pr)
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, nil, nil, nil)
+
+			item, err := tuc.GetCatalogItemAsOf(context.Background(), itemID, asOf)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && item.Price != tt.want {
+				t.Errorf("GetCatalogItemAsOf() price = %v, want %v", item.Price, tt.want)
+			}
+		})
+	}
+}
+
+func TestUseCase_SchedulePriceChange(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	patterns := []struct {
+		name          string
+		setup         func(m *mock.MockCatalogItemRepository, m1 *mock.MockPriceRepository)
+		price         entity.Money
+		effectiveFrom time.Time
+		wantErr       error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:    itemID,
+					Name:  "item",
+					Price: entity.Money{Amount: 10000, Currency: "USD"},
+				}, nil)
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil)
+				pr.EXPECT().AddPriceHistoryEntry(gomock.Any(), gomock.Any()).Do(func(_ context.Context, entry entity.PriceHistoryEntry) {
+					if entry.CatalogItemID != itemID || !entry.EffectiveFrom.Equal(tomorrow) || !entry.Scheduled() {
+						t.Errorf("unexpected price change: %v", entry)
+					}
+				}).Return(nil)
+			},
+			price:         entity.Money{Amount: 12000, Currency: "USD"},
+			effectiveFrom: tomorrow,
+			wantErr:       nil,
+		},
+		{
+			name:          "Fail: price change is in the past",
+			price:         entity.Money{Amount: 12000, Currency: "USD"},
+			effectiveFrom: time.Now().Add(-time.Hour),
+			wantErr:       entity.ErrInvalidArgument,
+		},
+		{
+			name:          "Fail: price is not positive",
+			price:         entity.Money{Amount: 0, Currency: "USD"},
+			effectiveFrom: tomorrow,
+			wantErr:       entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: item has an explicit price in the currency",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:    itemID,
+					Name:  "item",
+					Price: entity.Money{Amount: 10000, Currency: "USD"},
+				}, nil)
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(map[string][]entity.Money{
+					itemID: {{Amount: 9000, Currency: "EUR"}},
+				}, nil)
+			},
+			price:         entity.Money{Amount: 9500, Currency: "EUR"},
+			effectiveFrom: tomorrow,
+			wantErr:       entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(cr, pr)
+			}
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, nil, nil, nil)
+
+			entry, err := tuc.SchedulePriceChange(context.Background(), itemID, tt.price, tt.effectiveFrom)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && (entry.Price != tt.price || !entry.Scheduled()) {
+				t.Errorf("SchedulePriceChange() = %v, want a scheduled change to %v", entry, tt.price)
+			}
+		})
+	}
+}
+
+func TestUseCase_ApplyPriceChanges(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	now := time.Now()
+	change := entity.PriceHistoryEntry{
+		ID:            uuid.New().String(),
+		CatalogItemID: itemID,
+		Price:         entity.Money{Amount: 12000, Currency: "USD"},
+		EffectiveFrom: now.Add(-time.Minute),
+	}
+	errDB := errors.New("db error")
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockCatalogItemRepository, m1 *mock.MockPriceRepository)
+		prices     []entity.Money
+		trashed    bool
+		want       int
+		wantEvents []entity.EventType
+		wantErr    error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListDuePriceChanges(gomock.Any(), now, 10).Return([]entity.PriceHistoryEntry{change}, nil)
+				pr.EXPECT().MarkPriceChangeApplied(gomock.Any(), change.ID, now).Return(nil)
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), entity.CatalogItemFieldPrice).Do(func(_ context.Context, item entity.CatalogItem, _ ...string) {
+					if item.Price != change.Price {
+						t.Errorf("unexpected Price: got %v, want %v", item.Price, change.Price)
+					}
+				}).Return(nil)
+			},
+			want:       1,
+			wantEvents: []entity.EventType{entity.EventTypeCatalogItemUpdated, entity.EventTypeCatalogItemPriceChanged},
+			wantErr:    nil,
+		},
+		{
+			name: "success: change was made by another scheduler",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListDuePriceChanges(gomock.Any(), now, 10).Return([]entity.PriceHistoryEntry{change}, nil)
+				pr.EXPECT().MarkPriceChangeApplied(gomock.Any(), change.ID, now).Return(entity.NewError(entity.ErrNotFound, "scheduled price change not found"))
+			},
+			want:    0,
+			wantErr: nil,
+		},
+		{
+			name: "success: change that no longer applies is dropped",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				eur := change
+				eur.Price = entity.Money{Amount: 9500, Currency: "EUR"}
+				pr.EXPECT().ListDuePriceChanges(gomock.Any(), now, 10).Return([]entity.PriceHistoryEntry{eur}, nil)
+				pr.EXPECT().MarkPriceChangeApplied(gomock.Any(), change.ID, now).Return(nil)
+				pr.EXPECT().DeletePriceHistoryEntry(gomock.Any(), change.ID).Return(nil)
+			},
+			prices:  []entity.Money{{Amount: 9000, Currency: "EUR"}},
+			want:    1,
+			wantErr: nil,
+		},
+		{
+			name: "success: change of an item moved to the trash is left scheduled",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListDuePriceChanges(gomock.Any(), now, 10).Return([]entity.PriceHistoryEntry{change}, nil)
+				pr.EXPECT().MarkPriceChangeApplied(gomock.Any(), change.ID, now).Return(nil)
+			},
+			trashed: true,
+			want:    0,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: changes cannot be listed",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListDuePriceChanges(gomock.Any(), now, 10).Return(nil, errDB)
+			},
+			want:    0,
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			var rolledBack bool
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				err := fn(ctx)
+				rolledBack = rolledBack || err != nil
+				return err
+			}).AnyTimes()
+			if tt.trashed {
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(nil, entity.NewError(entity.ErrNotFound, "catalog item not found")).AnyTimes()
+			} else {
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:      itemID,
+					Name:    "item",
+					Price:   entity.Money{Amount: 10000, Currency: "USD"},
+					Version: 3,
+				}, nil).AnyTimes()
+			}
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(map[string][]entity.Money{itemID: tt.prices}, nil).AnyTimes()
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			tt.setup(cr, pr)
+
+			var gotEvents []entity.EventType
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				for _, event := range events {
+					gotEvents = append(gotEvents, event.Type)
+				}
+			}).Return(nil).Times(len(tt.wantEvents))
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			applied, err := tuc.ApplyPriceChanges(context.Background(), now, 10)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if applied != tt.want {
+				t.Errorf("ApplyPriceChanges() = %v, want %v", applied, tt.want)
+			}
+			if tt.trashed && !rolledBack {
+				t.Error("the change of an item in the trash was marked as applied")
+			}
+			if len(gotEvents) != len(tt.wantEvents) {
+				t.Fatalf("events = %v, want %v", gotEvents, tt.wantEvents)
+			}
+			for i := range gotEvents {
+				if gotEvents[i] != tt.wantEvents[i] {
+					t.Errorf("events = %v, want %v", gotEvents, tt.wantEvents)
+				}
+			}
+		})
+	}
+}
+
+// fakeSchedule makes at most limit of its due price changes each time.
+type fakeSchedule struct {
+	CatalogItemUseCase
+	changes int
+	now     []time.Time
+}
+
+func (f *fakeSchedule) ApplyPriceChanges(_ context.Context, now time.Time, limit int) (int, error) {
+	f.now = append(f.now, now)
+	applied := min(f.changes, limit)
+	f.changes -= applied
+	return applied, nil
+}
+
+func TestPriceScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	schedule := &fakeSchedule{changes: 4}
+	scheduler := &PriceScheduler{cuc: schedule, batchSize: 2}
+
+	scheduler.Apply(context.Background(), now)
+
+	if schedule.changes != 0 {
+		t.Errorf("Apply() left %d changes, want none", schedule.changes)
+	}
+	if len(schedule.now) != 3 {
+		t.Fatalf("Apply() applied %d batches, want %d", len(schedule.now), 3)
+	}
+	if !schedule.now[0].Equal(now) {
+		t.Errorf("Apply() applied the changes due at %v, want %v", schedule.now[0], now)
+	}
+}
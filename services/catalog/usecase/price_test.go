@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_ListCatalogItemsByIDs_Currency(t *testing.T) {
+	t.Parallel()
+
+	itemID1 := uuid.New().String()
+	itemID2 := uuid.New().String()
+	itemID3 := uuid.New().String()
+
+	items := func() []entity.CatalogItem {
+		return []entity.CatalogItem{
+			{ID: itemID1, Name: "item1", Price: entity.Money{Amount: 1000, Currency: "USD"}},
+			{ID: itemID2, Name: "item2", Price: entity.Money{Amount: 2000, Currency: "USD"}},
+			{ID: itemID3, Name: "item3", Price: entity.Money{Amount: 500, Currency: "GBP"}},
+		}
+	}
+	prices := map[string][]entity.Money{
+		itemID2: {{Amount: 1900, Currency: "EUR"}},
+	}
+	usdToEUR := &entity.ExchangeRate{From: "USD", To: "EUR", Rate: 90000000}
+	gbpToEUR := &entity.ExchangeRate{From: "GBP", To: "EUR", Rate: 120000000}
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockPriceRepository)
+		currency   string
+		wantPrices []entity.Money
+		wantErr    error
+	}{
+		{
+			name: "success: explicit prices are used and the others converted once per currency",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID1, itemID2, itemID3}).Return(prices, nil)
+				pr.EXPECT().GetExchangeRate(gomock.Any(), "USD", "EUR").Return(usdToEUR, nil)
+				pr.EXPECT().GetExchangeRate(gomock.Any(), "GBP", "EUR").Return(gbpToEUR, nil)
+			},
+			currency: "EUR",
+			wantPrices: []entity.Money{
+				{Amount: 900, Currency: "EUR"},
+				{Amount: 1900, Currency: "EUR"},
+				{Amount: 600, Currency: "EUR"},
+			},
+		},
+		{
+			name: "success: base prices without a currency",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID1, itemID2, itemID3}).Return(prices, nil)
+			},
+			currency: "",
+			wantPrices: []entity.Money{
+				{Amount: 1000, Currency: "USD"},
+				{Amount: 2000, Currency: "USD"},
+				{Amount: 500, Currency: "GBP"},
+			},
+		},
+		{
+			name: "Fail: no exchange rate",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID1, itemID2, itemID3}).Return(prices, nil)
+				pr.EXPECT().GetExchangeRate(gomock.Any(), "USD", "EUR").Return(nil, entity.NewError(entity.ErrNotFound, "exchange rate not found"))
+			},
+			currency: "EUR",
+			wantErr:  entity.ErrNoExchangeRate,
+		},
+		{
+			name:     "Fail: unsupported currency",
+			currency: "XXX",
+			wantErr:  entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+
+			cr.EXPECT().ListByIDs(gomock.Any(), []string{itemID1, itemID2, itemID3}).Return(items(), nil)
+			if tt.setup != nil {
+				tt.setup(pr)
+			}
+
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, nil, nil, nil)
+
+			got, err := cuc.ListCatalogItemsByIDs(context.Background(), []string{itemID1, itemID2, itemID3}, tt.currency)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			var gotPrices []entity.Money
+			for _, item := range got {
+				gotPrices = append(gotPrices, item.Price)
+			}
+			if !reflect.DeepEqual(gotPrices, tt.wantPrices) {
+				t.Errorf("ListCatalogItemsByIDs() prices = %v, want %v", gotPrices, tt.wantPrices)
+			}
+		})
+	}
+}
+
+func TestUseCase_SetCatalogItemPrices(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name        string
+		setup       func(m *mock.MockPriceRepository, m1 *mock.MockTransactionRepository, m2 *mock.MockOutboxRepository, m3 *mock.MockCatalogItemRepository)
+		prices      []entity.Money
+		version     int64
+		wantVersion int64
+		wantErr     error
+	}{
+		{
+			name: "success",
+			setup: func(pr *mock.MockPriceRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, cr *mock.MockCatalogItemRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().IncrementVersion(gomock.Any(), gomock.Any()).Do(func(_ context.Context, item entity.CatalogItem) {
+					if item.Version != 3 {
+						t.Errorf("unexpected version: got %v, want %v", item.Version, 3)
+					}
+				}).Return(nil)
+				pr.EXPECT().ReplacePrices(gomock.Any(), itemID, []entity.Money{{Amount: 900, Currency: "EUR"}}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemUpdated {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
+			},
+			prices:      []entity.Money{{Amount: 900, Currency: "EUR"}},
+			version:     3,
+			wantVersion: 4,
+			wantErr:     nil,
+		},
+		{
+			name:    "Fail: price in the base currency",
+			prices:  []entity.Money{{Amount: 900, Currency: "USD"}},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: stale version",
+			prices:  []entity.Money{{Amount: 900, Currency: "EUR"}},
+			version: 2,
+			wantErr: repository.ErrVersionConflict,
+		},
+		{
+			name: "Fail: item updated concurrently",
+			setup: func(pr *mock.MockPriceRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository, cr *mock.MockCatalogItemRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().IncrementVersion(gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+			},
+			prices:  []entity.Money{{Amount: 900, Currency: "EUR"}},
+			version: 3,
+			wantErr: repository.ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+				ID:      itemID,
+				Name:    "item",
+				Price:   entity.Money{Amount: 1000, Currency: "USD"},
+				Version: 3,
+			}, nil)
+			if tt.setup != nil {
+				tt.setup(pr, tr, obr, cr)
+			}
+
+			// The item is not loaded when its version is stale.
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).MaxTimes(1)
+			vr := mock.NewMockVariantRepository(ctrl)
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil).MaxTimes(1)
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			item, err := cuc.SetCatalogItemPrices(context.Background(), itemID, tt.prices, tt.version)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && !reflect.DeepEqual(item.Prices, tt.prices) {
+				t.Errorf("SetCatalogItemPrices() prices = %v, want %v", item.Prices, tt.prices)
+			}
+			if err == nil && item.Version != tt.wantVersion {
+				t.Errorf("SetCatalogItemPrices() version = %v, want %v", item.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestUseCase_SetExchangeRate(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name     string
+		setup    func(m *mock.MockPriceRepository)
+		from     string
+		to       string
+		rate     string
+		wantRate int64
+		wantErr  error
+	}{
+		{
+			name: "success",
+			setup: func(pr *mock.MockPriceRepository) {
+				pr.EXPECT().UpsertExchangeRate(gomock.Any(), gomock.Any()).Do(func(_ context.Context, rate entity.ExchangeRate) {
+					if rate.From != "USD" || rate.To != "EUR" || rate.Rate != 92000000 {
+						t.Errorf("unexpected exchange rate: %v", rate)
+					}
+				}).Return(nil)
+			},
+			from:     "USD",
+			to:       "EUR",
+			rate:     "0.92",
+			wantRate: 92000000,
+			wantErr:  nil,
+		},
+		{
+			name:    "Fail: same currency",
+			from:    "USD",
+			to:      "USD",
+			rate:    "1",
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name:    "Fail: rate is 0",
+			from:    "USD",
+			to:      "EUR",
+			rate:    "0",
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			pr := mock.NewMockPriceRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(pr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, nil, pr, nil, nil, nil, nil, nil, nil)
+
+			rate, err := cuc.SetExchangeRate(context.Background(), tt.from, tt.to, tt.rate)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && rate.Rate != tt.wantRate {
+				t.Errorf("SetExchangeRate() rate = %v, want %v", rate.Rate, tt.wantRate)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+// SearchParams are the parameters of a search of the catalog. MinPrice and MaxPrice bound the prices
+// of the items found in their currency, and Currency is the currency to return the prices in.
+type SearchParams struct {
+	Query    string
+	MinPrice *entity.Money
+	MaxPrice *entity.Money
+	Page     repository.Page
+	Currency string
+}
+
+// SearchResult is an item found by a search. Highlights are the ranges of bytes of the name of the
+// item that matched the query.
+type SearchResult struct {
+	CatalogItem entity.CatalogItem
+	Score       float64
+	Highlights  []repository.Highlight
+}
+
+// SearchResults are a page of the results of a search, the most relevant first, and Total the number
+// of results on all pages.
+type SearchResults struct {
+	Results  []SearchResult
+	Total    int
+	PageInfo repository.PageInfo
+}
+
+func (cu *catalogItemUseCase) SearchCatalogItems(ctx context.Context, params SearchParams) (*SearchResults, error) {
+	// The page token of a search is the offset of the page in the results.
+	offset := 0
+	if params.Page.Token != "" {
+		var err error
+		if offset, err = strconv.Atoi(params.Page.Token); err != nil || offset < 0 {
+			log.Warn("Invalid page token", log.Fstring("page_token", params.Page.Token))
+			return nil, repository.ErrInvalidPageToken
+		}
+	}
+	limit := params.Page.Limit()
+
+	// The index lags behind the catalog, and may still hold items trashed or deleted since. They are
+	// skipped before the page is cut, by reading further into the index until the page is full, so
+	// the offset of the next page is that of the hit after the last one read.
+	results := &SearchResults{
+		Results: make([]SearchResult, 0, limit),
+	}
+	next, total, skipped := offset, 0, 0
+	for {
+		want := limit - len(results.Results)
+		result, err := cu.si.Search(ctx, repository.SearchQuery{
+			Text:     params.Query,
+			MinPrice: params.MinPrice,
+			MaxPrice: params.MaxPrice,
+			Offset:   next,
+			Limit:    want,
+		})
+		if err != nil {
+			log.Warn("Failed to search catalog items", log.Fstring("query", params.Query), log.Ferror(err))
+			return nil, err
+		}
+		found, err := cu.searchResults(ctx, result.Hits, params.Currency)
+		if err != nil {
+			return nil, err
+		}
+		results.Results = append(results.Results, found...)
+		skipped += len(result.Hits) - len(found)
+		next += len(result.Hits)
+		total = result.Total
+
+		if len(results.Results) == limit || len(result.Hits) < want || next >= total {
+			break
+		}
+	}
+	results.Total = total - skipped
+	if next < total {
+		results.PageInfo.NextToken = strconv.Itoa(next)
+	}
+	if offset > 0 {
+		results.PageInfo.PrevToken = strconv.Itoa(max(offset-limit, 0))
+	}
+	return results, nil
+}
+
+// searchResults returns the items of hits that are still in the catalog, in the order of hits.
+func (cu *catalogItemUseCase) searchResults(ctx context.Context, hits []repository.SearchHit, currency string) ([]SearchResult, error) {
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	items, err := cu.cr.ListByIDs(ctx, ids)
+	if err != nil {
+		log.Error("Failed to list catalog items by ids", log.Ferror(err))
+		return nil, err
+	}
+	if err = cu.load(ctx, items, currency); err != nil {
+		return nil, err
+	}
+	itemMap := make(map[string]entity.CatalogItem, len(items))
+	for _, item := range items {
+		itemMap[item.ID] = item
+	}
+
+	results := make([]SearchResult, 0, len(items))
+	for _, hit := range hits {
+		item, ok := itemMap[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, SearchResult{
+			CatalogItem: item,
+			Score:       hit.Score,
+			Highlights:  hit.Highlights,
+		})
+	}
+	return results, nil
+}
+
+// SearchIndexer keeps the search index in sync with the catalog. It indexes the whole catalog, then
+// follows its changes, and indexes it again when it stops following them.
+type SearchIndexer struct {
+	cuc           CatalogItemUseCase
+	obr           repository.OutboxRepository
+	si            repository.SearchIndex
+	retryInterval time.Duration
+	// indexed are the IDs of the items in the index, to remove those deleted while the changes were not followed.
+	indexed map[string]bool
+}
+
+func NewSearchIndexer(cuc CatalogItemUseCase, obr repository.OutboxRepository, si repository.SearchIndex, conf *config.EventConfig) *SearchIndexer {
+	return &SearchIndexer{
+		cuc:           cuc,
+		obr:           obr,
+		si:            si,
+		retryInterval: conf.WatchPollInterval,
+		indexed:       make(map[string]bool),
+	}
+}
+
+// Run keeps the index in sync until ctx is cancelled.
+func (x *SearchIndexer) Run(ctx context.Context) {
+	for {
+		err := x.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Error("Failed to sync search index", log.Ferror(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(x.retryInterval):
+		}
+	}
+}
+
+func (x *SearchIndexer) sync(ctx context.Context) error {
+	// The changes are followed from before the catalog is read, so that none made while it is read is missed.
+	after, err := x.obr.LastSequence(ctx)
+	if err != nil {
+		return err
+	}
+	if err = x.Rebuild(ctx); err != nil {
+		return err
+	}
+	return x.cuc.WatchCatalogItems(ctx, after, func(change CatalogItemChange) error {
+		return x.Apply(ctx, change)
+	})
+}
+
+// Rebuild indexes every item of the catalog, and removes the items no longer in it from the index.
+func (x *SearchIndexer) Rebuild(ctx context.Context) error {
+	seen := make(map[string]bool)
+	page := repository.Page{Size: repository.MaxPageSize}
+	for {
+		items, info, err := x.cuc.ListCatalogItems(ctx, page, "")
+		if err != nil {
+			return err
+		}
+		if err = x.si.Put(ctx, items...); err != nil {
+			return err
+		}
+		for _, item := range items {
+			seen[item.ID] = true
+		}
+		if info.NextToken == "" {
+			break
+		}
+		page.Token = info.NextToken
+	}
+
+	var deleted []string
+	for id := range x.indexed {
+		if !seen[id] {
+			deleted = append(deleted, id)
+		}
+	}
+	if err := x.si.Delete(ctx, deleted...); err != nil {
+		return err
+	}
+	x.indexed = seen
+	log.Info("Search index rebuilt", log.Fint("items", len(seen)))
+	return nil
+}
+
+// Apply applies a change of the catalog to the index.
+func (x *SearchIndexer) Apply(ctx context.Context, change CatalogItemChange) error {
+	if change.Type == ChangeTypeDeleted {
+		delete(x.indexed, change.ID)
+		return x.si.Delete(ctx, change.ID)
+	}
+	x.indexed[change.ID] = true
+	return x.si.Put(ctx, *change.CatalogItem)
+}
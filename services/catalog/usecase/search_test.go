@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/search"
+)
+
+func TestUseCase_SearchCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	nextID := uuid.New().String()
+	staleID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			si *mock.MockSearchIndex,
+			cr *mock.MockCatalogItemRepository,
+			cgr *mock.MockCategoryRepository,
+			pr *mock.MockPriceRepository,
+			vr *mock.MockVariantRepository,
+		)
+		page      repository.Page
+		wantIDs   []string
+		wantTotal int
+		wantNext  string
+		wantPrev  string
+		wantErr   error
+	}{
+		{
+			name: "success: the page is filled past the items deleted since they were indexed",
+			setup: func(si *mock.MockSearchIndex, cr *mock.MockCatalogItemRepository, cgr *mock.MockCategoryRepository, pr *mock.MockPriceRepository, vr *mock.MockVariantRepository) {
+				gomock.InOrder(
+					si.EXPECT().Search(gomock.Any(), repository.SearchQuery{Text: "shirt", Offset: 2, Limit: 2}).Return(&repository.SearchResult{
+						Hits:  []repository.SearchHit{{ID: staleID, Score: 3}, {ID: itemID, Score: 2}},
+						Total: 6,
+					}, nil),
+					si.EXPECT().Search(gomock.Any(), repository.SearchQuery{Text: "shirt", Offset: 4, Limit: 1}).Return(&repository.SearchResult{
+						Hits:  []repository.SearchHit{{ID: nextID, Score: 1}},
+						Total: 6,
+					}, nil),
+				)
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{staleID, itemID}).Return([]entity.CatalogItem{
+					{ID: itemID, Name: "Shirt", Price: entity.Money{Amount: 1000, Currency: "USD"}},
+				}, nil)
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{nextID}).Return([]entity.CatalogItem{
+					{ID: nextID, Name: "T-Shirt", Price: entity.Money{Amount: 800, Currency: "USD"}},
+				}, nil)
+				cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				pr.EXPECT().ListPrices(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+			},
+			page:      repository.Page{Size: 2, Token: "2"},
+			wantIDs:   []string{itemID, nextID},
+			wantTotal: 5,
+			wantNext:  "5",
+			wantPrev:  "0",
+		},
+		{
+			name: "success: the last page is short when only deleted items are left in the index",
+			setup: func(si *mock.MockSearchIndex, cr *mock.MockCatalogItemRepository, cgr *mock.MockCategoryRepository, pr *mock.MockPriceRepository, vr *mock.MockVariantRepository) {
+				si.EXPECT().Search(gomock.Any(), repository.SearchQuery{Text: "shirt", Limit: 2}).Return(&repository.SearchResult{
+					Hits:  []repository.SearchHit{{ID: itemID, Score: 2}, {ID: staleID, Score: 1}},
+					Total: 2,
+				}, nil)
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{itemID, staleID}).Return([]entity.CatalogItem{
+					{ID: itemID, Name: "Shirt", Price: entity.Money{Amount: 1000, Currency: "USD"}},
+				}, nil)
+				cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil)
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil)
+				pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil)
+			},
+			page:      repository.Page{Size: 2},
+			wantIDs:   []string{itemID},
+			wantTotal: 1,
+		},
+		{
+			name: "success: no hits",
+			setup: func(si *mock.MockSearchIndex, _ *mock.MockCatalogItemRepository, _ *mock.MockCategoryRepository, _ *mock.MockPriceRepository, _ *mock.MockVariantRepository) {
+				si.EXPECT().Search(gomock.Any(), repository.SearchQuery{Text: "shirt", Limit: repository.DefaultPageSize}).Return(&repository.SearchResult{}, nil)
+			},
+		},
+		{
+			name:    "Fail: invalid page token",
+			page:    repository.Page{Token: "-1"},
+			wantErr: repository.ErrInvalidPageToken,
+		},
+		{
+			name: "Fail: invalid query",
+			setup: func(si *mock.MockSearchIndex, _ *mock.MockCatalogItemRepository, _ *mock.MockCategoryRepository, _ *mock.MockPriceRepository, _ *mock.MockVariantRepository) {
+				si.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, entity.NewError(entity.ErrInvalidArgument, "query has no words"))
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			si := mock.NewMockSearchIndex(ctrl)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(si, cr, cgr, pr, vr)
+			}
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, si, nil, nil, nil)
+
+			results, err := cuc.SearchCatalogItems(context.Background(), SearchParams{Query: "shirt", Page: tt.page})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if results.Total != tt.wantTotal {
+				t.Errorf("SearchCatalogItems() total = %d, want %d", results.Total, tt.wantTotal)
+			}
+			if len(results.Results) != len(tt.wantIDs) {
+				t.Fatalf("SearchCatalogItems() got %d results, want %d", len(results.Results), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if results.Results[i].CatalogItem.ID != id {
+					t.Errorf("SearchCatalogItems() result %d = %v, want %v", i, results.Results[i].CatalogItem.ID, id)
+				}
+			}
+			if results.PageInfo.NextToken != tt.wantNext || results.PageInfo.PrevToken != tt.wantPrev {
+				t.Errorf("SearchCatalogItems() page info = %+v, want next %q and prev %q", results.PageInfo, tt.wantNext, tt.wantPrev)
+			}
+		})
+	}
+}
+
+// fakeCatalogLister lists its items one per page.
+type fakeCatalogLister struct {
+	CatalogItemUseCase
+	items []entity.CatalogItem
+}
+
+func (l *fakeCatalogLister) ListCatalogItems(_ context.Context, page repository.Page, _ string) ([]entity.CatalogItem, repository.PageInfo, error) {
+	i := 0
+	for i < len(l.items) && l.items[i].ID != page.Token && page.Token != "" {
+		i++
+	}
+	if i == len(l.items) {
+		return nil, repository.PageInfo{}, nil
+	}
+	var info repository.PageInfo
+	if i+1 < len(l.items) {
+		info.NextToken = l.items[i+1].ID
+	}
+	return l.items[i : i+1], info, nil
+}
+
+func TestSearchIndexer(t *testing.T) {
+	t.Parallel()
+
+	shirt := entity.CatalogItem{ID: uuid.New().String(), Name: "Blue Shirt", Price: entity.Money{Amount: 1000, Currency: "USD"}}
+	socks := entity.CatalogItem{ID: uuid.New().String(), Name: "Wool Socks", Price: entity.Money{Amount: 500, Currency: "USD"}}
+	hat := entity.CatalogItem{ID: uuid.New().String(), Name: "Blue Hat", Price: entity.Money{Amount: 700, Currency: "USD"}}
+
+	ctx := context.Background()
+	si := search.NewInvertedIndex(2)
+	lister := &fakeCatalogLister{items: []entity.CatalogItem{shirt, socks}}
+	indexer := &SearchIndexer{cuc: lister, si: si, indexed: make(map[string]bool)}
+
+	found := func(query string) []string {
+		t.Helper()
+		result, err := si.Search(ctx, repository.SearchQuery{Text: query})
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", query, err)
+		}
+		var ids []string
+		for _, hit := range result.Hits {
+			ids = append(ids, hit.ID)
+		}
+		return ids
+	}
+
+	if err := indexer.Rebuild(ctx); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if ids := found("socks"); len(ids) != 1 || ids[0] != socks.ID {
+		t.Fatalf("after Rebuild() found %v, want %v", ids, socks.ID)
+	}
+
+	if err := indexer.Apply(ctx, CatalogItemChange{Type: ChangeTypeCreated, ID: hat.ID, CatalogItem: &hat}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if ids := found("blue"); len(ids) != 2 {
+		t.Fatalf("after a creation found %v, want the shirt and the hat", ids)
+	}
+	if err := indexer.Apply(ctx, CatalogItemChange{Type: ChangeTypeDeleted, ID: shirt.ID}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if ids := found("shirt"); len(ids) != 0 {
+		t.Fatalf("after a deletion found %v, want none", ids)
+	}
+
+	// The hat is deleted while the changes are not followed, and the next rebuild removes it.
+	lister.items = []entity.CatalogItem{socks}
+	if err := indexer.Rebuild(ctx); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	if ids := found("blue"); len(ids) != 0 {
+		t.Fatalf("after the second Rebuild() found %v, want none", ids)
+	}
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+var (
+	ErrCatalogItemNotFound      = entity.NewError(entity.ErrNotFound, "catalog item not found")
+	ErrVariantNotFound          = entity.NewError(entity.ErrNotFound, "variant not found")
+	ErrStockReservationNotFound = entity.NewError(entity.ErrNotFound, "stock reservation not found")
+)
+
+// StockQuantity is the quantity of a catalog item requested in a reservation. The stock of the
+// variant with SKU is reserved when it is set, and that of the item itself otherwise.
+type StockQuantity struct {
+	CatalogItemID string
+	SKU           string
+	Quantity      int
+}
+
+// stockKey identifies the stock a reservation entry holds.
+type stockKey struct {
+	catalogItemID string
+	sku           string
+}
+
+// ReserveStock holds the requested quantities under reservationID. Either every item is
+// reserved or none is. Quantities requested more than once for the same item or variant are added up.
+func (cu *catalogItemUseCase) ReserveStock(ctx context.Context, reservationID string, quantities []StockQuantity) ([]entity.StockReservation, error) {
+	if len(quantities) == 0 {
+		log.Warn("No items to reserve", log.Fstring("reservationID", reservationID))
+		return nil, entity.NewError(entity.ErrInvalidArgument, "at least one item is required")
+	}
+
+	now := time.Now()
+	var keys []stockKey
+	merged := make(map[stockKey]int, len(quantities))
+	for _, q := range quantities {
+		key := stockKey{catalogItemID: q.CatalogItemID, sku: q.SKU}
+		if _, ok := merged[key]; !ok {
+			keys = append(keys, key)
+		}
+		merged[key] += q.Quantity
+	}
+	reservations := make([]entity.StockReservation, 0, len(keys))
+	for _, key := range keys {
+		reservation, err := entity.NewStockReservation(reservationID, key.catalogItemID, key.sku, merged[key], now)
+		if err != nil {
+			log.Warn("Invalid stock reservation", log.Ferror(err))
+			return nil, err
+		}
+		reservations = append(reservations, *reservation)
+	}
+
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		itemMap, variantMap, err := cu.lockStock(ctx, reservations)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reservations {
+			item, ok := itemMap[r.CatalogItemID]
+			if !ok || item.DeletedAt != nil {
+				return fmt.Errorf("%w: %s", ErrCatalogItemNotFound, r.CatalogItemID)
+			}
+			if r.SKU != "" {
+				variant, ok := variantMap[r.SKU]
+				if !ok || variant.CatalogItemID != r.CatalogItemID {
+					return fmt.Errorf("%w: %s of %s", ErrVariantNotFound, r.SKU, r.CatalogItemID)
+				}
+				if err = variant.Reserve(r.Quantity); err != nil {
+					return err
+				}
+				continue
+			}
+			if err = item.Reserve(r.Quantity); err != nil {
+				return err
+			}
+		}
+		if err = cu.updateStock(ctx, reservations, itemMap, variantMap); err != nil {
+			return err
+		}
+		return cu.sr.CreateReservations(ctx, reservations)
+	}); err != nil {
+		log.Warn("Failed to reserve stock", log.Fstring("reservationID", reservationID), log.Ferror(err))
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// lockStock locks the items and the variants the reservations hold stock of, keyed by item ID and SKU.
+// The items of the variants are locked as well, so that they cannot be trashed while their variants are
+// reserved. Items and variants that do not exist are missing from the maps.
+func (cu *catalogItemUseCase) lockStock(ctx context.Context, reservations []entity.StockReservation) (map[string]*entity.CatalogItem, map[string]*entity.Variant, error) {
+	var ids, skus []string
+	for _, r := range reservations {
+		if r.SKU != "" {
+			skus = append(skus, r.SKU)
+		}
+		if !slices.Contains(ids, r.CatalogItemID) {
+			ids = append(ids, r.CatalogItemID)
+		}
+	}
+
+	itemMap := make(map[string]*entity.CatalogItem, len(ids))
+	if len(ids) > 0 {
+		items, err := cu.sr.LockItems(ctx, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range items {
+			itemMap[items[i].ID] = &items[i]
+		}
+	}
+	variantMap := make(map[string]*entity.Variant, len(skus))
+	if len(skus) > 0 {
+		variants, err := cu.sr.LockVariants(ctx, skus)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range variants {
+			variantMap[variants[i].SKU] = &variants[i]
+		}
+	}
+	return itemMap, variantMap, nil
+}
+
+// updateStock writes the stock the reservations hold, of the items and the variants locked by lockStock.
+// The items of the variants are left alone, as are the items and variants missing from the maps.
+func (cu *catalogItemUseCase) updateStock(ctx context.Context, reservations []entity.StockReservation, itemMap map[string]*entity.CatalogItem, variantMap map[string]*entity.Variant) error {
+	for _, r := range reservations {
+		if r.SKU != "" {
+			if variant, ok := variantMap[r.SKU]; ok {
+				if err := cu.sr.UpdateVariantStock(ctx, *variant); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if item, ok := itemMap[r.CatalogItemID]; ok {
+			if err := cu.sr.UpdateStock(ctx, *item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ReleaseStock returns the quantities held by a reservation to the available stock.
+func (cu *catalogItemUseCase) ReleaseStock(ctx context.Context, reservationID string) error {
+	return cu.closeReservation(ctx, reservationID, entity.ReservationStatusReleased)
+}
+
+// CommitStock removes the quantities held by a reservation from the stock on hand.
+func (cu *catalogItemUseCase) CommitStock(ctx context.Context, reservationID string) error {
+	return cu.closeReservation(ctx, reservationID, entity.ReservationStatusCommitted)
+}
+
+// closeReservation moves every entry of a reservation to status and applies the change to
+// the stock of the items. Closing an already closed reservation with the same status does nothing.
+func (cu *catalogItemUseCase) closeReservation(ctx context.Context, reservationID string, status entity.ReservationStatus) error {
+	now := time.Now()
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		reservations, err := cu.sr.LockReservations(ctx, reservationID)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return fmt.Errorf("%w: %s", ErrStockReservationNotFound, reservationID)
+		}
+
+		var closed []entity.StockReservation
+		for _, r := range reservations {
+			changed, cerr := r.Close(status, now)
+			if cerr != nil {
+				return cerr
+			}
+			if changed {
+				closed = append(closed, r)
+			}
+		}
+		if len(closed) == 0 {
+			return nil
+		}
+
+		itemMap, variantMap, err := cu.lockStock(ctx, closed)
+		if err != nil {
+			return err
+		}
+		for _, r := range closed {
+			// Items and variants deleted since they were reserved have no stock left to adjust.
+			if r.SKU != "" {
+				variant, ok := variantMap[r.SKU]
+				if !ok {
+					log.Warn("Reserved variant not found", log.Fstring("sku", r.SKU))
+					continue
+				}
+				if status == entity.ReservationStatusCommitted {
+					variant.Commit(r.Quantity)
+				} else {
+					variant.Release(r.Quantity)
+				}
+				continue
+			}
+			item, ok := itemMap[r.CatalogItemID]
+			if !ok {
+				log.Warn("Reserved catalog item not found", log.Fstring("itemID", r.CatalogItemID))
+				continue
+			}
+			if status == entity.ReservationStatusCommitted {
+				item.Commit(r.Quantity)
+			} else {
+				item.Release(r.Quantity)
+			}
+		}
+		if err = cu.updateStock(ctx, closed, itemMap, variantMap); err != nil {
+			return err
+		}
+		return cu.sr.UpdateReservationStatus(ctx, reservationID, status, now)
+	}); err != nil {
+		log.Warn("Failed to close stock reservation", log.Fstring("reservationID", reservationID), log.Fstring("status", string(status)), log.Ferror(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_ReserveStock(t *testing.T) {
+	t.Parallel()
+
+	reservationID := uuid.New().String()
+	itemID1 := uuid.New().String()
+	itemID2 := uuid.New().String()
+
+	items := func() []entity.CatalogItem {
+		return []entity.CatalogItem{
+			{ID: itemID1, Name: "item1", Price: entity.Money{Amount: 10000, Currency: "USD"}, Stock: 10, Reserved: 2},
+			{ID: itemID2, Name: "item2", Price: entity.Money{Amount: 20000, Currency: "USD"}, Stock: 1},
+		}
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockStockRepository,
+			m1 *mock.MockTransactionRepository,
+		)
+		quantities []StockQuantity
+		wantErr    error
+	}{
+		{
+			name: "success: quantities of the same item are added up",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1, itemID2}).Return(items(), nil)
+				sr.EXPECT().UpdateStock(gomock.Any(), gomock.Any()).Do(func(_ context.Context, item entity.CatalogItem) {
+					if item.ID == itemID1 && item.Reserved != 5 {
+						t.Errorf("unexpected Reserved: got %v, want %v", item.Reserved, 5)
+					}
+					if item.ID == itemID2 && item.Reserved != 1 {
+						t.Errorf("unexpected Reserved: got %v, want %v", item.Reserved, 1)
+					}
+				}).Return(nil).Times(2)
+				sr.EXPECT().CreateReservations(gomock.Any(), gomock.Any()).Do(func(_ context.Context, reservations []entity.StockReservation) {
+					if len(reservations) != 2 || reservations[0].Quantity != 3 || reservations[1].Quantity != 1 {
+						t.Errorf("unexpected reservations: %v", reservations)
+					}
+				}).Return(nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, Quantity: 1},
+				{CatalogItemID: itemID2, Quantity: 1},
+				{CatalogItemID: itemID1, Quantity: 2},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success: the stock of a variant is reserved",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1, itemID2}).Return(items(), nil)
+				sr.EXPECT().LockVariants(gomock.Any(), []string{"ITEM1-RED"}).Return([]entity.Variant{
+					{SKU: "ITEM1-RED", CatalogItemID: itemID1, Stock: 4, Reserved: 1},
+				}, nil)
+				// The item of the variant is locked but its own stock is not written.
+				sr.EXPECT().UpdateStock(gomock.Any(), gomock.Any()).Do(func(_ context.Context, item entity.CatalogItem) {
+					if item.ID != itemID2 || item.Reserved != 1 {
+						t.Errorf("unexpected item stock: %v", item)
+					}
+				}).Return(nil)
+				sr.EXPECT().UpdateVariantStock(gomock.Any(), entity.Variant{SKU: "ITEM1-RED", CatalogItemID: itemID1, Stock: 4, Reserved: 3}).Return(nil)
+				sr.EXPECT().CreateReservations(gomock.Any(), gomock.Any()).Do(func(_ context.Context, reservations []entity.StockReservation) {
+					if len(reservations) != 2 || reservations[0].SKU != "ITEM1-RED" || reservations[1].SKU != "" {
+						t.Errorf("unexpected reservations: %v", reservations)
+					}
+				}).Return(nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, SKU: "ITEM1-RED", Quantity: 2},
+				{CatalogItemID: itemID2, Quantity: 1},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: variant of another item",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID2}).Return(items()[1:], nil)
+				sr.EXPECT().LockVariants(gomock.Any(), []string{"ITEM1-RED"}).Return([]entity.Variant{
+					{SKU: "ITEM1-RED", CatalogItemID: itemID1, Stock: 4},
+				}, nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID2, SKU: "ITEM1-RED", Quantity: 1},
+			},
+			wantErr: entity.ErrNotFound,
+		},
+		{
+			name: "Fail: item of the variant is in the trash",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				deleted := items()[:1]
+				deletedAt := time.Now()
+				deleted[0].DeletedAt = &deletedAt
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1}).Return(deleted, nil)
+				sr.EXPECT().LockVariants(gomock.Any(), []string{"ITEM1-RED"}).Return([]entity.Variant{
+					{SKU: "ITEM1-RED", CatalogItemID: itemID1, Stock: 4},
+				}, nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, SKU: "ITEM1-RED", Quantity: 1},
+			},
+			wantErr: entity.ErrNotFound,
+		},
+		{
+			name: "Fail: insufficient stock",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1, itemID2}).Return(items(), nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, Quantity: 1},
+				{CatalogItemID: itemID2, Quantity: 2},
+			},
+			wantErr: entity.ErrInsufficientStock,
+		},
+		{
+			name: "Fail: catalog item not found",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1, itemID2}).Return(items()[:1], nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, Quantity: 1},
+				{CatalogItemID: itemID2, Quantity: 1},
+			},
+			wantErr: entity.ErrNotFound,
+		},
+		{
+			name: "Fail: catalog item is in the trash",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				deleted := items()
+				deletedAt := time.Now()
+				deleted[1].DeletedAt = &deletedAt
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID1, itemID2}).Return(deleted, nil)
+			},
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, Quantity: 1},
+				{CatalogItemID: itemID2, Quantity: 1},
+			},
+			wantErr: entity.ErrNotFound,
+		},
+		{
+			name: "Fail: quantity is 0",
+			quantities: []StockQuantity{
+				{CatalogItemID: itemID1, Quantity: 0},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name:       "Fail: no items",
+			quantities: nil,
+			wantErr:    entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			sr := mock.NewMockStockRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(sr, tr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, sr, nil, nil, nil, nil, tr, nil, nil)
+
+			reservations, err := cuc.ReserveStock(context.Background(), reservationID, tt.quantities)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && len(reservations) == 0 {
+				t.Errorf("ReserveStock() returned no reservations")
+			}
+		})
+	}
+}
+
+func TestUseCase_CloseStockReservation(t *testing.T) {
+	t.Parallel()
+
+	reservationID := uuid.New().String()
+	itemID := uuid.New().String()
+
+	reservations := func(status entity.ReservationStatus) []entity.StockReservation {
+		return []entity.StockReservation{
+			{ReservationID: reservationID, CatalogItemID: itemID, Quantity: 3, Status: status},
+		}
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockStockRepository,
+			m1 *mock.MockTransactionRepository,
+		)
+		commit  bool
+		wantErr error
+	}{
+		{
+			name: "success: release",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockReservations(gomock.Any(), reservationID).Return(reservations(entity.ReservationStatusReserved), nil)
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID}).Return([]entity.CatalogItem{
+					{ID: itemID, Stock: 10, Reserved: 3},
+				}, nil)
+				sr.EXPECT().UpdateStock(gomock.Any(), entity.CatalogItem{ID: itemID, Stock: 10, Reserved: 0}).Return(nil)
+				sr.EXPECT().UpdateReservationStatus(gomock.Any(), reservationID, entity.ReservationStatusReleased, gomock.Any()).Return(nil)
+			},
+			commit:  false,
+			wantErr: nil,
+		},
+		{
+			name: "success: commit",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockReservations(gomock.Any(), reservationID).Return(reservations(entity.ReservationStatusReserved), nil)
+				sr.EXPECT().LockItems(gomock.Any(), []string{itemID}).Return([]entity.CatalogItem{
+					{ID: itemID, Stock: 10, Reserved: 3},
+				}, nil)
+				sr.EXPECT().UpdateStock(gomock.Any(), entity.CatalogItem{ID: itemID, Stock: 7, Reserved: 0}).Return(nil)
+				sr.EXPECT().UpdateReservationStatus(gomock.Any(), reservationID, entity.ReservationStatusCommitted, gomock.Any()).Return(nil)
+			},
+			commit:  true,
+			wantErr: nil,
+		},
+		{
+			name: "success: releasing a released reservation does nothing",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockReservations(gomock.Any(), reservationID).Return(reservations(entity.ReservationStatusReleased), nil)
+			},
+			commit:  false,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: committing a released reservation",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockReservations(gomock.Any(), reservationID).Return(reservations(entity.ReservationStatusReleased), nil)
+			},
+			commit:  true,
+			wantErr: entity.ErrReservationClosed,
+		},
+		{
+			name: "Fail: reservation not found",
+			setup: func(sr *mock.MockStockRepository, tr *mock.MockTransactionRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				sr.EXPECT().LockReservations(gomock.Any(), reservationID).Return(nil, nil)
+			},
+			commit:  false,
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			sr := mock.NewMockStockRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(sr, tr)
+			}
+
+			cuc := NewCatalogItemUseCase(nil, sr, nil, nil, nil, nil, tr, nil, nil)
+
+			var err error
+			if tt.commit {
+				err = cuc.CommitStock(context.Background(), reservationID)
+			} else {
+				err = cuc.ReleaseStock(context.Background(), reservationID)
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
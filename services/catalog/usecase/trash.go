@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository"
+)
+
+func (cu *catalogItemUseCase) RestoreCatalogItem(ctx context.Context, id string) (*entity.CatalogItem, error) {
+	var item *entity.CatalogItem
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cu.cr.Restore(ctx, id); err != nil {
+			return err
+		}
+		restored, err := cu.cr.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		items := []entity.CatalogItem{*restored}
+		if err = cu.load(ctx, items, ""); err != nil {
+			return err
+		}
+		item = &items[0]
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemRestored, id, item)
+	}); err != nil {
+		log.Error("Failed to restore catalog item", log.Fstring("id", id), log.Ferror(err))
+		return nil, err
+	}
+	return item, nil
+}
+
+func (cu *catalogItemUseCase) ListDeletedCatalogItems(ctx context.Context, page repository.Page) ([]entity.CatalogItem, repository.PageInfo, error) {
+	items, info, err := cu.cr.ListDeleted(ctx, page)
+	if err != nil {
+		log.Error("Failed to list deleted catalog items", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+	return items, info, nil
+}
+
+func (cu *catalogItemUseCase) PurgeCatalogItems(ctx context.Context, before time.Time, limit int) (int, error) {
+	ids, err := cu.cr.ListDeletedBefore(ctx, before, limit)
+	if err != nil {
+		log.Error("Failed to list deleted catalog items", log.Ferror(err))
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		err = cu.tr.Transaction(ctx, func(ctx context.Context) error {
+			if err := cu.cr.Purge(ctx, id, before); err != nil {
+				return err
+			}
+			if err := cu.pr.ReplacePrices(ctx, id, nil); err != nil {
+				return err
+			}
+			if err := cu.pr.DeletePriceHistory(ctx, id); err != nil {
+				return err
+			}
+			if err := cu.cgr.ReplaceItemCategories(ctx, id, nil); err != nil {
+				return err
+			}
+			return cu.vr.DeleteByCatalogItemID(ctx, id)
+		})
+		if errors.Is(err, entity.ErrNotFound) {
+			// The item was restored since it was listed.
+			continue
+		}
+		if err != nil {
+			log.Error("Failed to purge catalog item", log.Fstring("id", id), log.Ferror(err))
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// TrashPurger periodically purges the items that have been in the trash for longer than the retention.
+type TrashPurger struct {
+	cuc       CatalogItemUseCase
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+func NewTrashPurger(cuc CatalogItemUseCase, conf *config.TrashConfig) *TrashPurger {
+	return &TrashPurger{
+		cuc:       cuc,
+		retention: conf.Retention,
+		interval:  conf.PurgeInterval,
+		batchSize: conf.PurgeBatchSize,
+	}
+}
+
+// Run purges the trash until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.Purge(ctx, now)
+		}
+	}
+}
+
+// Purge purges the items moved to the trash more than the retention before now, a batch at a time.
+func (p *TrashPurger) Purge(ctx context.Context, now time.Time) {
+	before := now.Add(-p.retention)
+	total := 0
+	for {
+		purged, err := p.cuc.PurgeCatalogItems(ctx, before, p.batchSize)
+		total += purged
+		if err != nil {
+			log.Error("Failed to purge the trash", log.Ferror(err))
+			break
+		}
+		if purged < p.batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info("Purged the trash", log.Fint("purged", total))
+	}
+}
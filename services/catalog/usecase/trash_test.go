@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_RestoreCatalogItem(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockOutboxRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCatalogItemRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Restore(gomock.Any(), itemID).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:      itemID,
+					Name:    "item",
+					Price:   entity.Money{Amount: 10000, Currency: "USD"},
+					Version: 3,
+				}, nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemRestored || events[0].AggregateID != itemID {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: item is not in the trash",
+			setup: func(cr *mock.MockCatalogItemRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Restore(gomock.Any(), itemID).Return(entity.NewError(entity.ErrNotFound, "deleted catalog item not found"))
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			tt.setup(cr, obr)
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			item, err := tuc.RestoreCatalogItem(context.Background(), itemID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && (item.ID != itemID || item.Version != 3) {
+				t.Errorf("RestoreCatalogItem() got = %v, want id %v at version %v", item, itemID, 3)
+			}
+		})
+	}
+}
+
+func TestUseCase_PurgeCatalogItems(t *testing.T) {
+	t.Parallel()
+
+	itemID1 := uuid.New().String()
+	itemID2 := uuid.New().String()
+	before := time.Now()
+	errDB := errors.New("connection refused")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockPriceRepository,
+			m2 *mock.MockCategoryRepository,
+			m3 *mock.MockVariantRepository,
+		)
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success: items restored since they were listed are skipped",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, cgr *mock.MockCategoryRepository, vr *mock.MockVariantRepository) {
+				cr.EXPECT().ListDeletedBefore(gomock.Any(), before, 10).Return([]string{itemID1, itemID2}, nil)
+				cr.EXPECT().Purge(gomock.Any(), itemID1, before).Return(entity.NewError(entity.ErrNotFound, "deleted catalog item not found"))
+				cr.EXPECT().Purge(gomock.Any(), itemID2, before).Return(nil)
+				pr.EXPECT().ReplacePrices(gomock.Any(), itemID2, nil).Return(nil)
+				pr.EXPECT().DeletePriceHistory(gomock.Any(), itemID2).Return(nil)
+				cgr.EXPECT().ReplaceItemCategories(gomock.Any(), itemID2, nil).Return(nil)
+				vr.EXPECT().DeleteByCatalogItemID(gomock.Any(), itemID2).Return(nil)
+			},
+			want: 1,
+		},
+		{
+			name: "Fail: item cannot be purged",
+			setup: func(cr *mock.MockCatalogItemRepository, pr *mock.MockPriceRepository, cgr *mock.MockCategoryRepository, vr *mock.MockVariantRepository) {
+				cr.EXPECT().ListDeletedBefore(gomock.Any(), before, 10).Return([]string{itemID1, itemID2}, nil)
+				cr.EXPECT().Purge(gomock.Any(), itemID1, before).Return(nil)
+				pr.EXPECT().ReplacePrices(gomock.Any(), itemID1, nil).Return(errDB)
+			},
+			want:    0,
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			tt.setup(cr, pr, cgr, vr)
+
+			tuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, nil, nil)
+
+			purged, err := tuc.PurgeCatalogItems(context.Background(), before, 10)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if purged != tt.want {
+				t.Errorf("PurgeCatalogItems() = %v, want %v", purged, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTrash purges at most limit of its items each time.
+type fakeTrash struct {
+	CatalogItemUseCase
+	items  int
+	before []time.Time
+}
+
+func (f *fakeTrash) PurgeCatalogItems(_ context.Context, before time.Time, limit int) (int, error) {
+	f.before = append(f.before, before)
+	purged := min(f.items, limit)
+	f.items -= purged
+	return purged, nil
+}
+
+func TestTrashPurger(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	trash := &fakeTrash{items: 5}
+	purger := &TrashPurger{cuc: trash, retention: 24 * time.Hour, batchSize: 2}
+
+	purger.Purge(context.Background(), now)
+
+	if trash.items != 0 {
+		t.Errorf("Purge() left %d items, want none", trash.items)
+	}
+	if len(trash.before) != 3 {
+		t.Fatalf("Purge() purged %d batches, want %d", len(trash.before), 3)
+	}
+	if want := now.Add(-24 * time.Hour); !trash.before[0].Equal(want) {
+		t.Errorf("Purge() purged the items deleted before %v, want %v", trash.before[0], want)
+	}
+}
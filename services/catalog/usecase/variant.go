@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+)
+
+func (cu *catalogItemUseCase) attachVariants(ctx context.Context, items []entity.CatalogItem) error {
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	variants, err := cu.vr.ListByCatalogItemIDs(ctx, ids)
+	if err != nil {
+		log.Error("Failed to list variants", log.Ferror(err))
+		return err
+	}
+	for i := range items {
+		items[i].Variants = variants[items[i].ID]
+	}
+	return nil
+}
+
+func (cu *catalogItemUseCase) GetVariant(ctx context.Context, sku string) (*entity.Variant, error) {
+	variant, err := cu.vr.Get(ctx, sku)
+	if err != nil {
+		log.Error("Failed to get variant", log.Ferror(err))
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (cu *catalogItemUseCase) ListVariants(ctx context.Context, catalogItemID string) ([]entity.Variant, error) {
+	if _, err := cu.cr.Get(ctx, catalogItemID); err != nil {
+		log.Error("Failed to get catalog item", log.Ferror(err))
+		return nil, err
+	}
+	variants, err := cu.vr.ListByCatalogItemIDs(ctx, []string{catalogItemID})
+	if err != nil {
+		log.Error("Failed to list variants", log.Ferror(err))
+		return nil, err
+	}
+	return variants[catalogItemID], nil
+}
+
+func (cu *catalogItemUseCase) CreateVariant(ctx context.Context, catalogItemID, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error) {
+	variant, err := entity.NewVariant(sku, catalogItemID, attributes, price)
+	if err != nil {
+		log.Warn("Invalid variant", log.Ferror(err))
+		return nil, err
+	}
+	if err = variant.SetStock(stock); err != nil {
+		log.Warn("Invalid stock", log.Ferror(err))
+		return nil, err
+	}
+
+	if err = cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		return cu.putVariant(ctx, *variant, cu.vr.Create)
+	}); err != nil {
+		log.Error("Failed to create variant", log.Ferror(err))
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (cu *catalogItemUseCase) UpdateVariant(ctx context.Context, sku string, attributes map[string]string, price *entity.Money, stock int) (*entity.Variant, error) {
+	var variant *entity.Variant
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		// The variant is locked so that a concurrent reservation cannot raise the reserved
+		// quantity above the stock it is checked against.
+		variants, err := cu.sr.LockVariants(ctx, []string{sku})
+		if err != nil {
+			return err
+		}
+		if len(variants) == 0 {
+			return ErrVariantNotFound
+		}
+		variant = &variants[0]
+		if err = variant.SetAttributes(attributes); err != nil {
+			return err
+		}
+		if err = variant.SetPrice(price); err != nil {
+			return err
+		}
+		if err = variant.SetStock(stock); err != nil {
+			return err
+		}
+		return cu.putVariant(ctx, *variant, cu.vr.Update)
+	}); err != nil {
+		log.Error("Failed to update variant", log.Ferror(err))
+		return nil, err
+	}
+	return variant, nil
+}
+
+// putVariant writes variant with write, once it is checked against the other variants of its item,
+// and records that the item was updated.
+func (cu *catalogItemUseCase) putVariant(ctx context.Context, variant entity.Variant, write func(context.Context, entity.Variant) error) error {
+	item, err := cu.cr.Get(ctx, variant.CatalogItemID)
+	if err != nil {
+		return err
+	}
+	items := []entity.CatalogItem{*item}
+	if err = cu.load(ctx, items, ""); err != nil {
+		return err
+	}
+	item = &items[0]
+	if err = item.SetVariant(variant); err != nil {
+		return err
+	}
+	if err = write(ctx, variant); err != nil {
+		return err
+	}
+	return cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item)
+}
+
+func (cu *catalogItemUseCase) DeleteVariant(ctx context.Context, sku string) error {
+	if err := cu.tr.Transaction(ctx, func(ctx context.Context) error {
+		variant, err := cu.vr.Get(ctx, sku)
+		if err != nil {
+			return err
+		}
+		item, err := cu.cr.Get(ctx, variant.CatalogItemID)
+		if err != nil {
+			return err
+		}
+		items := []entity.CatalogItem{*item}
+		if err = cu.load(ctx, items, ""); err != nil {
+			return err
+		}
+		item = &items[0]
+		item.RemoveVariant(sku)
+		if err = cu.vr.Delete(ctx, sku); err != nil {
+			return err
+		}
+		return cu.addEvent(ctx, entity.EventTypeCatalogItemUpdated, item.ID, item)
+	}); err != nil {
+		log.Error("Failed to delete variant", log.Ferror(err))
+		return err
+	}
+	return nil
+}
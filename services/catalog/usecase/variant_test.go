@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestUseCase_CreateVariant(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	red := entity.Variant{SKU: "SHIRT-RED", CatalogItemID: itemID, Attributes: map[string]string{"color": "red"}}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockVariantRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
+		)
+		sku        string
+		attributes map[string]string
+		price      *entity.Money
+		wantErr    error
+	}{
+		{
+			name: "success",
+			setup: func(vr *mock.MockVariantRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(map[string][]entity.Variant{itemID: {red}}, nil)
+				vr.EXPECT().Create(gomock.Any(), gomock.Any()).Do(func(_ context.Context, variant entity.Variant) {
+					if variant.SKU != "SHIRT-BLUE" || variant.Stock != 5 {
+						t.Errorf("unexpected variant: %v", variant)
+					}
+				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemUpdated {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
+			},
+			sku:        "SHIRT-BLUE",
+			attributes: map[string]string{"color": "blue"},
+			price:      &entity.Money{Amount: 1200, Currency: "USD"},
+			wantErr:    nil,
+		},
+		{
+			name: "Fail: another variant has the same attributes",
+			setup: func(vr *mock.MockVariantRepository, tr *mock.MockTransactionRepository, _ *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(map[string][]entity.Variant{itemID: {red}}, nil)
+			},
+			sku:        "SHIRT-RED-2",
+			attributes: map[string]string{"color": "red"},
+			wantErr:    entity.ErrAlreadyExists,
+		},
+		{
+			name:       "Fail: no attributes",
+			sku:        "SHIRT-BLUE",
+			attributes: nil,
+			wantErr:    entity.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+				ID:    itemID,
+				Name:  "shirt",
+				Price: entity.Money{Amount: 1000, Currency: "USD"},
+			}, nil).AnyTimes()
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(vr, tr, obr)
+			}
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			variant, err := cuc.CreateVariant(context.Background(), itemID, tt.sku, tt.attributes, tt.price, 5)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && variant.SKU != tt.sku {
+				t.Errorf("CreateVariant() SKU = %v, want %v", variant.SKU, tt.sku)
+			}
+		})
+	}
+}
+
+func TestUseCase_UpdateVariant(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	red := entity.Variant{SKU: "SHIRT-RED", CatalogItemID: itemID, Attributes: map[string]string{"color": "red"}, Stock: 10, Reserved: 4}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockStockRepository,
+			m1 *mock.MockVariantRepository,
+			m2 *mock.MockOutboxRepository,
+		)
+		stock   int
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(sr *mock.MockStockRepository, vr *mock.MockVariantRepository, obr *mock.MockOutboxRepository) {
+				sr.EXPECT().LockVariants(gomock.Any(), []string{red.SKU}).Return([]entity.Variant{red}, nil)
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(map[string][]entity.Variant{itemID: {red}}, nil)
+				vr.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, variant entity.Variant) {
+					if variant.SKU != red.SKU || variant.Stock != 6 || variant.Reserved != 4 {
+						t.Errorf("unexpected variant: %v", variant)
+					}
+				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			stock:   6,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: stock is lower than the reserved quantity",
+			setup: func(sr *mock.MockStockRepository, _ *mock.MockVariantRepository, _ *mock.MockOutboxRepository) {
+				sr.EXPECT().LockVariants(gomock.Any(), []string{red.SKU}).Return([]entity.Variant{red}, nil)
+			},
+			stock:   3,
+			wantErr: entity.ErrFailedPrecondition,
+		},
+		{
+			name: "Fail: variant not found",
+			setup: func(sr *mock.MockStockRepository, _ *mock.MockVariantRepository, _ *mock.MockOutboxRepository) {
+				sr.EXPECT().LockVariants(gomock.Any(), []string{red.SKU}).Return(nil, nil)
+			},
+			stock:   6,
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			sr := mock.NewMockStockRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+				ID:    itemID,
+				Name:  "shirt",
+				Price: entity.Money{Amount: 1000, Currency: "USD"},
+			}, nil).AnyTimes()
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(sr, vr, obr)
+			}
+
+			cuc := NewCatalogItemUseCase(cr, sr, pr, cgr, vr, nil, tr, obr, nil)
+
+			variant, err := cuc.UpdateVariant(context.Background(), red.SKU, red.Attributes, nil, tt.stock)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && variant.Stock != tt.stock {
+				t.Errorf("UpdateVariant() Stock = %v, want %v", variant.Stock, tt.stock)
+			}
+		})
+	}
+}
+
+func TestUseCase_DeleteVariant(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	red := entity.Variant{SKU: "SHIRT-RED", CatalogItemID: itemID, Attributes: map[string]string{"color": "red"}}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCatalogItemRepository,
+			m1 *mock.MockVariantRepository,
+			m2 *mock.MockOutboxRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCatalogItemRepository, vr *mock.MockVariantRepository, obr *mock.MockOutboxRepository) {
+				vr.EXPECT().Get(gomock.Any(), red.SKU).Return(&red, nil)
+				cr.EXPECT().Get(gomock.Any(), itemID).Return(&entity.CatalogItem{
+					ID:    itemID,
+					Name:  "shirt",
+					Price: entity.Money{Amount: 1000, Currency: "USD"},
+				}, nil)
+				vr.EXPECT().ListByCatalogItemIDs(gomock.Any(), []string{itemID}).Return(map[string][]entity.Variant{itemID: {red}}, nil)
+				vr.EXPECT().Delete(gomock.Any(), red.SKU).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCatalogItemUpdated {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: variant not found",
+			setup: func(_ *mock.MockCatalogItemRepository, vr *mock.MockVariantRepository, _ *mock.MockOutboxRepository) {
+				vr.EXPECT().Get(gomock.Any(), red.SKU).Return(nil, entity.NewError(entity.ErrNotFound, "variant not found"))
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCatalogItemRepository(ctrl)
+			pr := mock.NewMockPriceRepository(ctrl)
+			cgr := mock.NewMockCategoryRepository(ctrl)
+			vr := mock.NewMockVariantRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			cgr.EXPECT().ListItemCategoryIDs(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			pr.EXPECT().ListPrices(gomock.Any(), []string{itemID}).Return(nil, nil).AnyTimes()
+			if tt.setup != nil {
+				tt.setup(cr, vr, obr)
+			}
+
+			cuc := NewCatalogItemUseCase(cr, nil, pr, cgr, vr, nil, tr, obr, nil)
+
+			err := cuc.DeleteVariant(context.Background(), red.SKU)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("want: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/catalog/repository/mock"
+)
+
+func TestChangeFeed_Notify(t *testing.T) {
+	t.Parallel()
+
+	feed := NewChangeFeed()
+	notified, unsubscribe := feed.Subscribe()
+
+	feed.Notify()
+	feed.Notify()
+	select {
+	case <-notified:
+	default:
+		t.Fatal("Notify() did not notify the subscriber")
+	}
+	select {
+	case <-notified:
+		t.Fatal("Notify() did not coalesce the notifications")
+	default:
+	}
+
+	unsubscribe()
+	feed.Notify()
+	select {
+	case <-notified:
+		t.Fatal("Notify() notified an unsubscribed channel")
+	default:
+	}
+}
+
+func TestChangeWatcher_Watch(t *testing.T) {
+	t.Parallel()
+
+	events := make([]entity.Event, 3)
+	for i := range events {
+		itemID := uuid.New().String()
+		event, err := entity.NewEvent(entity.EventTypeCatalogItemDeleted, itemID, entity.Deletion{ID: itemID}, time.Now())
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		event.Sequence = int64(i + 6)
+		events[i] = *event
+	}
+	errSend := errors.New("stream closed")
+	errList := errors.New("connection refused")
+
+	patterns := []struct {
+		name       string
+		setup      func(m *mock.MockOutboxRepository, feed *ChangeFeed)
+		gapTimeout time.Duration
+		after      int64
+		failOn     int
+		cancel     bool
+		wantSent   []entity.Event
+		wantErr    error
+	}{
+		{
+			name: "success: watching from now on starts after the last event",
+			setup: func(obr *mock.MockOutboxRepository, _ *ChangeFeed) {
+				gomock.InOrder(
+					obr.EXPECT().LastSequence(gomock.Any()).Return(int64(5), nil),
+					obr.EXPECT().ListAfter(gomock.Any(), int64(5), 2).Return(events[:2], nil),
+					obr.EXPECT().ListAfter(gomock.Any(), int64(7), 2).Return(events[2:], nil),
+				)
+			},
+			after:    -1,
+			cancel:   true,
+			wantSent: events,
+			wantErr:  nil,
+		},
+		{
+			name: "success: watching resumes after the given sequence",
+			setup: func(obr *mock.MockOutboxRepository, _ *ChangeFeed) {
+				obr.EXPECT().ListAfter(gomock.Any(), int64(7), 2).Return(events[2:], nil)
+			},
+			after:    7,
+			cancel:   true,
+			wantSent: events[2:],
+			wantErr:  nil,
+		},
+		{
+			name: "success: a missing sequence is waited for until it is filled",
+			setup: func(obr *mock.MockOutboxRepository, feed *ChangeFeed) {
+				gomock.InOrder(
+					obr.EXPECT().ListAfter(gomock.Any(), int64(5), 2).DoAndReturn(
+						func(_ context.Context, _ int64, _ int) ([]entity.Event, error) {
+							// The transaction of the missing event commits, and the relay publishes it.
+							feed.Notify()
+							return []entity.Event{events[0], events[2]}, nil
+						}),
+					obr.EXPECT().ListAfter(gomock.Any(), int64(6), 2).Return(events[1:], nil),
+					obr.EXPECT().ListAfter(gomock.Any(), int64(8), 2).Return(nil, nil),
+				)
+			},
+			after:    5,
+			cancel:   true,
+			wantSent: events,
+			wantErr:  nil,
+		},
+		{
+			name: "success: a missing sequence is skipped once it times out",
+			setup: func(obr *mock.MockOutboxRepository, _ *ChangeFeed) {
+				obr.EXPECT().ListAfter(gomock.Any(), int64(5), 2).Return(events[2:], nil).Times(2)
+			},
+			gapTimeout: 10 * time.Millisecond,
+			after:      5,
+			cancel:     true,
+			wantSent:   events[2:],
+			wantErr:    nil,
+		},
+		{
+			name: "Fail: watching stops when an event cannot be sent",
+			setup: func(obr *mock.MockOutboxRepository, _ *ChangeFeed) {
+				obr.EXPECT().ListAfter(gomock.Any(), int64(5), 2).Return(events[:2], nil)
+			},
+			after:    5,
+			failOn:   2,
+			wantSent: events[:1],
+			wantErr:  errSend,
+		},
+		{
+			name: "Fail: the outbox cannot be read",
+			setup: func(obr *mock.MockOutboxRepository, _ *ChangeFeed) {
+				obr.EXPECT().ListAfter(gomock.Any(), int64(5), 2).Return(nil, errList)
+			},
+			after:    5,
+			wantSent: nil,
+			wantErr:  errList,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			feed := NewChangeFeed()
+			if tt.setup != nil {
+				tt.setup(obr, feed)
+			}
+
+			gapTimeout := tt.gapTimeout
+			if gapTimeout == 0 {
+				gapTimeout = time.Hour
+			}
+			w := NewChangeWatcher(obr, feed, &config.EventConfig{
+				RelayBatchSize:    2,
+				WatchPollInterval: time.Hour,
+				WatchGapTimeout:   gapTimeout,
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var sent []entity.Event
+			err := w.Watch(ctx, tt.after, func(event entity.Event) error {
+				if len(sent)+1 == tt.failOn {
+					return errSend
+				}
+				sent = append(sent, event)
+				if tt.cancel && len(sent) == len(tt.wantSent) {
+					cancel()
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Watch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(sent) != len(tt.wantSent) {
+				t.Fatalf("Watch() sent %d events, want %d", len(sent), len(tt.wantSent))
+			}
+			for i := range sent {
+				if sent[i].ID != tt.wantSent[i].ID {
+					t.Errorf("Watch() sent %v, want %v", sent[i].ID, tt.wantSent[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -107,6 +107,7 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 		MaxAge:           time.Duration(serverConfig.PreflightCacheDurationSec) * time.Second,
 	}))
 
+	r.SetFuncMap(handler.TemplateFuncs)
 	r.LoadHTMLFiles("gateway/web/templates/index.html")
 	r.LoadHTMLGlob("gateway/web/templates/**/*")
 
@@ -122,6 +123,9 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 			// List all catalog items
 			catalog.GET("/list", catalogHandler.ListCatalogItems)
 
+			// Show a catalog item
+			catalog.GET("/detail", catalogHandler.GetCatalogItem)
+
 			// Show the form to create a new catalog item
 			catalog.GET("/create", catalogHandler.CreateCatalogItemForm)
 
@@ -137,11 +141,32 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 			// Delete a catalog item
 			catalog.GET("/delete", catalogHandler.DeleteCatalogItem)
 
-			// Show the form to search for catalog items by name
-			catalog.GET("/search", catalogHandler.GetCatalogItemByNameForm)
+			// List the deleted catalog items that can still be restored
+			catalog.GET("/trash", catalogHandler.ListDeletedCatalogItems)
+
+			// Restore a deleted catalog item
+			catalog.GET("/restore", catalogHandler.RestoreCatalogItem)
+
+			// Show the form to import catalog items from a file
+			catalog.GET("/import", catalogHandler.ImportCatalogItemsForm)
+
+			// Process the uploaded file to import catalog items, and show the report of the import
+			catalog.POST("/import", catalogHandler.ImportCatalogItems)
+
+			// Download all the catalog items as a file
+			catalog.GET("/export", catalogHandler.ExportCatalogItems)
+
+			// Search for catalog items, or show the search form when no query is entered
+			catalog.GET("/search", catalogHandler.SearchCatalogItems)
 
 			// Process the form submission to search for catalog items by name
 			catalog.POST("/search", catalogHandler.GetCatalogItemByName)
+
+			// Process the form submission to add a variant to a catalog item
+			catalog.POST("/variant/create", catalogHandler.CreateVariant)
+
+			// Delete a variant of a catalog item
+			catalog.GET("/variant/delete", catalogHandler.DeleteVariant)
 		}
 	}
 	{
@@ -150,6 +175,9 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 			// List all customers
 			customer.GET("/list", customerHandler.ListCustomers)
 
+			// Show a customer
+			customer.GET("/detail", customerHandler.GetCustomer)
+
 			// Show the form to create a new customer
 			customer.GET("/create", customerHandler.CreateCustomerForm)
 
@@ -164,6 +192,12 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 
 			// Delete a customer
 			customer.GET("/delete", customerHandler.DeleteCustomer)
+
+			// List the deleted customers that can still be restored
+			customer.GET("/trash", customerHandler.ListDeletedCustomers)
+
+			// Restore a deleted customer
+			customer.GET("/restore", customerHandler.RestoreCustomer)
 		}
 	}
 	{
@@ -172,14 +206,26 @@ func BuildContainer(ctx context.Context, addr string) (*http.Server, error) { //
 			// List all orders
 			order.GET("/list", orderHandler.ListOrders)
 
+			// Show an order
+			order.GET("/detail", orderHandler.GetOrder)
+
 			// Show the form to create a new order
 			order.GET("/create", orderHandler.CreateOrderForm)
 
 			// Process the form submission to create a new order
 			order.POST("/create", orderHandler.CreateOrder)
 
+			// Move an order to another status
+			order.POST("/status", orderHandler.UpdateOrderStatus)
+
+			// Cancel an order
+			order.POST("/cancel", orderHandler.CancelOrder)
+
 			// Delete an order
 			order.GET("/delete", orderHandler.DeleteOrder)
+
+			// Stream the changes of the orders as server-sent events
+			order.GET("/events", orderHandler.WatchOrders)
 		}
 	}
 
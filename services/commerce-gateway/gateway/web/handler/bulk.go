@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// importChunkSize is the number of bytes of an uploaded file sent to the catalog service in a message at most.
+const importChunkSize = 64 * 1024
+
+// exportContentTypes are the content types of the files of catalog items, by format.
+var exportContentTypes = map[string]string{
+	"csv":   "text/csv",
+	"jsonl": "application/x-ndjson",
+}
+
+func (ch *catalogItemHandler) ImportCatalogItemsForm(c *gin.Context) {
+	c.HTML(http.StatusOK, "catalog/import.html", gin.H{
+		"Format": "csv",
+	})
+}
+
+// ImportCatalogItems uploads a file of catalog items to the catalog service, and shows the report of the import.
+func (ch *catalogItemHandler) ImportCatalogItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format := c.PostForm("format")
+	dryRun := c.PostForm("dry_run") == "on"
+	header, err := c.FormFile("file")
+	if err != nil {
+		log.Warn("File is required", log.Ferror(err))
+		c.String(http.StatusBadRequest, "File is required")
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		log.Error("Failed to open uploaded file", log.Ferror(err))
+		c.String(http.StatusInternalServerError, "Failed to open uploaded file")
+		return
+	}
+	defer file.Close()
+
+	stream, err := ch.client.ImportCatalogItems(ctx)
+	if err != nil {
+		renderError(c, err, "Failed to import catalog items")
+		return
+	}
+	// The format and dry run are sent with the first chunk, which is sent even when the file is empty.
+	req := &pb.ImportCatalogItemsRequest{Format: format, DryRun: dryRun}
+	buf := make([]byte, importChunkSize)
+	for first := true; ; first = false {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 || first {
+			req.Data = buf[:n]
+			// A failure to send is reported by CloseAndRecv, with the status of the import.
+			if sendErr := stream.Send(req); sendErr != nil {
+				break
+			}
+			req = &pb.ImportCatalogItemsRequest{}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			log.Error("Failed to read uploaded file", log.Ferror(err))
+			c.String(http.StatusInternalServerError, "Failed to read uploaded file")
+			return
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		renderError(c, err, "Failed to import catalog items")
+		return
+	}
+
+	c.HTML(http.StatusOK, "catalog/import.html", gin.H{
+		"Format":   format,
+		"FileName": header.Filename,
+		"Report":   resp,
+	})
+}
+
+// ExportCatalogItems downloads the file of all the catalog items from the catalog service.
+func (ch *catalogItemHandler) ExportCatalogItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	format := c.DefaultQuery("format", "csv")
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		log.Warn("Invalid format", log.Fstring("format", format))
+		c.String(http.StatusBadRequest, "Invalid format")
+		return
+	}
+
+	stream, err := ch.client.ExportCatalogItems(ctx, &pb.ExportCatalogItemsRequest{
+		Format: format,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to export catalog items")
+		return
+	}
+	// The first chunk is received before the response is started, so that an export that cannot start
+	// is reported on the error page.
+	resp, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		renderError(c, err, "Failed to export catalog items")
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+	c.Status(http.StatusOK)
+	for err == nil {
+		if _, err = c.Writer.Write(resp.GetData()); err != nil {
+			break
+		}
+		resp, err = stream.Recv()
+	}
+	if !errors.Is(err, io.EOF) {
+		// The response has started, so the download is cut short rather than replaced by the error page.
+		log.Error("Failed to export catalog items", log.Ferror(err))
+	}
+}
@@ -2,22 +2,33 @@ package handler
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 )
 
 type CatalogItemHandler interface {
-	GetCatalogItemByNameForm(c *gin.Context)
 	GetCatalogItemByName(c *gin.Context)
+	SearchCatalogItems(c *gin.Context)
 	ListCatalogItems(c *gin.Context)
+	GetCatalogItem(c *gin.Context)
 	CreateCatalogItemForm(c *gin.Context)
 	CreateCatalogItem(c *gin.Context)
 	UpdateCatalogItemForm(c *gin.Context)
 	UpdateCatalogItem(c *gin.Context)
 	DeleteCatalogItem(c *gin.Context)
+	ListDeletedCatalogItems(c *gin.Context)
+	RestoreCatalogItem(c *gin.Context)
+	ImportCatalogItemsForm(c *gin.Context)
+	ImportCatalogItems(c *gin.Context)
+	ExportCatalogItems(c *gin.Context)
+	CreateVariant(c *gin.Context)
+	DeleteVariant(c *gin.Context)
 }
 
 type catalogItemHandler struct {
@@ -30,10 +41,6 @@ func NewCatalogItemHandler(client pb.CatalogServiceClient) CatalogItemHandler {
 	}
 }
 
-func (ch *catalogItemHandler) GetCatalogItemByNameForm(c *gin.Context) {
-	c.HTML(http.StatusOK, "catalog/search.html", nil)
-}
-
 func (ch *catalogItemHandler) GetCatalogItemByName(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -48,8 +55,7 @@ func (ch *catalogItemHandler) GetCatalogItemByName(c *gin.Context) {
 		Name: name,
 	})
 	if err != nil {
-		log.Error("Failed to list catalog items by name", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to list catalog items by name")
 		return
 	}
 
@@ -61,25 +67,101 @@ func (ch *catalogItemHandler) GetCatalogItemByName(c *gin.Context) {
 func (ch *catalogItemHandler) ListCatalogItems(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	resp, err := ch.client.ListCatalogItems(ctx, &pb.ListCatalogItemsRequest{})
+	categories, err := ch.listCategories(ctx)
 	if err != nil {
-		log.Error("Failed to list catalog items", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to list categories")
 		return
 	}
 
+	// The items are listed at their base prices unless a currency is chosen,
+	// and only those in the chosen category or any category under it.
+	currency := c.Query("currency")
+	category := c.Query("category")
+	var items []*pb.CatalogItem
+	var nextPageToken, prevPageToken string
+	if category == "" {
+		resp, err := ch.client.ListCatalogItems(ctx, &pb.ListCatalogItemsRequest{
+			PageToken: c.Query("page_token"),
+			Currency:  currency,
+		})
+		if err != nil {
+			renderError(c, err, "Failed to list catalog items")
+			return
+		}
+		items, nextPageToken, prevPageToken = resp.GetItems(), resp.GetNextPageToken(), resp.GetPrevPageToken()
+	} else {
+		resp, err := ch.client.ListCatalogItemsByCategory(ctx, &pb.ListCatalogItemsByCategoryRequest{
+			CategoryId: category,
+			PageToken:  c.Query("page_token"),
+			Currency:   currency,
+		})
+		if err != nil {
+			renderError(c, err, "Failed to list catalog items by category")
+			return
+		}
+		items, nextPageToken, prevPageToken = resp.GetItems(), resp.GetNextPageToken(), resp.GetPrevPageToken()
+	}
+
 	c.HTML(http.StatusOK, "catalog/list.html", gin.H{
-		"Items": resp.GetItems(),
+		"Items":         items,
+		"NextPageToken": nextPageToken,
+		"PrevPageToken": prevPageToken,
+		"Currencies":    currencies(),
+		"Currency":      currency,
+		"Categories":    categories,
+		"Category":      category,
+		"CategoryPath":  categoryPath(categories, category),
+	})
+}
+
+func (ch *catalogItemHandler) GetCatalogItem(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Query("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "ID is required")
+		return
+	}
+
+	currency := c.Query("currency")
+	resp, err := ch.client.GetCatalogItem(ctx, &pb.GetCatalogItemRequest{
+		Id:       id,
+		Currency: currency,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to get catalog item")
+		return
+	}
+
+	categories, err := ch.listCategories(ctx)
+	if err != nil {
+		renderError(c, err, "Failed to list categories")
+		return
+	}
+
+	c.HTML(http.StatusOK, "catalog/detail.html", gin.H{
+		"Item":       resp.GetItem(),
+		"Currencies": currencies(),
+		"Currency":   currency,
+		"Categories": selectCategories(categories, resp.GetItem().GetCategoryIds()),
 	})
 }
 
 func (ch *catalogItemHandler) CreateCatalogItemForm(c *gin.Context) {
-	c.HTML(http.StatusOK, "catalog/create.html", nil)
+	c.HTML(http.StatusOK, "catalog/create.html", gin.H{
+		"IdempotencyKey": newIdempotencyKey(),
+		"Currencies":     currencies(),
+		"Currency":       defaultCurrency,
+	})
 }
 
 type CreateCatalogItemRequest struct {
-	Name  string  `form:"name"`
-	Price float64 `form:"price"`
+	Name           string `form:"name"`
+	Price          string `form:"price"`
+	Currency       string `form:"currency"`
+	Stock          int32  `form:"stock"`
+	IdempotencyKey string `form:"idempotency_key"`
 }
 
 func (ch *catalogItemHandler) CreateCatalogItem(c *gin.Context) {
@@ -95,22 +177,31 @@ func (ch *catalogItemHandler) CreateCatalogItem(c *gin.Context) {
 		c.String(http.StatusBadRequest, "Invalid request")
 		return
 	}
+	price, err := parseMoney(req.Price, req.Currency)
+	if err != nil {
+		log.Warn("Invalid price", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid price")
+		return
+	}
 
-	if _, err := ch.client.CreateCatalogItem(ctx, &pb.CreateCatalogItemRequest{
-		Name:  req.Name,
-		Price: req.Price,
-	}); err != nil {
-		log.Error("Failed to create catalog item", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+	resp, err := ch.client.CreateCatalogItem(ctx, &pb.CreateCatalogItemRequest{
+		Name:           req.Name,
+		Price:          price,
+		Stock:          req.Stock,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to create catalog item")
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/catalog/list")
+	c.Redirect(http.StatusFound, "/catalog/detail?id="+url.QueryEscape(resp.GetItem().GetId()))
 }
 
 func (ch *catalogItemHandler) isValidCreateCatalogItemRequest(req *CreateCatalogItemRequest) bool {
 	if req.Name == "" ||
-		req.Price <= 0 {
+		req.Price == "" ||
+		req.Stock < 0 {
 		log.Warn("Invalid request body: %v", req)
 		return false
 	}
@@ -118,8 +209,6 @@ func (ch *catalogItemHandler) isValidCreateCatalogItemRequest(req *CreateCatalog
 }
 
 func (ch *catalogItemHandler) UpdateCatalogItemForm(c *gin.Context) {
-	ctx := c.Request.Context()
-
 	id := c.Query("id")
 	if id == "" {
 		log.Warn("ID is required")
@@ -127,24 +216,51 @@ func (ch *catalogItemHandler) UpdateCatalogItemForm(c *gin.Context) {
 		return
 	}
 
+	ch.renderUpdateCatalogItemForm(c, http.StatusOK, id, nil)
+}
+
+// renderUpdateCatalogItemForm renders the update form with the current values of the item. The rejected
+// update is shown alongside them when the item was changed since the form it was made from was loaded.
+func (ch *catalogItemHandler) renderUpdateCatalogItemForm(c *gin.Context, code int, id string, rejected *UpdateCatalogItemRequest) {
+	ctx := c.Request.Context()
+
 	resp, err := ch.client.GetCatalogItem(ctx, &pb.GetCatalogItemRequest{
 		Id: id,
 	})
 	if err != nil {
-		log.Error("Failed to get catalog item", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to get catalog item")
+		return
+	}
+
+	categories, err := ch.listCategories(ctx)
+	if err != nil {
+		renderError(c, err, "Failed to list categories")
 		return
 	}
+	selected := make(map[string]bool)
+	for _, categoryID := range resp.GetItem().GetCategoryIds() {
+		selected[categoryID] = true
+	}
 
-	c.HTML(http.StatusOK, "catalog/update.html", gin.H{
-		"Item": resp.GetItem(),
+	c.HTML(code, "catalog/update.html", gin.H{
+		"Item":       resp.GetItem(),
+		"Currencies": currencies(),
+		"Currency":   resp.GetItem().GetPrice().GetCurrency(),
+		"Categories": categories,
+		"Selected":   selected,
+		"Rejected":   rejected,
 	})
 }
 
 type UpdateCatalogItemRequest struct {
-	ID    string  `form:"id"`
-	Name  string  `form:"name"`
-	Price float64 `form:"price"`
+	ID          string   `form:"id"`
+	Name        string   `form:"name"`
+	Price       string   `form:"price"`
+	Currency    string   `form:"currency"`
+	Stock       int32    `form:"stock"`
+	CategoryIDs []string `form:"category_ids"`
+	// Version is the version of the item the form was loaded at.
+	Version int64 `form:"version"`
 }
 
 func (ch *catalogItemHandler) UpdateCatalogItem(c *gin.Context) {
@@ -160,24 +276,46 @@ func (ch *catalogItemHandler) UpdateCatalogItem(c *gin.Context) {
 		c.String(http.StatusBadRequest, "Invalid request")
 		return
 	}
+	price, err := parseMoney(req.Price, req.Currency)
+	if err != nil {
+		log.Warn("Invalid price", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid price")
+		return
+	}
+
+	resp, err := ch.client.UpdateCatalogItem(ctx, &pb.UpdateCatalogItemRequest{
+		Id:      req.ID,
+		Name:    req.Name,
+		Price:   price,
+		Stock:   req.Stock,
+		Version: req.Version,
+	})
+	if status.Code(err) == codes.Aborted {
+		log.Warn("Catalog item changed since the form was loaded", log.Fstring("id", req.ID))
+		ch.renderUpdateCatalogItemForm(c, http.StatusConflict, req.ID, &req)
+		return
+	}
+	if err != nil {
+		renderError(c, err, "Failed to update catalog item")
+		return
+	}
 
-	if _, err := ch.client.UpdateCatalogItem(ctx, &pb.UpdateCatalogItemRequest{
-		Id:    req.ID,
-		Name:  req.Name,
-		Price: req.Price,
+	if _, err = ch.client.SetCatalogItemCategories(ctx, &pb.SetCatalogItemCategoriesRequest{
+		Id:          req.ID,
+		CategoryIds: req.CategoryIDs,
 	}); err != nil {
-		log.Error("Failed to update catalog item", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to set catalog item categories")
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/catalog/list")
+	c.Redirect(http.StatusFound, "/catalog/detail?id="+url.QueryEscape(resp.GetItem().GetId()))
 }
 
 func (ch *catalogItemHandler) isValidUpdateCatalogItemRequest(req *UpdateCatalogItemRequest) bool {
 	if req.ID == "" ||
 		req.Name == "" ||
-		req.Price <= 0 {
+		req.Price == "" ||
+		req.Stock < 0 {
 		log.Warn("Invalid request body: %v", req)
 		return false
 	}
@@ -197,8 +335,7 @@ func (ch *catalogItemHandler) DeleteCatalogItem(c *gin.Context) {
 	if _, err := ch.client.DeleteCatalogItem(ctx, &pb.DeleteCatalogItemRequest{
 		Id: id,
 	}); err != nil {
-		log.Error("Failed to delete catalog item", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to delete catalog item")
 		return
 	}
 
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// listCategories lists all categories, each after its parent.
+func (ch *catalogItemHandler) listCategories(ctx context.Context) ([]*pb.Category, error) {
+	resp, err := ch.client.ListCategories(ctx, &pb.ListCategoriesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetCategories(), nil
+}
+
+// categoryPath returns the category with id and the categories above it, from the root down.
+func categoryPath(categories []*pb.Category, id string) []*pb.Category {
+	byID := make(map[string]*pb.Category, len(categories))
+	for _, category := range categories {
+		byID[category.GetId()] = category
+	}
+
+	var path []*pb.Category
+	for category, ok := byID[id]; ok && len(path) < len(categories); category, ok = byID[category.GetParentId()] {
+		path = append([]*pb.Category{category}, path...)
+	}
+	return path
+}
+
+// selectCategories returns the categories with ids, in the order they are listed.
+func selectCategories(categories []*pb.Category, ids []string) []*pb.Category {
+	selected := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		selected[id] = true
+	}
+
+	var res []*pb.Category
+	for _, category := range categories {
+		if selected[category.GetId()] {
+			res = append(res, category)
+		}
+	}
+	return res
+}
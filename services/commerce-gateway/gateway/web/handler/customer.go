@@ -2,20 +2,26 @@ package handler
 
 import (
 	"net/http"
+	"net/url"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
 )
 
 type CustomerHandler interface {
 	ListCustomers(c *gin.Context)
+	GetCustomer(c *gin.Context)
 	CreateCustomerForm(c *gin.Context)
 	CreateCustomer(c *gin.Context)
 	UpdateCustomerForm(c *gin.Context)
 	UpdateCustomer(c *gin.Context)
 	DeleteCustomer(c *gin.Context)
+	ListDeletedCustomers(c *gin.Context)
+	RestoreCustomer(c *gin.Context)
 }
 
 type customerHandler struct {
@@ -31,28 +37,55 @@ func NewCustomerHandler(client pb.CustomerServiceClient) CustomerHandler {
 func (ch *customerHandler) ListCustomers(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	resp, err := ch.client.ListCustomers(ctx, &pb.ListCustomersRequest{})
+	resp, err := ch.client.ListCustomers(ctx, &pb.ListCustomersRequest{
+		PageToken: c.Query("page_token"),
+	})
 	if err != nil {
-		log.Error("Failed to list customers", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to list customers")
 		return
 	}
 
 	c.HTML(http.StatusOK, "customer/list.html", gin.H{
-		"Customers": resp.GetCustomers(),
+		"Customers":     resp.GetCustomers(),
+		"NextPageToken": resp.GetNextPageToken(),
+		"PrevPageToken": resp.GetPrevPageToken(),
+	})
+}
+
+func (ch *customerHandler) GetCustomer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Query("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	resp, err := ch.client.GetCustomer(ctx, &pb.GetCustomerRequest{Id: id})
+	if err != nil {
+		renderError(c, err, "Failed to get customer")
+		return
+	}
+
+	c.HTML(http.StatusOK, "customer/detail.html", gin.H{
+		"Customer": resp.GetCustomer(),
 	})
 }
 
 func (ch *customerHandler) CreateCustomerForm(c *gin.Context) {
-	c.HTML(http.StatusOK, "customer/create.html", nil)
+	c.HTML(http.StatusOK, "customer/create.html", gin.H{
+		"IdempotencyKey": newIdempotencyKey(),
+	})
 }
 
 type CreateCustomerRequest struct {
-	Name    string `form:"name"`
-	Email   string `form:"email"`
-	Street  string `form:"street"`
-	City    string `form:"city"`
-	Country string `form:"country"`
+	Name           string `form:"name"`
+	Email          string `form:"email"`
+	Street         string `form:"street"`
+	City           string `form:"city"`
+	Country        string `form:"country"`
+	IdempotencyKey string `form:"idempotency_key"`
 }
 
 func (ch *customerHandler) CreateCustomer(c *gin.Context) {
@@ -70,19 +103,20 @@ func (ch *customerHandler) CreateCustomer(c *gin.Context) {
 		return
 	}
 
-	if _, err := ch.client.CreateCustomer(ctx, &pb.CreateCustomerRequest{
-		Name:    req.Name,
-		Email:   req.Email,
-		Street:  req.Street,
-		City:    req.City,
-		Country: req.Country,
-	}); err != nil {
-		log.Error("Failed to create customer", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+	resp, err := ch.client.CreateCustomer(ctx, &pb.CreateCustomerRequest{
+		Name:           req.Name,
+		Email:          req.Email,
+		Street:         req.Street,
+		City:           req.City,
+		Country:        req.Country,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to create customer")
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/customer/list")
+	c.Redirect(http.StatusFound, "/customer/detail?id="+url.QueryEscape(resp.GetCustomer().GetId()))
 }
 
 func (ch *customerHandler) isValidCreateCustomerRequest(req *CreateCustomerRequest) bool {
@@ -98,8 +132,6 @@ func (ch *customerHandler) isValidCreateCustomerRequest(req *CreateCustomerReque
 }
 
 func (ch *customerHandler) UpdateCustomerForm(c *gin.Context) {
-	ctx := c.Request.Context()
-
 	id := c.Query("id")
 	if id == "" {
 		log.Warn("ID is required")
@@ -107,15 +139,23 @@ func (ch *customerHandler) UpdateCustomerForm(c *gin.Context) {
 		return
 	}
 
+	ch.renderUpdateCustomerForm(c, http.StatusOK, id, nil)
+}
+
+// renderUpdateCustomerForm renders the update form with the current values of the customer. The rejected
+// update is shown alongside them when the customer was changed since the form it was made from was loaded.
+func (ch *customerHandler) renderUpdateCustomerForm(c *gin.Context, code int, id string, rejected *UpdateCustomerRequest) {
+	ctx := c.Request.Context()
+
 	resp, err := ch.client.GetCustomer(ctx, &pb.GetCustomerRequest{Id: id})
 	if err != nil {
-		log.Error("Failed to get customer", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to get customer")
 		return
 	}
 
-	c.HTML(http.StatusOK, "customer/update.html", gin.H{
+	c.HTML(code, "customer/update.html", gin.H{
 		"Customer": resp.GetCustomer(),
+		"Rejected": rejected,
 	})
 }
 
@@ -126,6 +166,8 @@ type UpdateCustomerRequest struct {
 	Street  string `form:"street"`
 	City    string `form:"city"`
 	Country string `form:"country"`
+	// Version is the version of the customer the form was loaded at.
+	Version int64 `form:"version"`
 }
 
 func (ch *customerHandler) UpdateCustomer(c *gin.Context) {
@@ -143,20 +185,26 @@ func (ch *customerHandler) UpdateCustomer(c *gin.Context) {
 		return
 	}
 
-	if _, err := ch.client.UpdateCustomer(ctx, &pb.UpdateCustomerRequest{
+	resp, err := ch.client.UpdateCustomer(ctx, &pb.UpdateCustomerRequest{
 		Id:      req.ID,
 		Name:    req.Name,
 		Email:   req.Email,
 		Street:  req.Street,
 		City:    req.City,
 		Country: req.Country,
-	}); err != nil {
-		log.Error("Failed to update customer", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		Version: req.Version,
+	})
+	if status.Code(err) == codes.Aborted {
+		log.Warn("Customer changed since the form was loaded", log.Fstring("id", req.ID))
+		ch.renderUpdateCustomerForm(c, http.StatusConflict, req.ID, &req)
+		return
+	}
+	if err != nil {
+		renderError(c, err, "Failed to update customer")
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/customer/list")
+	c.Redirect(http.StatusFound, "/customer/detail?id="+url.QueryEscape(resp.GetCustomer().GetId()))
 }
 
 func (ch *customerHandler) isValidUpdateCustomerRequest(req *UpdateCustomerRequest) bool {
@@ -183,10 +231,46 @@ func (ch *customerHandler) DeleteCustomer(c *gin.Context) {
 	}
 
 	if _, err := ch.client.DeleteCustomer(ctx, &pb.DeleteCustomerRequest{Id: id}); err != nil {
-		log.Error("Failed to delete customer", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to delete customer")
 		return
 	}
 
 	c.Redirect(http.StatusFound, "/customer/list")
 }
+
+// ListDeletedCustomers shows the trash, the deleted customers that can still be restored.
+func (ch *customerHandler) ListDeletedCustomers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	resp, err := ch.client.ListDeletedCustomers(ctx, &pb.ListDeletedCustomersRequest{
+		PageToken: c.Query("page_token"),
+	})
+	if err != nil {
+		renderError(c, err, "Failed to list deleted customers")
+		return
+	}
+
+	c.HTML(http.StatusOK, "customer/trash.html", gin.H{
+		"Customers":     resp.GetCustomers(),
+		"NextPageToken": resp.GetNextPageToken(),
+		"PrevPageToken": resp.GetPrevPageToken(),
+	})
+}
+
+func (ch *customerHandler) RestoreCustomer(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Query("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if _, err := ch.client.RestoreCustomer(ctx, &pb.RestoreCustomerRequest{Id: id}); err != nil {
+		renderError(c, err, "Failed to restore customer")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/customer/detail?id="+url.QueryEscape(id))
+}
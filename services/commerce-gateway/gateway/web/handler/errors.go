@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var httpStatuses = map[codes.Code]int{
+	codes.NotFound:           http.StatusNotFound,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.FailedPrecondition: http.StatusConflict,
+}
+
+// renderError renders the error page for an error returned by a backend service.
+// Client errors show the message reported by the service; anything else is logged
+// with msg and rendered as an internal server error.
+func renderError(c *gin.Context, err error, msg string) {
+	st := status.Convert(err)
+	code, ok := httpStatuses[st.Code()]
+	message := st.Message()
+	if ok {
+		log.Warn(msg, log.Ferror(err))
+	} else {
+		log.Error(msg, log.Ferror(err))
+		code = http.StatusInternalServerError
+		message = "Internal server error"
+	}
+
+	c.HTML(code, "base/error.html", gin.H{
+		"Status":     code,
+		"StatusText": http.StatusText(code),
+		"Message":    message,
+	})
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newIdempotencyKey returns a random key that is embedded in create forms,
+// so that a resubmitted form is not applied twice by the backend service.
+func newIdempotencyKey() string {
+	b := make([]byte, 16) //nolint:gomnd // 128-bit key
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
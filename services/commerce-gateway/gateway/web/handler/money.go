@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// defaultCurrency is the currency preselected in the forms.
+const defaultCurrency = "USD"
+
+// currencyExponents is the number of digits of the minor unit of each currency the forms offer.
+// The services validate the currencies themselves, so this only needs to cover the ones they support.
+var currencyExponents = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"JPY": 0,
+}
+
+// currencies returns the currencies the forms offer in alphabetical order.
+func currencies() []string {
+	cs := make([]string, 0, len(currencyExponents))
+	for currency := range currencyExponents {
+		cs = append(cs, currency)
+	}
+	sort.Strings(cs)
+	return cs
+}
+
+// TemplateFuncs are the functions the templates use to show amounts of money.
+var TemplateFuncs = template.FuncMap{
+	"money":   formatMoney,
+	"decimal": formatDecimal,
+}
+
+// parseMoney parses an amount entered in a form in the major unit of currency, such as "12.34".
+// Amounts with more decimal places than the currency has are rejected rather than rounded.
+func parseMoney(amount, currency string) (*pb.Money, error) {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return nil, fmt.Errorf("unsupported currency %q", currency)
+	}
+	negative := strings.HasPrefix(amount, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return nil, fmt.Errorf("%q is not a decimal", amount)
+	}
+	if len(fraction) > exponent {
+		if strings.Trim(fraction[exponent:], "0") != "" {
+			return nil, fmt.Errorf("%q has more than %d decimal places", amount, exponent)
+		}
+		fraction = fraction[:exponent]
+	}
+	fraction += strings.Repeat("0", exponent-len(fraction))
+
+	minor, err := strconv.ParseInt("0"+whole+fraction, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is out of range", amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return &pb.Money{
+		Amount:   minor,
+		Currency: currency,
+	}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// formatMoney formats m with its currency, such as "12.34 USD". It is empty if m is not set.
+func formatMoney(m *pb.Money) string {
+	if m == nil {
+		return ""
+	}
+	return formatDecimal(m) + " " + m.GetCurrency()
+}
+
+// formatDecimal formats m without its currency, as parseMoney reads it back.
+func formatDecimal(m *pb.Money) string {
+	if m == nil {
+		return ""
+	}
+	amount := m.GetAmount()
+	exponent := currencyExponents[m.GetCurrency()]
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
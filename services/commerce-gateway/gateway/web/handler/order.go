@@ -1,18 +1,31 @@
 package handler
 
 import (
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
+	catalog_pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 	pb "github.com/tusmasoma/go-microservice-k8s/services/order/proto"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type OrderHandler interface {
+	GetOrder(c *gin.Context)
 	ListOrders(c *gin.Context)
 	CreateOrderForm(c *gin.Context)
 	CreateOrder(c *gin.Context)
+	UpdateOrderStatus(c *gin.Context)
+	CancelOrder(c *gin.Context)
 	DeleteOrder(c *gin.Context)
+	WatchOrders(c *gin.Context)
 }
 
 type orderHandler struct {
@@ -25,41 +38,210 @@ func NewOrderHandler(client pb.OrderServiceClient) OrderHandler {
 	}
 }
 
+func (oh *orderHandler) GetOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Query("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	resp, err := oh.client.GetOrder(ctx, &pb.GetOrderRequest{
+		OrderId: id,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to get order")
+		return
+	}
+
+	c.HTML(http.StatusOK, "order/detail.html", gin.H{
+		"Order": resp.GetOrder(),
+		"Lines": newOrderLineViews(resp.GetOrder().GetOrderLines()),
+	})
+}
+
+// orderLineView is an order line with its subtotal, which templates cannot compute themselves.
+type orderLineView struct {
+	Item     *pb.CatalogItem
+	SKU      string
+	Count    int32
+	Subtotal *catalog_pb.Money
+}
+
+func newOrderLineViews(orderLines []*pb.OrderLine) []orderLineView {
+	views := make([]orderLineView, 0, len(orderLines))
+	for _, ol := range orderLines {
+		views = append(views, orderLineView{
+			Item:  ol.GetItem(),
+			SKU:   ol.GetSku(),
+			Count: ol.GetCount(),
+			Subtotal: &catalog_pb.Money{
+				Amount:   ol.GetItem().GetPrice().GetAmount() * int64(ol.GetCount()),
+				Currency: ol.GetItem().GetPrice().GetCurrency(),
+			},
+		})
+	}
+	return views
+}
+
+type ListOrdersRequest struct {
+	CustomerID string `form:"customer_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	MinTotal   string `form:"min_total"`
+	MaxTotal   string `form:"max_total"`
+	Currency   string `form:"currency"`
+	Sort       string `form:"sort"`
+	PageToken  string `form:"page_token"`
+}
+
+// orderSorts are the choices of the sort field in the filter form.
+var orderSorts = []struct {
+	Value string
+	Label string
+}{
+	{"", "ID"},
+	{"-order_date", "Newest first"},
+	{"order_date", "Oldest first"},
+	{"-total_price", "Highest total first"},
+	{"total_price", "Lowest total first"},
+}
+
+const orderFilterDateLayout = "2006-01-02"
+
 func (oh *orderHandler) ListOrders(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	resp, err := oh.client.ListOrders(ctx, &pb.ListOrdersRequest{})
+	var req ListOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Failed to bind request", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if req.Currency == "" {
+		req.Currency = defaultCurrency
+	}
+
+	pbReq, err := newListOrdersRequest(&req)
 	if err != nil {
-		log.Error("Failed to get order list", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		log.Warn("Invalid order filter", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
 		return
 	}
 
+	resp, err := oh.client.ListOrders(ctx, pbReq)
+	if err != nil {
+		renderError(c, err, "Failed to get order list")
+		return
+	}
+
+	var nextPageURL, prevPageURL string
+	if resp.GetNextPageToken() != "" {
+		nextPageURL = orderListURL(&req, resp.GetNextPageToken())
+	}
+	if resp.GetPrevPageToken() != "" {
+		prevPageURL = orderListURL(&req, resp.GetPrevPageToken())
+	}
+
 	c.HTML(http.StatusOK, "order/list.html", gin.H{
-		"Orders": resp.GetOrders(),
+		"Orders":      resp.GetOrders(),
+		"Filter":      req,
+		"Sorts":       orderSorts,
+		"Currencies":  currencies(),
+		"NextPageURL": nextPageURL,
+		"PrevPageURL": prevPageURL,
 	})
 }
 
+// newListOrdersRequest converts the filter form into a ListOrdersRequest.
+// The dates of the form are whole days, so the to date is included in the range.
+// Totals are only compared within the currency of the form, so it restricts the orders
+// when they are sorted by total.
+func newListOrdersRequest(req *ListOrdersRequest) (*pb.ListOrdersRequest, error) {
+	pbReq := &pb.ListOrdersRequest{
+		CustomerId: req.CustomerID,
+		Sort:       req.Sort,
+		PageToken:  req.PageToken,
+	}
+	if strings.TrimPrefix(req.Sort, "-") == "total_price" {
+		pbReq.Currency = req.Currency
+	}
+	if req.From != "" {
+		from, err := time.Parse(orderFilterDateLayout, req.From)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.From = timestamppb.New(from)
+	}
+	if req.To != "" {
+		to, err := time.Parse(orderFilterDateLayout, req.To)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.To = timestamppb.New(to.AddDate(0, 0, 1))
+	}
+	if req.MinTotal != "" {
+		minTotal, err := parseMoney(req.MinTotal, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.MinTotal = minTotal
+	}
+	if req.MaxTotal != "" {
+		maxTotal, err := parseMoney(req.MaxTotal, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.MaxTotal = maxTotal
+	}
+	return pbReq, nil
+}
+
+// orderListURL returns the URL of the order list page at token, keeping the filters of req.
+func orderListURL(req *ListOrdersRequest, token string) string {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"customer_id": req.CustomerID,
+		"from":        req.From,
+		"to":          req.To,
+		"min_total":   req.MinTotal,
+		"max_total":   req.MaxTotal,
+		"currency":    req.Currency,
+		"sort":        req.Sort,
+		"page_token":  token,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return "/order/list?" + query.Encode()
+}
+
 func (oh *orderHandler) CreateOrderForm(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	resp, err := oh.client.GetOrderCreationResources(ctx, &pb.GetOrderCreationResourcesRequest{})
 	if err != nil {
-		log.Error("Failed to get order page data", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to get order page data")
 		return
 	}
 
 	c.HTML(http.StatusOK, "order/create.html", gin.H{
-		"Customers": resp.GetCustomers(),
-		"Items":     resp.GetItems(),
+		"Customers":      resp.GetCustomers(),
+		"Items":          resp.GetItems(),
+		"Currencies":     currencies(),
+		"IdempotencyKey": newIdempotencyKey(),
 	})
 }
 
 type CreateOrderRequest struct {
-	CustomerID string `form:"customer_id"`
-	Count      int    `form:"count"`
-	ItemID     string `form:"item_id"`
+	CustomerID     string `form:"customer_id"`
+	Count          int    `form:"count"`
+	ItemID         string `form:"item_id"`
+	SKU            string `form:"sku"`
+	Currency       string `form:"currency"`
+	IdempotencyKey string `form:"idempotency_key"`
 }
 
 func (oh *orderHandler) CreateOrder(c *gin.Context) {
@@ -80,19 +262,74 @@ func (oh *orderHandler) CreateOrder(c *gin.Context) {
 			Item: &pb.CatalogItem{
 				Id: req.ItemID,
 			},
+			Sku: req.SKU,
 		},
 	}
 
-	if _, err := oh.client.CreateOrder(ctx, &pb.CreateOrderRequest{
-		CustomerId: req.CustomerID,
-		OrderLines: orderLines,
-	}); err != nil {
-		log.Error("Failed to create order", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+	resp, err := oh.client.CreateOrder(ctx, &pb.CreateOrderRequest{
+		CustomerId:     req.CustomerID,
+		OrderLines:     orderLines,
+		Currency:       req.Currency,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to create order")
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/order/list")
+	c.Redirect(http.StatusFound, "/order/detail?id="+url.QueryEscape(resp.GetOrder().GetId()))
+}
+
+type UpdateOrderStatusRequest struct {
+	ID     string `form:"id"`
+	Status string `form:"status"`
+}
+
+func (oh *orderHandler) UpdateOrderStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Error("Failed to bind request", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if req.ID == "" || req.Status == "" {
+		log.Warn("Invalid request body: %v", req)
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	resp, err := oh.client.UpdateOrderStatus(ctx, &pb.UpdateOrderStatusRequest{
+		OrderId: req.ID,
+		Status:  req.Status,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to update order status")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/order/detail?id="+url.QueryEscape(resp.GetOrder().GetId()))
+}
+
+func (oh *orderHandler) CancelOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.PostForm("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	resp, err := oh.client.CancelOrder(ctx, &pb.CancelOrderRequest{
+		OrderId: id,
+	})
+	if err != nil {
+		renderError(c, err, "Failed to cancel order")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/order/detail?id="+url.QueryEscape(resp.GetOrder().GetId()))
 }
 
 func (oh *orderHandler) DeleteOrder(c *gin.Context) {
@@ -107,10 +344,56 @@ func (oh *orderHandler) DeleteOrder(c *gin.Context) {
 	if _, err := oh.client.DeleteOrder(ctx, &pb.DeleteOrderRequest{
 		OrderId: id,
 	}); err != nil {
-		log.Error("Failed to delete order", log.Ferror(err))
-		c.String(http.StatusInternalServerError, "Internal server error")
+		renderError(c, err, "Failed to delete order")
 		return
 	}
 
 	c.Redirect(http.StatusFound, "/order/list")
 }
+
+// orderChangeView is the data of the events streamed by WatchOrders.
+type orderChangeView struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Status string `json:"status,omitempty"`
+}
+
+// WatchOrders streams the changes of the orders as server-sent events. The ID of an event is its resume token,
+// which browsers send back in the Last-Event-ID header when they reconnect, so that no change is missed.
+func (oh *orderHandler) WatchOrders(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// The stream lasts longer than the write timeout of the server.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("Failed to clear write deadline", log.Ferror(err))
+	}
+
+	stream, err := oh.client.WatchOrders(ctx, &pb.WatchOrdersRequest{
+		ResumeToken: c.GetHeader("Last-Event-ID"),
+	})
+	if err != nil {
+		renderError(c, err, "Failed to watch orders")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Stream(func(io.Writer) bool {
+		res, rerr := stream.Recv()
+		if rerr != nil {
+			if status.Code(rerr) != codes.Canceled {
+				log.Warn("Order stream ended", log.Ferror(rerr))
+			}
+			return false
+		}
+		c.Render(-1, sse.Event{
+			Id:    res.GetResumeToken(),
+			Event: "order",
+			Data: orderChangeView{
+				Type:   res.GetType(),
+				ID:     res.GetId(),
+				Status: res.GetOrder().GetStatus(),
+			},
+		})
+		return true
+	})
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// SearchCatalogItemsRequest is the search form. The price range is in Currency, which is also the
+// currency the prices are shown in.
+type SearchCatalogItemsRequest struct {
+	Query     string `form:"q"`
+	MinPrice  string `form:"min_price"`
+	MaxPrice  string `form:"max_price"`
+	Currency  string `form:"currency"`
+	PageToken string `form:"page_token"`
+}
+
+// searchResultView is a search result with its name split into the words that matched the query and the rest.
+type searchResultView struct {
+	Item *pb.CatalogItem
+	Name []nameSegment
+}
+
+type nameSegment struct {
+	Text  string
+	Match bool
+}
+
+// SearchCatalogItems shows the search form, and the results of the search when a query is entered.
+func (ch *catalogItemHandler) SearchCatalogItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req SearchCatalogItemsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("Failed to bind request", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	page := gin.H{
+		"Filter":     req,
+		"Currencies": currencies(),
+	}
+	if req.Query == "" {
+		c.HTML(http.StatusOK, "catalog/search.html", page)
+		return
+	}
+
+	pbReq, err := newSearchCatalogItemsRequest(&req)
+	if err != nil {
+		log.Warn("Invalid price range", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	resp, err := ch.client.SearchCatalogItems(ctx, pbReq)
+	if err != nil {
+		renderError(c, err, "Failed to search catalog items")
+		return
+	}
+
+	results := make([]searchResultView, 0, len(resp.GetResults()))
+	for _, result := range resp.GetResults() {
+		results = append(results, searchResultView{
+			Item: result.GetItem(),
+			Name: highlightName(result.GetItem().GetName(), result.GetHighlights()),
+		})
+	}
+
+	var nextPageURL, prevPageURL string
+	if resp.GetNextPageToken() != "" {
+		nextPageURL = searchURL(&req, resp.GetNextPageToken())
+	}
+	if resp.GetPrevPageToken() != "" {
+		prevPageURL = searchURL(&req, resp.GetPrevPageToken())
+	}
+
+	page["Searched"] = true
+	page["Results"] = results
+	page["Total"] = resp.GetTotalSize()
+	page["NextPageURL"] = nextPageURL
+	page["PrevPageURL"] = prevPageURL
+	c.HTML(http.StatusOK, "catalog/search.html", page)
+}
+
+// newSearchCatalogItemsRequest converts the search form into a SearchCatalogItemsRequest.
+// The price range is in the default currency when no currency is chosen.
+func newSearchCatalogItemsRequest(req *SearchCatalogItemsRequest) (*pb.SearchCatalogItemsRequest, error) {
+	pbReq := &pb.SearchCatalogItemsRequest{
+		Query:     req.Query,
+		PageToken: req.PageToken,
+		Currency:  req.Currency,
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = defaultCurrency
+	}
+	if req.MinPrice != "" {
+		minPrice, err := parseMoney(req.MinPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.MinPrice = minPrice
+	}
+	if req.MaxPrice != "" {
+		maxPrice, err := parseMoney(req.MaxPrice, currency)
+		if err != nil {
+			return nil, err
+		}
+		pbReq.MaxPrice = maxPrice
+	}
+	return pbReq, nil
+}
+
+// searchURL returns the URL of the search page at token, keeping the query and filters of req.
+func searchURL(req *SearchCatalogItemsRequest, token string) string {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"q":          req.Query,
+		"min_price":  req.MinPrice,
+		"max_price":  req.MaxPrice,
+		"currency":   req.Currency,
+		"page_token": token,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	return "/catalog/search?" + query.Encode()
+}
+
+// highlightName splits name at the ranges of the words that matched. The ranges come from the
+// search index, which may lag behind the item, so those that do not fit the name are ignored.
+func highlightName(name string, highlights []*pb.TextRange) []nameSegment {
+	var segments []nameSegment
+	pos := 0
+	for _, h := range highlights {
+		start, end := int(h.GetStart()), int(h.GetEnd())
+		if start < pos || end <= start || end > len(name) ||
+			!utf8.RuneStart(name[start]) || (end < len(name) && !utf8.RuneStart(name[end])) {
+			continue
+		}
+		if start > pos {
+			segments = append(segments, nameSegment{Text: name[pos:start]})
+		}
+		segments = append(segments, nameSegment{Text: name[start:end], Match: true})
+		pos = end
+	}
+	if pos < len(name) {
+		segments = append(segments, nameSegment{Text: name[pos:]})
+	}
+	return segments
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// ListDeletedCatalogItems shows the trash, the deleted items that can still be restored.
+func (ch *catalogItemHandler) ListDeletedCatalogItems(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	resp, err := ch.client.ListDeletedCatalogItems(ctx, &pb.ListDeletedCatalogItemsRequest{
+		PageToken: c.Query("page_token"),
+	})
+	if err != nil {
+		renderError(c, err, "Failed to list deleted catalog items")
+		return
+	}
+
+	c.HTML(http.StatusOK, "catalog/trash.html", gin.H{
+		"Items":         resp.GetItems(),
+		"NextPageToken": resp.GetNextPageToken(),
+		"PrevPageToken": resp.GetPrevPageToken(),
+	})
+}
+
+func (ch *catalogItemHandler) RestoreCatalogItem(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Query("id")
+	if id == "" {
+		log.Warn("ID is required")
+		c.String(http.StatusBadRequest, "ID is required")
+		return
+	}
+
+	if _, err := ch.client.RestoreCatalogItem(ctx, &pb.RestoreCatalogItemRequest{
+		Id: id,
+	}); err != nil {
+		renderError(c, err, "Failed to restore catalog item")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/catalog/detail?id="+url.QueryEscape(id))
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+type CreateVariantRequest struct {
+	CatalogItemID string `form:"id"`
+	SKU           string `form:"sku"`
+	Attributes    string `form:"attributes"`
+	Price         string `form:"price"`
+	Currency      string `form:"currency"`
+	Stock         int32  `form:"stock"`
+}
+
+func (ch *catalogItemHandler) CreateVariant(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req CreateVariantRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Error("Failed to bind request", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+	if req.CatalogItemID == "" || req.SKU == "" || req.Stock < 0 {
+		log.Warn("Invalid request body: %v", req)
+		c.String(http.StatusBadRequest, "Invalid request")
+		return
+	}
+	attributes, err := parseAttributes(req.Attributes)
+	if err != nil {
+		log.Warn("Invalid attributes", log.Ferror(err))
+		c.String(http.StatusBadRequest, "Invalid attributes")
+		return
+	}
+	// The variant is sold at the price of its item unless a price is entered.
+	var price *pb.Money
+	if req.Price != "" {
+		if price, err = parseMoney(req.Price, req.Currency); err != nil {
+			log.Warn("Invalid price", log.Ferror(err))
+			c.String(http.StatusBadRequest, "Invalid price")
+			return
+		}
+	}
+
+	if _, err = ch.client.CreateVariant(ctx, &pb.CreateVariantRequest{
+		CatalogItemId: req.CatalogItemID,
+		Sku:           req.SKU,
+		Attributes:    attributes,
+		Price:         price,
+		Stock:         req.Stock,
+	}); err != nil {
+		renderError(c, err, "Failed to create variant")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/catalog/detail?id="+url.QueryEscape(req.CatalogItemID))
+}
+
+func (ch *catalogItemHandler) DeleteVariant(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := c.Query("id")
+	sku := c.Query("sku")
+	if id == "" || sku == "" {
+		log.Warn("ID and SKU are required")
+		c.String(http.StatusBadRequest, "ID and SKU are required")
+		return
+	}
+
+	if _, err := ch.client.DeleteVariant(ctx, &pb.DeleteVariantRequest{
+		Sku: sku,
+	}); err != nil {
+		renderError(c, err, "Failed to delete variant")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/catalog/detail?id="+url.QueryEscape(id))
+}
+
+// parseAttributes parses the attributes of a variant entered in a form as comma separated
+// name=value pairs, such as "size=M, color=red".
+func parseAttributes(s string) (map[string]string, error) {
+	attributes := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("attribute %q is not a name=value pair", strings.TrimSpace(pair))
+		}
+		attributes[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return attributes, nil
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/config"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/gateway"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository/mysql"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository/publisher"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/usecase"
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
 )
@@ -42,13 +44,20 @@ func main() {
 		return
 	}
 
-	err = container.Invoke(func(grpcHandler pb.CustomerServiceServer, config *config.ServerConfig) {
+	err = container.Invoke(func(
+		grpcHandler pb.CustomerServiceServer,
+		config *config.ServerConfig,
+		idempotencyInterceptor grpc.UnaryServerInterceptor,
+		idempotencySweeper *idempotency.Sweeper,
+		outboxRelay *usecase.OutboxRelay,
+		trashPurger *usecase.TrashPurger,
+	) {
 		lis, err := net.Listen("tcp", addr) //nolint:govet // This is not a mistake
 		if err != nil {
 			log.Critical("Failed to listen", log.Ferror(err))
 		}
 
-		srv := grpc.NewServer()
+		srv := grpc.NewServer(grpc.UnaryInterceptor(idempotencyInterceptor))
 
 		pb.RegisterCustomerServiceServer(srv, grpcHandler)
 
@@ -56,6 +65,10 @@ func main() {
 
 		log.Info("Server started", log.Fstring("addr", addr))
 
+		go idempotencySweeper.Run(mainCtx)
+		go outboxRelay.Run(mainCtx)
+		go trashPurger.Run(mainCtx)
+
 		go func() {
 			if err = srv.Serve(lis); err != nil {
 				log.Critical("Failed to serve", log.Ferror(err))
@@ -90,10 +103,20 @@ func BuildContainer(ctx context.Context) (*dig.Container, error) {
 		config.NewServerConfig,
 		config.NewDBConfig,
 		mysql.NewMySQLDB,
+		idempotency.NewConfig,
+		config.NewEventConfig,
+		config.NewTrashConfig,
 		mysql.NewTransactionRepository,
+		mysql.NewIdempotencyRepository,
+		mysql.NewOutboxRepository,
+		publisher.NewEventPublisher,
 		mysql.NewCustomerRepository,
 		usecase.NewCustomerUsecase,
 		gateway.NewCustomerHandler,
+		idempotency.NewInterceptor,
+		idempotency.NewSweeper,
+		usecase.NewOutboxRelay,
+		usecase.NewTrashPurger,
 	}
 
 	for _, provider := range providers {
@@ -11,6 +11,8 @@ import (
 
 const (
 	serverPrefix = "SERVER_"
+	eventPrefix  = "EVENT_"
+	trashPrefix  = "TRASH_"
 )
 
 type DBConfig struct {
@@ -29,6 +31,26 @@ type ServerConfig struct {
 	PreflightCacheDurationSec int           `env:"PREFLIGHT_CACHE_DURATION_SEC,default=300"`
 }
 
+// EventConfig selects the publisher of the events stored in the outbox and how often the outbox is relayed.
+// Publisher is either "log", which writes the events as JSON lines to LogFile or to the standard output,
+// or "memory", which keeps them in memory.
+// The relay claims the events of a batch for RelayLease, which should be longer than publishing a batch.
+type EventConfig struct {
+	Publisher      string        `env:"PUBLISHER,default=log"`
+	LogFile        string        `env:"LOG_FILE"`
+	RelayInterval  time.Duration `env:"RELAY_INTERVAL,default=1s"`
+	RelayBatchSize int           `env:"RELAY_BATCH_SIZE,default=100"`
+	RelayLease     time.Duration `env:"RELAY_LEASE,default=1m"`
+}
+
+// TrashConfig controls how long deleted customers are kept in the trash, from which they can be restored,
+// before they are purged. The trash is purged every PurgeInterval, PurgeBatchSize customers at a time.
+type TrashConfig struct {
+	Retention      time.Duration `env:"RETENTION,default=720h"`
+	PurgeInterval  time.Duration `env:"PURGE_INTERVAL,default=1h"`
+	PurgeBatchSize int           `env:"PURGE_BATCH_SIZE,default=100"`
+}
+
 func NewDBConfig(ctx context.Context, dbPrefix string) (*DBConfig, error) {
 	conf := &DBConfig{}
 	pl := envconfig.PrefixLookuper(dbPrefix, envconfig.OsLookuper())
@@ -54,3 +76,29 @@ func NewServerConfig(ctx context.Context) (*ServerConfig, error) {
 	}
 	return conf, nil
 }
+
+func NewEventConfig(ctx context.Context) (*EventConfig, error) {
+	conf := &EventConfig{}
+	pl := envconfig.PrefixLookuper(eventPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load event config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewTrashConfig(ctx context.Context) (*TrashConfig, error) {
+	conf := &TrashConfig{}
+	pl := envconfig.PrefixLookuper(trashPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load trash config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
@@ -14,31 +14,45 @@ type Customer struct {
 	Street  string `json:"street" db:"street"`
 	City    string `json:"city" db:"city"`
 	Country string `json:"country" db:"country"`
+	// Version is incremented by every update of the customer, so that an update made from a stale read is rejected.
+	Version int64 `json:"version" db:"version"`
+	// DeletedAt is when the customer was moved to the trash, and nil while they are not there.
+	// A customer in the trash can be restored until they are purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// The fields of a customer an update can be limited to.
+const (
+	CustomerFieldName    = "name"
+	CustomerFieldEmail   = "email"
+	CustomerFieldStreet  = "street"
+	CustomerFieldCity    = "city"
+	CustomerFieldCountry = "country"
+)
+
 func NewCustomer(id, name, email, street, city, country string) (*Customer, error) {
 	if id == "" {
 		id = uuid.New().String()
 	}
 	if name == "" {
 		log.Error("name is required")
-		return nil, errors.New("name is required")
+		return nil, NewError(ErrInvalidArgument, "name is required")
 	}
 	if email == "" {
 		log.Error("email is required")
-		return nil, errors.New("email is required")
+		return nil, NewError(ErrInvalidArgument, "email is required")
 	}
 	if street == "" {
 		log.Error("street is required")
-		return nil, errors.New("street is required")
+		return nil, NewError(ErrInvalidArgument, "street is required")
 	}
 	if city == "" {
 		log.Error("city is required")
-		return nil, errors.New("city is required")
+		return nil, NewError(ErrInvalidArgument, "city is required")
 	}
 	if country == "" {
 		log.Error("country is required")
-		return nil, errors.New("country is required")
+		return nil, NewError(ErrInvalidArgument, "country is required")
 	}
 	return &Customer{
 		ID:      id,
@@ -47,5 +61,6 @@ func NewCustomer(id, name, email, street, city, country string) (*Customer, erro
 		Street:  street,
 		City:    city,
 		Country: country,
+		Version: 1,
 	}, nil
 }
@@ -57,6 +57,7 @@ func TestEntity_NewCustomer(t *testing.T) {
 					Street:  "1600 Pennsylvania Avenue NW",
 					City:    "Washington",
 					Country: "USA",
+					Version: 1,
 				},
 				err: nil,
 			},
@@ -89,6 +90,7 @@ func TestEntity_NewCustomer(t *testing.T) {
 					Street:  "1600 Pennsylvania Avenue NW",
 					City:    "Washington",
 					Country: "USA",
+					Version: 1,
 				},
 				err: nil,
 			},
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_NewEvent(t *testing.T) {
+	t.Parallel()
+
+	customerID := uuid.New().String()
+	now := time.Now()
+
+	patterns := []struct {
+		name string
+		arg  struct {
+			eventType   EventType
+			aggregateID string
+			payload     interface{}
+		}
+		want    string
+		wantErr error
+	}{
+		{
+			name: "success",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeCustomerUpdated,
+				aggregateID: customerID,
+				payload:     Customer{ID: customerID, Name: "John Doe", Email: "john.doe@example.com"},
+			},
+			want:    `{"id":"` + customerID + `","name":"John Doe","email":"john.doe@example.com","street":"","city":"","country":"","version":0}`,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: aggregate id is required",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType: EventTypeCustomerDeleted,
+				payload:   Deletion{ID: customerID},
+			},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name: "Fail: payload cannot be encoded",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeCustomerCreated,
+				aggregateID: customerID,
+				payload:     make(chan int),
+			},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := NewEvent(tt.arg.eventType, tt.arg.aggregateID, tt.arg.payload, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if event.ID == "" || event.Type != tt.arg.eventType || event.AggregateID != tt.arg.aggregateID || !event.OccurredAt.Equal(now) {
+					t.Errorf("NewEvent() = %v", event)
+				}
+				if !json.Valid(event.Payload) || string(event.Payload) != tt.want {
+					t.Errorf("NewEvent() payload = %s, want %s", event.Payload, tt.want)
+				}
+			}
+		})
+	}
+}
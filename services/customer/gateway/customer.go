@@ -2,11 +2,15 @@ package gateway
 
 import (
 	"context"
+	"slices"
 
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/usecase"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
@@ -15,9 +19,12 @@ import (
 type CustomerHandler interface {
 	GetCustomer(ctx context.Context, req *pb.GetCustomerRequest) (*pb.GetCustomerResponse, error)
 	ListCustomers(ctx context.Context, req *pb.ListCustomersRequest) (*pb.ListCustomersResponse, error)
+	ListCustomersByIDs(ctx context.Context, req *pb.ListCustomersByIDsRequest) (*pb.ListCustomersByIDsResponse, error)
 	CreateCustomer(ctx context.Context, req *pb.CreateCustomerRequest) (*pb.CreateCustomerResponse, error)
 	UpdateCustomer(ctx context.Context, req *pb.UpdateCustomerRequest) (*pb.UpdateCustomerResponse, error)
 	DeleteCustomer(ctx context.Context, req *pb.DeleteCustomerRequest) (*pb.DeleteCustomerResponse, error)
+	RestoreCustomer(ctx context.Context, req *pb.RestoreCustomerRequest) (*pb.RestoreCustomerResponse, error)
+	ListDeletedCustomers(ctx context.Context, req *pb.ListDeletedCustomersRequest) (*pb.ListDeletedCustomersResponse, error)
 }
 
 type customerHandler struct {
@@ -38,44 +45,60 @@ func (ch *customerHandler) GetCustomer(ctx context.Context, req *pb.GetCustomerR
 		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
 	}
 
-	customer, err := ch.cuc.GetCustomer(ctx, id)
+	customer, err := ch.cuc.GetCustomer(ctx, id, req.GetShowDeleted())
 	if err != nil {
-		log.Error("Failed to get customer", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to get customer")
+		return nil, toStatusError(err, "Failed to get customer")
 	}
 
 	return &pb.GetCustomerResponse{
-		Customer: &pb.Customer{
-			Id:      customer.ID,
-			Name:    customer.Name,
-			Email:   customer.Email,
-			Street:  customer.Street,
-			City:    customer.City,
-			Country: customer.Country,
-		},
+		Customer: convertCustomer(customer),
 	}, nil
 }
 
-func (ch *customerHandler) ListCustomers(ctx context.Context, _ *pb.ListCustomersRequest) (*pb.ListCustomersResponse, error) {
-	customers, err := ch.cuc.ListCustomers(ctx)
+func (ch *customerHandler) ListCustomers(ctx context.Context, req *pb.ListCustomersRequest) (*pb.ListCustomersResponse, error) {
+	if req.GetPageSize() < 0 {
+		log.Warn("Invalid page size", log.Fint("page_size", int(req.GetPageSize())))
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	}
+
+	customers, info, err := ch.cuc.ListCustomers(ctx, repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	})
 	if err != nil {
-		log.Error("Failed to list customers", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to list customers")
+		return nil, toStatusError(err, "Failed to list customers")
 	}
 
 	var res []*pb.Customer
-	for _, customer := range customers {
-		res = append(res, &pb.Customer{
-			Id:      customer.ID,
-			Name:    customer.Name,
-			Email:   customer.Email,
-			Street:  customer.Street,
-			City:    customer.City,
-			Country: customer.Country,
-		})
+	for i := range customers {
+		res = append(res, convertCustomer(&customers[i]))
 	}
 
 	return &pb.ListCustomersResponse{
+		Customers:     res,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
+	}, nil
+}
+
+func (ch *customerHandler) ListCustomersByIDs(ctx context.Context, req *pb.ListCustomersByIDsRequest) (*pb.ListCustomersByIDsResponse, error) {
+	ids := req.GetIds()
+	if len(ids) == 0 {
+		log.Warn("IDs are required")
+		return nil, status.Errorf(codes.InvalidArgument, "IDs are required")
+	}
+
+	customers, err := ch.cuc.ListCustomersByIDs(ctx, ids, req.GetShowDeleted())
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list customers by IDs")
+	}
+
+	var res []*pb.Customer
+	for i := range customers {
+		res = append(res, convertCustomer(&customers[i]))
+	}
+
+	return &pb.ListCustomersByIDsResponse{
 		Customers: res,
 	}, nil
 }
@@ -86,12 +109,14 @@ func (ch *customerHandler) CreateCustomer(ctx context.Context, req *pb.CreateCus
 	}
 
 	params := ch.convertCreateCustomerReqeuestToParams(req)
-	if err := ch.cuc.CreateCustomer(ctx, params); err != nil {
-		log.Error("Failed to create customer", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to create customer")
+	customer, err := ch.cuc.CreateCustomer(ctx, params)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to create customer")
 	}
 
-	return &pb.CreateCustomerResponse{}, nil
+	return &pb.CreateCustomerResponse{
+		Customer: convertCustomer(customer),
+	}, nil
 }
 
 func (ch *customerHandler) isValidCreateCustomerRequest(req *pb.CreateCustomerRequest) bool {
@@ -123,34 +148,54 @@ func (ch *customerHandler) convertCreateCustomerReqeuestToParams(req *pb.CreateC
 	}
 }
 
+// customerPaths maps the paths of an update mask to the fields of a customer they update.
+var customerPaths = map[string]string{
+	"name":    entity.CustomerFieldName,
+	"email":   entity.CustomerFieldEmail,
+	"street":  entity.CustomerFieldStreet,
+	"city":    entity.CustomerFieldCity,
+	"country": entity.CustomerFieldCountry,
+}
+
 func (ch *customerHandler) UpdateCustomer(ctx context.Context, req *pb.UpdateCustomerRequest) (*pb.UpdateCustomerResponse, error) {
-	if !ch.isValidUpdateCustomerRequest(req) {
+	fields, err := toUpdateFields(req.GetUpdateMask(), customerPaths)
+	if err != nil {
+		return nil, toStatusError(err, "Invalid update mask")
+	}
+	if !ch.isValidUpdateCustomerRequest(req, fields) {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid request")
 	}
 
-	params := ch.convertUpdateCustomerReqeuestToParams(req)
-	if err := ch.cuc.UpdateCustomer(ctx, params); err != nil {
-		log.Error("Failed to update customer", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to update customer")
+	params := ch.convertUpdateCustomerReqeuestToParams(req, fields)
+	customer, err := ch.cuc.UpdateCustomer(ctx, params)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update customer")
 	}
 
-	return &pb.UpdateCustomerResponse{}, nil
+	return &pb.UpdateCustomerResponse{
+		Customer: convertCustomer(customer),
+	}, nil
 }
 
-func (ch *customerHandler) isValidUpdateCustomerRequest(req *pb.UpdateCustomerRequest) bool {
+// isValidUpdateCustomerRequest checks the request, requiring only the fields it updates to be set.
+func (ch *customerHandler) isValidUpdateCustomerRequest(req *pb.UpdateCustomerRequest, fields []string) bool {
+	missing := func(field, value string) bool {
+		return value == "" && (len(fields) == 0 || slices.Contains(fields, field))
+	}
 	if req.GetId() == "" ||
-		req.GetName() == "" ||
-		req.GetEmail() == "" ||
-		req.GetStreet() == "" ||
-		req.GetCity() == "" ||
-		req.GetCountry() == "" {
+		missing(entity.CustomerFieldName, req.GetName()) ||
+		missing(entity.CustomerFieldEmail, req.GetEmail()) ||
+		missing(entity.CustomerFieldStreet, req.GetStreet()) ||
+		missing(entity.CustomerFieldCity, req.GetCity()) ||
+		missing(entity.CustomerFieldCountry, req.GetCountry()) ||
+		req.GetVersion() < 0 {
 		log.Warn("Invalid request body: %v", req)
 		return false
 	}
 	return true
 }
 
-func (ch *customerHandler) convertUpdateCustomerReqeuestToParams(req *pb.UpdateCustomerRequest) *usecase.UpdateCustomerParams {
+func (ch *customerHandler) convertUpdateCustomerReqeuestToParams(req *pb.UpdateCustomerRequest, fields []string) *usecase.UpdateCustomerParams {
 	return &usecase.UpdateCustomerParams{
 		ID:      req.GetId(),
 		Name:    req.GetName(),
@@ -158,6 +203,8 @@ func (ch *customerHandler) convertUpdateCustomerReqeuestToParams(req *pb.UpdateC
 		Street:  req.GetStreet(),
 		City:    req.GetCity(),
 		Country: req.GetCountry(),
+		Version: req.GetVersion(),
+		Fields:  fields,
 	}
 }
 
@@ -169,9 +216,25 @@ func (ch *customerHandler) DeleteCustomer(ctx context.Context, req *pb.DeleteCus
 	}
 
 	if err := ch.cuc.DeleteCustomer(ctx, id); err != nil {
-		log.Error("Failed to delete customer", log.Ferror(err))
-		return nil, status.Errorf(codes.Internal, "Failed to delete customer")
+		return nil, toStatusError(err, "Failed to delete customer")
 	}
 
 	return &pb.DeleteCustomerResponse{}, nil
 }
+
+func convertCustomer(customer *entity.Customer) *pb.Customer {
+	var deletedAt *timestamppb.Timestamp
+	if customer.DeletedAt != nil {
+		deletedAt = timestamppb.New(*customer.DeletedAt)
+	}
+	return &pb.Customer{
+		Id:        customer.ID,
+		Name:      customer.Name,
+		Email:     customer.Email,
+		Street:    customer.Street,
+		City:      customer.City,
+		Country:   customer.Country,
+		Version:   customer.Version,
+		DeletedAt: deletedAt,
+	}
+}
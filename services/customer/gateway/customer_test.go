@@ -13,8 +13,10 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/usecase"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
@@ -91,16 +93,41 @@ func TestHandler_GetCustomer(t *testing.T) {
 				tuc.EXPECT().GetCustomer(
 					gomock.Any(),
 					itemID,
+					false,
 				).Return(&customer, nil)
 			},
 			request:    &pb.GetCustomerRequest{Id: itemID},
 			wantStatus: codes.OK,
 		},
+		{
+			name: "success: customer in the trash",
+			setup: func(tuc *mock.MockCustomerUseCase) {
+				tuc.EXPECT().GetCustomer(
+					gomock.Any(),
+					itemID,
+					true,
+				).Return(&customer, nil)
+			},
+			request:    &pb.GetCustomerRequest{Id: itemID, ShowDeleted: true},
+			wantStatus: codes.OK,
+		},
 		{
 			name:       "Fail: invalid request of id is empty",
 			request:    &pb.GetCustomerRequest{Id: ""},
 			wantStatus: codes.InvalidArgument,
 		},
+		{
+			name: "Fail: customer not found",
+			setup: func(tuc *mock.MockCustomerUseCase) {
+				tuc.EXPECT().GetCustomer(
+					gomock.Any(),
+					itemID,
+					false,
+				).Return(nil, entity.NewError(entity.ErrNotFound, "customer not found"))
+			},
+			request:    &pb.GetCustomerRequest{Id: itemID},
+			wantStatus: codes.NotFound,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -152,11 +179,28 @@ func TestHandler_ListCustomers(t *testing.T) {
 			setup: func(tuc *mock.MockCustomerUseCase) {
 				tuc.EXPECT().ListCustomers(
 					gomock.Any(),
-				).Return(customers, nil)
+					repository.Page{Size: 1, Token: "token"},
+				).Return(customers, repository.PageInfo{NextToken: "next", PrevToken: "prev"}, nil)
 			},
-			request:    &pb.ListCustomersRequest{},
+			request:    &pb.ListCustomersRequest{PageSize: 1, PageToken: "token"},
 			wantStatus: codes.OK,
 		},
+		{
+			name:       "Fail: invalid request of negative page size",
+			request:    &pb.ListCustomersRequest{PageSize: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(tuc *mock.MockCustomerUseCase) {
+				tuc.EXPECT().ListCustomers(
+					gomock.Any(),
+					repository.Page{Token: "invalid"},
+				).Return(nil, repository.PageInfo{}, repository.ErrInvalidPageToken)
+			},
+			request:    &pb.ListCustomersRequest{PageToken: "invalid"},
+			wantStatus: codes.InvalidArgument,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -176,6 +220,82 @@ func TestHandler_ListCustomers(t *testing.T) {
 				if len(resp.GetCustomers()) != len(customers) {
 					t.Fatalf("handler returned wrong item data")
 				}
+				if resp.GetNextPageToken() != "next" || resp.GetPrevPageToken() != "prev" {
+					t.Fatalf("handler returned wrong page tokens")
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_ListCustomersByIDs(t *testing.T) {
+	t.Parallel()
+
+	customers := []entity.Customer{
+		{
+			ID:      uuid.New().String(),
+			Name:    "John Doe",
+			Email:   "john.doe@example.com",
+			Street:  "123 Maple Street",
+			City:    "Springfield",
+			Country: "USA",
+		},
+		{
+			ID:      uuid.New().String(),
+			Name:    "Jane Doe",
+			Email:   "jane.doe@example.com",
+			Street:  "456 Oak Street",
+			City:    "Springfield",
+			Country: "USA",
+		},
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerUseCase,
+		)
+		request    *pb.ListCustomersByIDsRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCustomerUseCase) {
+				cuc.EXPECT().ListCustomersByIDs(
+					gomock.Any(),
+					[]string{customers[0].ID, customers[1].ID},
+					false,
+				).Return(customers, nil)
+			},
+			request: &pb.ListCustomersByIDsRequest{
+				Ids: []string{customers[0].ID, customers[1].ID},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid request of ids is empty",
+			request:    &pb.ListCustomersByIDsRequest{Ids: []string{}},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.ListCustomersByIDs(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				if len(resp.GetCustomers()) != len(customers) {
+					t.Fatalf("handler returned wrong customer data")
+				}
 			}
 		})
 	}
@@ -184,6 +304,15 @@ func TestHandler_ListCustomers(t *testing.T) {
 func TestHandler_CreateCustomer(t *testing.T) {
 	t.Parallel()
 
+	customer := entity.Customer{
+		ID:      uuid.New().String(),
+		Name:    "John Doe",
+		Email:   "john.doe@example.com",
+		Street:  "123 Maple Street",
+		City:    "Springfield",
+		Country: "USA",
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -204,7 +333,7 @@ func TestHandler_CreateCustomer(t *testing.T) {
 						City:    "Springfield",
 						Country: "USA",
 					},
-				).Return(nil)
+				).Return(&customer, nil)
 			},
 			request: &pb.CreateCustomerRequest{
 				Name:    "John Doe",
@@ -280,13 +409,13 @@ func TestHandler_CreateCustomer(t *testing.T) {
 			client, cleanup := setupTestServer(t, tt.setup)
 			defer cleanup()
 
-			req, err := client.CreateCustomer(context.Background(), tt.request)
+			resp, err := client.CreateCustomer(context.Background(), tt.request)
 			if status.Code(err) != tt.wantStatus {
 				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
 			}
 
 			if tt.wantStatus == codes.OK {
-				if req == nil {
+				if resp.GetCustomer().GetId() != customer.ID {
 					t.Fatalf("handler returned wrong item data")
 				}
 			}
@@ -299,6 +428,15 @@ func TestHandler_UpdateCustomer(t *testing.T) {
 
 	itemID := uuid.New().String()
 
+	customer := entity.Customer{
+		ID:      itemID,
+		Name:    "New John Doe",
+		Email:   "john.new.doe@example.com",
+		Street:  "123 Maple Street",
+		City:    "Springfield",
+		Country: "USA",
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -320,7 +458,7 @@ func TestHandler_UpdateCustomer(t *testing.T) {
 						City:    "Springfield",
 						Country: "USA",
 					},
-				).Return(nil)
+				).Return(&customer, nil)
 			},
 			request: &pb.UpdateCustomerRequest{
 				Id:      itemID,
@@ -332,6 +470,70 @@ func TestHandler_UpdateCustomer(t *testing.T) {
 			},
 			wantStatus: codes.OK,
 		},
+		{
+			name: "success: only the masked fields are validated and updated",
+			setup: func(tuc *mock.MockCustomerUseCase) {
+				tuc.EXPECT().UpdateCustomer(
+					gomock.Any(),
+					&usecase.UpdateCustomerParams{
+						ID:     itemID,
+						Email:  "john.new.doe@example.com",
+						Fields: []string{entity.CustomerFieldEmail},
+					},
+				).Return(&customer, nil)
+			},
+			request: &pb.UpdateCustomerRequest{
+				Id:         itemID,
+				Email:      "john.new.doe@example.com",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email"}},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: invalid request of unknown update mask path",
+			request: &pb.UpdateCustomerRequest{
+				Id:         itemID,
+				Email:      "john.new.doe@example.com",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email", "phone"}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid request of masked city is empty",
+			request: &pb.UpdateCustomerRequest{
+				Id:         itemID,
+				Email:      "john.new.doe@example.com",
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"email", "city"}},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: customer is updated concurrently",
+			setup: func(tuc *mock.MockCustomerUseCase) {
+				tuc.EXPECT().UpdateCustomer(
+					gomock.Any(),
+					&usecase.UpdateCustomerParams{
+						ID:      itemID,
+						Name:    "New John Doe",
+						Email:   "john.new.doe@example.com",
+						Street:  "123 Maple Street",
+						City:    "Springfield",
+						Country: "USA",
+						Version: 1,
+					},
+				).Return(nil, entity.NewError(entity.ErrConflict, "customer has been changed concurrently"))
+			},
+			request: &pb.UpdateCustomerRequest{
+				Id:      itemID,
+				Name:    "New John Doe",
+				Email:   "john.new.doe@example.com",
+				Street:  "123 Maple Street",
+				City:    "Springfield",
+				Country: "USA",
+				Version: 1,
+			},
+			wantStatus: codes.Aborted,
+		},
 		{
 			name: "Fail: invalid request of id is empty",
 			request: &pb.UpdateCustomerRequest{
@@ -414,13 +616,13 @@ func TestHandler_UpdateCustomer(t *testing.T) {
 			client, cleanup := setupTestServer(t, tt.setup)
 			defer cleanup()
 
-			req, err := client.UpdateCustomer(context.Background(), tt.request)
+			resp, err := client.UpdateCustomer(context.Background(), tt.request)
 			if status.Code(err) != tt.wantStatus {
 				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
 			}
 
 			if tt.wantStatus == codes.OK {
-				if req == nil {
+				if resp.GetCustomer().GetId() != customer.ID {
 					t.Fatalf("handler returned wrong item data")
 				}
 			}
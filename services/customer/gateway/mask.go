@@ -0,0 +1,26 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+)
+
+// toUpdateFields converts the paths of an update mask into the fields they update, with paths mapping each
+// path to its field. An empty mask updates every field, and is returned as no fields.
+func toUpdateFields(mask *fieldmaskpb.FieldMask, paths map[string]string) ([]string, error) {
+	var fields []string
+	for _, path := range mask.GetPaths() {
+		field, ok := paths[path]
+		if !ok {
+			return nil, entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unknown update mask path %q", path))
+		}
+		if !slices.Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
+)
+
+func (ch *customerHandler) RestoreCustomer(ctx context.Context, req *pb.RestoreCustomerRequest) (*pb.RestoreCustomerResponse, error) {
+	id := req.GetId()
+	if id == "" {
+		log.Warn("ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "ID is required")
+	}
+
+	customer, err := ch.cuc.RestoreCustomer(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to restore customer")
+	}
+
+	return &pb.RestoreCustomerResponse{
+		Customer: convertCustomer(customer),
+	}, nil
+}
+
+func (ch *customerHandler) ListDeletedCustomers(ctx context.Context, req *pb.ListDeletedCustomersRequest) (*pb.ListDeletedCustomersResponse, error) {
+	if req.GetPageSize() < 0 {
+		log.Warn("Invalid page size", log.Fint("page_size", int(req.GetPageSize())))
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	}
+
+	customers, info, err := ch.cuc.ListDeletedCustomers(ctx, repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list deleted customers")
+	}
+
+	var res []*pb.Customer
+	for i := range customers {
+		res = append(res, convertCustomer(&customers[i]))
+	}
+
+	return &pb.ListDeletedCustomersResponse{
+		Customers:     res,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
+	}, nil
+}
@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/usecase/mock"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
+)
+
+func TestHandler_RestoreCustomer(t *testing.T) {
+	t.Parallel()
+
+	customerID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerUseCase,
+		)
+		request    *pb.RestoreCustomerRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCustomerUseCase) {
+				cuc.EXPECT().RestoreCustomer(gomock.Any(), customerID).Return(&entity.Customer{
+					ID:      customerID,
+					Name:    "John Doe",
+					Email:   "john.doe@example.com",
+					Street:  "123 Maple Street",
+					City:    "Springfield",
+					Country: "USA",
+				}, nil)
+			},
+			request:    &pb.RestoreCustomerRequest{Id: customerID},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: customer is not in the trash",
+			setup: func(cuc *mock.MockCustomerUseCase) {
+				cuc.EXPECT().RestoreCustomer(gomock.Any(), customerID).Return(nil, entity.NewError(entity.ErrNotFound, "deleted customer not found"))
+			},
+			request:    &pb.RestoreCustomerRequest{Id: customerID},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name:       "Fail: invalid request of id is empty",
+			request:    &pb.RestoreCustomerRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.RestoreCustomer(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus == codes.OK && resp.GetCustomer().GetId() != customerID {
+				t.Fatalf("handler returned wrong customer data")
+			}
+		})
+	}
+}
+
+func TestHandler_ListDeletedCustomers(t *testing.T) {
+	t.Parallel()
+
+	customerID := uuid.New().String()
+	deletedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerUseCase,
+		)
+		request    *pb.ListDeletedCustomersRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(cuc *mock.MockCustomerUseCase) {
+				cuc.EXPECT().ListDeletedCustomers(gomock.Any(), repository.Page{Size: 10}).Return([]entity.Customer{
+					{
+						ID:        customerID,
+						Name:      "John Doe",
+						Email:     "john.doe@example.com",
+						Street:    "123 Maple Street",
+						City:      "Springfield",
+						Country:   "USA",
+						DeletedAt: &deletedAt,
+					},
+				}, repository.PageInfo{}, nil)
+			},
+			request:    &pb.ListDeletedCustomersRequest{PageSize: 10},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid request of page size is negative",
+			request:    &pb.ListDeletedCustomersRequest{PageSize: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.ListDeletedCustomers(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if tt.wantStatus != codes.OK {
+				return
+			}
+			if len(resp.GetCustomers()) != 1 || !resp.GetCustomers()[0].GetDeletedAt().AsTime().Equal(deletedAt) {
+				t.Fatalf("handler returned wrong customers: %v", resp.GetCustomers())
+			}
+		})
+	}
+}
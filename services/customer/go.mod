@@ -11,6 +11,7 @@ require (
 	github.com/ory/dockertest v3.3.5+incompatible
 	github.com/sethvargo/go-envconfig v1.1.0
 	github.com/stretchr/testify v1.9.0
+	github.com/tusmasoma/go-microservice-k8s/services/idempotency v0.0.0-00010101000000-000000000000
 	github.com/tusmasoma/go-tech-dojo v0.0.0-20240805120803-02e31d5c8a21
 	go.uber.org/dig v1.18.0
 	google.golang.org/grpc v1.66.0
@@ -41,3 +42,5 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/tusmasoma/go-microservice-k8s/services/idempotency => ../idempotency
@@ -20,11 +20,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CustomerService_GetCustomer_FullMethodName    = "/customer.CustomerService/GetCustomer"
-	CustomerService_ListCustomers_FullMethodName  = "/customer.CustomerService/ListCustomers"
-	CustomerService_CreateCustomer_FullMethodName = "/customer.CustomerService/CreateCustomer"
-	CustomerService_UpdateCustomer_FullMethodName = "/customer.CustomerService/UpdateCustomer"
-	CustomerService_DeleteCustomer_FullMethodName = "/customer.CustomerService/DeleteCustomer"
+	CustomerService_GetCustomer_FullMethodName          = "/customer.CustomerService/GetCustomer"
+	CustomerService_ListCustomers_FullMethodName        = "/customer.CustomerService/ListCustomers"
+	CustomerService_ListCustomersByIDs_FullMethodName   = "/customer.CustomerService/ListCustomersByIDs"
+	CustomerService_CreateCustomer_FullMethodName       = "/customer.CustomerService/CreateCustomer"
+	CustomerService_UpdateCustomer_FullMethodName       = "/customer.CustomerService/UpdateCustomer"
+	CustomerService_DeleteCustomer_FullMethodName       = "/customer.CustomerService/DeleteCustomer"
+	CustomerService_RestoreCustomer_FullMethodName      = "/customer.CustomerService/RestoreCustomer"
+	CustomerService_ListDeletedCustomers_FullMethodName = "/customer.CustomerService/ListDeletedCustomers"
 )
 
 // CustomerServiceClient is the client API for CustomerService service.
@@ -33,9 +36,13 @@ const (
 type CustomerServiceClient interface {
 	GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*GetCustomerResponse, error)
 	ListCustomers(ctx context.Context, in *ListCustomersRequest, opts ...grpc.CallOption) (*ListCustomersResponse, error)
+	ListCustomersByIDs(ctx context.Context, in *ListCustomersByIDsRequest, opts ...grpc.CallOption) (*ListCustomersByIDsResponse, error)
 	CreateCustomer(ctx context.Context, in *CreateCustomerRequest, opts ...grpc.CallOption) (*CreateCustomerResponse, error)
 	UpdateCustomer(ctx context.Context, in *UpdateCustomerRequest, opts ...grpc.CallOption) (*UpdateCustomerResponse, error)
+	// DeleteCustomer moves a customer to the trash, from which they can be restored until they are purged.
 	DeleteCustomer(ctx context.Context, in *DeleteCustomerRequest, opts ...grpc.CallOption) (*DeleteCustomerResponse, error)
+	RestoreCustomer(ctx context.Context, in *RestoreCustomerRequest, opts ...grpc.CallOption) (*RestoreCustomerResponse, error)
+	ListDeletedCustomers(ctx context.Context, in *ListDeletedCustomersRequest, opts ...grpc.CallOption) (*ListDeletedCustomersResponse, error)
 }
 
 type customerServiceClient struct {
@@ -64,6 +71,15 @@ func (c *customerServiceClient) ListCustomers(ctx context.Context, in *ListCusto
 	return out, nil
 }
 
+func (c *customerServiceClient) ListCustomersByIDs(ctx context.Context, in *ListCustomersByIDsRequest, opts ...grpc.CallOption) (*ListCustomersByIDsResponse, error) {
+	out := new(ListCustomersByIDsResponse)
+	err := c.cc.Invoke(ctx, CustomerService_ListCustomersByIDs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *customerServiceClient) CreateCustomer(ctx context.Context, in *CreateCustomerRequest, opts ...grpc.CallOption) (*CreateCustomerResponse, error) {
 	out := new(CreateCustomerResponse)
 	err := c.cc.Invoke(ctx, CustomerService_CreateCustomer_FullMethodName, in, out, opts...)
@@ -91,15 +107,37 @@ func (c *customerServiceClient) DeleteCustomer(ctx context.Context, in *DeleteCu
 	return out, nil
 }
 
+func (c *customerServiceClient) RestoreCustomer(ctx context.Context, in *RestoreCustomerRequest, opts ...grpc.CallOption) (*RestoreCustomerResponse, error) {
+	out := new(RestoreCustomerResponse)
+	err := c.cc.Invoke(ctx, CustomerService_RestoreCustomer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *customerServiceClient) ListDeletedCustomers(ctx context.Context, in *ListDeletedCustomersRequest, opts ...grpc.CallOption) (*ListDeletedCustomersResponse, error) {
+	out := new(ListDeletedCustomersResponse)
+	err := c.cc.Invoke(ctx, CustomerService_ListDeletedCustomers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CustomerServiceServer is the server API for CustomerService service.
 // All implementations must embed UnimplementedCustomerServiceServer
 // for forward compatibility
 type CustomerServiceServer interface {
 	GetCustomer(context.Context, *GetCustomerRequest) (*GetCustomerResponse, error)
 	ListCustomers(context.Context, *ListCustomersRequest) (*ListCustomersResponse, error)
+	ListCustomersByIDs(context.Context, *ListCustomersByIDsRequest) (*ListCustomersByIDsResponse, error)
 	CreateCustomer(context.Context, *CreateCustomerRequest) (*CreateCustomerResponse, error)
 	UpdateCustomer(context.Context, *UpdateCustomerRequest) (*UpdateCustomerResponse, error)
+	// DeleteCustomer moves a customer to the trash, from which they can be restored until they are purged.
 	DeleteCustomer(context.Context, *DeleteCustomerRequest) (*DeleteCustomerResponse, error)
+	RestoreCustomer(context.Context, *RestoreCustomerRequest) (*RestoreCustomerResponse, error)
+	ListDeletedCustomers(context.Context, *ListDeletedCustomersRequest) (*ListDeletedCustomersResponse, error)
 	mustEmbedUnimplementedCustomerServiceServer()
 }
 
@@ -114,6 +152,10 @@ func (UnimplementedCustomerServiceServer) ListCustomers(context.Context, *ListCu
 	return nil, status.Errorf(codes.Unimplemented, "method ListCustomers not implemented")
 }
 
+func (UnimplementedCustomerServiceServer) ListCustomersByIDs(context.Context, *ListCustomersByIDsRequest) (*ListCustomersByIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomersByIDs not implemented")
+}
+
 func (UnimplementedCustomerServiceServer) CreateCustomer(context.Context, *CreateCustomerRequest) (*CreateCustomerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateCustomer not implemented")
 }
@@ -125,6 +167,14 @@ func (UnimplementedCustomerServiceServer) UpdateCustomer(context.Context, *Updat
 func (UnimplementedCustomerServiceServer) DeleteCustomer(context.Context, *DeleteCustomerRequest) (*DeleteCustomerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteCustomer not implemented")
 }
+
+func (UnimplementedCustomerServiceServer) RestoreCustomer(context.Context, *RestoreCustomerRequest) (*RestoreCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreCustomer not implemented")
+}
+
+func (UnimplementedCustomerServiceServer) ListDeletedCustomers(context.Context, *ListDeletedCustomersRequest) (*ListDeletedCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeletedCustomers not implemented")
+}
 func (UnimplementedCustomerServiceServer) mustEmbedUnimplementedCustomerServiceServer() {}
 
 // UnsafeCustomerServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -174,6 +224,24 @@ func _CustomerService_ListCustomers_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_ListCustomersByIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomersByIDsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).ListCustomersByIDs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_ListCustomersByIDs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).ListCustomersByIDs(ctx, req.(*ListCustomersByIDsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CustomerService_CreateCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateCustomerRequest)
 	if err := dec(in); err != nil {
@@ -228,6 +296,42 @@ func _CustomerService_DeleteCustomer_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CustomerService_RestoreCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).RestoreCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_RestoreCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).RestoreCustomer(ctx, req.(*RestoreCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CustomerService_ListDeletedCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CustomerServiceServer).ListDeletedCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CustomerService_ListDeletedCustomers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CustomerServiceServer).ListDeletedCustomers(ctx, req.(*ListDeletedCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CustomerService_ServiceDesc is the grpc.ServiceDesc for CustomerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -243,6 +347,10 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCustomers",
 			Handler:    _CustomerService_ListCustomers_Handler,
 		},
+		{
+			MethodName: "ListCustomersByIDs",
+			Handler:    _CustomerService_ListCustomersByIDs_Handler,
+		},
 		{
 			MethodName: "CreateCustomer",
 			Handler:    _CustomerService_CreateCustomer_Handler,
@@ -255,6 +363,14 @@ var CustomerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCustomer",
 			Handler:    _CustomerService_DeleteCustomer_Handler,
 		},
+		{
+			MethodName: "RestoreCustomer",
+			Handler:    _CustomerService_RestoreCustomer_Handler,
+		},
+		{
+			MethodName: "ListDeletedCustomers",
+			Handler:    _CustomerService_ListDeletedCustomers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/customer.proto",
@@ -3,14 +3,33 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
 )
 
+// ErrVersionConflict is returned by Update when the stored version of the customer no longer
+// matches the version the change was computed from.
+var ErrVersionConflict = entity.NewError(entity.ErrConflict, "customer has been changed concurrently")
+
+// CustomerRepository stores the customers. The customers in the trash are hidden from all the methods
+// but ListDeleted, ListDeletedBefore, Restore and Purge, and from Get and ListByIDs unless withDeleted is set.
 type CustomerRepository interface {
-	Get(ctx context.Context, id string) (*entity.Customer, error)
-	List(ctx context.Context) ([]entity.Customer, error)
+	Get(ctx context.Context, id string, withDeleted bool) (*entity.Customer, error)
+	List(ctx context.Context, page Page) ([]entity.Customer, PageInfo, error)
+	ListByIDs(ctx context.Context, ids []string, withDeleted bool) ([]entity.Customer, error)
 	Create(ctx context.Context, customer entity.Customer) error
-	Update(ctx context.Context, customer entity.Customer) error
-	Delete(ctx context.Context, id string) error
+	// Update stores the fields of the customer, or all of them when none is given, if its stored version is still
+	// customer.Version, and increments the stored version.
+	Update(ctx context.Context, customer entity.Customer, fields ...string) error
+	// Delete moves the customer to the trash at deletedAt, and increments their version.
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
+	// Restore takes the customer out of the trash, and increments their version.
+	Restore(ctx context.Context, id string) error
+	// ListDeleted lists the customers in the trash.
+	ListDeleted(ctx context.Context, page Page) ([]entity.Customer, PageInfo, error)
+	// ListDeletedBefore returns the IDs of at most limit customers moved to the trash before before, oldest first.
+	ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error)
+	// Purge deletes the customer for good if they were moved to the trash before before.
+	Purge(ctx context.Context, id string, before time.Time) error
 }
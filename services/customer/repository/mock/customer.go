@@ -7,9 +7,11 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 )
 
 // MockCustomerRepository is a mock of CustomerRepository interface.
@@ -50,59 +52,139 @@ func (mr *MockCustomerRepositoryMockRecorder) Create(ctx, customer interface{})
 }
 
 // Delete mocks base method.
-func (m *MockCustomerRepository) Delete(ctx context.Context, id string) error {
+func (m *MockCustomerRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockCustomerRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Delete(ctx, id, deletedAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerRepository)(nil).Delete), ctx, id, deletedAt)
 }
 
 // Get mocks base method.
-func (m *MockCustomerRepository) Get(ctx context.Context, id string) (*entity.Customer, error) {
+func (m *MockCustomerRepository) Get(ctx context.Context, id string, withDeleted bool) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret := m.ctrl.Call(m, "Get", ctx, id, withDeleted)
 	ret0, _ := ret[0].(*entity.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCustomerRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Get(ctx, id, withDeleted interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCustomerRepository)(nil).Get), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCustomerRepository)(nil).Get), ctx, id, withDeleted)
 }
 
 // List mocks base method.
-func (m *MockCustomerRepository) List(ctx context.Context) ([]entity.Customer, error) {
+func (m *MockCustomerRepository) List(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
+	ret := m.ctrl.Call(m, "List", ctx, page)
+	ret0, _ := ret[0].([]entity.Customer)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockCustomerRepositoryMockRecorder) List(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCustomerRepository)(nil).List), ctx, page)
+}
+
+// ListByIDs mocks base method.
+func (m *MockCustomerRepository) ListByIDs(ctx context.Context, ids []string, withDeleted bool) ([]entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByIDs", ctx, ids, withDeleted)
 	ret0, _ := ret[0].([]entity.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// List indicates an expected call of List.
-func (mr *MockCustomerRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+// ListByIDs indicates an expected call of ListByIDs.
+func (mr *MockCustomerRepositoryMockRecorder) ListByIDs(ctx, ids, withDeleted interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIDs", reflect.TypeOf((*MockCustomerRepository)(nil).ListByIDs), ctx, ids, withDeleted)
+}
+
+// ListDeleted mocks base method.
+func (m *MockCustomerRepository) ListDeleted(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, page)
+	ret0, _ := ret[0].([]entity.Customer)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockCustomerRepositoryMockRecorder) ListDeleted(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockCustomerRepository)(nil).ListDeleted), ctx, page)
+}
+
+// ListDeletedBefore mocks base method.
+func (m *MockCustomerRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBefore", ctx, before, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedBefore indicates an expected call of ListDeletedBefore.
+func (mr *MockCustomerRepositoryMockRecorder) ListDeletedBefore(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBefore", reflect.TypeOf((*MockCustomerRepository)(nil).ListDeletedBefore), ctx, before, limit)
+}
+
+// Purge mocks base method.
+func (m *MockCustomerRepository) Purge(ctx context.Context, id string, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, id, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockCustomerRepositoryMockRecorder) Purge(ctx, id, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockCustomerRepository)(nil).Purge), ctx, id, before)
+}
+
+// Restore mocks base method.
+func (m *MockCustomerRepository) Restore(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockCustomerRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockCustomerRepository)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockCustomerRepository)(nil).Restore), ctx, id)
 }
 
 // Update mocks base method.
-func (m *MockCustomerRepository) Update(ctx context.Context, customer entity.Customer) error {
+func (m *MockCustomerRepository) Update(ctx context.Context, customer entity.Customer, fields ...string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, customer)
+	varargs := []interface{}{ctx, customer}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Update", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, customer interface{}) *gomock.Call {
+func (mr *MockCustomerRepositoryMockRecorder) Update(ctx, customer interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), ctx, customer)
+	varargs := append([]interface{}{ctx, customer}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), varargs...)
 }
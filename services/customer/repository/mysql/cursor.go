@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
+)
+
+// cursor is the decoded form of an opaque page token.
+// Backward is set on tokens that point to the previous page.
+type cursor struct {
+	Backward bool   `json:"b,omitempty"`
+	ID       string `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(token string) (*cursor, error) {
+	if token == "" {
+		return nil, nil //nolint:nilnil // an empty token means the first page
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, repository.ErrInvalidPageToken
+	}
+	var c cursor
+	if err = json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, repository.ErrInvalidPageToken
+	}
+	return &c, nil
+}
+
+// paginate trims the extra look-ahead row fetched with LIMIT limit+1 and builds the
+// cursors of the adjacent pages. Rows of a backward page arrive in descending key order
+// and are reversed here so callers always receive ascending order.
+func paginate[T any](rows []T, limit int, c *cursor, key func(T) string) ([]T, repository.PageInfo) {
+	var info repository.PageInfo
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	if c != nil && c.Backward {
+		slices.Reverse(rows)
+		first, last = last, first
+		if hasMore {
+			info.PrevToken = encodeCursor(cursor{Backward: true, ID: first})
+		}
+		info.NextToken = encodeCursor(cursor{ID: last})
+		return rows, info
+	}
+
+	if hasMore {
+		info.NextToken = encodeCursor(cursor{ID: last})
+	}
+	if c != nil {
+		info.PrevToken = encodeCursor(cursor{Backward: true, ID: first})
+	}
+	return rows, info
+}
@@ -3,6 +3,10 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
@@ -18,21 +22,22 @@ func NewCustomerRepository(db *sql.DB) repository.CustomerRepository {
 	}
 }
 
-func (cr *customerRepository) Get(ctx context.Context, id string) (*entity.Customer, error) {
+func (cr *customerRepository) Get(ctx context.Context, id string, withDeleted bool) (*entity.Customer, error) {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
 	}
 
 	query := `
-	SELECT id, name, email, street, city, country
+	SELECT id, name, email, street, city, country, version, deleted_at
 	FROM Customers
-	WHERE id = ?
+	WHERE id = ? AND (? OR deleted_at IS NULL)
 	LIMIT 1
 	`
 
-	row := executor.QueryRowContext(ctx, query, id)
+	row := executor.QueryRowContext(ctx, query, id, withDeleted)
 	var customer entity.Customer
+	var deletedAt sql.NullTime
 	if err := row.Scan(
 		&customer.ID,
 		&customer.Name,
@@ -40,24 +45,99 @@ func (cr *customerRepository) Get(ctx context.Context, id string) (*entity.Custo
 		&customer.Street,
 		&customer.City,
 		&customer.Country,
+		&customer.Version,
+		&deletedAt,
 	); err != nil {
-		return nil, err
+		return nil, translateError(err, "customer not found")
+	}
+	if deletedAt.Valid {
+		customer.DeletedAt = &deletedAt.Time
 	}
 	return &customer, nil
 }
 
-func (cr *customerRepository) List(ctx context.Context) ([]entity.Customer, error) {
+func (cr *customerRepository) List(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
 	}
 
+	c, err := decodeCursor(page.Token)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	limit := page.Limit()
+
+	query := `
+	SELECT id, name, email, street, city, country, version
+	FROM Customers
+	WHERE deleted_at IS NULL
+	`
+	args := make([]interface{}, 0, 2) //nolint:gomnd // cursor and limit
+	switch {
+	case c == nil:
+		query += `ORDER BY id ASC`
+	case c.Backward:
+		query += `AND id < ? ORDER BY id DESC`
+		args = append(args, c.ID)
+	default:
+		query += `AND id > ? ORDER BY id ASC`
+		args = append(args, c.ID)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var customers []entity.Customer
+	for rows.Next() {
+		var customer entity.Customer
+		if err = rows.Scan(
+			&customer.ID,
+			&customer.Name,
+			&customer.Email,
+			&customer.Street,
+			&customer.City,
+			&customer.Country,
+			&customer.Version,
+		); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		customers = append(customers, customer)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	customers, info := paginate(customers, limit, c, func(customer entity.Customer) string { return customer.ID })
+	return customers, info, nil
+}
+
+func (cr *customerRepository) ListByIDs(ctx context.Context, ids []string, withDeleted bool) ([]entity.Customer, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, 0, len(ids)+1)
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	args = append(args, withDeleted)
+
 	query := `
-	SELECT id, name, email, street, city, country
+	SELECT id, name, email, street, city, country, version, deleted_at
 	FROM Customers
+	WHERE id IN (` + strings.Join(placeholders, ",") + `) AND (? OR deleted_at IS NULL)
 	`
 
-	rows, err := executor.QueryContext(ctx, query)
+	rows, err := executor.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +146,7 @@ func (cr *customerRepository) List(ctx context.Context) ([]entity.Customer, erro
 	var customers []entity.Customer
 	for rows.Next() {
 		var customer entity.Customer
+		var deletedAt sql.NullTime
 		if err = rows.Scan(
 			&customer.ID,
 			&customer.Name,
@@ -73,9 +154,14 @@ func (cr *customerRepository) List(ctx context.Context) ([]entity.Customer, erro
 			&customer.Street,
 			&customer.City,
 			&customer.Country,
+			&customer.Version,
+			&deletedAt,
 		); err != nil {
 			return nil, err
 		}
+		if deletedAt.Valid {
+			customer.DeletedAt = &deletedAt.Time
+		}
 		customers = append(customers, customer)
 	}
 	if err = rows.Err(); err != nil {
@@ -93,9 +179,9 @@ func (cr *customerRepository) Create(ctx context.Context, customer entity.Custom
 
 	query := `
 	INSERT INTO Customers (
-	id, name, email, street, city, country
+	id, name, email, street, city, country, version
 	)
-	VALUES (?, ?, ?, ?, ?, ?)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
 	if _, err := executor.ExecContext(
@@ -107,40 +193,211 @@ func (cr *customerRepository) Create(ctx context.Context, customer entity.Custom
 		customer.Street,
 		customer.City,
 		customer.Country,
+		customer.Version,
 	); err != nil {
-		return err
+		return translateError(err, "customer not found")
 	}
 	return nil
 }
 
-func (cr *customerRepository) Update(ctx context.Context, customer entity.Customer) error {
+// customerColumn is the column a field of a customer is stored in.
+type customerColumn struct {
+	field string
+	value func(customer entity.Customer) interface{}
+}
+
+var customerColumns = []customerColumn{
+	{entity.CustomerFieldName, func(customer entity.Customer) interface{} { return customer.Name }},
+	{entity.CustomerFieldEmail, func(customer entity.Customer) interface{} { return customer.Email }},
+	{entity.CustomerFieldStreet, func(customer entity.Customer) interface{} { return customer.Street }},
+	{entity.CustomerFieldCity, func(customer entity.Customer) interface{} { return customer.City }},
+	{entity.CustomerFieldCountry, func(customer entity.Customer) interface{} { return customer.Country }},
+}
+
+func (cr *customerRepository) Update(ctx context.Context, customer entity.Customer, fields ...string) error {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
 	}
 
+	updated := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !slices.ContainsFunc(customerColumns, func(c customerColumn) bool { return c.field == field }) {
+			return entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unknown customer field %q", field))
+		}
+		updated[field] = true
+	}
+	var sets []string
+	var args []interface{}
+	for _, column := range customerColumns {
+		if len(fields) > 0 && !updated[column.field] {
+			continue
+		}
+		sets = append(sets, column.field+" = ?")
+		args = append(args, column.value(customer))
+	}
+
+	// The version is part of the condition so that a concurrent change made after
+	// the customer was read is not silently overwritten.
 	query := `
 	UPDATE Customers
-	SET name = ?, email = ?, street = ?, city = ?, country = ?
-	WHERE id = ?
+	SET ` + strings.Join(sets, ", ") + `, version = version + 1
+	WHERE id = ? AND version = ? AND deleted_at IS NULL
 	`
+	args = append(args, customer.ID, customer.Version)
 
-	if _, err := executor.ExecContext(
-		ctx,
-		query,
-		customer.Name,
-		customer.Email,
-		customer.Street,
-		customer.City,
-		customer.Country,
-		customer.ID,
-	); err != nil {
+	res, err := executor.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
 		return err
 	}
+	if affected == 0 {
+		return repository.ErrVersionConflict
+	}
 	return nil
 }
 
-func (cr *customerRepository) Delete(ctx context.Context, id string) error {
+func (cr *customerRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE Customers
+	SET deleted_at = ?, version = version + 1
+	WHERE id = ? AND deleted_at IS NULL
+	`
+
+	res, err := executor.ExecContext(ctx, query, deletedAt, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "customer not found")
+}
+
+func (cr *customerRepository) Restore(ctx context.Context, id string) error {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	UPDATE Customers
+	SET deleted_at = NULL, version = version + 1
+	WHERE id = ? AND deleted_at IS NOT NULL
+	`
+
+	res, err := executor.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res, "deleted customer not found")
+}
+
+func (cr *customerRepository) ListDeleted(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	c, err := decodeCursor(page.Token)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	limit := page.Limit()
+
+	query := `
+	SELECT id, name, email, street, city, country, version, deleted_at
+	FROM Customers
+	WHERE deleted_at IS NOT NULL
+	`
+	args := make([]interface{}, 0, 2) //nolint:gomnd // cursor and limit
+	switch {
+	case c == nil:
+		query += `ORDER BY id ASC`
+	case c.Backward:
+		query += `AND id < ? ORDER BY id DESC`
+		args = append(args, c.ID)
+	default:
+		query += `AND id > ? ORDER BY id ASC`
+		args = append(args, c.ID)
+	}
+	query += ` LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	var customers []entity.Customer
+	for rows.Next() {
+		var customer entity.Customer
+		var deletedAt time.Time
+		if err = rows.Scan(
+			&customer.ID,
+			&customer.Name,
+			&customer.Email,
+			&customer.Street,
+			&customer.City,
+			&customer.Country,
+			&customer.Version,
+			&deletedAt,
+		); err != nil {
+			return nil, repository.PageInfo{}, err
+		}
+		customer.DeletedAt = &deletedAt
+		customers = append(customers, customer)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	customers, info := paginate(customers, limit, c, func(customer entity.Customer) string { return customer.ID })
+	return customers, info, nil
+}
+
+func (cr *customerRepository) ListDeletedBefore(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	executor := cr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT id
+	FROM Customers
+	WHERE deleted_at < ?
+	ORDER BY deleted_at ASC, id ASC
+	LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (cr *customerRepository) Purge(ctx context.Context, id string, before time.Time) error {
 	executor := cr.db
 	if tx := TxFromCtx(ctx); tx != nil {
 		executor = tx
@@ -148,11 +405,12 @@ func (cr *customerRepository) Delete(ctx context.Context, id string) error {
 
 	query := `
 	DELETE FROM Customers
-	WHERE id = ?
+	WHERE id = ? AND deleted_at < ?
 	`
 
-	if _, err := executor.ExecContext(ctx, query, id); err != nil {
+	res, err := executor.ExecContext(ctx, query, id, before)
+	if err != nil {
 		return err
 	}
-	return nil
+	return expectAffected(res, "deleted customer not found")
 }
@@ -2,10 +2,13 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 )
 
 func Test_CustomerRepository(t *testing.T) {
@@ -38,14 +41,41 @@ func Test_CustomerRepository(t *testing.T) {
 	ValidateErr(t, err, nil)
 
 	// Get
-	gotCustomer, err := repo.Get(ctx, customer1.ID)
+	gotCustomer, err := repo.Get(ctx, customer1.ID, false)
 	ValidateErr(t, err, nil)
 	if !reflect.DeepEqual(gotCustomer, customer1) {
 		t.Errorf("expected: %v, got: %v", customer1, gotCustomer)
 	}
 
 	// List
-	gotCustomers, err := repo.List(ctx)
+	gotCustomers, _, err := repo.List(ctx, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotCustomers) != 2 {
+		t.Errorf("expected: 2, got: %d", len(gotCustomers))
+	}
+
+	// List with pagination
+	firstPage, firstInfo, err := repo.List(ctx, repository.Page{Size: 1})
+	ValidateErr(t, err, nil)
+	if len(firstPage) != 1 || firstInfo.NextToken == "" || firstInfo.PrevToken != "" {
+		t.Errorf("unexpected first page: %v, %+v", firstPage, firstInfo)
+	}
+	secondPage, secondInfo, err := repo.List(ctx, repository.Page{Size: 1, Token: firstInfo.NextToken})
+	ValidateErr(t, err, nil)
+	if len(secondPage) != 1 || secondInfo.NextToken != "" || secondInfo.PrevToken == "" {
+		t.Errorf("unexpected second page: %v, %+v", secondPage, secondInfo)
+	}
+	prevPage, _, err := repo.List(ctx, repository.Page{Size: 1, Token: secondInfo.PrevToken})
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(prevPage, firstPage) {
+		t.Errorf("expected: %v, got: %v", firstPage, prevPage)
+	}
+
+	_, _, err = repo.List(ctx, repository.Page{Token: "invalid"})
+	ValidateErr(t, err, repository.ErrInvalidPageToken)
+
+	// ListByIDs
+	gotCustomers, err = repo.ListByIDs(ctx, []string{customer1.ID, customer2.ID}, false)
 	ValidateErr(t, err, nil)
 	if len(gotCustomers) != 2 {
 		t.Errorf("expected: 2, got: %d", len(gotCustomers))
@@ -55,17 +85,123 @@ func Test_CustomerRepository(t *testing.T) {
 	customer1.Name = "John Smith"
 	err = repo.Update(ctx, *customer1)
 	ValidateErr(t, err, nil)
-	gotCustomer, err = repo.Get(ctx, customer1.ID)
+	customer1.Version++
+	gotCustomer, err = repo.Get(ctx, customer1.ID, false)
 	ValidateErr(t, err, nil)
 	if !reflect.DeepEqual(gotCustomer, customer1) {
 		t.Errorf("expected: %v, got: %v", customer1, gotCustomer)
 	}
 
+	// Update of only some fields
+	partial := *customer1
+	partial.City = "Shelbyville"
+	partial.Country = "Canada"
+	err = repo.Update(ctx, partial, entity.CustomerFieldCity)
+	ValidateErr(t, err, nil)
+	customer1.City = partial.City
+	customer1.Version++
+	gotCustomer, err = repo.Get(ctx, customer1.ID, false)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(gotCustomer, customer1) {
+		t.Errorf("expected: %v, got: %v", customer1, gotCustomer)
+	}
+
+	if err = repo.Update(ctx, *customer1, "phone"); !errors.Is(err, entity.ErrInvalidArgument) {
+		t.Errorf("expected: %v, got: %v", entity.ErrInvalidArgument, err)
+	}
+
+	// Update from a stale version
+	stale := *customer1
+	stale.Version--
+	stale.Name = "stale"
+	err = repo.Update(ctx, stale)
+	ValidateErr(t, err, repository.ErrVersionConflict)
+
 	// Delete
-	err = repo.Delete(ctx, customer1.ID)
+	deletedAt := time.Now().Truncate(time.Second)
+	err = repo.Delete(ctx, customer1.ID, deletedAt)
+	ValidateErr(t, err, nil)
+	customer1.Version++
+
+	_, err = repo.Get(ctx, customer1.ID, false)
+	if err == nil {
+		t.Errorf("expected: error, got: nil")
+	}
+	gotCustomers, _, err = repo.List(ctx, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotCustomers) != 1 || gotCustomers[0].ID != customer2.ID {
+		t.Errorf("expected: %v, got: %v", []entity.Customer{*customer2}, gotCustomers)
+	}
+	gotCustomers, err = repo.ListByIDs(ctx, []string{customer1.ID, customer2.ID}, false)
+	ValidateErr(t, err, nil)
+	if len(gotCustomers) != 1 {
+		t.Errorf("expected: 1, got: %d", len(gotCustomers))
+	}
+
+	err = repo.Delete(ctx, customer1.ID, deletedAt)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// Get and ListByIDs with the deleted customers
+	gotCustomer, err = repo.Get(ctx, customer1.ID, true)
+	ValidateErr(t, err, nil)
+	if gotCustomer.DeletedAt == nil || !gotCustomer.DeletedAt.Equal(deletedAt) {
+		t.Errorf("expected: deleted at %v, got: %v", deletedAt, gotCustomer.DeletedAt)
+	}
+	gotCustomers, err = repo.ListByIDs(ctx, []string{customer1.ID, customer2.ID}, true)
+	ValidateErr(t, err, nil)
+	if len(gotCustomers) != 2 {
+		t.Errorf("expected: 2, got: %d", len(gotCustomers))
+	}
+
+	// ListDeleted
+	gotCustomers, _, err = repo.ListDeleted(ctx, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotCustomers) != 1 || gotCustomers[0].ID != customer1.ID || gotCustomers[0].DeletedAt == nil {
+		t.Errorf("expected: %v, got: %v", customer1.ID, gotCustomers)
+	}
+
+	// Restore
+	err = repo.Restore(ctx, customer1.ID)
+	ValidateErr(t, err, nil)
+	customer1.Version++
+
+	gotCustomer, err = repo.Get(ctx, customer1.ID, false)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(gotCustomer, customer1) {
+		t.Errorf("expected: %v, got: %v", customer1, gotCustomer)
+	}
+
+	err = repo.Restore(ctx, customer1.ID)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	// Purge
+	err = repo.Delete(ctx, customer1.ID, deletedAt)
+	ValidateErr(t, err, nil)
+
+	ids, err := repo.ListDeletedBefore(ctx, deletedAt, 10)
+	ValidateErr(t, err, nil)
+	if len(ids) != 0 {
+		t.Errorf("expected: none, got: %v", ids)
+	}
+	err = repo.Purge(ctx, customer1.ID, deletedAt)
+	if !errors.Is(err, entity.ErrNotFound) {
+		t.Errorf("expected: %v, got: %v", entity.ErrNotFound, err)
+	}
+
+	before := deletedAt.Add(time.Second)
+	ids, err = repo.ListDeletedBefore(ctx, before, 10)
+	ValidateErr(t, err, nil)
+	if !reflect.DeepEqual(ids, []string{customer1.ID}) {
+		t.Errorf("expected: %v, got: %v", []string{customer1.ID}, ids)
+	}
+	err = repo.Purge(ctx, customer1.ID, before)
 	ValidateErr(t, err, nil)
 
-	_, err = repo.Get(ctx, customer1.ID)
+	_, err = repo.Get(ctx, customer1.ID, true)
 	if err == nil {
 		t.Errorf("expected: error, got: nil")
 	}
@@ -0,0 +1,49 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+
+	driver "github.com/go-sql-driver/mysql"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number reported for a duplicate key.
+const mysqlErrDuplicateEntry = 1062
+
+// translateError maps driver errors onto domain error kinds.
+// notFoundMsg is used as the message when the query matched no row.
+func translateError(err error, notFoundMsg string) error {
+	var mysqlErr *driver.MySQLError
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return entity.WrapError(entity.ErrNotFound, notFoundMsg, err)
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry:
+		return entity.WrapError(entity.ErrAlreadyExists, mysqlErr.Message, err)
+	default:
+		return err
+	}
+}
+
+// translateInsertError maps a duplicate key on an insert onto ErrAlreadyExists.
+// existsMsg is used as the message when a row with the same key is already stored.
+func translateInsertError(err error, existsMsg string) error {
+	var mysqlErr *driver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+		return entity.WrapError(entity.ErrAlreadyExists, existsMsg, err)
+	}
+	return err
+}
+
+// expectAffected returns a not found error with notFoundMsg when the statement of res changed no row.
+func expectAffected(res sql.Result, notFoundMsg string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return translateError(sql.ErrNoRows, notFoundMsg)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ CREATE DATABASE IF NOT EXISTS `microservice-k8s-demo-test-db` DEFAULT CHARACTER
 USE `microservice-k8s-demo-test-db`;
 
 DROP TABLE IF EXISTS Customers;
+DROP TABLE IF EXISTS CustomerIdempotencyKeys;
+DROP TABLE IF EXISTS CustomerOutboxEvents;
 
 -- Customers Table
 CREATE TABLE Customers (
@@ -10,5 +12,39 @@ CREATE TABLE Customers (
     email VARCHAR(255) NOT NULL,
     street VARCHAR(255) NOT NULL,
     city VARCHAR(255) NOT NULL,
-    country VARCHAR(255) NOT NULL
-);
\ No newline at end of file
+    country VARCHAR(255) NOT NULL,
+    -- version is incremented by every update, which is made only if it is unchanged since the customer was read.
+    version BIGINT NOT NULL DEFAULT 1,
+    -- deleted_at is set while the customer is in the trash, from which it is purged once it has been there long enough.
+    deleted_at TIMESTAMP NULL,
+    INDEX idx_customers_deleted_at (deleted_at)
+);
+
+-- CustomerIdempotencyKeys Table
+CREATE TABLE CustomerIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_customer_idempotency_keys_expires_at (expires_at)
+);
+
+-- CustomerOutboxEvents Table
+CREATE TABLE CustomerOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    -- The relay publishing the event, until claimed_until.
+    claim_id CHAR(36) NULL,
+    claimed_until TIMESTAMP NULL,
+    INDEX idx_customer_outbox_events_published_at (published_at, sequence),
+    INDEX idx_customer_outbox_events_claim_id (claim_id)
+);
@@ -0,0 +1,27 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+)
+
+// OutboxRepository stores the events to be published. Events added inside
+// TransactionRepository.Transaction are stored only if the transaction commits.
+type OutboxRepository interface {
+	Add(ctx context.Context, events ...entity.Event) error
+	// ClaimUnpublished claims up to limit unpublished events for claimID until until, and returns the
+	// unpublished events of claimID in the order they were added. The events of other claims are
+	// skipped until their claim expires at now or is released.
+	ClaimUnpublished(ctx context.Context, claimID string, limit int, now, until time.Time) ([]entity.Event, error)
+	// ReleaseClaim releases the unpublished events of claimID, so that they can be claimed again.
+	ReleaseClaim(ctx context.Context, claimID string) error
+	MarkPublished(ctx context.Context, id string, now time.Time) error
+}
+
+// EventPublisher delivers events to their consumers.
+type EventPublisher interface {
+	Publish(ctx context.Context, event entity.Event) error
+}
@@ -0,0 +1,29 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+)
+
+// LogPublisher writes each event as a line of JSON.
+type LogPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func NewLogPublisher(w io.Writer) *LogPublisher {
+	return &LogPublisher{
+		enc: json.NewEncoder(w),
+	}
+}
+
+func (lp *LogPublisher) Publish(_ context.Context, event entity.Event) error {
+	lp.mu.Lock()
+	defer lp.mu.Unlock()
+
+	return lp.enc.Encode(event)
+}
@@ -3,6 +3,9 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 
@@ -11,25 +14,42 @@ import (
 )
 
 type CustomerUseCase interface {
-	GetCustomer(ctx context.Context, id string) (*entity.Customer, error)
-	ListCustomers(ctx context.Context) ([]entity.Customer, error)
-	CreateCustomer(ctx context.Context, params *CreateCustomerParams) error
-	UpdateCustomer(ctx context.Context, params *UpdateCustomerParams) error
+	// GetCustomer gets a customer, even one in the trash if showDeleted is set.
+	GetCustomer(ctx context.Context, id string, showDeleted bool) (*entity.Customer, error)
+	ListCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error)
+	// ListCustomersByIDs lists the customers of ids, including those in the trash if showDeleted is set.
+	ListCustomersByIDs(ctx context.Context, ids []string, showDeleted bool) ([]entity.Customer, error)
+	CreateCustomer(ctx context.Context, params *CreateCustomerParams) (*entity.Customer, error)
+	UpdateCustomer(ctx context.Context, params *UpdateCustomerParams) (*entity.Customer, error)
+	// DeleteCustomer moves a customer to the trash, from which they can be restored until they are purged.
 	DeleteCustomer(ctx context.Context, id string) error
+	RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error)
+	ListDeletedCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error)
+	// PurgeCustomers deletes for good at most limit customers moved to the trash before before,
+	// and returns how many were purged.
+	PurgeCustomers(ctx context.Context, before time.Time, limit int) (int, error)
 }
 
 type customerUseCase struct {
-	cr repository.CustomerRepository
+	cr  repository.CustomerRepository
+	tr  repository.TransactionRepository
+	obr repository.OutboxRepository
 }
 
-func NewCustomerUsecase(cr repository.CustomerRepository) CustomerUseCase {
+func NewCustomerUsecase(
+	cr repository.CustomerRepository,
+	tr repository.TransactionRepository,
+	obr repository.OutboxRepository,
+) CustomerUseCase {
 	return &customerUseCase{
-		cr: cr,
+		cr:  cr,
+		tr:  tr,
+		obr: obr,
 	}
 }
 
-func (cuc *customerUseCase) GetCustomer(ctx context.Context, id string) (*entity.Customer, error) {
-	customer, err := cuc.cr.Get(ctx, id)
+func (cuc *customerUseCase) GetCustomer(ctx context.Context, id string, showDeleted bool) (*entity.Customer, error) {
+	customer, err := cuc.cr.Get(ctx, id, showDeleted)
 	if err != nil {
 		log.Error("failed to get customer", log.Ferror(err))
 		return nil, err
@@ -37,10 +57,19 @@ func (cuc *customerUseCase) GetCustomer(ctx context.Context, id string) (*entity
 	return customer, nil
 }
 
-func (cuc *customerUseCase) ListCustomers(ctx context.Context) ([]entity.Customer, error) {
-	customers, err := cuc.cr.List(ctx)
+func (cuc *customerUseCase) ListCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	customers, info, err := cuc.cr.List(ctx, page)
 	if err != nil {
 		log.Error("failed to list customers", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+	return customers, info, nil
+}
+
+func (cuc *customerUseCase) ListCustomersByIDs(ctx context.Context, ids []string, showDeleted bool) ([]entity.Customer, error) {
+	customers, err := cuc.cr.ListByIDs(ctx, ids, showDeleted)
+	if err != nil {
+		log.Error("failed to list customers by ids", log.Ferror(err))
 		return nil, err
 	}
 	return customers, nil
@@ -54,7 +83,7 @@ type CreateCustomerParams struct {
 	Country string
 }
 
-func (cuc *customerUseCase) CreateCustomer(ctx context.Context, params *CreateCustomerParams) error {
+func (cuc *customerUseCase) CreateCustomer(ctx context.Context, params *CreateCustomerParams) (*entity.Customer, error) {
 	customer, err := entity.NewCustomer(
 		"",
 		params.Name,
@@ -65,15 +94,23 @@ func (cuc *customerUseCase) CreateCustomer(ctx context.Context, params *CreateCu
 	)
 	if err != nil {
 		log.Error("failed to create customer", log.Ferror(err))
-		return err
+		return nil, err
 	}
-	if err = cuc.cr.Create(ctx, *customer); err != nil {
+	if err = cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cuc.cr.Create(ctx, *customer); err != nil {
+			return err
+		}
+		return cuc.addEvent(ctx, entity.EventTypeCustomerCreated, customer.ID, customer)
+	}); err != nil {
 		log.Error("failed to create customer", log.Ferror(err))
-		return err
+		return nil, err
 	}
-	return nil
+	return customer, nil
 }
 
+// UpdateCustomerParams are the new values of a customer. Fields are the fields to update, all of them when it
+// is empty, and the others are left as they are. Version is the version of the customer the values were made
+// from; the update is rejected if the customer has been updated since, unless it is 0.
 type UpdateCustomerParams struct {
 	ID      string
 	Name    string
@@ -81,32 +118,102 @@ type UpdateCustomerParams struct {
 	Street  string
 	City    string
 	Country string
+	Version int64
+	Fields  []string
+}
+
+// customerFields are the fields of a customer an update can be limited to.
+var customerFields = []string{
+	entity.CustomerFieldName,
+	entity.CustomerFieldEmail,
+	entity.CustomerFieldStreet,
+	entity.CustomerFieldCity,
+	entity.CustomerFieldCountry,
 }
 
-func (cuc *customerUseCase) UpdateCustomer(ctx context.Context, params *UpdateCustomerParams) error {
-	customer, err := cuc.cr.Get(ctx, params.ID)
+func (cuc *customerUseCase) UpdateCustomer(ctx context.Context, params *UpdateCustomerParams) (*entity.Customer, error) {
+	masked := make(map[string]bool, len(customerFields))
+	for _, field := range params.Fields {
+		if !slices.Contains(customerFields, field) {
+			log.Warn("unknown update field", log.Fstring("field", field))
+			return nil, entity.NewError(entity.ErrInvalidArgument, fmt.Sprintf("unknown field %q", field))
+		}
+		masked[field] = true
+	}
+	if len(params.Fields) == 0 {
+		for _, field := range customerFields {
+			masked[field] = true
+		}
+	}
+
+	customer, err := cuc.cr.Get(ctx, params.ID, false)
 	if err != nil {
 		log.Error("failed to get customer", log.Ferror(err))
-		return err
+		return nil, err
+	}
+	if params.Version != 0 && customer.Version != params.Version {
+		log.Warn("stale customer version", log.Fstring("id", params.ID), log.Fint("version", int(params.Version)))
+		return nil, repository.ErrVersionConflict
 	}
 
-	customer.Name = params.Name
-	customer.Email = params.Email
-	customer.Street = params.Street
-	customer.City = params.City
-	customer.Country = params.Country
+	// Only the fields whose values change are written.
+	var changed []string
+	for _, f := range []struct {
+		field  string
+		value  string
+		stored *string
+	}{
+		{entity.CustomerFieldName, params.Name, &customer.Name},
+		{entity.CustomerFieldEmail, params.Email, &customer.Email},
+		{entity.CustomerFieldStreet, params.Street, &customer.Street},
+		{entity.CustomerFieldCity, params.City, &customer.City},
+		{entity.CustomerFieldCountry, params.Country, &customer.Country},
+	} {
+		if !masked[f.field] || f.value == *f.stored {
+			continue
+		}
+		if f.value == "" {
+			log.Warn("required field is empty", log.Fstring("field", f.field))
+			return nil, entity.NewError(entity.ErrInvalidArgument, f.field+" is required")
+		}
+		*f.stored = f.value
+		changed = append(changed, f.field)
+	}
+	if len(changed) == 0 {
+		return customer, nil
+	}
 
-	if err = cuc.cr.Update(ctx, *customer); err != nil {
+	if err = cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cuc.cr.Update(ctx, *customer, changed...); err != nil {
+			return err
+		}
+		customer.Version++
+		return cuc.addEvent(ctx, entity.EventTypeCustomerUpdated, customer.ID, customer)
+	}); err != nil {
 		log.Error("failed to update customer", log.Ferror(err))
-		return err
+		return nil, err
 	}
-	return nil
+	return customer, nil
 }
 
 func (cuc *customerUseCase) DeleteCustomer(ctx context.Context, id string) error {
-	if err := cuc.cr.Delete(ctx, id); err != nil {
+	if err := cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cuc.cr.Delete(ctx, id, time.Now()); err != nil {
+			return err
+		}
+		return cuc.addEvent(ctx, entity.EventTypeCustomerDeleted, id, entity.Deletion{ID: id})
+	}); err != nil {
 		log.Error("failed to delete customer", log.Ferror(err))
 		return err
 	}
 	return nil
 }
+
+// addEvent stores an event in the outbox, within the transaction of ctx.
+func (cuc *customerUseCase) addEvent(ctx context.Context, eventType entity.EventType, aggregateID string, payload interface{}) error {
+	event, err := entity.NewEvent(eventType, aggregateID, payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return cuc.obr.Add(ctx, *event)
+}
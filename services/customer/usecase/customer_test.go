@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository/mock"
 )
 
@@ -44,7 +45,7 @@ func TestUseCase_GetCustomer(t *testing.T) {
 		{
 			name: "success",
 			setup: func(cr *mock.MockCustomerRepository) {
-				cr.EXPECT().Get(gomock.Any(), customerID).Return(customer, nil)
+				cr.EXPECT().Get(gomock.Any(), customerID, false).Return(customer, nil)
 			},
 			arg: struct {
 				ctx context.Context
@@ -75,9 +76,9 @@ func TestUseCase_GetCustomer(t *testing.T) {
 				tt.setup(cr)
 			}
 
-			cuc := NewCustomerUsecase(cr)
+			cuc := NewCustomerUsecase(cr, nil, nil)
 
-			getCustomer, err := cuc.GetCustomer(tt.arg.ctx, tt.arg.id)
+			getCustomer, err := cuc.GetCustomer(tt.arg.ctx, tt.arg.id, false)
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("GetCustomer() error = %v, wantErr %v", err, tt.want.err)
@@ -112,31 +113,58 @@ func TestUseCase_ListCustomers(t *testing.T) {
 			m *mock.MockCustomerRepository,
 		)
 		arg struct {
-			ctx context.Context
+			ctx  context.Context
+			page repository.Page
 		}
 		want struct {
 			customers []entity.Customer
+			info      repository.PageInfo
 			err       error
 		}
 	}{
 		{
 			name: "success",
 			setup: func(cr *mock.MockCustomerRepository) {
-				cr.EXPECT().List(gomock.Any()).Return(customers, nil)
+				cr.EXPECT().List(gomock.Any(), repository.Page{Size: 1}).Return(customers, repository.PageInfo{NextToken: "next"}, nil)
 			},
 			arg: struct {
-				ctx context.Context
+				ctx  context.Context
+				page repository.Page
 			}{
-				ctx: context.Background(),
+				ctx:  context.Background(),
+				page: repository.Page{Size: 1},
 			},
 			want: struct {
 				customers []entity.Customer
+				info      repository.PageInfo
 				err       error
 			}{
 				customers: customers,
+				info:      repository.PageInfo{NextToken: "next"},
 				err:       nil,
 			},
 		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(cr *mock.MockCustomerRepository) {
+				cr.EXPECT().List(gomock.Any(), repository.Page{Token: "invalid"}).Return(nil, repository.PageInfo{}, repository.ErrInvalidPageToken)
+			},
+			arg: struct {
+				ctx  context.Context
+				page repository.Page
+			}{
+				ctx:  context.Background(),
+				page: repository.Page{Token: "invalid"},
+			},
+			want: struct {
+				customers []entity.Customer
+				info      repository.PageInfo
+				err       error
+			}{
+				customers: nil,
+				err:       repository.ErrInvalidPageToken,
+			},
+		},
 	}
 
 	for _, tt := range patterns {
@@ -151,9 +179,9 @@ func TestUseCase_ListCustomers(t *testing.T) {
 				tt.setup(cr)
 			}
 
-			cuc := NewCustomerUsecase(cr)
+			cuc := NewCustomerUsecase(cr, nil, nil)
 
-			getCustomers, err := cuc.ListCustomers(tt.arg.ctx)
+			getCustomers, getInfo, err := cuc.ListCustomers(tt.arg.ctx, tt.arg.page)
 
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("ListCustomers() error = %v, wantErr %v", err, tt.want.err)
@@ -164,6 +192,97 @@ func TestUseCase_ListCustomers(t *testing.T) {
 			if !reflect.DeepEqual(getCustomers, tt.want.customers) {
 				t.Errorf("ListCustomers() got = %v, want %v", getCustomers, tt.want.customers)
 			}
+			if getInfo != tt.want.info {
+				t.Errorf("ListCustomers() got = %v, want %v", getInfo, tt.want.info)
+			}
+		})
+	}
+}
+
+func TestUseCase_ListCustomersByIDs(t *testing.T) {
+	t.Parallel()
+
+	customer1 := entity.Customer{
+		ID:      uuid.New().String(),
+		Name:    "John Doe",
+		Email:   "john.doe@example.com",
+		Street:  "123 Maple Street",
+		City:    "Springfield",
+		Country: "USA",
+	}
+	customer2 := entity.Customer{
+		ID:      uuid.New().String(),
+		Name:    "Jane Doe",
+		Email:   "jane.doe@example.com",
+		Street:  "456 Oak Street",
+		City:    "Springfield",
+		Country: "USA",
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerRepository,
+		)
+		arg struct {
+			ctx context.Context
+			ids []string
+		}
+		want struct {
+			customers []entity.Customer
+			err       error
+		}
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCustomerRepository) {
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{customer1.ID, customer2.ID}, false).Return(
+					[]entity.Customer{customer1, customer2},
+					nil,
+				)
+			},
+			arg: struct {
+				ctx context.Context
+				ids []string
+			}{
+				ctx: context.Background(),
+				ids: []string{customer1.ID, customer2.ID},
+			},
+			want: struct {
+				customers []entity.Customer
+				err       error
+			}{
+				customers: []entity.Customer{customer1, customer2},
+				err:       nil,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCustomerRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr)
+			}
+
+			cuc := NewCustomerUsecase(cr, nil, nil)
+
+			getCustomers, err := cuc.ListCustomersByIDs(tt.arg.ctx, tt.arg.ids, false)
+
+			if (err != nil) != (tt.want.err != nil) {
+				t.Errorf("ListCustomersByIDs() error = %v, wantErr %v", err, tt.want.err)
+			} else if err != nil && tt.want.err != nil && err.Error() != tt.want.err.Error() {
+				t.Errorf("ListCustomersByIDs() error = %v, wantErr %v", err, tt.want.err)
+			}
+
+			if !reflect.DeepEqual(getCustomers, tt.want.customers) {
+				t.Errorf("ListCustomersByIDs() got = %v, want %v", getCustomers, tt.want.customers)
+			}
 		})
 	}
 }
@@ -175,6 +294,8 @@ func TestUseCase_CreateCustomer(t *testing.T) { //nolint: gocognit // This is a
 		name  string
 		setup func(
 			m *mock.MockCustomerRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
 		)
 		arg struct {
 			ctx    context.Context
@@ -184,7 +305,10 @@ func TestUseCase_CreateCustomer(t *testing.T) { //nolint: gocognit // This is a
 	}{
 		{
 			name: "success",
-			setup: func(cr *mock.MockCustomerRepository) {
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
 				cr.EXPECT().Create(
 					gomock.Any(),
 					gomock.Any(),
@@ -205,6 +329,11 @@ func TestUseCase_CreateCustomer(t *testing.T) { //nolint: gocognit // This is a
 						t.Errorf("unexpected Country: got %v, want %v", customer.Country, "USA")
 					}
 				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCustomerCreated {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
 			},
 			arg: struct {
 				ctx    context.Context
@@ -230,18 +359,23 @@ func TestUseCase_CreateCustomer(t *testing.T) { //nolint: gocognit // This is a
 
 			ctrl := gomock.NewController(t)
 			cr := mock.NewMockCustomerRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(cr)
+				tt.setup(cr, tr, obr)
 			}
 
-			cuc := NewCustomerUsecase(cr)
+			cuc := NewCustomerUsecase(cr, tr, obr)
 
-			err := cuc.CreateCustomer(tt.arg.ctx, tt.arg.params)
+			customer, err := cuc.CreateCustomer(tt.arg.ctx, tt.arg.params)
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("want: %v, got: %v", tt.wantErr, err)
 			}
+			if err == nil && (customer.ID == "" || customer.Name != tt.arg.params.Name) {
+				t.Errorf("CreateCustomer() got = %v, want a new customer named %v", customer, tt.arg.params.Name)
+			}
 		})
 	}
 }
@@ -251,42 +385,55 @@ func TestUseCase_UpdateCustomer(t *testing.T) { //nolint: gocognit // This is a
 
 	customerID := uuid.New().String()
 
-	customer := &entity.Customer{
-		ID:      customerID,
-		Name:    "John Doe",
-		Email:   "john.doe@example.com",
-		Street:  "123 Maple Street",
-		City:    "Springfield",
-		Country: "USA",
+	newCustomer := func() *entity.Customer {
+		return &entity.Customer{
+			ID:      customerID,
+			Name:    "John Doe",
+			Email:   "john.doe@example.com",
+			Street:  "123 Maple Street",
+			City:    "Springfield",
+			Country: "USA",
+			Version: 2,
+		}
 	}
 
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockCustomerRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
 		)
 		arg struct {
 			ctx    context.Context
 			params *UpdateCustomerParams
 		}
-		wantErr error
+		wantName    string
+		wantVersion int64
+		wantErr     error
 	}{
 		{
 			name: "success",
-			setup: func(cr *mock.MockCustomerRepository) {
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
 				cr.EXPECT().Get(
 					gomock.Any(),
 					customerID,
-				).Return(customer, nil)
+					false,
+				).Return(newCustomer(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
 				cr.EXPECT().Update(
 					gomock.Any(),
 					gomock.Any(),
-				).Do(func(_ context.Context, customer entity.Customer) {
-					if customer.Name != "John Doe" {
-						t.Errorf("unexpected Name: got %v, want %v", customer.Name, "John Doe")
+					entity.CustomerFieldName,
+					entity.CustomerFieldEmail,
+				).Do(func(_ context.Context, customer entity.Customer, _ ...string) {
+					if customer.Name != "Jane Doe" {
+						t.Errorf("unexpected Name: got %v, want %v", customer.Name, "Jane Doe")
 					}
-					if customer.Email != "john.doe@example.com" {
-						t.Errorf("unexpected Email: got %v, want %v", customer.Email, "john.doe@example.com")
+					if customer.Email != "jane.doe@example.com" {
+						t.Errorf("unexpected Email: got %v, want %v", customer.Email, "jane.doe@example.com")
 					}
 					if customer.Street != "123 Maple Street" {
 						t.Errorf("unexpected Street: got %v, want %v", customer.Street, "123 Maple Street")
@@ -297,6 +444,14 @@ func TestUseCase_UpdateCustomer(t *testing.T) { //nolint: gocognit // This is a
 					if customer.Country != "USA" {
 						t.Errorf("unexpected Country: got %v, want %v", customer.Country, "USA")
 					}
+					if customer.Version != 2 {
+						t.Errorf("unexpected Version: got %v, want %v", customer.Version, 2)
+					}
+				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCustomerUpdated {
+						t.Errorf("unexpected events: %v", events)
+					}
 				}).Return(nil)
 			},
 			arg: struct {
@@ -306,14 +461,165 @@ func TestUseCase_UpdateCustomer(t *testing.T) { //nolint: gocognit // This is a
 				ctx: context.Background(),
 				params: &UpdateCustomerParams{
 					ID:      customerID,
-					Name:    "John Doe",
-					Email:   "john.doe@example.com",
+					Name:    "Jane Doe",
+					Email:   "jane.doe@example.com",
 					Street:  "123 Maple Street",
 					City:    "Springfield",
 					Country: "USA",
+					Version: 2,
 				},
 			},
-			wantErr: nil,
+			wantName:    "Jane Doe",
+			wantVersion: 3,
+			wantErr:     nil,
+		},
+		{
+			name: "success: only the masked fields are updated",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					customerID,
+					false,
+				).Return(newCustomer(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), entity.CustomerFieldCity).Do(func(_ context.Context, customer entity.Customer, _ ...string) {
+					if customer.Name != "John Doe" || customer.City != "Shelbyville" {
+						t.Errorf("unexpected Name and City: got %v and %v, want %v and %v", customer.Name, customer.City, "John Doe", "Shelbyville")
+					}
+				}).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:     customerID,
+					City:   "Shelbyville",
+					Fields: []string{entity.CustomerFieldCity},
+				},
+			},
+			wantName:    "John Doe",
+			wantVersion: 3,
+			wantErr:     nil,
+		},
+		{
+			name: "success: nothing changes",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					customerID,
+					false,
+				).Return(newCustomer(), nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:     customerID,
+					Name:   "John Doe",
+					Fields: []string{entity.CustomerFieldName},
+				},
+			},
+			wantName:    "John Doe",
+			wantVersion: 2,
+			wantErr:     nil,
+		},
+		{
+			name: "Fail: unknown field",
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:     customerID,
+					Fields: []string{"phone"},
+				},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: masked field is empty",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					customerID,
+					false,
+				).Return(newCustomer(), nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:     customerID,
+					Name:   "Jane Doe",
+					Fields: []string{entity.CustomerFieldName, entity.CustomerFieldEmail},
+				},
+			},
+			wantErr: entity.ErrInvalidArgument,
+		},
+		{
+			name: "Fail: version is stale",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					customerID,
+					false,
+				).Return(newCustomer(), nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:      customerID,
+					Name:    "Jane Doe",
+					Email:   "jane.doe@example.com",
+					Street:  "123 Maple Street",
+					City:    "Springfield",
+					Country: "USA",
+					Version: 1,
+				},
+			},
+			wantErr: entity.ErrConflict,
+		},
+		{
+			name: "Fail: customer is updated concurrently",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Get(
+					gomock.Any(),
+					customerID,
+					false,
+				).Return(newCustomer(), nil)
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(repository.ErrVersionConflict)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *UpdateCustomerParams
+			}{
+				ctx: context.Background(),
+				params: &UpdateCustomerParams{
+					ID:      customerID,
+					Name:    "Jane Doe",
+					Email:   "jane.doe@example.com",
+					Street:  "123 Maple Street",
+					City:    "Springfield",
+					Country: "USA",
+				},
+			},
+			wantErr: entity.ErrConflict,
 		},
 	}
 
@@ -324,18 +630,26 @@ func TestUseCase_UpdateCustomer(t *testing.T) { //nolint: gocognit // This is a
 
 			ctrl := gomock.NewController(t)
 			cr := mock.NewMockCustomerRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(cr)
+				tt.setup(cr, tr, obr)
 			}
 
-			cuc := NewCustomerUsecase(cr)
+			cuc := NewCustomerUsecase(cr, tr, obr)
 
-			err := cuc.UpdateCustomer(tt.arg.ctx, tt.arg.params)
+			customer, err := cuc.UpdateCustomer(tt.arg.ctx, tt.arg.params)
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("want: %v, got: %v", tt.wantErr, err)
 			}
+			if err == nil && (customer.ID != tt.arg.params.ID || customer.Name != tt.wantName) {
+				t.Errorf("UpdateCustomer() got = %v, want id %v and name %v", customer, tt.arg.params.ID, tt.wantName)
+			}
+			if err == nil && customer.Version != tt.wantVersion {
+				t.Errorf("UpdateCustomer() version = %v, want %v", customer.Version, tt.wantVersion)
+			}
 		})
 	}
 }
@@ -344,11 +658,14 @@ func TestUsaCase_DeleteCustomer(t *testing.T) {
 	t.Parallel()
 
 	customerID := uuid.New().String()
+	errOutbox := errors.New("connection refused")
 
 	patterns := []struct {
 		name  string
 		setup func(
 			m *mock.MockCustomerRepository,
+			m1 *mock.MockTransactionRepository,
+			m2 *mock.MockOutboxRepository,
 		)
 		arg struct {
 			ctx context.Context
@@ -358,8 +675,16 @@ func TestUsaCase_DeleteCustomer(t *testing.T) {
 	}{
 		{
 			name: "success",
-			setup: func(cr *mock.MockCustomerRepository) {
-				cr.EXPECT().Delete(gomock.Any(), customerID).Return(nil)
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Delete(gomock.Any(), customerID, gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCustomerDeleted || events[0].AggregateID != customerID {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
 			},
 			arg: struct {
 				ctx context.Context
@@ -370,6 +695,24 @@ func TestUsaCase_DeleteCustomer(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "Fail: event cannot be stored",
+			setup: func(cr *mock.MockCustomerRepository, tr *mock.MockTransactionRepository, obr *mock.MockOutboxRepository) {
+				tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				})
+				cr.EXPECT().Delete(gomock.Any(), customerID, gomock.Any()).Return(nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errOutbox)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  customerID,
+			},
+			wantErr: errOutbox,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -379,12 +722,14 @@ func TestUsaCase_DeleteCustomer(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			cr := mock.NewMockCustomerRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
 
 			if tt.setup != nil {
-				tt.setup(cr)
+				tt.setup(cr, tr, obr)
 			}
 
-			cuc := NewCustomerUsecase(cr)
+			cuc := NewCustomerUsecase(cr, tr, obr)
 
 			err := cuc.DeleteCustomer(tt.arg.ctx, tt.arg.id)
 
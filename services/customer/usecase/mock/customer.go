@@ -7,9 +7,11 @@ package mock
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
 	usecase "github.com/tusmasoma/go-microservice-k8s/services/customer/usecase"
 )
 
@@ -37,11 +39,12 @@ func (m *MockCustomerUseCase) EXPECT() *MockCustomerUseCaseMockRecorder {
 }
 
 // CreateCustomer mocks base method.
-func (m *MockCustomerUseCase) CreateCustomer(ctx context.Context, params *usecase.CreateCustomerParams) error {
+func (m *MockCustomerUseCase) CreateCustomer(ctx context.Context, params *usecase.CreateCustomerParams) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateCustomer", ctx, params)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateCustomer indicates an expected call of CreateCustomer.
@@ -65,41 +68,104 @@ func (mr *MockCustomerUseCaseMockRecorder) DeleteCustomer(ctx, id interface{}) *
 }
 
 // GetCustomer mocks base method.
-func (m *MockCustomerUseCase) GetCustomer(ctx context.Context, id string) (*entity.Customer, error) {
+func (m *MockCustomerUseCase) GetCustomer(ctx context.Context, id string, showDeleted bool) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCustomer", ctx, id)
+	ret := m.ctrl.Call(m, "GetCustomer", ctx, id, showDeleted)
 	ret0, _ := ret[0].(*entity.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCustomer indicates an expected call of GetCustomer.
-func (mr *MockCustomerUseCaseMockRecorder) GetCustomer(ctx, id interface{}) *gomock.Call {
+func (mr *MockCustomerUseCaseMockRecorder) GetCustomer(ctx, id, showDeleted interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomer", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomer), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomer", reflect.TypeOf((*MockCustomerUseCase)(nil).GetCustomer), ctx, id, showDeleted)
 }
 
 // ListCustomers mocks base method.
-func (m *MockCustomerUseCase) ListCustomers(ctx context.Context) ([]entity.Customer, error) {
+func (m *MockCustomerUseCase) ListCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomers", ctx, page)
+	ret0, _ := ret[0].([]entity.Customer)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCustomers indicates an expected call of ListCustomers.
+func (mr *MockCustomerUseCaseMockRecorder) ListCustomers(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomers", reflect.TypeOf((*MockCustomerUseCase)(nil).ListCustomers), ctx, page)
+}
+
+// ListCustomersByIDs mocks base method.
+func (m *MockCustomerUseCase) ListCustomersByIDs(ctx context.Context, ids []string, showDeleted bool) ([]entity.Customer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCustomers", ctx)
+	ret := m.ctrl.Call(m, "ListCustomersByIDs", ctx, ids, showDeleted)
 	ret0, _ := ret[0].([]entity.Customer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCustomers indicates an expected call of ListCustomers.
-func (mr *MockCustomerUseCaseMockRecorder) ListCustomers(ctx interface{}) *gomock.Call {
+// ListCustomersByIDs indicates an expected call of ListCustomersByIDs.
+func (mr *MockCustomerUseCaseMockRecorder) ListCustomersByIDs(ctx, ids, showDeleted interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomers", reflect.TypeOf((*MockCustomerUseCase)(nil).ListCustomers), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomersByIDs", reflect.TypeOf((*MockCustomerUseCase)(nil).ListCustomersByIDs), ctx, ids, showDeleted)
+}
+
+// ListDeletedCustomers mocks base method.
+func (m *MockCustomerUseCase) ListDeletedCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedCustomers", ctx, page)
+	ret0, _ := ret[0].([]entity.Customer)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeletedCustomers indicates an expected call of ListDeletedCustomers.
+func (mr *MockCustomerUseCaseMockRecorder) ListDeletedCustomers(ctx, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedCustomers", reflect.TypeOf((*MockCustomerUseCase)(nil).ListDeletedCustomers), ctx, page)
+}
+
+// PurgeCustomers mocks base method.
+func (m *MockCustomerUseCase) PurgeCustomers(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeCustomers", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeCustomers indicates an expected call of PurgeCustomers.
+func (mr *MockCustomerUseCaseMockRecorder) PurgeCustomers(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeCustomers", reflect.TypeOf((*MockCustomerUseCase)(nil).PurgeCustomers), ctx, before, limit)
+}
+
+// RestoreCustomer mocks base method.
+func (m *MockCustomerUseCase) RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCustomer", ctx, id)
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCustomer indicates an expected call of RestoreCustomer.
+func (mr *MockCustomerUseCaseMockRecorder) RestoreCustomer(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCustomer", reflect.TypeOf((*MockCustomerUseCase)(nil).RestoreCustomer), ctx, id)
 }
 
 // UpdateCustomer mocks base method.
-func (m *MockCustomerUseCase) UpdateCustomer(ctx context.Context, params *usecase.UpdateCustomerParams) error {
+func (m *MockCustomerUseCase) UpdateCustomer(ctx context.Context, params *usecase.UpdateCustomerParams) (*entity.Customer, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateCustomer", ctx, params)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // UpdateCustomer indicates an expected call of UpdateCustomer.
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository"
+)
+
+func (cuc *customerUseCase) RestoreCustomer(ctx context.Context, id string) (*entity.Customer, error) {
+	var customer *entity.Customer
+	if err := cuc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := cuc.cr.Restore(ctx, id); err != nil {
+			return err
+		}
+		restored, err := cuc.cr.Get(ctx, id, false)
+		if err != nil {
+			return err
+		}
+		customer = restored
+		return cuc.addEvent(ctx, entity.EventTypeCustomerRestored, id, customer)
+	}); err != nil {
+		log.Error("failed to restore customer", log.Fstring("id", id), log.Ferror(err))
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (cuc *customerUseCase) ListDeletedCustomers(ctx context.Context, page repository.Page) ([]entity.Customer, repository.PageInfo, error) {
+	customers, info, err := cuc.cr.ListDeleted(ctx, page)
+	if err != nil {
+		log.Error("failed to list deleted customers", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+	return customers, info, nil
+}
+
+func (cuc *customerUseCase) PurgeCustomers(ctx context.Context, before time.Time, limit int) (int, error) {
+	ids, err := cuc.cr.ListDeletedBefore(ctx, before, limit)
+	if err != nil {
+		log.Error("failed to list deleted customers", log.Ferror(err))
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		err = cuc.cr.Purge(ctx, id, before)
+		if errors.Is(err, entity.ErrNotFound) {
+			// The customer was restored since they were listed.
+			continue
+		}
+		if err != nil {
+			log.Error("failed to purge customer", log.Fstring("id", id), log.Ferror(err))
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// TrashPurger periodically purges the customers that have been in the trash for longer than the retention.
+type TrashPurger struct {
+	cuc       CustomerUseCase
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+}
+
+func NewTrashPurger(cuc CustomerUseCase, conf *config.TrashConfig) *TrashPurger {
+	return &TrashPurger{
+		cuc:       cuc,
+		retention: conf.Retention,
+		interval:  conf.PurgeInterval,
+		batchSize: conf.PurgeBatchSize,
+	}
+}
+
+// Run purges the trash until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.Purge(ctx, now)
+		}
+	}
+}
+
+// Purge purges the customers moved to the trash more than the retention before now, a batch at a time.
+func (p *TrashPurger) Purge(ctx context.Context, now time.Time) {
+	before := now.Add(-p.retention)
+	total := 0
+	for {
+		purged, err := p.cuc.PurgeCustomers(ctx, before, p.batchSize)
+		total += purged
+		if err != nil {
+			log.Error("Failed to purge the trash", log.Ferror(err))
+			break
+		}
+		if purged < p.batchSize {
+			break
+		}
+	}
+	if total > 0 {
+		log.Info("Purged the trash", log.Fint("purged", total))
+	}
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/customer/repository/mock"
+)
+
+func TestUseCase_RestoreCustomer(t *testing.T) {
+	t.Parallel()
+
+	customerID := uuid.New().String()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerRepository,
+			m1 *mock.MockOutboxRepository,
+		)
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(cr *mock.MockCustomerRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Restore(gomock.Any(), customerID).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), customerID, false).Return(&entity.Customer{
+					ID:      customerID,
+					Name:    "John Doe",
+					Email:   "john.doe@example.com",
+					Street:  "123 Maple Street",
+					City:    "Springfield",
+					Country: "USA",
+					Version: 3,
+				}, nil)
+				obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+					if len(events) != 1 || events[0].Type != entity.EventTypeCustomerRestored || events[0].AggregateID != customerID {
+						t.Errorf("unexpected events: %v", events)
+					}
+				}).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: customer is not in the trash",
+			setup: func(cr *mock.MockCustomerRepository, obr *mock.MockOutboxRepository) {
+				cr.EXPECT().Restore(gomock.Any(), customerID).Return(entity.NewError(entity.ErrNotFound, "deleted customer not found"))
+			},
+			wantErr: entity.ErrNotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCustomerRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+			tt.setup(cr, obr)
+
+			cuc := NewCustomerUsecase(cr, tr, obr)
+
+			customer, err := cuc.RestoreCustomer(context.Background(), customerID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && (customer.ID != customerID || customer.Version != 3) {
+				t.Errorf("RestoreCustomer() got = %v, want id %v at version %v", customer, customerID, 3)
+			}
+		})
+	}
+}
+
+func TestUseCase_PurgeCustomers(t *testing.T) {
+	t.Parallel()
+
+	customerID1 := uuid.New().String()
+	customerID2 := uuid.New().String()
+	before := time.Now()
+	errDB := errors.New("connection refused")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerRepository,
+		)
+		want    int
+		wantErr error
+	}{
+		{
+			name: "success: customers restored since they were listed are skipped",
+			setup: func(cr *mock.MockCustomerRepository) {
+				cr.EXPECT().ListDeletedBefore(gomock.Any(), before, 10).Return([]string{customerID1, customerID2}, nil)
+				cr.EXPECT().Purge(gomock.Any(), customerID1, before).Return(entity.NewError(entity.ErrNotFound, "deleted customer not found"))
+				cr.EXPECT().Purge(gomock.Any(), customerID2, before).Return(nil)
+			},
+			want: 1,
+		},
+		{
+			name: "Fail: customer cannot be purged",
+			setup: func(cr *mock.MockCustomerRepository) {
+				cr.EXPECT().ListDeletedBefore(gomock.Any(), before, 10).Return([]string{customerID1, customerID2}, nil)
+				cr.EXPECT().Purge(gomock.Any(), customerID1, before).Return(errDB)
+			},
+			want:    0,
+			wantErr: errDB,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCustomerRepository(ctrl)
+			tt.setup(cr)
+
+			cuc := NewCustomerUsecase(cr, nil, nil)
+
+			purged, err := cuc.PurgeCustomers(context.Background(), before, 10)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected: %v, got: %v", tt.wantErr, err)
+			}
+			if purged != tt.want {
+				t.Errorf("PurgeCustomers() = %v, want %v", purged, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTrash purges at most limit of its customers each time.
+type fakeTrash struct {
+	CustomerUseCase
+	customers int
+	before    []time.Time
+}
+
+func (f *fakeTrash) PurgeCustomers(_ context.Context, before time.Time, limit int) (int, error) {
+	f.before = append(f.before, before)
+	purged := min(f.customers, limit)
+	f.customers -= purged
+	return purged, nil
+}
+
+func TestTrashPurger(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	trash := &fakeTrash{customers: 5}
+	purger := &TrashPurger{cuc: trash, retention: 24 * time.Hour, batchSize: 2}
+
+	purger.Purge(context.Background(), now)
+
+	if trash.customers != 0 {
+		t.Errorf("Purge() left %d customers, want none", trash.customers)
+	}
+	if len(trash.before) != 3 {
+		t.Fatalf("Purge() purged %d batches, want %d", len(trash.before), 3)
+	}
+	if want := now.Add(-24 * time.Hour); !trash.before[0].Equal(want) {
+		t.Errorf("Purge() purged the customers deleted before %v, want %v", trash.before[0], want)
+	}
+}
@@ -0,0 +1,35 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/sethvargo/go-envconfig"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+const configPrefix = "IDEMPOTENCY_"
+
+// Config controls how long the records of requests with an idempotency key are kept.
+// A completed request keeps its response for TTL, while a request still in progress
+// holds its key for InProgressTTL only, so that the key of a request whose handler
+// crashed can be reused soon. InProgressTTL should be longer than any request takes.
+type Config struct {
+	TTL           time.Duration `env:"TTL,default=24h"`
+	InProgressTTL time.Duration `env:"IN_PROGRESS_TTL,default=5m"`
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL,default=1h"`
+}
+
+func NewConfig(ctx context.Context) (*Config, error) {
+	conf := &Config{}
+	pl := envconfig.PrefixLookuper(configPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load idempotency config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
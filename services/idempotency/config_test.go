@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *Config
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &Config{
+				TTL:           24 * time.Hour,
+				InProgressTTL: 5 * time.Minute,
+				SweepInterval: time.Hour,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("IDEMPOTENCY_TTL", "30m")
+				t.Setenv("IDEMPOTENCY_IN_PROGRESS_TTL", "1m")
+				t.Setenv("IDEMPOTENCY_SWEEP_INTERVAL", "5m")
+			},
+			want: &Config{
+				TTL:           30 * time.Minute,
+				InProgressTTL: time.Minute,
+				SweepInterval: 5 * time.Minute,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
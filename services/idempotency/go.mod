@@ -0,0 +1,27 @@
+module github.com/tusmasoma/go-microservice-k8s/services/idempotency
+
+go 1.21.3
+
+require (
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/golang/mock v1.6.0
+	github.com/google/uuid v1.6.0
+	github.com/sethvargo/go-envconfig v1.1.0
+	github.com/stretchr/testify v1.9.0
+	github.com/tusmasoma/go-tech-dojo v0.0.0-20240805120803-02e31d5c8a21
+	google.golang.org/grpc v1.66.0
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	filippo.io/edwards25519 v1.1.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/slack-go/slack v0.13.1 // indirect
+	golang.org/x/net v0.27.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,159 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// maxKeyLength matches the width of the idempotency_key column.
+	maxKeyLength = 255
+	// writeTimeout bounds the writes made after the handler has returned. They do not
+	// use the request context, as a cancelled client must not leave its key reserved.
+	writeTimeout = 5 * time.Second
+)
+
+// idempotentRequest is implemented by every request message with an idempotency_key field.
+type idempotentRequest interface {
+	proto.Message
+	GetIdempotencyKey() string
+}
+
+// NewInterceptor returns an interceptor that replays the stored response of a request
+// whose idempotency key has already been seen within the configured TTL.
+// Requests without an idempotency key are passed through unchanged.
+func NewInterceptor(repo Repository, conf *Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		r, ok := req.(idempotentRequest)
+		if !ok || r.GetIdempotencyKey() == "" {
+			return handler(ctx, req)
+		}
+		if len(r.GetIdempotencyKey()) > maxKeyLength {
+			return nil, status.Error(codes.InvalidArgument, "idempotency key is too long")
+		}
+
+		hash, err := hashRequest(r)
+		if err != nil {
+			return nil, internalError(err, "Failed to hash request")
+		}
+
+		now := time.Now()
+		record := Record{
+			Key:         r.GetIdempotencyKey(),
+			Method:      info.FullMethod,
+			RequestHash: hash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(conf.InProgressTTL),
+		}
+		stored, err := reserve(ctx, repo, record)
+		if err != nil {
+			return nil, internalError(err, "Failed to reserve idempotency key")
+		}
+		if stored != nil {
+			return replayResponse(stored, hash)
+		}
+
+		resp, err := handler(ctx, req)
+
+		wctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), writeTimeout)
+		defer cancel()
+
+		if err != nil {
+			// Release the key so that the request can be retried.
+			if derr := repo.Delete(wctx, record.Key, record.Method); derr != nil {
+				log.Error("Failed to release idempotency key", log.Fstring("key", record.Key), log.Ferror(derr))
+			}
+			return nil, err
+		}
+
+		msg, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+		if record.Response, err = proto.Marshal(msg); err != nil {
+			log.Error("Failed to marshal response", log.Ferror(err))
+			return resp, nil
+		}
+		record.ResponseType = string(proto.MessageName(msg))
+		record.ExpiresAt = time.Now().Add(conf.TTL)
+		if err = repo.Complete(wctx, record); err != nil {
+			log.Error("Failed to store idempotent response", log.Fstring("key", record.Key), log.Ferror(err))
+		}
+		return resp, nil
+	}
+}
+
+// reserve stores record and returns nil, or returns the record already stored
+// for the same key. An expired record is replaced as if it did not exist.
+func reserve(ctx context.Context, repo Repository, record Record) (*Record, error) {
+	err := repo.Reserve(ctx, record)
+	if !errors.Is(err, ErrAlreadyExists) {
+		return nil, err
+	}
+
+	stored, err := repo.Get(ctx, record.Key, record.Method)
+	if errors.Is(err, ErrNotFound) {
+		// The record expired and was swept in the meantime.
+		return nil, repo.Reserve(ctx, record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !stored.IsExpired(record.CreatedAt) {
+		return stored, nil
+	}
+
+	if err = repo.Delete(ctx, record.Key, record.Method); err != nil {
+		return nil, err
+	}
+	return nil, repo.Reserve(ctx, record)
+}
+
+func replayResponse(stored *Record, hash string) (interface{}, error) {
+	if stored.RequestHash != hash {
+		log.Warn("Idempotency key reused", log.Fstring("key", stored.Key), log.Fstring("method", stored.Method))
+		return nil, status.Error(codes.InvalidArgument, "idempotency key has already been used for a different request")
+	}
+	if !stored.IsCompleted() {
+		log.Warn("Idempotent request in progress", log.Fstring("key", stored.Key), log.Fstring("method", stored.Method))
+		return nil, status.Error(codes.Aborted, "a request with the same idempotency key is in progress")
+	}
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(stored.ResponseType))
+	if err != nil {
+		return nil, internalError(err, "Failed to find stored response type")
+	}
+	msg := mt.New().Interface()
+	if err = proto.Unmarshal(stored.Response, msg); err != nil {
+		return nil, internalError(err, "Failed to unmarshal stored response")
+	}
+	log.Info("Replayed idempotent response", log.Fstring("key", stored.Key), log.Fstring("method", stored.Method))
+	return msg, nil
+}
+
+func hashRequest(req proto.Message) (string, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// internalError logs err and reports msg as codes.Internal, so that internal
+// details are not leaked to the client.
+func internalError(err error, msg string) error {
+	log.Error(msg, log.Ferror(err))
+	return status.Error(codes.Internal, msg)
+}
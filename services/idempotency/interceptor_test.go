@@ -0,0 +1,343 @@
+package idempotency_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency"
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency/mock"
+)
+
+// testRequest is a request message whose idempotency key is its value.
+type testRequest struct {
+	*wrapperspb.StringValue
+}
+
+func (r testRequest) GetIdempotencyKey() string {
+	return r.GetValue()
+}
+
+func hashRequest(t *testing.T, req proto.Message) string {
+	t.Helper()
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestInterceptor(t *testing.T) { //nolint:gocognit // This is a test function
+	t.Parallel()
+
+	key := uuid.New().String()
+	method := "/test.TestService/Create"
+
+	req := testRequest{wrapperspb.String(key)}
+	hash := hashRequest(t, req)
+
+	resp := wrapperspb.String(uuid.New().String())
+	storedResp, err := proto.Marshal(resp)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error = %v", err)
+	}
+
+	now := time.Now()
+	conf := &idempotency.Config{TTL: 24 * time.Hour, InProgressTTL: time.Minute}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockRepository,
+		)
+		request     interface{}
+		ctx         func() context.Context
+		handlerErr  error
+		wantHandled bool
+		wantStatus  codes.Code
+	}{
+		{
+			name:        "success: request without idempotency key",
+			request:     testRequest{wrapperspb.String("")},
+			wantHandled: true,
+			wantStatus:  codes.OK,
+		},
+		{
+			name: "success: first request stores the response",
+			setup: func(ir *mock.MockRepository) {
+				gomock.InOrder(
+					ir.EXPECT().Reserve(
+						gomock.Any(),
+						gomock.Any(),
+					).Do(func(_ context.Context, record idempotency.Record) {
+						if record.ExpiresAt.After(time.Now().Add(conf.InProgressTTL)) {
+							t.Errorf("reservation expires too late: got %v", record.ExpiresAt)
+						}
+					}).Return(nil),
+					ir.EXPECT().Complete(
+						gomock.Any(),
+						gomock.Any(),
+					).Do(func(_ context.Context, record idempotency.Record) {
+						if record.Key != key || record.Method != method {
+							t.Errorf("unexpected record: got %v/%v, want %v/%v", record.Key, record.Method, key, method)
+						}
+						if record.ResponseType != "google.protobuf.StringValue" {
+							t.Errorf("unexpected ResponseType: got %v", record.ResponseType)
+						}
+						if record.ExpiresAt.Before(time.Now().Add(conf.TTL - time.Minute)) {
+							t.Errorf("response expires too early: got %v", record.ExpiresAt)
+						}
+					}).Return(nil),
+				)
+			},
+			request:     req,
+			wantHandled: true,
+			wantStatus:  codes.OK,
+		},
+		{
+			name: "success: retried request replays the stored response",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(idempotency.ErrAlreadyExists)
+				ir.EXPECT().Get(gomock.Any(), key, method).Return(&idempotency.Record{
+					Key:          key,
+					Method:       method,
+					RequestHash:  hash,
+					ResponseType: "google.protobuf.StringValue",
+					Response:     storedResp,
+					ExpiresAt:    now.Add(time.Hour),
+				}, nil)
+			},
+			request:     req,
+			wantHandled: false,
+			wantStatus:  codes.OK,
+		},
+		{
+			name: "success: expired record is replaced",
+			setup: func(ir *mock.MockRepository) {
+				gomock.InOrder(
+					ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(idempotency.ErrAlreadyExists),
+					ir.EXPECT().Get(gomock.Any(), key, method).Return(&idempotency.Record{
+						Key:         key,
+						Method:      method,
+						RequestHash: hash,
+						ExpiresAt:   now.Add(-time.Hour),
+					}, nil),
+					ir.EXPECT().Delete(gomock.Any(), key, method).Return(nil),
+					ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(nil),
+					ir.EXPECT().Complete(gomock.Any(), gomock.Any()).Return(nil),
+				)
+			},
+			request:     req,
+			wantHandled: true,
+			wantStatus:  codes.OK,
+		},
+		{
+			name: "success: response is stored after the client has gone away",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(nil)
+				ir.EXPECT().Complete(
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(ctx context.Context, _ idempotency.Record) error {
+					if ctx.Err() != nil {
+						t.Errorf("response stored on a cancelled context: %v", ctx.Err())
+					}
+					return nil
+				})
+			},
+			request:     req,
+			ctx:         cancelledContext,
+			wantHandled: true,
+			wantStatus:  codes.OK,
+		},
+		{
+			name:        "Fail: idempotency key is too long",
+			request:     testRequest{wrapperspb.String(string(make([]byte, 256)))},
+			wantHandled: false,
+			wantStatus:  codes.InvalidArgument,
+		},
+		{
+			name: "Fail: idempotency key reused for a different request",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(idempotency.ErrAlreadyExists)
+				ir.EXPECT().Get(gomock.Any(), key, method).Return(&idempotency.Record{
+					Key:          key,
+					Method:       method,
+					RequestHash:  "another request",
+					ResponseType: "google.protobuf.StringValue",
+					Response:     storedResp,
+					ExpiresAt:    now.Add(time.Hour),
+				}, nil)
+			},
+			request:     req,
+			wantHandled: false,
+			wantStatus:  codes.InvalidArgument,
+		},
+		{
+			name: "Fail: request with the same key in progress",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(idempotency.ErrAlreadyExists)
+				ir.EXPECT().Get(gomock.Any(), key, method).Return(&idempotency.Record{
+					Key:         key,
+					Method:      method,
+					RequestHash: hash,
+					ExpiresAt:   now.Add(time.Minute),
+				}, nil)
+			},
+			request:     req,
+			wantHandled: false,
+			wantStatus:  codes.Aborted,
+		},
+		{
+			name: "Fail: reservation error",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+			request:     req,
+			wantHandled: false,
+			wantStatus:  codes.Internal,
+		},
+		{
+			name: "Fail: handler error releases the key",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(nil)
+				ir.EXPECT().Delete(gomock.Any(), key, method).Return(nil)
+			},
+			request:     req,
+			handlerErr:  status.Error(codes.NotFound, "customer not found"),
+			wantHandled: true,
+			wantStatus:  codes.NotFound,
+		},
+		{
+			name: "Fail: handler error releases the key after the client has gone away",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().Reserve(gomock.Any(), gomock.Any()).Return(nil)
+				ir.EXPECT().Delete(
+					gomock.Any(),
+					key,
+					method,
+				).DoAndReturn(func(ctx context.Context, _, _ string) error {
+					if ctx.Err() != nil {
+						t.Errorf("key released on a cancelled context: %v", ctx.Err())
+					}
+					if _, ok := ctx.Deadline(); !ok {
+						t.Error("key released without a deadline")
+					}
+					return nil
+				})
+			},
+			request:     req,
+			ctx:         cancelledContext,
+			handlerErr:  status.Error(codes.Canceled, "context canceled"),
+			wantHandled: true,
+			wantStatus:  codes.Canceled,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ir := mock.NewMockRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(ir)
+			}
+
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx()
+			}
+
+			interceptor := idempotency.NewInterceptor(ir, conf)
+
+			handled := false
+			handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+				handled = true
+				if tt.handlerErr != nil {
+					return nil, tt.handlerErr
+				}
+				return resp, nil
+			}
+
+			got, err := interceptor(ctx, tt.request, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("interceptor returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+			if handled != tt.wantHandled {
+				t.Errorf("handler called = %v, want %v", handled, tt.wantHandled)
+			}
+
+			if tt.wantStatus == codes.OK {
+				gotResp, ok := got.(*wrapperspb.StringValue)
+				if !ok || gotResp.GetValue() != resp.GetValue() {
+					t.Errorf("interceptor returned wrong response: got %v want %v", got, resp)
+				}
+			}
+		})
+	}
+}
+
+// cancelledContext returns the context of a client that has gone away while its request was handled.
+func cancelledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockRepository,
+		)
+	}{
+		{
+			name: "success",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().DeleteExpired(gomock.Any(), now).Return(int64(2), nil)
+			},
+		},
+		{
+			name: "Fail: repository error is logged",
+			setup: func(ir *mock.MockRepository) {
+				ir.EXPECT().DeleteExpired(gomock.Any(), now).Return(int64(0), errors.New("connection refused"))
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			ir := mock.NewMockRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(ir)
+			}
+
+			sweeper := idempotency.NewSweeper(ir, &idempotency.Config{SweepInterval: time.Minute})
+			sweeper.Sweep(context.Background(), now)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number reported for a duplicate key.
+const mysqlErrDuplicateEntry = 1062
+
+type mysqlRepository struct {
+	db    *sql.DB
+	table string
+}
+
+// NewMySQLRepository returns a Repository storing the records in table, which each service
+// creates with the columns idempotency_key, method, request_hash, response_type, response,
+// created_at and expires_at.
+func NewMySQLRepository(db *sql.DB, table string) Repository {
+	return &mysqlRepository{
+		db:    db,
+		table: table,
+	}
+}
+
+// query fills the table name into q, which refers to it as {table}.
+// The table name is chosen by the service and never comes from a request.
+func (r *mysqlRepository) query(q string) string {
+	return strings.ReplaceAll(q, "{table}", r.table)
+}
+
+func (r *mysqlRepository) Get(ctx context.Context, key, method string) (*Record, error) {
+	query := r.query(`
+	SELECT idempotency_key, method, request_hash, response_type, response, created_at, expires_at
+	FROM {table}
+	WHERE idempotency_key = ? AND method = ?
+	LIMIT 1
+	`)
+
+	row := r.db.QueryRowContext(ctx, query, key, method)
+	var record Record
+	var responseType sql.NullString
+	if err := row.Scan(
+		&record.Key,
+		&record.Method,
+		&record.RequestHash,
+		&responseType,
+		&record.Response,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	record.ResponseType = responseType.String
+	return &record, nil
+}
+
+func (r *mysqlRepository) Reserve(ctx context.Context, record Record) error {
+	query := r.query(`
+	INSERT INTO {table} (
+	idempotency_key, method, request_hash, created_at, expires_at
+	)
+	VALUES (?, ?, ?, ?, ?)
+	`)
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		query,
+		record.Key,
+		record.Method,
+		record.RequestHash,
+		record.CreatedAt,
+		record.ExpiresAt,
+	); err != nil {
+		var mysqlErr *driver.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return ErrAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *mysqlRepository) Complete(ctx context.Context, record Record) error {
+	query := r.query(`
+	UPDATE {table}
+	SET response_type = ?, response = ?, expires_at = ?
+	WHERE idempotency_key = ? AND method = ? AND response_type IS NULL
+	`)
+
+	if _, err := r.db.ExecContext(
+		ctx,
+		query,
+		record.ResponseType,
+		record.Response,
+		record.ExpiresAt,
+		record.Key,
+		record.Method,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *mysqlRepository) Delete(ctx context.Context, key, method string) error {
+	query := r.query(`
+	DELETE FROM {table}
+	WHERE idempotency_key = ? AND method = ?
+	`)
+
+	if _, err := r.db.ExecContext(ctx, query, key, method); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *mysqlRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	query := r.query(`
+	DELETE FROM {table}
+	WHERE expires_at <= ?
+	`)
+
+	res, err := r.db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,33 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned by Repository.Get when no record is stored for the key.
+	ErrNotFound = errors.New("idempotency key not found")
+	// ErrAlreadyExists is returned by Repository.Reserve when a record is already stored for the key.
+	ErrAlreadyExists = errors.New("idempotency key already exists")
+)
+
+// Record is the outcome of a request made with an idempotency key.
+// A record without a response marks a request that is still being processed.
+type Record struct {
+	Key          string
+	Method       string
+	RequestHash  string
+	ResponseType string
+	Response     []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func (r *Record) IsCompleted() bool {
+	return r.ResponseType != ""
+}
+
+func (r *Record) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
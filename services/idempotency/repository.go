@@ -0,0 +1,19 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Get fails with ErrNotFound if no record is stored for the key and method.
+	Get(ctx context.Context, key, method string) (*Record, error)
+	// Reserve stores a record without response. It fails with ErrAlreadyExists
+	// if a record for the same key and method is already stored.
+	Reserve(ctx context.Context, record Record) error
+	// Complete stores the response of a reserved record together with its new expiry.
+	Complete(ctx context.Context, record Record) error
+	Delete(ctx context.Context, key, method string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+)
+
+// Sweeper periodically deletes the records whose TTL has passed.
+type Sweeper struct {
+	repo     Repository
+	interval time.Duration
+}
+
+func NewSweeper(repo Repository, conf *Config) *Sweeper {
+	return &Sweeper{
+		repo:     repo,
+		interval: conf.SweepInterval,
+	}
+}
+
+// Run sweeps expired records until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Sweep(ctx, now)
+		}
+	}
+}
+
+func (s *Sweeper) Sweep(ctx context.Context, now time.Time) {
+	deleted, err := s.repo.DeleteExpired(ctx, now)
+	if err != nil {
+		log.Error("Failed to sweep expired idempotency keys", log.Ferror(err))
+		return
+	}
+	if deleted > 0 {
+		log.Info("Swept expired idempotency keys", log.Fint("deleted", int(deleted)))
+	}
+}
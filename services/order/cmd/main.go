@@ -18,12 +18,15 @@ import (
 
 	catalog_pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 	cusotmer_pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/config"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/gateway"
 	pb "github.com/tusmasoma/go-microservice-k8s/services/order/proto"
 	catalogservice "github.com/tusmasoma/go-microservice-k8s/services/order/repository/catalog_service"
 	customerservice "github.com/tusmasoma/go-microservice-k8s/services/order/repository/customer_service"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/repository/mysql"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository/publisher"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/service"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/usecase"
 )
 
@@ -45,13 +48,20 @@ func main() {
 		return
 	}
 
-	err = container.Invoke(func(grpcHandler pb.OrderServiceServer, config *config.ServerConfig) {
+	err = container.Invoke(func(
+		grpcHandler pb.OrderServiceServer,
+		config *config.ServerConfig,
+		idempotencyInterceptor grpc.UnaryServerInterceptor,
+		idempotencySweeper *idempotency.Sweeper,
+		outboxRelay *usecase.OutboxRelay,
+		sagaRecoverer *service.SagaRecoverer,
+	) {
 		lis, err := net.Listen("tcp", addr) //nolint:govet // This is not a mistake
 		if err != nil {
 			log.Critical("Failed to listen", log.Ferror(err))
 		}
 
-		srv := grpc.NewServer()
+		srv := grpc.NewServer(grpc.UnaryInterceptor(idempotencyInterceptor))
 
 		pb.RegisterOrderServiceServer(srv, grpcHandler)
 
@@ -59,6 +69,10 @@ func main() {
 
 		log.Info("Server started", log.Fstring("addr", addr))
 
+		go idempotencySweeper.Run(mainCtx)
+		go outboxRelay.Run(mainCtx)
+		go sagaRecoverer.Run(mainCtx)
+
 		go func() {
 			if err = srv.Serve(lis); err != nil {
 				log.Critical("Failed to serve", log.Ferror(err))
@@ -93,14 +107,30 @@ func BuildContainer(ctx context.Context) (*dig.Container, error) {
 		config.NewServerConfig,
 		config.NewDBConfig,
 		mysql.NewMySQLDB,
+		idempotency.NewConfig,
+		config.NewEventConfig,
+		config.NewSagaConfig,
 		mysql.NewTransactionRepository,
+		mysql.NewIdempotencyRepository,
+		mysql.NewOutboxRepository,
+		publisher.NewEventPublisher,
 		mysql.NewOrderRepository,
+		mysql.NewSagaRepository,
 		NewCustomerServiceClient,
 		NewCatalogServiceClient,
 		customerservice.NewCustomerRepository,
 		catalogservice.NewCatalogItemRepository,
+		catalogservice.NewStockRepository,
+		service.NewOrderService,
+		service.NewSagaRecoverer,
+		usecase.NewChangeFeed,
+		usecase.NewChangeWatcher,
 		usecase.NewOrderUseCase,
 		gateway.NewOrderHandler,
+		idempotency.NewInterceptor,
+		idempotency.NewSweeper,
+		usecase.NewStockSettler,
+		usecase.NewOutboxRelay,
 	}
 
 	for _, provider := range providers {
@@ -11,6 +11,8 @@ import (
 
 const (
 	serverPrefix = "SERVER_"
+	eventPrefix  = "EVENT_"
+	sagaPrefix   = "SAGA_"
 )
 
 type DBConfig struct {
@@ -29,6 +31,29 @@ type ServerConfig struct {
 	PreflightCacheDurationSec int           `env:"PREFLIGHT_CACHE_DURATION_SEC,default=300"`
 }
 
+// SagaConfig controls when sagas left unfinished by a stopped process are taken over.
+type SagaConfig struct {
+	StaleAfter       time.Duration `env:"STALE_AFTER,default=1m"`
+	RecoveryInterval time.Duration `env:"RECOVERY_INTERVAL,default=30s"`
+}
+
+// EventConfig selects the publisher of the events stored in the outbox and how often the outbox is relayed.
+// Publisher is either "log", which writes the events as JSON lines to LogFile or to the standard output,
+// or "memory", which keeps them in memory.
+// The relay claims the events of a batch for RelayLease, which should be longer than publishing a batch.
+// Watchers read the outbox when the relay has published events, and every WatchPollInterval for
+// the events relayed by other replicas. They wait up to WatchGapTimeout for the events of a sequence
+// missing from the outbox, which should be longer than the transactions storing events.
+type EventConfig struct {
+	Publisher         string        `env:"PUBLISHER,default=log"`
+	LogFile           string        `env:"LOG_FILE"`
+	RelayInterval     time.Duration `env:"RELAY_INTERVAL,default=1s"`
+	RelayBatchSize    int           `env:"RELAY_BATCH_SIZE,default=100"`
+	RelayLease        time.Duration `env:"RELAY_LEASE,default=1m"`
+	WatchPollInterval time.Duration `env:"WATCH_POLL_INTERVAL,default=2s"`
+	WatchGapTimeout   time.Duration `env:"WATCH_GAP_TIMEOUT,default=5s"`
+}
+
 func NewDBConfig(ctx context.Context, dbPrefix string) (*DBConfig, error) {
 	conf := &DBConfig{}
 	pl := envconfig.PrefixLookuper(dbPrefix, envconfig.OsLookuper())
@@ -54,3 +79,29 @@ func NewServerConfig(ctx context.Context) (*ServerConfig, error) {
 	}
 	return conf, nil
 }
+
+func NewSagaConfig(ctx context.Context) (*SagaConfig, error) {
+	conf := &SagaConfig{}
+	pl := envconfig.PrefixLookuper(sagaPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load saga config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewEventConfig(ctx context.Context) (*EventConfig, error) {
+	conf := &EventConfig{}
+	pl := envconfig.PrefixLookuper(eventPrefix, envconfig.OsLookuper())
+	if err := envconfig.ProcessWith(ctx, &envconfig.Config{
+		Target:   conf,
+		Lookuper: pl,
+	}); err != nil {
+		log.Error("Failed to load event config", log.Ferror(err))
+		return nil, err
+	}
+	return conf, nil
+}
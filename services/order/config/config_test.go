@@ -114,3 +114,105 @@ func Test_NewServerConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewSagaConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *SagaConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &SagaConfig{
+				StaleAfter:       time.Minute,
+				RecoveryInterval: 30 * time.Second,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("SAGA_STALE_AFTER", "5m")
+				t.Setenv("SAGA_RECOVERY_INTERVAL", "1m")
+			},
+			want: &SagaConfig{
+				StaleAfter:       5 * time.Minute,
+				RecoveryInterval: time.Minute,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewSagaConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_NewEventConfig(t *testing.T) {
+	ctx := context.Background()
+
+	patterns := []struct {
+		name  string
+		setup func(t *testing.T)
+		want  *EventConfig
+	}{
+		{
+			name: "default",
+			setup: func(t *testing.T) {
+				t.Helper()
+			},
+			want: &EventConfig{
+				Publisher:         "log",
+				RelayInterval:     time.Second,
+				RelayBatchSize:    100,
+				RelayLease:        time.Minute,
+				WatchPollInterval: 2 * time.Second,
+				WatchGapTimeout:   5 * time.Second,
+			},
+		},
+		{
+			name: "set env",
+			setup: func(t *testing.T) {
+				t.Helper()
+				t.Setenv("EVENT_PUBLISHER", "memory")
+				t.Setenv("EVENT_LOG_FILE", "/var/log/events.jsonl")
+				t.Setenv("EVENT_RELAY_INTERVAL", "500ms")
+				t.Setenv("EVENT_RELAY_BATCH_SIZE", "10")
+				t.Setenv("EVENT_RELAY_LEASE", "30s")
+				t.Setenv("EVENT_WATCH_POLL_INTERVAL", "5s")
+				t.Setenv("EVENT_WATCH_GAP_TIMEOUT", "10s")
+			},
+			want: &EventConfig{
+				Publisher:         "memory",
+				LogFile:           "/var/log/events.jsonl",
+				RelayInterval:     500 * time.Millisecond,
+				RelayBatchSize:    10,
+				RelayLease:        30 * time.Second,
+				WatchPollInterval: 5 * time.Second,
+				WatchGapTimeout:   10 * time.Second,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+
+			got, err := NewEventConfig(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
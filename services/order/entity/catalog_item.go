@@ -1,26 +1,68 @@
 package entity
 
 import (
-	"errors"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+// CatalogItem is an item as the catalog prices it in the currency of an order.
+// Conversion is set when Price was converted from the base price of the item.
+// An item with variants is ordered as one of them.
 type CatalogItem struct {
-	ID    string  `json:"id" db:"id"`
-	Name  string  `json:"name" db:"name"`
-	Price float64 `json:"price" db:"price"`
+	ID         string           `json:"id" db:"id"`
+	Name       string           `json:"name" db:"name"`
+	Price      Money            `json:"price" db:"price"`
+	Conversion *PriceConversion `json:"conversion,omitempty" db:"-"`
+	Variants   []Variant        `json:"variants,omitempty" db:"-"`
 }
 
-func NewCatalogItem(id, name string, price float64) (*CatalogItem, error) {
+// Variant is a SKU of a catalog item. Price is nil when the variant is sold at the price of its item,
+// and Conversion is set when Price was converted from the price override of the variant.
+type Variant struct {
+	SKU        string            `json:"sku"`
+	Attributes map[string]string `json:"attributes"`
+	Price      *Money            `json:"price,omitempty"`
+	Conversion *PriceConversion  `json:"conversion,omitempty"`
+}
+
+// Label describes the variant by its attributes, sorted by name, e.g. "color: red, size: M".
+func (v *Variant) Label() string {
+	names := make([]string, 0, len(v.Attributes))
+	for name := range v.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+": "+v.Attributes[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Variant returns the variant of the item with sku.
+func (i *CatalogItem) Variant(sku string) (*Variant, bool) {
+	for j := range i.Variants {
+		if i.Variants[j].SKU == sku {
+			return &i.Variants[j], true
+		}
+	}
+	return nil, false
+}
+
+func NewCatalogItem(id, name string, price Money) (*CatalogItem, error) {
 	if id == "" {
 		id = uuid.New().String()
 	}
 	if name == "" {
-		return nil, errors.New("name is required")
+		return nil, NewError(ErrInvalidArgument, "name is required")
+	}
+	if err := price.Validate(); err != nil {
+		return nil, err
 	}
-	if price <= 0 {
-		return nil, errors.New("price must be greater than 0")
+	if !price.IsPositive() {
+		return nil, NewError(ErrInvalidArgument, "price must be greater than 0")
 	}
 	return &CatalogItem{
 		ID:    id,
@@ -19,7 +19,7 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 		arg  struct {
 			id    string
 			name  string
-			price float64
+			price Money
 		}
 		want struct {
 			item *CatalogItem
@@ -31,11 +31,11 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				id:    catalogItemID,
 				name:  "item",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -44,7 +44,7 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 				item: &CatalogItem{
 					ID:    catalogItemID,
 					Name:  "item",
-					Price: 100,
+					Price: Money{Amount: 10000, Currency: "USD"},
 				},
 				err: nil,
 			},
@@ -54,10 +54,10 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				name:  "item",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -66,7 +66,7 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 				item: &CatalogItem{
 					ID:    uuid.New().String(),
 					Name:  "item",
-					Price: 100,
+					Price: Money{Amount: 10000, Currency: "USD"},
 				},
 				err: nil,
 			},
@@ -76,10 +76,10 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				name:  "",
-				price: 100,
+				price: Money{Amount: 10000, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -94,10 +94,10 @@ func TestEntity_NewCatalogItem(t *testing.T) {
 			arg: struct {
 				id    string
 				name  string
-				price float64
+				price Money
 			}{
 				name:  "item",
-				price: -1,
+				price: Money{Amount: -100, Currency: "USD"},
 			},
 			want: struct {
 				item *CatalogItem
@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
@@ -14,6 +14,8 @@ type Customer struct {
 	Street  string `json:"street" db:"street"`
 	City    string `json:"city" db:"city"`
 	Country string `json:"country" db:"country"`
+	// DeletedAt is set while the customer is in the trash, from which they cannot place orders.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 func NewCustomer(id, name, email, street, city, country string) (*Customer, error) {
@@ -22,23 +24,23 @@ func NewCustomer(id, name, email, street, city, country string) (*Customer, erro
 	}
 	if name == "" {
 		log.Error("name is required")
-		return nil, errors.New("name is required")
+		return nil, NewError(ErrInvalidArgument, "name is required")
 	}
 	if email == "" {
 		log.Error("email is required")
-		return nil, errors.New("email is required")
+		return nil, NewError(ErrInvalidArgument, "email is required")
 	}
 	if street == "" {
 		log.Error("street is required")
-		return nil, errors.New("street is required")
+		return nil, NewError(ErrInvalidArgument, "street is required")
 	}
 	if city == "" {
 		log.Error("city is required")
-		return nil, errors.New("city is required")
+		return nil, NewError(ErrInvalidArgument, "city is required")
 	}
 	if country == "" {
 		log.Error("country is required")
-		return nil, errors.New("country is required")
+		return nil, NewError(ErrInvalidArgument, "country is required")
 	}
 	return &Customer{
 		ID:      id,
@@ -0,0 +1,50 @@
+package entity
+
+import "errors"
+
+// Domain error kinds. Entities and repositories return errors that match one of these
+// kinds with errors.Is, so that the gateway can translate them into status codes
+// without knowing where they were produced.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrConflict           = errors.New("conflict")
+	ErrFailedPrecondition = errors.New("failed precondition")
+)
+
+// Error is a domain error of a given kind. Its message is reported as is,
+// while errors.Is matches both the kind and the wrapped cause.
+type Error struct {
+	kind error
+	msg  string
+	err  error
+}
+
+func NewError(kind error, msg string) error {
+	return &Error{
+		kind: kind,
+		msg:  msg,
+	}
+}
+
+// WrapError attaches a kind to err, keeping err as the cause.
+func WrapError(kind error, msg string, err error) error {
+	return &Error{
+		kind: kind,
+		msg:  msg,
+		err:  err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Is(target error) bool {
+	return e.kind == target
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
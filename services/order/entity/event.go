@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventTypeOrderPlaced        EventType = "OrderPlaced"
+	EventTypeOrderStatusChanged EventType = "OrderStatusChanged"
+	EventTypeOrderCancelled     EventType = "OrderCancelled"
+	EventTypeOrderDeleted       EventType = "OrderDeleted"
+)
+
+// Event is a change of the orders published to other services. Events are stored in the outbox
+// by the transaction making the change and published afterwards in the order of Sequence,
+// which is assigned when they are stored.
+type Event struct {
+	ID          string          `json:"id"`
+	Sequence    int64           `json:"sequence"`
+	Type        EventType       `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+func NewEvent(eventType EventType, aggregateID string, payload interface{}, now time.Time) (*Event, error) {
+	if eventType == "" {
+		return nil, NewError(ErrInvalidArgument, "event type is required")
+	}
+	if aggregateID == "" {
+		return nil, NewError(ErrInvalidArgument, "aggregate id is required")
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, WrapError(ErrInvalidArgument, "payload cannot be encoded", err)
+	}
+	return &Event{
+		ID:          uuid.New().String(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     b,
+		OccurredAt:  now,
+	}, nil
+}
+
+// Deletion is the payload of the events of deleted aggregates.
+type Deletion struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,88 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_NewEvent(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	patterns := []struct {
+		name string
+		arg  struct {
+			eventType   EventType
+			aggregateID string
+			payload     interface{}
+		}
+		want    string
+		wantErr error
+	}{
+		{
+			name: "success",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeOrderCancelled,
+				aggregateID: orderID,
+				payload:     OrderStatusHistory{OrderID: orderID, FromStatus: OrderStatusConfirmed, ToStatus: OrderStatusCancelled, ChangedAt: now},
+			},
+			want:    `{"order_id":"` + orderID + `","from_status":"confirmed","to_status":"cancelled","changed_at":"2024-01-02T03:04:05Z"}`,
+			wantErr: nil,
+		},
+		{
+			name: "Fail: aggregate id is required",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType: EventTypeOrderDeleted,
+				payload:   Deletion{ID: orderID},
+			},
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name: "Fail: payload cannot be encoded",
+			arg: struct {
+				eventType   EventType
+				aggregateID string
+				payload     interface{}
+			}{
+				eventType:   EventTypeOrderPlaced,
+				aggregateID: orderID,
+				payload:     make(chan int),
+			},
+			wantErr: ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event, err := NewEvent(tt.arg.eventType, tt.arg.aggregateID, tt.arg.payload, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if event.ID == "" || event.Type != tt.arg.eventType || event.AggregateID != tt.arg.aggregateID || !event.OccurredAt.Equal(now) {
+					t.Errorf("NewEvent() = %v", event)
+				}
+				if !json.Valid(event.Payload) || string(event.Payload) != tt.want {
+					t.Errorf("NewEvent() payload = %s, want %s", event.Payload, tt.want)
+				}
+			}
+		})
+	}
+}
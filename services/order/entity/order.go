@@ -1,7 +1,7 @@
 package entity
 
 import (
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,20 +13,90 @@ type Order struct {
 	CustomerID string       `json:"customer_id"`
 	OrderDate  *time.Time   `json:"order_date"`
 	OrderLines []*OrderLine `json:"order_lines"`
-	TotalPrice float64      `json:"total_price"`
+	TotalPrice Money        `json:"total_price"`
+	Status     OrderStatus  `json:"status"`
 }
 
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusConfirmed OrderStatus = "confirmed"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+var ErrInvalidStatusTransition = NewError(ErrFailedPrecondition, "invalid order status transition")
+
+// orderStatusTransitions lists the statuses an order may move to from each status.
+// Delivered and cancelled orders are final.
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:   {OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusConfirmed: {OrderStatusPaid, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:   {OrderStatusDelivered},
+	OrderStatusDelivered: {},
+	OrderStatusCancelled: {},
+}
+
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	status := OrderStatus(s)
+	if _, ok := orderStatusTransitions[status]; !ok {
+		return "", NewError(ErrInvalidArgument, fmt.Sprintf("unknown order status: %q", s))
+	}
+	return status, nil
+}
+
+// NextStatuses returns the statuses the order may legally move to.
+func (s OrderStatus) NextStatuses() []OrderStatus {
+	return orderStatusTransitions[s]
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, status := range orderStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderStatusHistory records a single status change of an order.
+// FromStatus is empty for the entry written when the order is created.
+type OrderStatusHistory struct {
+	OrderID    string      `json:"order_id"`
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	ChangedAt  time.Time   `json:"changed_at"`
+}
+
+// OrderLine keeps a snapshot of the item name and unit price taken when the order
+// was placed, so later catalog changes do not alter historical orders. Conversion is
+// set when the unit price was converted from the base price of the item, and records
+// the rate it was converted at so that the price can be reproduced. SKU is the variant
+// of the item ordered, and is empty for items without variants.
 type OrderLine struct {
-	Count         int    `json:"count"`
-	CatalogItemID string `json:"catalog_item_id"`
+	Count         int              `json:"count"`
+	CatalogItemID string           `json:"catalog_item_id"`
+	SKU           string           `json:"sku,omitempty"`
+	ItemName      string           `json:"item_name"`
+	UnitPrice     Money            `json:"unit_price"`
+	Conversion    *PriceConversion `json:"conversion,omitempty"`
 }
 
+var (
+	ErrVariantNotFound = NewError(ErrNotFound, "variant not found")
+	ErrVariantRequired = NewError(ErrFailedPrecondition, "variant is required")
+)
+
 func NewOrder(id, customerID string, orderDate *time.Time, orderLines []*OrderLine) (*Order, error) {
 	if id == "" {
 		id = uuid.New().String()
 	}
 	if customerID == "" {
-		return nil, errors.New("customerID is required")
+		return nil, NewError(ErrInvalidArgument, "customerID is required")
 	}
 	if orderDate == nil {
 		orderDate = new(time.Time)
@@ -36,29 +106,97 @@ func NewOrder(id, customerID string, orderDate *time.Time, orderLines []*OrderLi
 		CustomerID: customerID,
 		OrderDate:  orderDate,
 		OrderLines: orderLines,
+		Status:     OrderStatusPending,
 	}
 
-	// order.TotalPrice = order.GetTotalPrice()
+	if err := order.UpdateTotalPrice(); err != nil {
+		return nil, err
+	}
 	return order, nil
 }
 
-func NewOrderLine(count int, itemID string) (*OrderLine, error) {
+func NewOrderLine(count int, itemID, sku string) (*OrderLine, error) {
 	if count <= 0 {
-		return nil, errors.New("count must be greater than 0")
+		return nil, NewError(ErrInvalidArgument, "count must be greater than 0")
 	}
 	if itemID == "" {
-		return nil, errors.New("catalogItemID is required")
+		return nil, NewError(ErrInvalidArgument, "catalogItemID is required")
 	}
 	return &OrderLine{
 		Count:         count,
 		CatalogItemID: itemID,
+		SKU:           sku,
 	}, nil
 }
 
-// func (o *Order) GetTotalPrice() float64 {
-// 	var total float64
-// 	for _, ol := range o.OrderLines {
-// 		total += ol.CatalogItem.Price * float64(ol.Count)
-// 	}
-// 	return total
-// }
+// TransitionTo moves the order to the next status if the transition is legal
+// and returns the history entry describing the change.
+func (o *Order) TransitionTo(next OrderStatus, at time.Time) (*OrderStatusHistory, error) {
+	if !o.Status.CanTransitionTo(next) {
+		return nil, WrapError(ErrFailedPrecondition, fmt.Sprintf("invalid order status transition: %s -> %s", o.Status, next), ErrInvalidStatusTransition)
+	}
+	history := &OrderStatusHistory{
+		OrderID:    o.ID,
+		FromStatus: o.Status,
+		ToStatus:   next,
+		ChangedAt:  at,
+	}
+	o.Status = next
+	return history, nil
+}
+
+// SetItemSnapshot records the name and price of the item at the time of ordering. The line of a variant
+// is named after the item and the variant, and priced at the price override of the variant if it has one.
+// An item with variants can only be ordered as one of them.
+func (ol *OrderLine) SetItemSnapshot(item CatalogItem) error {
+	ol.ItemName = item.Name
+	ol.UnitPrice = item.Price
+	ol.Conversion = item.Conversion
+	if ol.SKU == "" {
+		if len(item.Variants) > 0 {
+			return fmt.Errorf("%w: %s", ErrVariantRequired, item.ID)
+		}
+		return nil
+	}
+
+	variant, ok := item.Variant(ol.SKU)
+	if !ok {
+		return fmt.Errorf("%w: %s of %s", ErrVariantNotFound, ol.SKU, item.ID)
+	}
+	ol.ItemName = fmt.Sprintf("%s (%s)", item.Name, variant.Label())
+	if variant.Price != nil {
+		ol.UnitPrice = *variant.Price
+		ol.Conversion = variant.Conversion
+	}
+	return nil
+}
+
+func (ol *OrderLine) Subtotal() Money {
+	return ol.UnitPrice.Mul(int64(ol.Count))
+}
+
+// GetTotalPrice sums the subtotals of the lines exactly in the minor unit of their currency,
+// so the total is never rounded. Lines in different currencies cannot be summed.
+func (o *Order) GetTotalPrice() (Money, error) {
+	var total Money
+	for i, ol := range o.OrderLines {
+		if i == 0 {
+			total.Currency = ol.UnitPrice.Currency
+		}
+		var err error
+		if total, err = total.Add(ol.Subtotal()); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// UpdateTotalPrice sets the total price to the sum of the lines.
+func (o *Order) UpdateTotalPrice() error {
+	total, err := o.GetTotalPrice()
+	if err != nil {
+		return err
+	}
+	o.TotalPrice = total
+	return nil
+}
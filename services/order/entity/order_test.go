@@ -0,0 +1,355 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+func TestEntity_NewOrder(t *testing.T) {
+	t.Parallel()
+
+	customerID := uuid.New().String()
+	orderDate := time.Now()
+
+	orderLines := []*OrderLine{
+		{
+			Count:         2,
+			CatalogItemID: uuid.New().String(),
+			ItemName:      "item1",
+			UnitPrice:     Money{Amount: 1000, Currency: "USD"},
+		},
+		{
+			Count:         1,
+			CatalogItemID: uuid.New().String(),
+			ItemName:      "item2",
+			UnitPrice:     Money{Amount: 500, Currency: "USD"},
+		},
+	}
+
+	order, err := NewOrder("", customerID, &orderDate, orderLines)
+	if err != nil {
+		t.Fatalf("NewOrder() error = %v", err)
+	}
+	if want := (Money{Amount: 2500, Currency: "USD"}); order.TotalPrice != want {
+		t.Errorf("NewOrder() TotalPrice = %v, want %v", order.TotalPrice, want)
+	}
+	if order.Status != OrderStatusPending {
+		t.Errorf("NewOrder() Status = %v, want %v", order.Status, OrderStatusPending)
+	}
+
+	if _, err = NewOrder("", "", &orderDate, orderLines); err == nil {
+		t.Errorf("NewOrder() error = nil, want error for empty customerID")
+	}
+}
+
+func TestEntity_Order_GetTotalPrice(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name       string
+		orderLines []*OrderLine
+		want       Money
+		wantErr    error
+	}{
+		{
+			name: "success: amounts that are inexact as floats add up exactly",
+			orderLines: []*OrderLine{
+				{Count: 3, UnitPrice: Money{Amount: 10, Currency: "USD"}},
+				{Count: 1, UnitPrice: Money{Amount: 20, Currency: "USD"}},
+			},
+			want: Money{Amount: 50, Currency: "USD"},
+		},
+		{
+			name: "success: no order lines",
+			want: Money{},
+		},
+		{
+			name: "Fail: order lines in different currencies",
+			orderLines: []*OrderLine{
+				{Count: 1, UnitPrice: Money{Amount: 1000, Currency: "USD"}},
+				{Count: 1, UnitPrice: Money{Amount: 1000, Currency: "EUR"}},
+			},
+			wantErr: ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order := &Order{OrderLines: tt.orderLines}
+			got, err := order.GetTotalPrice()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTotalPrice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetTotalPrice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntity_Order_TransitionTo(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	now := time.Now()
+
+	patterns := []struct {
+		name string
+		arg  struct {
+			from OrderStatus
+			to   OrderStatus
+		}
+		want struct {
+			history *OrderStatusHistory
+			status  OrderStatus
+			err     error
+		}
+	}{
+		{
+			name: "success: pending to confirmed",
+			arg: struct {
+				from OrderStatus
+				to   OrderStatus
+			}{
+				from: OrderStatusPending,
+				to:   OrderStatusConfirmed,
+			},
+			want: struct {
+				history *OrderStatusHistory
+				status  OrderStatus
+				err     error
+			}{
+				history: &OrderStatusHistory{
+					OrderID:    orderID,
+					FromStatus: OrderStatusPending,
+					ToStatus:   OrderStatusConfirmed,
+					ChangedAt:  now,
+				},
+				status: OrderStatusConfirmed,
+				err:    nil,
+			},
+		},
+		{
+			name: "success: paid to cancelled",
+			arg: struct {
+				from OrderStatus
+				to   OrderStatus
+			}{
+				from: OrderStatusPaid,
+				to:   OrderStatusCancelled,
+			},
+			want: struct {
+				history *OrderStatusHistory
+				status  OrderStatus
+				err     error
+			}{
+				history: &OrderStatusHistory{
+					OrderID:    orderID,
+					FromStatus: OrderStatusPaid,
+					ToStatus:   OrderStatusCancelled,
+					ChangedAt:  now,
+				},
+				status: OrderStatusCancelled,
+				err:    nil,
+			},
+		},
+		{
+			name: "Fail: pending to shipped",
+			arg: struct {
+				from OrderStatus
+				to   OrderStatus
+			}{
+				from: OrderStatusPending,
+				to:   OrderStatusShipped,
+			},
+			want: struct {
+				history *OrderStatusHistory
+				status  OrderStatus
+				err     error
+			}{
+				history: nil,
+				status:  OrderStatusPending,
+				err:     ErrInvalidStatusTransition,
+			},
+		},
+		{
+			name: "Fail: shipped to cancelled",
+			arg: struct {
+				from OrderStatus
+				to   OrderStatus
+			}{
+				from: OrderStatusShipped,
+				to:   OrderStatusCancelled,
+			},
+			want: struct {
+				history *OrderStatusHistory
+				status  OrderStatus
+				err     error
+			}{
+				history: nil,
+				status:  OrderStatusShipped,
+				err:     ErrInvalidStatusTransition,
+			},
+		},
+		{
+			name: "Fail: cancelled is final",
+			arg: struct {
+				from OrderStatus
+				to   OrderStatus
+			}{
+				from: OrderStatusCancelled,
+				to:   OrderStatusPending,
+			},
+			want: struct {
+				history *OrderStatusHistory
+				status  OrderStatus
+				err     error
+			}{
+				history: nil,
+				status:  OrderStatusCancelled,
+				err:     ErrInvalidStatusTransition,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order := &Order{ID: orderID, Status: tt.arg.from}
+
+			history, err := order.TransitionTo(tt.arg.to, now)
+			if !errors.Is(err, tt.want.err) {
+				t.Errorf("TransitionTo() error = %v, wantErr %v", err, tt.want.err)
+			}
+			if d := cmp.Diff(tt.want.history, history); len(d) != 0 {
+				t.Errorf("differs: (-want +got)\n%s", d)
+			}
+			if order.Status != tt.want.status {
+				t.Errorf("TransitionTo() status = %v, want %v", order.Status, tt.want.status)
+			}
+		})
+	}
+}
+
+func TestEntity_ParseOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	patterns := []struct {
+		name    string
+		arg     string
+		want    OrderStatus
+		wantErr bool
+	}{
+		{
+			name: "success",
+			arg:  "shipped",
+			want: OrderStatusShipped,
+		},
+		{
+			name:    "Fail: unknown status",
+			arg:     "lost",
+			wantErr: true,
+		},
+		{
+			name:    "Fail: empty status",
+			arg:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseOrderStatus(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseOrderStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOrderStatus() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntity_OrderLine_SetItemSnapshot(t *testing.T) {
+	t.Parallel()
+
+	itemID := uuid.New().String()
+	item := CatalogItem{
+		ID:    itemID,
+		Name:  "Shirt",
+		Price: Money{Amount: 1000, Currency: "USD"},
+		Variants: []Variant{
+			{SKU: "SHIRT-M-RED", Attributes: map[string]string{"size": "M", "color": "red"}},
+			{SKU: "SHIRT-XL-RED", Attributes: map[string]string{"size": "XL", "color": "red"}, Price: &Money{Amount: 1200, Currency: "USD"}},
+		},
+	}
+
+	patterns := []struct {
+		name      string
+		item      CatalogItem
+		sku       string
+		wantName  string
+		wantPrice Money
+		wantErr   error
+	}{
+		{
+			name:      "success: item without variants",
+			item:      CatalogItem{ID: itemID, Name: "Mug", Price: Money{Amount: 500, Currency: "USD"}},
+			wantName:  "Mug",
+			wantPrice: Money{Amount: 500, Currency: "USD"},
+		},
+		{
+			name:      "success: variant at the price of the item",
+			item:      item,
+			sku:       "SHIRT-M-RED",
+			wantName:  "Shirt (color: red, size: M)",
+			wantPrice: Money{Amount: 1000, Currency: "USD"},
+		},
+		{
+			name:      "success: variant with a price override",
+			item:      item,
+			sku:       "SHIRT-XL-RED",
+			wantName:  "Shirt (color: red, size: XL)",
+			wantPrice: Money{Amount: 1200, Currency: "USD"},
+		},
+		{
+			name:    "Fail: unknown sku",
+			item:    item,
+			sku:     "SHIRT-S-RED",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "Fail: item with variants ordered without a sku",
+			item:    item,
+			wantErr: ErrFailedPrecondition,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ol := &OrderLine{Count: 1, CatalogItemID: itemID, SKU: tt.sku}
+			err := ol.SetItemSnapshot(tt.item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetItemSnapshot() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (ol.ItemName != tt.wantName || ol.UnitPrice != tt.wantPrice) {
+				t.Errorf("SetItemSnapshot() got = %v %v, want %v %v", ol.ItemName, ol.UnitPrice, tt.wantName, tt.wantPrice)
+			}
+		})
+	}
+}
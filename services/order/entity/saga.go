@@ -0,0 +1,103 @@
+package entity
+
+import "time"
+
+type SagaStatus string
+
+const (
+	SagaStatusRunning      SagaStatus = "running"
+	SagaStatusCompensating SagaStatus = "compensating"
+	SagaStatusCompleted    SagaStatus = "completed"
+	SagaStatusCompensated  SagaStatus = "compensated"
+)
+
+type SagaStep string
+
+const (
+	SagaStepValidateCustomer SagaStep = "validate_customer"
+	SagaStepReserveStock     SagaStep = "reserve_stock"
+	SagaStepPersistOrder     SagaStep = "persist_order"
+	SagaStepConfirmOrder     SagaStep = "confirm_order"
+)
+
+// OrderPlacementSteps are the steps of placing an order, in the order they run.
+// Compensations run in the reverse order.
+var OrderPlacementSteps = []SagaStep{
+	SagaStepValidateCustomer,
+	SagaStepReserveStock,
+	SagaStepPersistOrder,
+	SagaStepConfirmOrder,
+}
+
+// Saga is the persisted state of an order placement, identified by the ID of the order.
+// While running, Step is the next step to run; while compensating, it is the next step to undo.
+type Saga struct {
+	ID        string
+	Status    SagaStatus
+	Step      SagaStep
+	Order     Order
+	Reason    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewOrderPlacementSaga(order Order, now time.Time) *Saga {
+	return &Saga{
+		ID:        order.ID,
+		Status:    SagaStatusRunning,
+		Step:      OrderPlacementSteps[0],
+		Order:     order,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// IsFinished reports whether nothing is left to run or to undo.
+func (s *Saga) IsFinished() bool {
+	return s.Status == SagaStatusCompleted || s.Status == SagaStatusCompensated
+}
+
+// Advance records that the current step has run and moves to the next one.
+// The saga is completed after the last step.
+func (s *Saga) Advance(now time.Time) {
+	i := stepIndex(s.Step)
+	if i == len(OrderPlacementSteps)-1 {
+		s.Status = SagaStatusCompleted
+	} else {
+		s.Step = OrderPlacementSteps[i+1]
+	}
+	s.UpdatedAt = now
+}
+
+// Fail records that the current step failed for reason. The failed step is assumed to have
+// had no effect, so compensation starts from the step before it.
+func (s *Saga) Fail(reason string, now time.Time) {
+	s.Status = SagaStatusCompensating
+	s.Reason = reason
+	s.rewind(now)
+}
+
+// Compensated records that the current step has been undone and moves to the step before it.
+// The saga is compensated once the first step has been undone.
+func (s *Saga) Compensated(now time.Time) {
+	s.rewind(now)
+}
+
+func (s *Saga) rewind(now time.Time) {
+	i := stepIndex(s.Step)
+	if i <= 0 {
+		s.Status = SagaStatusCompensated
+	} else {
+		s.Step = OrderPlacementSteps[i-1]
+	}
+	s.UpdatedAt = now
+}
+
+func stepIndex(step SagaStep) int {
+	for i, s := range OrderPlacementSteps {
+		if s == step {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEntity_Saga(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	patterns := []struct {
+		name string
+		run  func(s *Saga)
+		want struct {
+			status SagaStatus
+			step   SagaStep
+		}
+	}{
+		{
+			name: "success: new saga starts with the first step",
+			run:  func(s *Saga) {},
+			want: struct {
+				status SagaStatus
+				step   SagaStep
+			}{
+				status: SagaStatusRunning,
+				step:   SagaStepValidateCustomer,
+			},
+		},
+		{
+			name: "success: saga is completed after the last step",
+			run: func(s *Saga) {
+				for range OrderPlacementSteps {
+					s.Advance(now)
+				}
+			},
+			want: struct {
+				status SagaStatus
+				step   SagaStep
+			}{
+				status: SagaStatusCompleted,
+				step:   SagaStepConfirmOrder,
+			},
+		},
+		{
+			name: "success: compensation starts before the failed step",
+			run: func(s *Saga) {
+				s.Advance(now)
+				s.Advance(now)
+				s.Fail("connection refused", now)
+			},
+			want: struct {
+				status SagaStatus
+				step   SagaStep
+			}{
+				status: SagaStatusCompensating,
+				step:   SagaStepReserveStock,
+			},
+		},
+		{
+			name: "success: saga failing at the first step has nothing to compensate",
+			run: func(s *Saga) {
+				s.Fail("customer not found", now)
+			},
+			want: struct {
+				status SagaStatus
+				step   SagaStep
+			}{
+				status: SagaStatusCompensated,
+				step:   SagaStepValidateCustomer,
+			},
+		},
+		{
+			name: "success: saga is compensated after the first step is undone",
+			run: func(s *Saga) {
+				s.Advance(now)
+				s.Advance(now)
+				s.Fail("connection refused", now)
+				s.Compensated(now)
+				s.Compensated(now)
+			},
+			want: struct {
+				status SagaStatus
+				step   SagaStep
+			}{
+				status: SagaStatusCompensated,
+				step:   SagaStepValidateCustomer,
+			},
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			order, err := NewOrder("", uuid.New().String(), nil, nil)
+			if err != nil {
+				t.Fatalf("NewOrder() error = %v", err)
+			}
+			saga := NewOrderPlacementSaga(*order, now.Add(-time.Hour))
+
+			tt.run(saga)
+
+			if saga.Status != tt.want.status || saga.Step != tt.want.step {
+				t.Errorf("got = %v/%v, want %v/%v", saga.Status, saga.Step, tt.want.status, tt.want.step)
+			}
+			if saga.ID != order.ID {
+				t.Errorf("ID = %v, want %v", saga.ID, order.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+
+	catalog_pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+// toOptionalMoney converts an optional amount of a request. The clients that do not set it yet
+// may send a legacy amount instead, which is in the currency prices had before they had one.
+func toOptionalMoney(m *catalog_pb.Money, legacy *float64) (*entity.Money, error) {
+	var money entity.Money
+	var err error
+	switch {
+	case m != nil:
+		money, err = entity.NewMoney(m.GetAmount(), m.GetCurrency())
+	case legacy != nil:
+		money, err = entity.MoneyFromFloat(*legacy, entity.DefaultCurrency)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &money, nil
+}
+
+func convertMoney(m entity.Money) *catalog_pb.Money {
+	return &catalog_pb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+func convertPriceConversion(c *entity.PriceConversion) *catalog_pb.PriceConversion {
+	if c == nil {
+		return nil
+	}
+	return &catalog_pb.PriceConversion{
+		BasePrice: convertMoney(c.BasePrice),
+		ExchangeRate: &catalog_pb.ExchangeRate{
+			From:      c.Rate.From,
+			To:        c.Rate.To,
+			Rate:      c.Rate.Decimal(),
+			UpdatedAt: timestamppb.New(c.Rate.UpdatedAt),
+		},
+	}
+}
@@ -2,17 +2,28 @@ package gateway
 
 import (
 	"context"
+	"strconv"
 
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
 	pb "github.com/tusmasoma/go-microservice-k8s/services/order/proto"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/usecase"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type OrderHandler interface {
+	GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error)
 	ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error)
 	GetOrderCreationResources(ctx context.Context, req *pb.GetOrderCreationResourcesRequest) (*pb.GetOrderCreationResourcesResponse, error)
 	CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.CreateOrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrderStatusRequest) (*pb.UpdateOrderStatusResponse, error)
+	CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error)
 	DeleteOrder(ctx context.Context, req *pb.DeleteOrderRequest) (*pb.DeleteOrderResponse, error)
+	WatchOrders(req *pb.WatchOrdersRequest, stream pb.OrderService_WatchOrdersServer) error
 }
 
 type orderHandler struct {
@@ -26,49 +37,83 @@ func NewOrderHandler(ouc usecase.OrderUseCase) pb.OrderServiceServer {
 	}
 }
 
-func (oh *orderHandler) ListOrders(ctx context.Context, _ *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
-	orderDetails, err := oh.ouc.ListOrders(ctx)
+func (oh *orderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	id := req.GetOrderId()
+	if id == "" {
+		log.Warn("Order ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Order ID is required")
+	}
+
+	orderDetails, err := oh.ouc.GetOrder(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err, "Failed to get order")
 	}
-	orderResponses := make([]*pb.Order, 0, len(orderDetails))
-	for _, od := range orderDetails {
-		orderLines := make([]*pb.OrderLine, 0, len(od.OrderLines))
-		for _, ol := range od.OrderLines {
-			orderLines = append(orderLines, &pb.OrderLine{
-				Item: &pb.CatalogItem{
-					Id:    ol.CatalogItem.ID,
-					Name:  ol.CatalogItem.Name,
-					Price: ol.CatalogItem.Price,
-				},
-				Count: int32(ol.Count),
-			})
+	return &pb.GetOrderResponse{
+		Order: convertOrderDetailsToOrder(orderDetails),
+	}, nil
+}
+
+func (oh *orderHandler) ListOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
+	if req.GetPageSize() < 0 {
+		log.Warn("Invalid page size", log.Fint("page_size", int(req.GetPageSize())))
+		return nil, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	}
+
+	sort, err := repository.ParseOrderSort(req.GetSort())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid order sort")
+	}
+	minTotal, err := toOptionalMoney(req.GetMinTotal(), req.LegacyMinTotal) //nolint:staticcheck // legacy_min_total is read until clients set min_total
+	if err != nil {
+		return nil, toStatusError(err, "Invalid min total")
+	}
+	maxTotal, err := toOptionalMoney(req.GetMaxTotal(), req.LegacyMaxTotal) //nolint:staticcheck // legacy_max_total is read until clients set max_total
+	if err != nil {
+		return nil, toStatusError(err, "Invalid max total")
+	}
+	if req.GetCurrency() != "" {
+		if err = entity.ValidateCurrency(req.GetCurrency()); err != nil {
+			return nil, toStatusError(err, "Invalid currency")
 		}
+	}
+	filter := repository.OrderFilter{
+		CustomerID: req.GetCustomerId(),
+		MinTotal:   minTotal,
+		MaxTotal:   maxTotal,
+		Currency:   req.GetCurrency(),
+		Sort:       sort,
+	}
+	if req.GetFrom() != nil {
+		from := req.GetFrom().AsTime()
+		filter.From = &from
+	}
+	if req.GetTo() != nil {
+		to := req.GetTo().AsTime()
+		filter.To = &to
+	}
 
-		orderResponses = append(orderResponses, &pb.Order{
-			Id: od.Order.ID,
-			Customer: &pb.Customer{
-				Id:      od.Customer.ID,
-				Name:    od.Customer.Name,
-				Email:   od.Customer.Email,
-				Street:  od.Customer.Street,
-				City:    od.Customer.City,
-				Country: od.Customer.Country,
-			},
-			OrderDate:  timestamppb.New(*od.Order.OrderDate),
-			OrderLines: orderLines,
-			TotalPrice: od.Order.TotalPrice,
-		})
+	orderDetails, info, err := oh.ouc.ListOrders(ctx, filter, repository.Page{
+		Size:  int(req.GetPageSize()),
+		Token: req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, toStatusError(err, "Failed to list orders")
+	}
+	orderResponses := make([]*pb.Order, 0, len(orderDetails))
+	for _, od := range orderDetails {
+		orderResponses = append(orderResponses, convertOrderDetailsToOrder(od))
 	}
 	return &pb.ListOrdersResponse{
-		Orders: orderResponses,
+		Orders:        orderResponses,
+		NextPageToken: info.NextToken,
+		PrevPageToken: info.PrevToken,
 	}, nil
 }
 
 func (oh *orderHandler) GetOrderCreationResources(ctx context.Context, _ *pb.GetOrderCreationResourcesRequest) (*pb.GetOrderCreationResourcesResponse, error) {
 	customers, items, err := oh.ouc.GetOrderCreationResources(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err, "Failed to get order creation resources")
 	}
 	customerResponses := make([]*pb.Customer, 0, len(customers))
 	for _, customer := range customers {
@@ -80,9 +125,10 @@ func (oh *orderHandler) GetOrderCreationResources(ctx context.Context, _ *pb.Get
 	itemResponses := make([]*pb.CatalogItem, 0, len(items))
 	for _, item := range items {
 		itemResponses = append(itemResponses, &pb.CatalogItem{
-			Id:    item.ID,
-			Name:  item.Name,
-			Price: item.Price,
+			Id:          item.ID,
+			Name:        item.Name,
+			Price:       convertMoney(item.Price),
+			LegacyPrice: item.Price.Float64(),
 		})
 	}
 	return &pb.GetOrderCreationResourcesResponse{
@@ -95,29 +141,143 @@ func (oh *orderHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderRequ
 	orderLines := make([]struct {
 		CatalogItemID string
 		Count         int
+		SKU           string
 	}, 0, len(req.GetOrderLines()))
 
 	for _, ol := range req.GetOrderLines() {
 		orderLines = append(orderLines, struct {
 			CatalogItemID string
 			Count         int
+			SKU           string
 		}{
 			CatalogItemID: ol.GetItem().GetId(),
 			Count:         int(ol.GetCount()),
+			SKU:           ol.GetSku(),
 		})
 	}
-	if err := oh.ouc.CreateOrder(ctx, &usecase.CreateOrderParams{
+	orderDetails, err := oh.ouc.CreateOrder(ctx, &usecase.CreateOrderParams{
 		CustomerID: req.GetCustomerId(),
+		Currency:   req.GetCurrency(),
 		OrderLine:  orderLines,
-	}); err != nil {
-		return nil, err
+	})
+	if err != nil {
+		return nil, toStatusError(err, "Failed to create order")
+	}
+	return &pb.CreateOrderResponse{
+		Order: convertOrderDetailsToOrder(orderDetails),
+	}, nil
+}
+
+func (oh *orderHandler) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrderStatusRequest) (*pb.UpdateOrderStatusResponse, error) {
+	id := req.GetOrderId()
+	if id == "" {
+		log.Warn("Order ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Order ID is required")
+	}
+	orderStatus, err := entity.ParseOrderStatus(req.GetStatus())
+	if err != nil {
+		return nil, toStatusError(err, "Invalid order status")
 	}
-	return &pb.CreateOrderResponse{}, nil
+
+	orderDetails, err := oh.ouc.UpdateOrderStatus(ctx, id, orderStatus)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to update order status")
+	}
+	return &pb.UpdateOrderStatusResponse{
+		Order: convertOrderDetailsToOrder(orderDetails),
+	}, nil
+}
+
+func (oh *orderHandler) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
+	id := req.GetOrderId()
+	if id == "" {
+		log.Warn("Order ID is required")
+		return nil, status.Errorf(codes.InvalidArgument, "Order ID is required")
+	}
+
+	orderDetails, err := oh.ouc.CancelOrder(ctx, id)
+	if err != nil {
+		return nil, toStatusError(err, "Failed to cancel order")
+	}
+	return &pb.CancelOrderResponse{
+		Order: convertOrderDetailsToOrder(orderDetails),
+	}, nil
+}
+
+func convertOrderDetailsToOrder(od *usecase.OrderDetails) *pb.Order {
+	orderLines := make([]*pb.OrderLine, 0, len(od.OrderLines))
+	for _, ol := range od.OrderLines {
+		orderLines = append(orderLines, &pb.OrderLine{
+			Item: &pb.CatalogItem{
+				Id:          ol.CatalogItem.ID,
+				Name:        ol.CatalogItem.Name,
+				Price:       convertMoney(ol.CatalogItem.Price),
+				LegacyPrice: ol.CatalogItem.Price.Float64(),
+				Conversion:  convertPriceConversion(ol.CatalogItem.Conversion),
+			},
+			Count: int32(ol.Count),
+			Sku:   ol.SKU,
+		})
+	}
+
+	return &pb.Order{
+		Id: od.Order.ID,
+		Customer: &pb.Customer{
+			Id:      od.Customer.ID,
+			Name:    od.Customer.Name,
+			Email:   od.Customer.Email,
+			Street:  od.Customer.Street,
+			City:    od.Customer.City,
+			Country: od.Customer.Country,
+		},
+		OrderDate:        timestamppb.New(*od.Order.OrderDate),
+		OrderLines:       orderLines,
+		TotalPrice:       convertMoney(od.Order.TotalPrice),
+		LegacyTotalPrice: od.Order.TotalPrice.Float64(),
+		Status:           string(od.Order.Status),
+		NextStatuses:     nextStatuses(od.Order.Status),
+	}
+}
+
+func nextStatuses(s entity.OrderStatus) []string {
+	statuses := make([]string, 0, len(s.NextStatuses()))
+	for _, next := range s.NextStatuses() {
+		statuses = append(statuses, string(next))
+	}
+	return statuses
 }
 
 func (oh *orderHandler) DeleteOrder(ctx context.Context, req *pb.DeleteOrderRequest) (*pb.DeleteOrderResponse, error) {
 	if err := oh.ouc.DeleteOrder(ctx, req.GetOrderId()); err != nil {
-		return nil, err
+		return nil, toStatusError(err, "Failed to delete order")
 	}
 	return &pb.DeleteOrderResponse{}, nil
 }
+
+func (oh *orderHandler) WatchOrders(req *pb.WatchOrdersRequest, stream pb.OrderService_WatchOrdersServer) error {
+	after := int64(-1)
+	if token := req.GetResumeToken(); token != "" {
+		sequence, err := strconv.ParseInt(token, 10, 64)
+		if err != nil || sequence < 0 {
+			log.Warn("Invalid resume token", log.Fstring("resume_token", token))
+			return status.Errorf(codes.InvalidArgument, "Invalid resume token")
+		}
+		after = sequence
+	}
+
+	err := oh.ouc.WatchOrders(stream.Context(), after, func(change usecase.OrderChange) error {
+		res := &pb.WatchOrdersResponse{
+			Type:        string(change.Type),
+			Id:          change.ID,
+			ResumeToken: strconv.FormatInt(change.Sequence, 10),
+		}
+		if change.Order != nil {
+			res.Order = convertOrderDetailsToOrder(change.Order)
+		}
+		return stream.Send(res)
+	})
+	if err != nil {
+		return toStatusError(err, "Failed to watch orders")
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package gateway
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net"
 	"reflect"
 	"testing"
@@ -15,13 +17,17 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/usecase"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/order/proto"
 
+	catalog_pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+
 	"github.com/tusmasoma/go-microservice-k8s/services/order/usecase/mock"
 )
 
@@ -66,6 +72,111 @@ func setupTestServer(t *testing.T, setup func(m *mock.MockOrderUseCase)) (pb.Ord
 	return client, cleanup
 }
 
+func newTestOrderDetails(orderID, customerID string) *usecase.OrderDetails {
+	date := time.Now()
+	return &usecase.OrderDetails{
+		Order: &entity.Order{
+			ID:         orderID,
+			CustomerID: customerID,
+			OrderDate:  &date,
+			Status:     entity.OrderStatusPending,
+		},
+		Customer: &entity.Customer{
+			ID:   customerID,
+			Name: "John Doe",
+		},
+	}
+}
+
+func TestHandler_GetOrder(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+
+	orderDetails := newTestOrderDetails(orderID, uuid.New().String())
+	orderDetails.OrderLines = []*usecase.OrderLineDetails{
+		{
+			Count: 2,
+			CatalogItem: &entity.CatalogItem{
+				ID:    uuid.New().String(),
+				Name:  "item",
+				Price: entity.Money{Amount: 1000, Currency: "USD"},
+			},
+		},
+	}
+	orderDetails.Order.TotalPrice = entity.Money{Amount: 2000, Currency: "USD"}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockOrderUseCase,
+		)
+		request    *pb.GetOrderRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().GetOrder(
+					gomock.Any(),
+					orderID,
+				).Return(orderDetails, nil)
+			},
+			request: &pb.GetOrderRequest{
+				OrderId: orderID,
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: order id is empty",
+			request:    &pb.GetOrderRequest{},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: order not found",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().GetOrder(
+					gomock.Any(),
+					orderID,
+				).Return(nil, entity.NewError(entity.ErrNotFound, "order not found"))
+			},
+			request: &pb.GetOrderRequest{
+				OrderId: orderID,
+			},
+			wantStatus: codes.NotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.GetOrder(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				order := resp.GetOrder()
+				if order.GetId() != orderDetails.Order.ID ||
+					order.GetCustomer().GetId() != orderDetails.Customer.ID ||
+					order.GetTotalPrice().GetAmount() != orderDetails.Order.TotalPrice.Amount {
+					t.Fatalf("handler returned wrong order data")
+				}
+				if len(order.GetOrderLines()) != 1 ||
+					order.GetOrderLines()[0].GetCount() != 2 ||
+					order.GetOrderLines()[0].GetItem().GetPrice().GetAmount() != 1000 {
+					t.Fatalf("handler returned wrong order lines: %v", order.GetOrderLines())
+				}
+			}
+		})
+	}
+}
+
 func TestHandler_ListOrders(t *testing.T) {
 	t.Parallel()
 
@@ -86,7 +197,7 @@ func TestHandler_ListOrders(t *testing.T) {
 	item := &entity.CatalogItem{
 		ID:    itemID,
 		Name:  "item",
-		Price: 1000,
+		Price: entity.Money{Amount: 1000, Currency: "USD"},
 	}
 	order := &entity.Order{
 		ID:         orderID,
@@ -98,7 +209,8 @@ func TestHandler_ListOrders(t *testing.T) {
 				CatalogItemID: itemID,
 			},
 		},
-		TotalPrice: 1000,
+		TotalPrice: entity.Money{Amount: 1000, Currency: "USD"},
+		Status:     entity.OrderStatusPending,
 	}
 
 	orderDetails := &usecase.OrderDetails{
@@ -112,6 +224,39 @@ func TestHandler_ListOrders(t *testing.T) {
 		},
 	}
 
+	wantOrder := &pb.Order{
+		Id: orderID,
+		Customer: &pb.Customer{
+			Id:      customerID,
+			Name:    "John Doe",
+			Email:   "john.doe@example.com",
+			Street:  "123 Maple Street",
+			City:    "Springfield",
+			Country: "USA",
+		},
+		OrderDate: timestamppb.New(date),
+		OrderLines: []*pb.OrderLine{
+			{
+				Item: &pb.CatalogItem{
+					Id:          itemID,
+					Name:        "item",
+					Price:       &catalog_pb.Money{Amount: 1000, Currency: "USD"},
+					LegacyPrice: 10,
+				},
+				Count: 1,
+			},
+		},
+		TotalPrice:       &catalog_pb.Money{Amount: 1000, Currency: "USD"},
+		LegacyTotalPrice: 10,
+		Status:           "pending",
+		NextStatuses:     []string{"confirmed", "cancelled"},
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	minTotal := entity.Money{Amount: 500, Currency: "USD"}
+	legacyMinTotal := 5.0
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -126,38 +271,135 @@ func TestHandler_ListOrders(t *testing.T) {
 			setup: func(ouc *mock.MockOrderUseCase) {
 				ouc.EXPECT().ListOrders(
 					gomock.Any(),
+					repository.OrderFilter{},
+					repository.Page{Size: 1},
 				).Return(
 					[]*usecase.OrderDetails{orderDetails},
+					repository.PageInfo{NextToken: "next"},
 					nil,
 				)
 			},
-			request:    &pb.ListOrdersRequest{},
+			request:    &pb.ListOrdersRequest{PageSize: 1},
 			wantStatus: codes.OK,
-			want: []*pb.Order{
-				{
-					Id: orderID,
-					Customer: &pb.Customer{
-						Id:      customerID,
-						Name:    "John Doe",
-						Email:   "john.doe@example.com",
-						Street:  "123 Maple Street",
-						City:    "Springfield",
-						Country: "USA",
-					},
-					OrderDate: timestamppb.New(date),
-					OrderLines: []*pb.OrderLine{
-						{
-							Item: &pb.CatalogItem{
-								Id:    itemID,
-								Name:  "item",
-								Price: 1000,
-							},
-							Count: 1,
-						},
+			want:       []*pb.Order{wantOrder},
+		},
+		{
+			name:       "Fail: negative page size",
+			request:    &pb.ListOrdersRequest{PageSize: -1},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid page token",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().ListOrders(
+					gomock.Any(),
+					repository.OrderFilter{},
+					repository.Page{Token: "invalid"},
+				).Return(
+					nil,
+					repository.PageInfo{},
+					repository.ErrInvalidPageToken,
+				)
+			},
+			request:    &pb.ListOrdersRequest{PageToken: "invalid"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "success: filtered and sorted",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().ListOrders(
+					gomock.Any(),
+					repository.OrderFilter{
+						CustomerID: customerID,
+						From:       &from,
+						To:         &to,
+						MinTotal:   &minTotal,
+						Sort:       repository.OrderSortOrderDateDesc,
 					},
-					TotalPrice: 1000,
-				},
+					repository.Page{},
+				).Return(
+					[]*usecase.OrderDetails{orderDetails},
+					repository.PageInfo{NextToken: "next"},
+					nil,
+				)
+			},
+			request: &pb.ListOrdersRequest{
+				CustomerId: customerID,
+				From:       timestamppb.New(from),
+				To:         timestamppb.New(to),
+				MinTotal:   &catalog_pb.Money{Amount: 500, Currency: "USD"},
+				Sort:       "-order_date",
+			},
+			wantStatus: codes.OK,
+			want:       []*pb.Order{wantOrder},
+		},
+		{
+			name: "success: legacy min total is in the default currency",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().ListOrders(
+					gomock.Any(),
+					repository.OrderFilter{MinTotal: &minTotal},
+					repository.Page{},
+				).Return(
+					[]*usecase.OrderDetails{orderDetails},
+					repository.PageInfo{NextToken: "next"},
+					nil,
+				)
 			},
+			request:    &pb.ListOrdersRequest{LegacyMinTotal: &legacyMinTotal},
+			wantStatus: codes.OK,
+			want:       []*pb.Order{wantOrder},
+		},
+		{
+			name: "success: sorted by total price within a currency",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().ListOrders(
+					gomock.Any(),
+					repository.OrderFilter{Currency: "USD", Sort: repository.OrderSortTotalPriceDesc},
+					repository.Page{},
+				).Return(
+					[]*usecase.OrderDetails{orderDetails},
+					repository.PageInfo{NextToken: "next"},
+					nil,
+				)
+			},
+			request:    &pb.ListOrdersRequest{Currency: "USD", Sort: "-total_price"},
+			wantStatus: codes.OK,
+			want:       []*pb.Order{wantOrder},
+		},
+		{
+			name:       "Fail: unsupported currency",
+			request:    &pb.ListOrdersRequest{Currency: "XXX", Sort: "-total_price"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: unsupported currency of min total",
+			request:    &pb.ListOrdersRequest{MinTotal: &catalog_pb.Money{Amount: 500, Currency: "XXX"}},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name:       "Fail: unknown sort",
+			request:    &pb.ListOrdersRequest{Sort: "customer_id"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid filter",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().ListOrders(
+					gomock.Any(),
+					repository.OrderFilter{From: &to, To: &from},
+					repository.Page{},
+				).Return(
+					nil,
+					repository.PageInfo{},
+					repository.ErrInvalidOrderFilter,
+				)
+			},
+			request: &pb.ListOrdersRequest{
+				From: timestamppb.New(to),
+				To:   timestamppb.New(from),
+			},
+			wantStatus: codes.InvalidArgument,
 		},
 	}
 
@@ -178,6 +420,9 @@ func TestHandler_ListOrders(t *testing.T) {
 				if !reflect.DeepEqual(resp.GetOrders(), tt.want) {
 					t.Errorf("handler returned wrong orders: got %v want %v", resp.GetOrders(), tt.want)
 				}
+				if resp.GetNextPageToken() != "next" {
+					t.Errorf("handler returned wrong next page token: got %v want %v", resp.GetNextPageToken(), "next")
+				}
 			}
 		})
 	}
@@ -201,7 +446,7 @@ func TestHandler_GetOrderCreationResources(t *testing.T) {
 	item := entity.CatalogItem{
 		ID:    itemID,
 		Name:  "item",
-		Price: 1000,
+		Price: entity.Money{Amount: 1000, Currency: "USD"},
 	}
 
 	patterns := []struct {
@@ -259,6 +504,8 @@ func TestHandler_CreateOrder(t *testing.T) {
 	customerID := uuid.New().String()
 	itemID := uuid.New().String()
 
+	orderDetails := newTestOrderDetails(uuid.New().String(), customerID)
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -277,6 +524,7 @@ func TestHandler_CreateOrder(t *testing.T) {
 						OrderLine: []struct {
 							CatalogItemID string
 							Count         int
+							SKU           string
 						}{
 							{
 								CatalogItemID: itemID,
@@ -284,7 +532,7 @@ func TestHandler_CreateOrder(t *testing.T) {
 							},
 						},
 					},
-				).Return(nil)
+				).Return(orderDetails, nil)
 			},
 			request: &pb.CreateOrderRequest{
 				CustomerId: customerID,
@@ -299,6 +547,168 @@ func TestHandler_CreateOrder(t *testing.T) {
 			},
 			wantStatus: codes.OK,
 		},
+		{
+			name: "Fail: invalid request",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().CreateOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).Return(nil, fmt.Errorf("%w: count must be greater than 0", usecase.ErrInvalidOrderRequest))
+			},
+			request: &pb.CreateOrderRequest{
+				CustomerId: customerID,
+				OrderLines: []*pb.OrderLine{
+					{
+						Item: &pb.CatalogItem{
+							Id: itemID,
+						},
+						Count: 1,
+					},
+				},
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: customer not found",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().CreateOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).Return(nil, fmt.Errorf("%w: %s", usecase.ErrCustomerNotFound, customerID))
+			},
+			request: &pb.CreateOrderRequest{
+				CustomerId: customerID,
+				OrderLines: []*pb.OrderLine{
+					{
+						Item: &pb.CatalogItem{
+							Id: itemID,
+						},
+						Count: 1,
+					},
+				},
+			},
+			wantStatus: codes.NotFound,
+		},
+		{
+			name: "Fail: catalog item not found",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().CreateOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).Return(nil, fmt.Errorf("%w: %s", usecase.ErrCatalogItemNotFound, itemID))
+			},
+			request: &pb.CreateOrderRequest{
+				CustomerId: customerID,
+				OrderLines: []*pb.OrderLine{
+					{
+						Item: &pb.CatalogItem{
+							Id: itemID,
+						},
+						Count: 1,
+					},
+				},
+			},
+			wantStatus: codes.NotFound,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.CreateOrder(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				if resp.GetOrder().GetId() != orderDetails.Order.ID {
+					t.Fatalf("handler returned wrong order data")
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+
+	orderDetails := newTestOrderDetails(orderID, uuid.New().String())
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockOrderUseCase,
+		)
+		request    *pb.UpdateOrderStatusRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().UpdateOrderStatus(
+					gomock.Any(),
+					orderID,
+					entity.OrderStatusShipped,
+				).Return(orderDetails, nil)
+			},
+			request: &pb.UpdateOrderStatusRequest{
+				OrderId: orderID,
+				Status:  "shipped",
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: order id is empty",
+			request: &pb.UpdateOrderStatusRequest{
+				Status: "shipped",
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: unknown status",
+			request: &pb.UpdateOrderStatusRequest{
+				OrderId: orderID,
+				Status:  "lost",
+			},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: invalid transition",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().UpdateOrderStatus(
+					gomock.Any(),
+					orderID,
+					entity.OrderStatusPending,
+				).Return(nil, entity.ErrInvalidStatusTransition)
+			},
+			request: &pb.UpdateOrderStatusRequest{
+				OrderId: orderID,
+				Status:  "pending",
+			},
+			wantStatus: codes.FailedPrecondition,
+		},
+		{
+			name: "Fail: status changed concurrently",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().UpdateOrderStatus(
+					gomock.Any(),
+					orderID,
+					entity.OrderStatusPaid,
+				).Return(nil, repository.ErrStatusConflict)
+			},
+			request: &pb.UpdateOrderStatusRequest{
+				OrderId: orderID,
+				Status:  "paid",
+			},
+			wantStatus: codes.Aborted,
+		},
 	}
 
 	for _, tt := range patterns {
@@ -309,10 +719,81 @@ func TestHandler_CreateOrder(t *testing.T) {
 			client, cleanup := setupTestServer(t, tt.setup)
 			defer cleanup()
 
-			_, err := client.CreateOrder(context.Background(), tt.request)
+			resp, err := client.UpdateOrderStatus(context.Background(), tt.request)
 			if status.Code(err) != tt.wantStatus {
 				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
 			}
+
+			if tt.wantStatus == codes.OK {
+				if resp.GetOrder().GetId() != orderDetails.Order.ID {
+					t.Fatalf("handler returned wrong order data")
+				}
+			}
+		})
+	}
+}
+
+func TestHandler_CancelOrder(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+
+	orderDetails := newTestOrderDetails(orderID, uuid.New().String())
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockOrderUseCase,
+		)
+		request    *pb.CancelOrderRequest
+		wantStatus codes.Code
+	}{
+		{
+			name: "success",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().CancelOrder(
+					gomock.Any(),
+					orderID,
+				).Return(orderDetails, nil)
+			},
+			request: &pb.CancelOrderRequest{
+				OrderId: orderID,
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "Fail: already shipped",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().CancelOrder(
+					gomock.Any(),
+					orderID,
+				).Return(nil, entity.ErrInvalidStatusTransition)
+			},
+			request: &pb.CancelOrderRequest{
+				OrderId: orderID,
+			},
+			wantStatus: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			resp, err := client.CancelOrder(context.Background(), tt.request)
+			if status.Code(err) != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+			}
+
+			if tt.wantStatus == codes.OK {
+				if resp.GetOrder().GetId() != orderDetails.Order.ID {
+					t.Fatalf("handler returned wrong order data")
+				}
+			}
 		})
 	}
 }
@@ -360,3 +841,118 @@ func TestHandler_DeleteOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_WatchOrders(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	orderDate := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	orderDetails := &usecase.OrderDetails{
+		Order: &entity.Order{
+			ID:         orderID,
+			OrderDate:  &orderDate,
+			TotalPrice: entity.Money{Amount: 1000, Currency: "USD"},
+			Status:     entity.OrderStatusShipped,
+		},
+		Customer: &entity.Customer{
+			ID:   uuid.New().String(),
+			Name: "customer1",
+		},
+	}
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockOrderUseCase,
+		)
+		request    *pb.WatchOrdersRequest
+		want       []*pb.WatchOrdersResponse
+		wantStatus codes.Code
+	}{
+		{
+			name: "success: changes are streamed from the resume token",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().WatchOrders(
+					gomock.Any(),
+					int64(41),
+					gomock.Any(),
+				).DoAndReturn(func(_ context.Context, _ int64, send func(usecase.OrderChange) error) error {
+					if err := send(usecase.OrderChange{Type: usecase.ChangeTypeUpdated, ID: orderID, Order: orderDetails, Sequence: 42}); err != nil {
+						return err
+					}
+					return send(usecase.OrderChange{Type: usecase.ChangeTypeDeleted, ID: orderID, Sequence: 43})
+				})
+			},
+			request: &pb.WatchOrdersRequest{ResumeToken: "41"},
+			want: []*pb.WatchOrdersResponse{
+				{Type: "updated", Id: orderID, Order: convertOrderDetailsToOrder(orderDetails), ResumeToken: "42"},
+				{Type: "deleted", Id: orderID, ResumeToken: "43"},
+			},
+			wantStatus: codes.OK,
+		},
+		{
+			name: "success: changes are watched from now on without a resume token",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().WatchOrders(gomock.Any(), int64(-1), gomock.Any()).Return(nil)
+			},
+			request:    &pb.WatchOrdersRequest{},
+			want:       nil,
+			wantStatus: codes.OK,
+		},
+		{
+			name:       "Fail: invalid resume token",
+			request:    &pb.WatchOrdersRequest{ResumeToken: "-5"},
+			wantStatus: codes.InvalidArgument,
+		},
+		{
+			name: "Fail: internal error",
+			setup: func(ouc *mock.MockOrderUseCase) {
+				ouc.EXPECT().WatchOrders(gomock.Any(), int64(-1), gomock.Any()).Return(errors.New("connection refused"))
+			},
+			request:    &pb.WatchOrdersRequest{},
+			wantStatus: codes.Internal,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, cleanup := setupTestServer(t, tt.setup)
+			defer cleanup()
+
+			stream, err := client.WatchOrders(context.Background(), tt.request)
+			if err != nil {
+				t.Fatalf("failed to open stream: %v", err)
+			}
+
+			var got []*pb.WatchOrdersResponse
+			for {
+				res, err := stream.Recv() //nolint:govet // err shadowed
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					if status.Code(err) != tt.wantStatus {
+						t.Fatalf("handler returned wrong status code: got %v want %v", status.Code(err), tt.wantStatus)
+					}
+					return
+				}
+				got = append(got, res)
+			}
+			if tt.wantStatus != codes.OK {
+				t.Fatalf("handler returned wrong status code: got %v want %v", codes.OK, tt.wantStatus)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("handler streamed %d changes, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("handler streamed %v, want %v", got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -13,6 +13,7 @@ require (
 	github.com/stretchr/testify v1.9.0
 	github.com/tusmasoma/go-microservice-k8s/services/catalog v0.0.0-20240909075020-3aaa6e21f967
 	github.com/tusmasoma/go-microservice-k8s/services/customer v0.0.0-20240909075020-3aaa6e21f967
+	github.com/tusmasoma/go-microservice-k8s/services/idempotency v0.0.0-00010101000000-000000000000
 	github.com/tusmasoma/go-tech-dojo v0.0.0-20240805120803-02e31d5c8a21
 	go.uber.org/dig v1.18.0
 	google.golang.org/grpc v1.66.0
@@ -43,3 +44,9 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240604185151-ef581f913117 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace (
+	github.com/tusmasoma/go-microservice-k8s/services/catalog => ../catalog
+	github.com/tusmasoma/go-microservice-k8s/services/customer => ../customer
+	github.com/tusmasoma/go-microservice-k8s/services/idempotency => ../idempotency
+)
@@ -7,12 +7,14 @@ import (
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
 )
 
+// CatalogItemRepository reads and writes the items of the catalog. ListByIDs prices the items in
+// currency, or at their base price when it is empty.
 type CatalogItemRepository interface {
 	Get(ctx context.Context, id string) (*entity.CatalogItem, error)
 	List(ctx context.Context) ([]entity.CatalogItem, error)
 	ListByName(ctx context.Context, name string) ([]entity.CatalogItem, error)
-	ListByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error)
-	Create(ctx context.Context, item entity.CatalogItem) error
-	Update(ctx context.Context, item entity.CatalogItem) error
+	ListByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error)
+	Create(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error)
+	Update(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error)
 	Delete(ctx context.Context, id string) error
 }
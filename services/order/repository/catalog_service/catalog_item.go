@@ -9,6 +9,9 @@ import (
 	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
 )
 
+// listPageSize is the page size used to walk through all catalog items in List.
+const listPageSize = 100
+
 type catalogItemRepository struct {
 	client pb.CatalogServiceClient
 }
@@ -25,11 +28,7 @@ func (r *catalogItemRepository) Get(ctx context.Context, id string) (*entity.Cat
 		return nil, err
 	}
 
-	item, err := entity.NewCatalogItem(
-		resp.GetItem().GetId(),
-		resp.GetItem().GetName(),
-		resp.GetItem().GetPrice(),
-	)
+	item, err := newCatalogItem(resp.GetItem())
 	if err != nil {
 		return nil, err
 	}
@@ -38,25 +37,30 @@ func (r *catalogItemRepository) Get(ctx context.Context, id string) (*entity.Cat
 }
 
 func (r *catalogItemRepository) List(ctx context.Context) ([]entity.CatalogItem, error) {
-	resp, err := r.client.ListCatalogItems(ctx, &pb.ListCatalogItemsRequest{})
-	if err != nil {
-		return nil, err
-	}
-
-	items := make([]entity.CatalogItem, 0, len(resp.GetItems()))
-	for _, i := range resp.GetItems() {
-		item, err := entity.NewCatalogItem(
-			i.GetId(),
-			i.GetName(),
-			i.GetPrice(),
-		)
+	var items []entity.CatalogItem
+	var pageToken string
+	for {
+		resp, err := r.client.ListCatalogItems(ctx, &pb.ListCatalogItemsRequest{
+			PageSize:  listPageSize,
+			PageToken: pageToken,
+		})
 		if err != nil {
 			return nil, err
 		}
-		items = append(items, *item)
-	}
 
-	return items, nil
+		for _, i := range resp.GetItems() {
+			item, err := newCatalogItem(i)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, *item)
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			return items, nil
+		}
+	}
 }
 
 func (r *catalogItemRepository) ListByName(ctx context.Context, name string) ([]entity.CatalogItem, error) {
@@ -67,11 +71,7 @@ func (r *catalogItemRepository) ListByName(ctx context.Context, name string) ([]
 
 	items := make([]entity.CatalogItem, 0, len(resp.GetItems()))
 	for _, i := range resp.GetItems() {
-		item, err := entity.NewCatalogItem(
-			i.GetId(),
-			i.GetName(),
-			i.GetPrice(),
-		)
+		item, err := newCatalogItem(i)
 		if err != nil {
 			return nil, err
 		}
@@ -81,19 +81,15 @@ func (r *catalogItemRepository) ListByName(ctx context.Context, name string) ([]
 	return items, nil
 }
 
-func (r *catalogItemRepository) ListByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
-	resp, err := r.client.ListCatalogItemsByIDs(ctx, &pb.ListCatalogItemsByIDsRequest{Ids: ids})
+func (r *catalogItemRepository) ListByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error) {
+	resp, err := r.client.ListCatalogItemsByIDs(ctx, &pb.ListCatalogItemsByIDsRequest{Ids: ids, Currency: currency})
 	if err != nil {
 		return nil, err
 	}
 
 	items := make([]entity.CatalogItem, 0, len(resp.GetItems()))
 	for _, i := range resp.GetItems() {
-		item, err := entity.NewCatalogItem(
-			i.GetId(),
-			i.GetName(),
-			i.GetPrice(),
-		)
+		item, err := newCatalogItem(i)
 		if err != nil {
 			return nil, err
 		}
@@ -103,25 +99,29 @@ func (r *catalogItemRepository) ListByIDs(ctx context.Context, ids []string) ([]
 	return items, nil
 }
 
-func (r *catalogItemRepository) Create(ctx context.Context, item entity.CatalogItem) error {
-	if _, err := r.client.CreateCatalogItem(ctx, &pb.CreateCatalogItemRequest{
+func (r *catalogItemRepository) Create(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error) {
+	resp, err := r.client.CreateCatalogItem(ctx, &pb.CreateCatalogItemRequest{
 		Name:  item.Name,
-		Price: item.Price,
-	}); err != nil {
-		return err
+		Price: convertMoney(item.Price),
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	return newCatalogItem(resp.GetItem())
 }
 
-func (r *catalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) error {
-	if _, err := r.client.UpdateCatalogItem(ctx, &pb.UpdateCatalogItemRequest{
+func (r *catalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error) {
+	resp, err := r.client.UpdateCatalogItem(ctx, &pb.UpdateCatalogItemRequest{
 		Id:    item.ID,
 		Name:  item.Name,
-		Price: item.Price,
-	}); err != nil {
-		return err
+		Price: convertMoney(item.Price),
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	return newCatalogItem(resp.GetItem())
 }
 
 func (r *catalogItemRepository) Delete(ctx context.Context, id string) error {
@@ -130,3 +130,78 @@ func (r *catalogItemRepository) Delete(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// newCatalogItem converts an item of the catalog service, whose price is read from legacy_price
+// if the service does not set price yet.
+func newCatalogItem(i *pb.CatalogItem) (*entity.CatalogItem, error) {
+	price := entity.Money{Amount: i.GetPrice().GetAmount(), Currency: i.GetPrice().GetCurrency()}
+	if i.GetPrice() == nil {
+		legacy, err := entity.MoneyFromFloat(i.GetLegacyPrice(), entity.DefaultCurrency) //nolint:staticcheck // legacy_price is read until the catalog service sets price
+		if err != nil {
+			return nil, err
+		}
+		price = legacy
+	}
+	item, err := entity.NewCatalogItem(i.GetId(), i.GetName(), price)
+	if err != nil {
+		return nil, err
+	}
+	if c := i.GetConversion(); c != nil {
+		if item.Conversion, err = newPriceConversion(c); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range i.GetVariants() {
+		variant, verr := newVariant(v)
+		if verr != nil {
+			return nil, verr
+		}
+		item.Variants = append(item.Variants, *variant)
+	}
+	return item, nil
+}
+
+func newVariant(v *pb.Variant) (*entity.Variant, error) {
+	variant := &entity.Variant{
+		SKU:        v.GetSku(),
+		Attributes: v.GetAttributes(),
+	}
+	if p := v.GetPrice(); p != nil {
+		price, err := entity.NewMoney(p.GetAmount(), p.GetCurrency())
+		if err != nil {
+			return nil, err
+		}
+		variant.Price = &price
+	}
+	if c := v.GetConversion(); c != nil {
+		conversion, err := newPriceConversion(c)
+		if err != nil {
+			return nil, err
+		}
+		variant.Conversion = conversion
+	}
+	return variant, nil
+}
+
+func newPriceConversion(c *pb.PriceConversion) (*entity.PriceConversion, error) {
+	basePrice, err := entity.NewMoney(c.GetBasePrice().GetAmount(), c.GetBasePrice().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	r := c.GetExchangeRate()
+	rate, err := entity.NewExchangeRate(r.GetFrom(), r.GetTo(), r.GetRate(), r.GetUpdatedAt().AsTime())
+	if err != nil {
+		return nil, err
+	}
+	return &entity.PriceConversion{
+		BasePrice: basePrice,
+		Rate:      *rate,
+	}, nil
+}
+
+func convertMoney(m entity.Money) *pb.Money {
+	return &pb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
@@ -0,0 +1,67 @@
+package catalogservice
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+
+	pb "github.com/tusmasoma/go-microservice-k8s/services/catalog/proto"
+)
+
+type stockRepository struct {
+	client pb.CatalogServiceClient
+}
+
+func NewStockRepository(client pb.CatalogServiceClient) repository.StockRepository {
+	return &stockRepository{
+		client,
+	}
+}
+
+func (r *stockRepository) Reserve(ctx context.Context, reservationID string, orderLines []*entity.OrderLine) error {
+	items := make([]*pb.StockQuantity, 0, len(orderLines))
+	for _, ol := range orderLines {
+		items = append(items, &pb.StockQuantity{
+			CatalogItemId: ol.CatalogItemID,
+			Sku:           ol.SKU,
+			Quantity:      int32(ol.Count),
+		})
+	}
+
+	_, err := r.client.ReserveStock(ctx, &pb.ReserveStockRequest{
+		ReservationId: reservationID,
+		Items:         items,
+	})
+	return translateStockError(err)
+}
+
+func (r *stockRepository) Release(ctx context.Context, reservationID string) error {
+	_, err := r.client.ReleaseStock(ctx, &pb.ReleaseStockRequest{ReservationId: reservationID})
+	return translateStockError(err)
+}
+
+func (r *stockRepository) Commit(ctx context.Context, reservationID string) error {
+	_, err := r.client.CommitStock(ctx, &pb.CommitStockRequest{ReservationId: reservationID})
+	return translateStockError(err)
+}
+
+// translateStockError maps the status codes reported by the catalog service onto domain error kinds,
+// keeping the message of the catalog service, e.g. which item is out of stock.
+func translateStockError(err error) error {
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return entity.WrapError(entity.ErrNotFound, status.Convert(err).Message(), err)
+	case codes.FailedPrecondition:
+		return entity.WrapError(entity.ErrFailedPrecondition, status.Convert(err).Message(), err)
+	case codes.AlreadyExists:
+		return entity.WrapError(entity.ErrAlreadyExists, status.Convert(err).Message(), err)
+	default:
+		return err
+	}
+}
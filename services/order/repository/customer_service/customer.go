@@ -3,12 +3,18 @@ package customerservice
 import (
 	"context"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 
 	pb "github.com/tusmasoma/go-microservice-k8s/services/customer/proto"
 )
 
+// listPageSize is the page size used to walk through all customers in List.
+const listPageSize = 100
+
 type customerRepository struct {
 	client pb.CustomerServiceClient
 }
@@ -19,8 +25,13 @@ func NewCustomerRepository(client pb.CustomerServiceClient) repository.CustomerR
 	}
 }
 
+// Get gets the customer even if they are in the trash, since the orders they placed still refer to them.
+// A customer in the trash has DeletedAt set.
 func (r *customerRepository) Get(ctx context.Context, id string) (*entity.Customer, error) {
-	resp, err := r.client.GetCustomer(ctx, &pb.GetCustomerRequest{Id: id})
+	resp, err := r.client.GetCustomer(ctx, &pb.GetCustomerRequest{Id: id, ShowDeleted: true})
+	if status.Code(err) == codes.NotFound {
+		return nil, entity.WrapError(entity.ErrNotFound, "customer not found", err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -36,12 +47,56 @@ func (r *customerRepository) Get(ctx context.Context, id string) (*entity.Custom
 	if err != nil {
 		return nil, err
 	}
+	setDeletedAt(customer, resp.GetCustomer())
 
 	return customer, nil
 }
 
+// setDeletedAt marks the customer as in the trash if c is.
+func setDeletedAt(customer *entity.Customer, c *pb.Customer) {
+	if c.GetDeletedAt() != nil {
+		deletedAt := c.GetDeletedAt().AsTime()
+		customer.DeletedAt = &deletedAt
+	}
+}
+
 func (r *customerRepository) List(ctx context.Context) ([]entity.Customer, error) {
-	resp, err := r.client.ListCustomers(ctx, &pb.ListCustomersRequest{})
+	var customers []entity.Customer
+	var pageToken string
+	for {
+		resp, err := r.client.ListCustomers(ctx, &pb.ListCustomersRequest{
+			PageSize:  listPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range resp.GetCustomers() {
+			customer, err := entity.NewCustomer(
+				c.GetId(),
+				c.GetName(),
+				c.GetEmail(),
+				c.GetStreet(),
+				c.GetCity(),
+				c.GetCountry(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			customers = append(customers, *customer)
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			return customers, nil
+		}
+	}
+}
+
+// ListByIDs lists the customers including those in the trash, like Get.
+func (r *customerRepository) ListByIDs(ctx context.Context, ids []string) ([]entity.Customer, error) {
+	resp, err := r.client.ListCustomersByIDs(ctx, &pb.ListCustomersByIDsRequest{Ids: ids, ShowDeleted: true})
 	if err != nil {
 		return nil, err
 	}
@@ -59,37 +114,56 @@ func (r *customerRepository) List(ctx context.Context) ([]entity.Customer, error
 		if err != nil {
 			return nil, err
 		}
+		setDeletedAt(customer, c)
 		customers = append(customers, *customer)
 	}
 
 	return customers, nil
 }
 
-func (r *customerRepository) Create(ctx context.Context, customer entity.Customer) error {
-	if _, err := r.client.CreateCustomer(ctx, &pb.CreateCustomerRequest{
+func (r *customerRepository) Create(ctx context.Context, customer entity.Customer) (*entity.Customer, error) {
+	resp, err := r.client.CreateCustomer(ctx, &pb.CreateCustomerRequest{
 		Name:    customer.Name,
 		Email:   customer.Email,
 		Street:  customer.Street,
 		City:    customer.City,
 		Country: customer.Country,
-	}); err != nil {
-		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	return entity.NewCustomer(
+		resp.GetCustomer().GetId(),
+		resp.GetCustomer().GetName(),
+		resp.GetCustomer().GetEmail(),
+		resp.GetCustomer().GetStreet(),
+		resp.GetCustomer().GetCity(),
+		resp.GetCustomer().GetCountry(),
+	)
 }
 
-func (r *customerRepository) Update(ctx context.Context, customer entity.Customer) error {
-	if _, err := r.client.UpdateCustomer(ctx, &pb.UpdateCustomerRequest{
+func (r *customerRepository) Update(ctx context.Context, customer entity.Customer) (*entity.Customer, error) {
+	resp, err := r.client.UpdateCustomer(ctx, &pb.UpdateCustomerRequest{
 		Id:      customer.ID,
 		Name:    customer.Name,
 		Email:   customer.Email,
 		Street:  customer.Street,
 		City:    customer.City,
 		Country: customer.Country,
-	}); err != nil {
-		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	return entity.NewCustomer(
+		resp.GetCustomer().GetId(),
+		resp.GetCustomer().GetName(),
+		resp.GetCustomer().GetEmail(),
+		resp.GetCustomer().GetStreet(),
+		resp.GetCustomer().GetCity(),
+		resp.GetCustomer().GetCountry(),
+	)
 }
 
 func (r *customerRepository) Delete(ctx context.Context, id string) error {
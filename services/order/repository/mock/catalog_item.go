@@ -36,11 +36,12 @@ func (m *MockCatalogItemRepository) EXPECT() *MockCatalogItemRepositoryMockRecor
 }
 
 // Create mocks base method.
-func (m *MockCatalogItemRepository) Create(ctx context.Context, item entity.CatalogItem) error {
+func (m *MockCatalogItemRepository) Create(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, item)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
@@ -94,18 +95,18 @@ func (mr *MockCatalogItemRepositoryMockRecorder) List(ctx interface{}) *gomock.C
 }
 
 // ListByIDs mocks base method.
-func (m *MockCatalogItemRepository) ListByIDs(ctx context.Context, ids []string) ([]entity.CatalogItem, error) {
+func (m *MockCatalogItemRepository) ListByIDs(ctx context.Context, ids []string, currency string) ([]entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListByIDs", ctx, ids)
+	ret := m.ctrl.Call(m, "ListByIDs", ctx, ids, currency)
 	ret0, _ := ret[0].([]entity.CatalogItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListByIDs indicates an expected call of ListByIDs.
-func (mr *MockCatalogItemRepositoryMockRecorder) ListByIDs(ctx, ids interface{}) *gomock.Call {
+func (mr *MockCatalogItemRepositoryMockRecorder) ListByIDs(ctx, ids, currency interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIDs", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListByIDs), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByIDs", reflect.TypeOf((*MockCatalogItemRepository)(nil).ListByIDs), ctx, ids, currency)
 }
 
 // ListByName mocks base method.
@@ -124,11 +125,12 @@ func (mr *MockCatalogItemRepositoryMockRecorder) ListByName(ctx, name interface{
 }
 
 // Update mocks base method.
-func (m *MockCatalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) error {
+func (m *MockCatalogItemRepository) Update(ctx context.Context, item entity.CatalogItem) (*entity.CatalogItem, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Update", ctx, item)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*entity.CatalogItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 )
 
 // MockOrderRepository is a mock of OrderRepository interface.
@@ -79,16 +80,31 @@ func (mr *MockOrderRepositoryMockRecorder) Get(ctx, id interface{}) *gomock.Call
 }
 
 // List mocks base method.
-func (m *MockOrderRepository) List(ctx context.Context) ([]*entity.Order, error) {
+func (m *MockOrderRepository) List(ctx context.Context, filter repository.OrderFilter, page repository.Page) ([]*entity.Order, repository.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
+	ret := m.ctrl.Call(m, "List", ctx, filter, page)
 	ret0, _ := ret[0].([]*entity.Order)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // List indicates an expected call of List.
-func (mr *MockOrderRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockOrderRepositoryMockRecorder) List(ctx, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrderRepository)(nil).List), ctx, filter, page)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdateStatus(ctx, history interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrderRepository)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdateStatus), ctx, history)
 }
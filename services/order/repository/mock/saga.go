@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: saga.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+// MockSagaRepository is a mock of SagaRepository interface.
+type MockSagaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSagaRepositoryMockRecorder
+}
+
+// MockSagaRepositoryMockRecorder is the mock recorder for MockSagaRepository.
+type MockSagaRepositoryMockRecorder struct {
+	mock *MockSagaRepository
+}
+
+// NewMockSagaRepository creates a new mock instance.
+func NewMockSagaRepository(ctrl *gomock.Controller) *MockSagaRepository {
+	mock := &MockSagaRepository{ctrl: ctrl}
+	mock.recorder = &MockSagaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSagaRepository) EXPECT() *MockSagaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockSagaRepository) Claim(ctx context.Context, saga entity.Saga, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, saga, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockSagaRepositoryMockRecorder) Claim(ctx, saga, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockSagaRepository)(nil).Claim), ctx, saga, now)
+}
+
+// Create mocks base method.
+func (m *MockSagaRepository) Create(ctx context.Context, saga entity.Saga) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, saga)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSagaRepositoryMockRecorder) Create(ctx, saga interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSagaRepository)(nil).Create), ctx, saga)
+}
+
+// ListStale mocks base method.
+func (m *MockSagaRepository) ListStale(ctx context.Context, before time.Time) ([]entity.Saga, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, before)
+	ret0, _ := ret[0].([]entity.Saga)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MockSagaRepositoryMockRecorder) ListStale(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MockSagaRepository)(nil).ListStale), ctx, before)
+}
+
+// Update mocks base method.
+func (m *MockSagaRepository) Update(ctx context.Context, saga entity.Saga) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, saga)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSagaRepositoryMockRecorder) Update(ctx, saga interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSagaRepository)(nil).Update), ctx, saga)
+}
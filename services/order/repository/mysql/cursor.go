@@ -0,0 +1,78 @@
+package mysql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+)
+
+// cursor is the decoded form of an opaque page token.
+// Backward is set on tokens that point to the previous page. Lists sorted by a field other
+// than the ID also record the sort and the field value of the row the cursor points at.
+type cursor struct {
+	Backward bool   `json:"b,omitempty"`
+	ID       string `json:"id"`
+	Sort     string `json:"s,omitempty"`
+	Value    string `json:"v,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(token string) (*cursor, error) {
+	if token == "" {
+		return nil, nil //nolint:nilnil // an empty token means the first page
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, repository.ErrInvalidPageToken
+	}
+	var c cursor
+	if err = json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, repository.ErrInvalidPageToken
+	}
+	return &c, nil
+}
+
+// paginate trims the extra look-ahead row fetched with LIMIT limit+1 and builds the
+// cursors of the adjacent pages from the cursors key returns for the first and last rows.
+// Rows of a backward page arrive in reverse list order and are reversed here so callers
+// always receive them in list order.
+func paginate[T any](rows []T, limit int, c *cursor, key func(T) cursor) ([]T, repository.PageInfo) {
+	var info repository.PageInfo
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+
+	backward := c != nil && c.Backward
+	if backward {
+		slices.Reverse(rows)
+	}
+
+	first, last := key(rows[0]), key(rows[len(rows)-1])
+	first.Backward = true
+	if backward {
+		if hasMore {
+			info.PrevToken = encodeCursor(first)
+		}
+		info.NextToken = encodeCursor(last)
+		return rows, info
+	}
+
+	if hasMore {
+		info.NextToken = encodeCursor(last)
+	}
+	if c != nil {
+		info.PrevToken = encodeCursor(first)
+	}
+	return rows, info
+}
@@ -0,0 +1,12 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/idempotency"
+)
+
+// NewIdempotencyRepository stores the idempotency keys of the order service in OrderIdempotencyKeys.
+func NewIdempotencyRepository(db *sql.DB) idempotency.Repository {
+	return idempotency.NewMySQLRepository(db, "OrderIdempotencyKeys")
+}
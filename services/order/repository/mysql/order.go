@@ -3,6 +3,8 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
@@ -22,12 +24,76 @@ type orderModel struct {
 	ID         string    `db:"id"`
 	CustomerID string    `db:"customer_id"`
 	OrderDate  time.Time `db:"order_date"`
+	// TotalPriceAmount is in the minor unit of TotalPriceCurrency.
+	TotalPriceAmount   int64  `db:"total_price_amount"`
+	TotalPriceCurrency string `db:"total_price_currency"`
+	Status             string `db:"status"`
 }
 
 type orderLineModel struct {
-	OrderID       string `db:"order_id"`
-	CatalogItemID string `db:"catalog_item_id"`
-	Count         int    `db:"count"`
+	OrderID           string `db:"order_id"`
+	LineNumber        int    `db:"line_number"`
+	CatalogItemID     string `db:"catalog_item_id"`
+	SKU               string `db:"sku"`
+	Count             int    `db:"count"`
+	ItemName          string `db:"item_name"`
+	UnitPriceAmount   int64  `db:"unit_price_amount"`
+	UnitPriceCurrency string `db:"unit_price_currency"`
+	// The base price and exchange rate are NULL unless the unit price was converted from the base price,
+	// and the rate is from BasePriceCurrency to UnitPriceCurrency.
+	BasePriceAmount       sql.NullInt64  `db:"base_price_amount"`
+	BasePriceCurrency     sql.NullString `db:"base_price_currency"`
+	ExchangeRate          sql.NullString `db:"exchange_rate"`
+	ExchangeRateUpdatedAt sql.NullTime   `db:"exchange_rate_updated_at"`
+}
+
+func newOrderLineModel(orderID string, lineNumber int, line *entity.OrderLine) orderLineModel {
+	olm := orderLineModel{
+		OrderID:           orderID,
+		LineNumber:        lineNumber,
+		CatalogItemID:     line.CatalogItemID,
+		SKU:               line.SKU,
+		Count:             line.Count,
+		ItemName:          line.ItemName,
+		UnitPriceAmount:   line.UnitPrice.Amount,
+		UnitPriceCurrency: line.UnitPrice.Currency,
+	}
+	if c := line.Conversion; c != nil {
+		olm.BasePriceAmount = sql.NullInt64{Int64: c.BasePrice.Amount, Valid: true}
+		olm.BasePriceCurrency = sql.NullString{String: c.BasePrice.Currency, Valid: true}
+		olm.ExchangeRate = sql.NullString{String: c.Rate.Decimal(), Valid: true}
+		olm.ExchangeRateUpdatedAt = sql.NullTime{Time: c.Rate.UpdatedAt, Valid: true}
+	}
+	return olm
+}
+
+func (olm orderLineModel) toEntity() (*entity.OrderLine, error) {
+	orderLine, err := entity.NewOrderLine(olm.Count, olm.CatalogItemID, olm.SKU)
+	if err != nil {
+		return nil, err
+	}
+	orderLine.ItemName = olm.ItemName
+	orderLine.UnitPrice = entity.Money{Amount: olm.UnitPriceAmount, Currency: olm.UnitPriceCurrency}
+	if !olm.ExchangeRate.Valid {
+		return orderLine, nil
+	}
+
+	rate, err := entity.NewExchangeRate(olm.BasePriceCurrency.String, olm.UnitPriceCurrency, olm.ExchangeRate.String, olm.ExchangeRateUpdatedAt.Time)
+	if err != nil {
+		return nil, err
+	}
+	orderLine.Conversion = &entity.PriceConversion{
+		BasePrice: entity.Money{Amount: olm.BasePriceAmount.Int64, Currency: olm.BasePriceCurrency.String},
+		Rate:      *rate,
+	}
+	return orderLine, nil
+}
+
+type orderStatusHistoryModel struct {
+	OrderID    string         `db:"order_id"`
+	FromStatus sql.NullString `db:"from_status"`
+	ToStatus   string         `db:"to_status"`
+	ChangedAt  time.Time      `db:"changed_at"`
 }
 
 type orderRepository struct {
@@ -41,186 +107,375 @@ func NewOrderRepository(db *sql.DB) repository.OrderRepository {
 }
 
 func (or *orderRepository) Get(ctx context.Context, id string) (*entity.Order, error) {
-	// Orders table query
 	query := `
-	SELECT id, customer_id, order_date
+	SELECT id, customer_id, order_date, total_price_amount, total_price_currency, status
 	FROM Orders
 	WHERE id = ?
 	LIMIT 1
 	`
 
-	row := or.db.QueryRowContext(ctx, query, id)
-
-	var om orderModel
-	if err := row.Scan(
-		&om.ID,
-		&om.CustomerID,
-		&om.OrderDate,
-	); err != nil {
+	orders, err := or.listOrders(ctx, query, id)
+	if err != nil {
 		return nil, err
 	}
+	if len(orders) == 0 {
+		return nil, translateError(sql.ErrNoRows, "order not found")
+	}
+	return orders[0], nil
+}
 
-	// OrderLines table query
-	query = `
-	SELECT catalog_item_id, count
-	FROM OrderLines
-	WHERE order_id = ?
+func (or *orderRepository) List(ctx context.Context, filter repository.OrderFilter, page repository.Page) ([]*entity.Order, repository.PageInfo, error) {
+	c, err := decodeCursor(page.Token)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+	// A token is only valid for the sort it was issued for.
+	if c != nil && c.Sort != string(filter.Sort) {
+		return nil, repository.PageInfo{}, repository.ErrInvalidPageToken
+	}
+	limit := page.Limit()
+
+	column := orderSortColumns[filter.Sort.Field()]
+	descending := filter.Sort.Descending()
+	if c != nil && c.Backward {
+		descending = !descending
+	}
+	direction, comparison := `ASC`, `>`
+	if descending {
+		direction, comparison = `DESC`, `<`
+	}
+
+	conditions, args := orderFilterConditions(filter)
+	if c != nil {
+		if column == "id" {
+			conditions = append(conditions, `id `+comparison+` ?`)
+			args = append(args, c.ID)
+		} else {
+			value, verr := orderSortValue(filter.Sort, c.Value)
+			if verr != nil {
+				return nil, repository.PageInfo{}, verr
+			}
+			conditions = append(conditions, `(`+column+` `+comparison+` ? OR (`+column+` = ? AND id `+comparison+` ?))`)
+			args = append(args, value, value, c.ID)
+		}
+	}
+
+	query := `
+	SELECT id, customer_id, order_date, total_price_amount, total_price_currency, status
+	FROM Orders
 	`
+	if len(conditions) > 0 {
+		query += `WHERE ` + strings.Join(conditions, ` AND `) + ` `
+	}
+	orderBy := column + ` ` + direction
+	if column != "id" {
+		orderBy += `, id ` + direction
+	}
+	query += `ORDER BY ` + orderBy + ` LIMIT ?`
+	args = append(args, limit+1)
 
-	rows, err := or.db.QueryContext(ctx, query, id)
+	orders, err := or.listOrders(ctx, query, args...)
+	if err != nil {
+		return nil, repository.PageInfo{}, err
+	}
+
+	orders, info := paginate(orders, limit, c, func(order *entity.Order) cursor {
+		return cursor{
+			ID:    order.ID,
+			Sort:  string(filter.Sort),
+			Value: orderCursorValue(filter.Sort, order),
+		}
+	})
+	return orders, info, nil
+}
+
+// listOrders is the read path shared by Get and List. It loads the orders selected by query,
+// which must select the columns of orderModel, and then the lines of all of them in a second
+// query keyed by order ID. Orders are returned in the order of the query and their lines in
+// the order they were created, so an order without lines is returned with no lines.
+func (or *orderRepository) listOrders(ctx context.Context, query string, args ...interface{}) ([]*entity.Order, error) {
+	rows, err := or.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var olms []orderLineModel
+	var orders []*entity.Order
+	orderMap := make(map[string]*entity.Order)
 	for rows.Next() {
-		var olm orderLineModel
+		var om orderModel
 		if err = rows.Scan(
-			&olm.CatalogItemID,
-			&olm.Count,
+			&om.ID,
+			&om.CustomerID,
+			&om.OrderDate,
+			&om.TotalPriceAmount,
+			&om.TotalPriceCurrency,
+			&om.Status,
 		); err != nil {
 			return nil, err
 		}
-		olms = append(olms, olm)
+
+		order, oerr := entity.NewOrder(om.ID, om.CustomerID, &om.OrderDate, nil)
+		if oerr != nil {
+			return nil, oerr
+		}
+		order.TotalPrice = entity.Money{Amount: om.TotalPriceAmount, Currency: om.TotalPriceCurrency}
+		order.Status = entity.OrderStatus(om.Status)
+		orderMap[om.ID] = order
+		orders = append(orders, order)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
-
-	// Mapping to entity.Order and entity.OrderLine
-	orderLines := make([]*entity.OrderLine, 0, len(olms))
-	for _, line := range olms {
-		orderLine, err := entity.NewOrderLine(line.Count, line.CatalogItemID) //nolint:govet // err shadowed
-		if err != nil {
-			return nil, err
-		}
-		orderLines = append(orderLines, orderLine)
+	if len(orders) == 0 {
+		return orders, nil
 	}
 
-	order, err := entity.NewOrder(om.ID, om.CustomerID, &om.OrderDate, orderLines)
-	if err != nil {
+	if err = or.loadOrderLines(ctx, orderMap); err != nil {
 		return nil, err
 	}
-
-	return order, nil
+	return orders, nil
 }
 
-func (or *orderRepository) List(ctx context.Context) ([]*entity.Order, error) {
+// loadOrderLines attaches the lines of the orders in orderMap in one query.
+func (or *orderRepository) loadOrderLines(ctx context.Context, orderMap map[string]*entity.Order) error {
+	placeholders := make([]string, 0, len(orderMap))
+	args := make([]interface{}, 0, len(orderMap))
+	for id := range orderMap {
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
 	query := `
-	SELECT
-		Orders.id,
-		Orders.customer_id,
-		Orders.order_date,
-		OrderLines.catalog_item_id,
-		OrderLines.count
-	FROM
-   		Orders
-	INNER JOIN
-    	OrderLines ON Orders.id = OrderLines.order_id
+	SELECT order_id, catalog_item_id, sku, count, item_name, unit_price_amount, unit_price_currency,
+		base_price_amount, base_price_currency, exchange_rate, exchange_rate_updated_at
+	FROM OrderLines
+	WHERE order_id IN (` + strings.Join(placeholders, ",") + `)
+	ORDER BY order_id, line_number
 	`
 
-	rows, err := or.db.QueryContext(ctx, query)
+	rows, err := or.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var orders []*entity.Order
-	orderMap := make(map[string]*entity.Order)
-
 	for rows.Next() {
-		var om orderModel
 		var olm orderLineModel
-
 		if err = rows.Scan(
-			&om.ID,
-			&om.CustomerID,
-			&om.OrderDate,
+			&olm.OrderID,
 			&olm.CatalogItemID,
+			&olm.SKU,
 			&olm.Count,
+			&olm.ItemName,
+			&olm.UnitPriceAmount,
+			&olm.UnitPriceCurrency,
+			&olm.BasePriceAmount,
+			&olm.BasePriceCurrency,
+			&olm.ExchangeRate,
+			&olm.ExchangeRateUpdatedAt,
 		); err != nil {
-			return nil, err
+			return err
 		}
 
-		order, exists := orderMap[om.ID]
-		if !exists {
-			order, err = entity.NewOrder(om.ID, om.CustomerID, &om.OrderDate, nil)
-			if err != nil {
-				return nil, err
-			}
-			orderMap[om.ID] = order
-			orders = append(orders, order)
+		orderLine, lerr := olm.toEntity()
+		if lerr != nil {
+			return lerr
 		}
 
-		orderLine, err := entity.NewOrderLine(olm.Count, olm.CatalogItemID) //nolint:govet // err shadowed
-		if err != nil {
-			return nil, err
-		}
+		order := orderMap[olm.OrderID]
 		order.OrderLines = append(order.OrderLines, orderLine)
 	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+	return rows.Err()
+}
+
+// orderSortColumns maps the fields of repository.OrderSort to the columns of the Orders table.
+var orderSortColumns = map[string]string{
+	"":            "id",
+	"order_date":  "order_date",
+	"total_price": "total_price_amount",
+}
+
+// orderFilterConditions builds the WHERE conditions of the filter and their arguments.
+func orderFilterConditions(filter repository.OrderFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.CustomerID != "" {
+		conditions = append(conditions, `customer_id = ?`)
+		args = append(args, filter.CustomerID)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, `order_date >= ?`)
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, `order_date < ?`)
+		args = append(args, *filter.To)
+	}
+	if currency := filter.TotalCurrency(); currency != "" {
+		conditions = append(conditions, `total_price_currency = ?`)
+		args = append(args, currency)
+	}
+	if filter.MinTotal != nil {
+		conditions = append(conditions, `total_price_amount >= ?`)
+		args = append(args, filter.MinTotal.Amount)
+	}
+	if filter.MaxTotal != nil {
+		conditions = append(conditions, `total_price_amount <= ?`)
+		args = append(args, filter.MaxTotal.Amount)
 	}
+	return conditions, args
+}
 
-	return orders, nil
+// orderCursorValue returns the value of the sorted field of order as stored in a cursor.
+func orderCursorValue(sort repository.OrderSort, order *entity.Order) string {
+	switch sort.Field() {
+	case "order_date":
+		return order.OrderDate.Format(time.RFC3339Nano)
+	case "total_price":
+		return strconv.FormatInt(order.TotalPrice.Amount, 10)
+	default:
+		return ""
+	}
 }
 
-func (or *orderRepository) Create(ctx context.Context, order entity.Order) error {
-	tx, err := or.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
-	if err != nil {
-		return err
+// orderSortValue parses the value of the sorted field stored in a cursor.
+func orderSortValue(sort repository.OrderSort, value string) (interface{}, error) {
+	switch sort.Field() {
+	case "order_date":
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, repository.ErrInvalidPageToken
+		}
+		return t, nil
+	case "total_price":
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, repository.ErrInvalidPageToken
+		}
+		return amount, nil
+	default:
+		return nil, repository.ErrInvalidPageToken
 	}
+}
 
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback() //nolint:errcheck // The error is checked in the outer function.
-			panic(p)      // re-throw the panic after Rollback
-		} else if err != nil {
-			tx.Rollback() //nolint:errcheck // The error is checked in the outer function.
-		} else {
-			err = tx.Commit() // The error is checked here.
+func (or *orderRepository) Create(ctx context.Context, order entity.Order) error {
+	return or.inTx(ctx, func(tx *sql.Tx) (err error) {
+		om := orderModel{
+			ID:                 order.ID,
+			CustomerID:         order.CustomerID,
+			OrderDate:          *order.OrderDate,
+			TotalPriceAmount:   order.TotalPrice.Amount,
+			TotalPriceCurrency: order.TotalPrice.Currency,
+			Status:             string(order.Status),
 		}
-	}()
 
-	om := orderModel{
-		ID:         order.ID,
-		CustomerID: order.CustomerID,
-		OrderDate:  *order.OrderDate,
-	}
+		query := `
+		INSERT INTO Orders (id, customer_id, order_date, total_price_amount, total_price_currency, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+		`
+		if _, err = tx.ExecContext(
+			ctx,
+			query,
+			om.ID,
+			om.CustomerID,
+			om.OrderDate,
+			om.TotalPriceAmount,
+			om.TotalPriceCurrency,
+			om.Status,
+		); err != nil {
+			return translateInsertError(err, "order already exists")
+		}
 
-	query := `
-	INSERT INTO Orders (id, customer_id, order_date)
-	VALUES (?, ?, ?)
-	`
-	if _, err = tx.ExecContext(
-		ctx,
-		query,
-		om.ID,
-		om.CustomerID,
-		om.OrderDate,
-	); err != nil {
-		return err
-	}
+		// The initial status is recorded as a history entry without a previous status,
+		// at the time the order is stored rather than the date given on the order.
+		if err = insertOrderStatusHistory(ctx, tx, orderStatusHistoryModel{
+			OrderID:   om.ID,
+			ToStatus:  om.Status,
+			ChangedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
 
-	query = `
-	INSERT INTO OrderLines (order_id, catalog_item_id, count) VALUES`
-	values := make([]interface{}, 0, len(order.OrderLines)*3) //nolint:gomnd // 3 is the number of columns.
-	for i, line := range order.OrderLines {
-		if i > 0 {
-			query += ", "
+		// The line number keeps the lines in the order they were given when they are read back.
+		query = `
+		INSERT INTO OrderLines (
+			order_id, line_number, catalog_item_id, sku, count, item_name, unit_price_amount, unit_price_currency,
+			base_price_amount, base_price_currency, exchange_rate, exchange_rate_updated_at
+		) VALUES`
+		values := make([]interface{}, 0, len(order.OrderLines)*12) //nolint:gomnd // 12 is the number of columns.
+		for i, line := range order.OrderLines {
+			if i > 0 {
+				query += ", "
+			}
+			query += "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+			olm := newOrderLineModel(order.ID, i, line)
+			values = append(
+				values,
+				olm.OrderID, olm.LineNumber, olm.CatalogItemID, olm.SKU, olm.Count, olm.ItemName, olm.UnitPriceAmount, olm.UnitPriceCurrency,
+				olm.BasePriceAmount, olm.BasePriceCurrency, olm.ExchangeRate, olm.ExchangeRateUpdatedAt,
+			)
 		}
-		query += "(?, ?, ?)"
 
-		olm := orderLineModel{
-			OrderID:       order.ID,
-			CatalogItemID: line.CatalogItemID,
-			Count:         line.Count,
+		if _, err = tx.ExecContext(ctx, query, values...); err != nil {
+			return err
 		}
-		values = append(values, olm.OrderID, olm.CatalogItemID, olm.Count)
-	}
+		return nil
+	})
+}
+
+func (or *orderRepository) UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) error {
+	return or.inTx(ctx, func(tx *sql.Tx) (err error) {
+		// The current status is part of the condition so that a concurrent change
+		// made after the order was read is not silently overwritten.
+		query := `
+		UPDATE Orders SET status = ? WHERE id = ? AND status = ?
+		`
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			string(history.ToStatus),
+			history.OrderID,
+			string(history.FromStatus),
+		)
+		if err != nil {
+			return err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			err = repository.ErrStatusConflict
+			return err
+		}
+
+		if err = insertOrderStatusHistory(ctx, tx, orderStatusHistoryModel{
+			OrderID:    history.OrderID,
+			FromStatus: sql.NullString{String: string(history.FromStatus), Valid: history.FromStatus != ""},
+			ToStatus:   string(history.ToStatus),
+			ChangedAt:  history.ChangedAt,
+		}); err != nil {
+			return err
+		}
+		return nil
+	})
+}
 
-	if _, err = tx.ExecContext(ctx, query, values...); err != nil {
+func insertOrderStatusHistory(ctx context.Context, tx *sql.Tx, ohm orderStatusHistoryModel) error {
+	query := `
+	INSERT INTO OrderStatusHistory (order_id, from_status, to_status, changed_at)
+	VALUES (?, ?, ?, ?)
+	`
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		ohm.OrderID,
+		ohm.FromStatus,
+		ohm.ToStatus,
+		ohm.ChangedAt,
+	); err != nil {
 		return err
 	}
 	return nil
@@ -231,6 +486,39 @@ func (or *orderRepository) Delete(ctx context.Context, id string) error {
 	// This method is responsible for deleting both the order and its associated order lines.
 	// Although the database could handle this automatically with ON DELETE CASCADE,
 	// we are managing the deletion process here at the application level for greater flexibility.
+	return or.inTx(ctx, func(tx *sql.Tx) (err error) {
+		query := `
+		DELETE FROM OrderStatusHistory WHERE order_id = ?
+		`
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+
+		query = `
+		DELETE FROM OrderLines WHERE order_id = ?
+		`
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+
+		query = `
+		DELETE FROM Orders WHERE id = ?
+		`
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// inTx runs fn in the transaction of ctx, so that the changes are committed together with the other
+// changes of the transaction, or in a transaction of its own if ctx has none.
+func (or *orderRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	if tx := TxFromCtx(ctx); tx != nil {
+		return fn(tx)
+	}
+
 	tx, err := or.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 	if err != nil {
 		return err
@@ -247,19 +535,5 @@ func (or *orderRepository) Delete(ctx context.Context, id string) error {
 		}
 	}()
 
-	query := `
-	DELETE FROM OrderLines WHERE order_id = ?
-	`
-	if _, err = tx.ExecContext(ctx, query, id); err != nil {
-		return err
-	}
-
-	query = `
-	DELETE FROM Orders WHERE id = ?
-	`
-	if _, err = tx.ExecContext(ctx, query, id); err != nil {
-		return err
-	}
-
-	return nil
+	return fn(tx)
 }
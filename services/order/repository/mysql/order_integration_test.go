@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/uuid"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 )
 
 func Test_OrderRepository(t *testing.T) {
@@ -24,15 +26,42 @@ func Test_OrderRepository(t *testing.T) {
 		OrderLines: []*entity.OrderLine{
 			{
 				CatalogItemID: uuid.New().String(),
+				Count:         2,
+				ItemName:      "item",
+				UnitPrice:     entity.Money{Amount: 1000, Currency: "USD"},
+			},
+			// Sorts before the first line by primary key, to check that lines keep their given order.
+			{
+				CatalogItemID: "00000000-0000-0000-0000-000000000000",
+				SKU:           "ITEM0-RED",
 				Count:         1,
+				ItemName:      "item0 (color: red)",
+				UnitPrice:     entity.Money{Amount: 500, Currency: "USD"},
+				Conversion: &entity.PriceConversion{
+					BasePrice: entity.Money{Amount: 400, Currency: "GBP"},
+					Rate: entity.ExchangeRate{
+						From:      "GBP",
+						To:        "USD",
+						Rate:      125000000,
+						UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+				},
 			},
 		},
+		TotalPrice: entity.Money{Amount: 2500, Currency: "USD"},
+		Status:     entity.OrderStatusPending,
 	}
 
 	// Create
 	err := repo.Create(ctx, order)
 	ValidateErr(t, err, nil)
 
+	// An order is stored only once
+	err = repo.Create(ctx, order)
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
 	// Get
 	gotOrder, err := repo.Get(ctx, order.ID)
 	ValidateErr(t, err, nil)
@@ -40,12 +69,88 @@ func Test_OrderRepository(t *testing.T) {
 		t.Errorf("differs: (-want +got)\n%s", d)
 	}
 
+	// UpdateStatus
+	history, err := gotOrder.TransitionTo(entity.OrderStatusConfirmed, time.Now())
+	ValidateErr(t, err, nil)
+	err = repo.UpdateStatus(ctx, *history)
+	ValidateErr(t, err, nil)
+
+	gotOrder, err = repo.Get(ctx, order.ID)
+	ValidateErr(t, err, nil)
+	if gotOrder.Status != entity.OrderStatusConfirmed {
+		t.Errorf("got status %v, want %v", gotOrder.Status, entity.OrderStatusConfirmed)
+	}
+
+	// Applying the same transition again must not overwrite the newer status.
+	err = repo.UpdateStatus(ctx, *history)
+	ValidateErr(t, err, repository.ErrStatusConflict)
+
 	// List
-	gotOrders, err := repo.List(ctx)
+	gotOrders, info, err := repo.List(ctx, repository.OrderFilter{}, repository.Page{})
 	ValidateErr(t, err, nil)
 	if len(gotOrders) != 1 {
 		t.Errorf("got %d orders, want 1", len(gotOrders))
 	}
+	if info.NextToken != "" || info.PrevToken != "" {
+		t.Errorf("got page info %+v, want no adjacent pages", info)
+	}
+
+	_, _, err = repo.List(ctx, repository.OrderFilter{}, repository.Page{Token: "invalid"})
+	ValidateErr(t, err, repository.ErrInvalidPageToken)
+
+	// List with filters
+	minTotal, maxTotal := entity.Money{Amount: 2000, Currency: "USD"}, entity.Money{Amount: 2500, Currency: "USD"}
+	gotOrders, _, err = repo.List(ctx, repository.OrderFilter{
+		CustomerID: order.CustomerID,
+		MinTotal:   &minTotal,
+		MaxTotal:   &maxTotal,
+		Sort:       repository.OrderSortOrderDateDesc,
+	}, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotOrders) != 1 || gotOrders[0].ID != order.ID {
+		t.Errorf("got %v, want the created order", gotOrders)
+	}
+
+	// Totals are only sorted within a currency.
+	gotOrders, _, err = repo.List(ctx, repository.OrderFilter{Currency: "USD", Sort: repository.OrderSortTotalPriceDesc}, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotOrders) != 1 || gotOrders[0].ID != order.ID {
+		t.Errorf("got %v, want the created order", gotOrders)
+	}
+	gotOrders, _, err = repo.List(ctx, repository.OrderFilter{Currency: "EUR", Sort: repository.OrderSortTotalPrice}, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotOrders) != 0 {
+		t.Errorf("got %d orders, want 0", len(gotOrders))
+	}
+
+	from := orderDate.Add(time.Hour)
+	gotOrders, _, err = repo.List(ctx, repository.OrderFilter{From: &from}, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotOrders) != 0 {
+		t.Errorf("got %d orders, want 0", len(gotOrders))
+	}
+
+	// An order whose lines are missing is still read by both Get and List.
+	emptyOrderID := uuid.New().String()
+	_, err = db.ExecContext(ctx, `
+	INSERT INTO Orders (id, customer_id, order_date, total_price_amount, total_price_currency, status)
+	VALUES (?, ?, ?, ?, ?, ?)
+	`, emptyOrderID, order.CustomerID, orderDate, 0, "USD", string(entity.OrderStatusPending))
+	ValidateErr(t, err, nil)
+
+	gotOrder, err = repo.Get(ctx, emptyOrderID)
+	ValidateErr(t, err, nil)
+	if len(gotOrder.OrderLines) != 0 {
+		t.Errorf("got %d order lines, want 0", len(gotOrder.OrderLines))
+	}
+	gotOrders, _, err = repo.List(ctx, repository.OrderFilter{}, repository.Page{})
+	ValidateErr(t, err, nil)
+	if len(gotOrders) != 2 {
+		t.Errorf("got %d orders, want 2", len(gotOrders))
+	}
+
+	err = repo.Delete(ctx, emptyOrderID)
+	ValidateErr(t, err, nil)
 
 	// Delete
 	err = repo.Delete(ctx, order.ID)
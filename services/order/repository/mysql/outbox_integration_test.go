@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+func Test_OutboxRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOutboxRepository(db)
+	tr := NewTransactionRepository(db)
+
+	now := time.Now().Truncate(time.Second)
+	orderID := uuid.New().String()
+	created, err := entity.NewEvent(entity.EventTypeOrderPlaced, orderID, entity.Order{ID: orderID, Status: entity.OrderStatusConfirmed}, now)
+	ValidateErr(t, err, nil)
+	deleted, err := entity.NewEvent(entity.EventTypeOrderDeleted, orderID, entity.Deletion{ID: orderID}, now)
+	ValidateErr(t, err, nil)
+
+	// Events added by a rolled back transaction are not stored
+	errRollback := errors.New("rollback")
+	err = tr.Transaction(ctx, func(ctx context.Context) error {
+		if aerr := repo.Add(ctx, *created); aerr != nil {
+			return aerr
+		}
+		return errRollback
+	})
+	ValidateErr(t, err, errRollback)
+
+	events, err := repo.ClaimUnpublished(ctx, uuid.New().String(), 10, now, now.Add(time.Minute))
+	ValidateErr(t, err, nil)
+	if len(events) != 0 {
+		t.Errorf("want: 0, got: %d", len(events))
+	}
+
+	// Add and ClaimUnpublished
+	err = tr.Transaction(ctx, func(ctx context.Context) error {
+		return repo.Add(ctx, *created, *deleted)
+	})
+	ValidateErr(t, err, nil)
+
+	claimID := uuid.New().String()
+	events, err = repo.ClaimUnpublished(ctx, claimID, 10, now, now.Add(time.Minute))
+	ValidateErr(t, err, nil)
+	if len(events) != 2 {
+		t.Fatalf("want: 2, got: %d", len(events))
+	}
+	if events[0].ID != created.ID || events[1].ID != deleted.ID || events[0].Sequence >= events[1].Sequence {
+		t.Errorf("unexpected order: %v", events)
+	}
+	if events[0].Type != created.Type || events[0].AggregateID != orderID || !events[0].OccurredAt.Equal(now) {
+		t.Errorf("want: %v, got: %v", created, events[0])
+	}
+
+	// Claimed events are not claimed by another claim until the claim expires
+	events, err = repo.ClaimUnpublished(ctx, uuid.New().String(), 10, now, now.Add(time.Minute))
+	ValidateErr(t, err, nil)
+	if len(events) != 0 {
+		t.Errorf("want: 0, got: %d", len(events))
+	}
+
+	// An event is added only once
+	err = repo.Add(ctx, *created)
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
+	// MarkPublished and ReleaseClaim
+	err = repo.MarkPublished(ctx, created.ID, now)
+	ValidateErr(t, err, nil)
+	err = repo.ReleaseClaim(ctx, claimID)
+	ValidateErr(t, err, nil)
+
+	claimID = uuid.New().String()
+	events, err = repo.ClaimUnpublished(ctx, claimID, 10, now, now.Add(time.Minute))
+	ValidateErr(t, err, nil)
+	if len(events) != 1 || events[0].ID != deleted.ID {
+		t.Errorf("unexpected events: %v", events)
+	}
+
+	// An expired claim is claimed again
+	events, err = repo.ClaimUnpublished(ctx, uuid.New().String(), 10, now.Add(time.Minute), now.Add(2*time.Minute))
+	ValidateErr(t, err, nil)
+	if len(events) != 1 || events[0].ID != deleted.ID {
+		t.Errorf("unexpected events: %v", events)
+	}
+
+	// ListAfter and LastSequence
+	last, err := repo.LastSequence(ctx)
+	ValidateErr(t, err, nil)
+
+	events, err = repo.ListAfter(ctx, last-2, 10)
+	ValidateErr(t, err, nil)
+	if len(events) != 2 || events[0].ID != created.ID || events[1].Sequence != last {
+		t.Errorf("unexpected events: %v", events)
+	}
+
+	events, err = repo.ListAfter(ctx, last-2, 1)
+	ValidateErr(t, err, nil)
+	if len(events) != 1 || events[0].ID != created.ID {
+		t.Errorf("unexpected events: %v", events)
+	}
+}
@@ -0,0 +1,167 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+)
+
+type sagaRepository struct {
+	db SQLExecutor
+}
+
+func NewSagaRepository(db *sql.DB) repository.SagaRepository {
+	return &sagaRepository{
+		db: db,
+	}
+}
+
+func (sr *sagaRepository) Create(ctx context.Context, saga entity.Saga) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// The order being placed is stored with the saga, so that a saga can be resumed
+	// before the order itself has been persisted.
+	payload, err := json.Marshal(saga.Order)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO OrderSagas (id, status, step, payload, reason, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	if _, err = executor.ExecContext(
+		ctx,
+		query,
+		saga.ID,
+		string(saga.Status),
+		string(saga.Step),
+		payload,
+		saga.Reason,
+		saga.CreatedAt,
+		saga.UpdatedAt,
+	); err != nil {
+		return translateInsertError(err, "saga already exists")
+	}
+	return nil
+}
+
+func (sr *sagaRepository) Update(ctx context.Context, saga entity.Saga) error {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	payload, err := json.Marshal(saga.Order)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	UPDATE OrderSagas
+	SET status = ?, step = ?, payload = ?, reason = ?, updated_at = ?
+	WHERE id = ?
+	`
+
+	if _, err = executor.ExecContext(
+		ctx,
+		query,
+		string(saga.Status),
+		string(saga.Step),
+		payload,
+		saga.Reason,
+		saga.UpdatedAt,
+		saga.ID,
+	); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (sr *sagaRepository) ListStale(ctx context.Context, before time.Time) ([]entity.Saga, error) {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	query := `
+	SELECT id, status, step, payload, reason, created_at, updated_at
+	FROM OrderSagas
+	WHERE status IN (?, ?) AND updated_at < ?
+	ORDER BY updated_at
+	`
+
+	rows, err := executor.QueryContext(
+		ctx,
+		query,
+		string(entity.SagaStatusRunning),
+		string(entity.SagaStatusCompensating),
+		before,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []entity.Saga
+	for rows.Next() {
+		var saga entity.Saga
+		var status, step string
+		var payload []byte
+		if err = rows.Scan(
+			&saga.ID,
+			&status,
+			&step,
+			&payload,
+			&saga.Reason,
+			&saga.CreatedAt,
+			&saga.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(payload, &saga.Order); err != nil {
+			return nil, err
+		}
+		saga.Status = entity.SagaStatus(status)
+		saga.Step = entity.SagaStep(step)
+		sagas = append(sagas, saga)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sagas, nil
+}
+
+func (sr *sagaRepository) Claim(ctx context.Context, saga entity.Saga, now time.Time) (bool, error) {
+	executor := sr.db
+	if tx := TxFromCtx(ctx); tx != nil {
+		executor = tx
+	}
+
+	// The update time read by ListStale is part of the condition, so that only one
+	// of the replicas listing the same saga takes it over.
+	query := `
+	UPDATE OrderSagas
+	SET updated_at = ?
+	WHERE id = ? AND updated_at = ?
+	`
+
+	res, err := executor.ExecContext(ctx, query, now, saga.ID, saga.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
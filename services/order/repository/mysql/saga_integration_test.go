@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+func Test_SagaRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSagaRepository(db)
+
+	orderDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	order, err := entity.NewOrder("", uuid.New().String(), &orderDate, []*entity.OrderLine{
+		{Count: 2, CatalogItemID: uuid.New().String(), ItemName: "item1", UnitPrice: entity.Money{Amount: 1000, Currency: "USD"}},
+	})
+	ValidateErr(t, err, nil)
+
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	saga := entity.NewOrderPlacementSaga(*order, createdAt)
+
+	// Create
+	err = repo.Create(ctx, *saga)
+	ValidateErr(t, err, nil)
+
+	// A saga is stored only once
+	err = repo.Create(ctx, *saga)
+	if !errors.Is(err, entity.ErrAlreadyExists) {
+		t.Errorf("want: %v, got: %v", entity.ErrAlreadyExists, err)
+	}
+
+	// ListStale
+	sagas, err := repo.ListStale(ctx, time.Now())
+	ValidateErr(t, err, nil)
+	if len(sagas) != 1 {
+		t.Fatalf("want: 1, got: %d", len(sagas))
+	}
+	if sagas[0].ID != saga.ID || sagas[0].Status != entity.SagaStatusRunning || sagas[0].Step != entity.SagaStepValidateCustomer {
+		t.Errorf("unexpected saga: %+v", sagas[0])
+	}
+	if sagas[0].Order.CustomerID != order.CustomerID || len(sagas[0].Order.OrderLines) != 1 || sagas[0].Order.OrderLines[0].UnitPrice != (entity.Money{Amount: 1000, Currency: "USD"}) {
+		t.Errorf("unexpected order: %+v", sagas[0].Order)
+	}
+
+	sagas, err = repo.ListStale(ctx, createdAt)
+	ValidateErr(t, err, nil)
+	if len(sagas) != 0 {
+		t.Errorf("want: 0, got: %d", len(sagas))
+	}
+
+	// Claim
+	stale, err := repo.ListStale(ctx, time.Now())
+	ValidateErr(t, err, nil)
+	claimedAt := time.Now().Truncate(time.Second)
+	claimed, err := repo.Claim(ctx, stale[0], claimedAt)
+	ValidateErr(t, err, nil)
+	if !claimed {
+		t.Errorf("want: claimed, got: not claimed")
+	}
+	claimed, err = repo.Claim(ctx, stale[0], claimedAt.Add(time.Second))
+	ValidateErr(t, err, nil)
+	if claimed {
+		t.Errorf("want: not claimed, got: claimed")
+	}
+
+	// Update
+	saga.Advance(claimedAt)
+	saga.Fail("insufficient stock", claimedAt)
+	saga.Compensated(claimedAt)
+	err = repo.Update(ctx, *saga)
+	ValidateErr(t, err, nil)
+
+	sagas, err = repo.ListStale(ctx, time.Now().Add(time.Hour))
+	ValidateErr(t, err, nil)
+	if len(sagas) != 0 {
+		t.Errorf("want: 0, got: %d", len(sagas))
+	}
+}
@@ -1,21 +1,95 @@
 CREATE DATABASE IF NOT EXISTS `microservice-k8s-demo-test-db` DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;
 USE `microservice-k8s-demo-test-db`;
 
+DROP TABLE IF EXISTS OrderStatusHistory;
 DROP TABLE IF EXISTS OrderLines;
 DROP TABLE IF EXISTS Orders;
+DROP TABLE IF EXISTS OrderIdempotencyKeys;
+DROP TABLE IF EXISTS OrderSagas;
+DROP TABLE IF EXISTS OrderOutboxEvents;
 
 -- Orders Table
 CREATE TABLE Orders (
     id CHAR(36) PRIMARY KEY,
     customer_id CHAR(36) NOT NULL,
-    order_date TIMESTAMP NOT NULL
+    order_date TIMESTAMP NOT NULL,
+    -- Prices are in the minor unit of their ISO 4217 currency, such as cents for USD.
+    total_price_amount BIGINT NOT NULL DEFAULT 0,
+    total_price_currency CHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    INDEX idx_orders_customer_id_order_date (customer_id, order_date),
+    INDEX idx_orders_order_date (order_date),
+    INDEX idx_orders_total_price (total_price_amount)
 );
 
 -- OrderLines Table
 CREATE TABLE OrderLines (
     order_id CHAR(36) NOT NULL,
+    line_number INT NOT NULL DEFAULT 0,
     catalog_item_id CHAR(36) NOT NULL,
+    -- The variant of the item ordered; empty for items without variants.
+    sku VARCHAR(64) NOT NULL DEFAULT '',
     count INT NOT NULL,
-    PRIMARY KEY (order_id, catalog_item_id),
+    item_name VARCHAR(255) NOT NULL,
+    unit_price_amount BIGINT NOT NULL,
+    unit_price_currency CHAR(3) NOT NULL,
+    -- Set when the unit price was converted from the base price of the item, at exchange_rate to unit_price_currency.
+    base_price_amount BIGINT NULL,
+    base_price_currency CHAR(3) NULL,
+    exchange_rate DECIMAL(18, 8) NULL,
+    exchange_rate_updated_at TIMESTAMP NULL,
+    PRIMARY KEY (order_id, catalog_item_id, sku),
     FOREIGN KEY (order_id) REFERENCES Orders(id)
 );
+
+-- OrderStatusHistory Table
+CREATE TABLE OrderStatusHistory (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id CHAR(36) NOT NULL,
+    from_status VARCHAR(16),
+    to_status VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP NOT NULL,
+    INDEX idx_order_status_history_order_id (order_id),
+    FOREIGN KEY (order_id) REFERENCES Orders(id)
+);
+
+-- OrderIdempotencyKeys Table
+CREATE TABLE OrderIdempotencyKeys (
+    idempotency_key VARCHAR(255) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response_type VARCHAR(255),
+    response BLOB,
+    created_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (idempotency_key, method),
+    INDEX idx_order_idempotency_keys_expires_at (expires_at)
+);
+
+-- OrderSagas Table
+CREATE TABLE OrderSagas (
+    id CHAR(36) PRIMARY KEY,
+    status VARCHAR(16) NOT NULL,
+    step VARCHAR(32) NOT NULL,
+    payload JSON NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_order_sagas_status_updated_at (status, updated_at)
+);
+
+-- OrderOutboxEvents Table
+CREATE TABLE OrderOutboxEvents (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY,
+    id CHAR(36) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    aggregate_id CHAR(36) NOT NULL,
+    payload JSON NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    published_at TIMESTAMP NULL,
+    -- The relay publishing the event, until claimed_until.
+    claim_id CHAR(36) NULL,
+    claimed_until TIMESTAMP NULL,
+    INDEX idx_order_outbox_events_published_at (published_at, sequence),
+    INDEX idx_order_outbox_events_claim_id (claim_id)
+);
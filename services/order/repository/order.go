@@ -3,13 +3,107 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
 )
 
+// ErrStatusConflict is returned by UpdateStatus when the stored status no longer
+// matches the status the change was computed from.
+var ErrStatusConflict = entity.NewError(entity.ErrConflict, "order status has been changed concurrently")
+
+var (
+	ErrInvalidOrderSort   = entity.NewError(entity.ErrInvalidArgument, "invalid order sort")
+	ErrInvalidOrderFilter = entity.NewError(entity.ErrInvalidArgument, "invalid order filter")
+)
+
+// OrderSort is the order in which List returns orders.
+// A leading "-" sorts by the field in descending order; ties are broken by order ID.
+type OrderSort string
+
+const (
+	OrderSortDefault        OrderSort = ""
+	OrderSortOrderDate      OrderSort = "order_date"
+	OrderSortOrderDateDesc  OrderSort = "-order_date"
+	OrderSortTotalPrice     OrderSort = "total_price"
+	OrderSortTotalPriceDesc OrderSort = "-total_price"
+)
+
+func ParseOrderSort(s string) (OrderSort, error) {
+	switch sort := OrderSort(s); sort {
+	case OrderSortDefault, OrderSortOrderDate, OrderSortOrderDateDesc, OrderSortTotalPrice, OrderSortTotalPriceDesc:
+		return sort, nil
+	default:
+		return "", ErrInvalidOrderSort
+	}
+}
+
+// Field returns the name of the sorted field without the direction prefix.
+func (s OrderSort) Field() string {
+	return strings.TrimPrefix(string(s), "-")
+}
+
+// Descending reports whether the field is sorted in descending order.
+func (s OrderSort) Descending() bool {
+	return strings.HasPrefix(string(s), "-")
+}
+
+// OrderFilter narrows down the orders returned by List. Zero-valued fields do not filter.
+// From is inclusive and To is exclusive; MinTotal and MaxTotal are both inclusive.
+// Totals in different currencies cannot be compared, so Currency, MinTotal and MaxTotal
+// only match orders whose total is in their currency, and sorting by total price requires one.
+type OrderFilter struct {
+	CustomerID string
+	From       *time.Time
+	To         *time.Time
+	MinTotal   *entity.Money
+	MaxTotal   *entity.Money
+	Currency   string
+	Sort       OrderSort
+}
+
+// TotalCurrency returns the currency the totals of the listed orders are in,
+// or an empty string if orders in any currency are listed.
+func (f OrderFilter) TotalCurrency() string {
+	switch {
+	case f.Currency != "":
+		return f.Currency
+	case f.MinTotal != nil:
+		return f.MinTotal.Currency
+	case f.MaxTotal != nil:
+		return f.MaxTotal.Currency
+	default:
+		return ""
+	}
+}
+
+// Validate reports an error when the bounds of a range are reversed, or when totals
+// would be compared across currencies.
+func (f OrderFilter) Validate() error {
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidOrderFilter)
+	}
+	currency := f.TotalCurrency()
+	if (f.MinTotal != nil && f.MinTotal.Currency != currency) || (f.MaxTotal != nil && f.MaxTotal.Currency != currency) {
+		return fmt.Errorf("%w: min total and max total must be in the currency of the filter", ErrInvalidOrderFilter)
+	}
+	if f.Sort.Field() == OrderSortTotalPrice.Field() && currency == "" {
+		return fmt.Errorf("%w: sorting by total price requires a currency", ErrInvalidOrderFilter)
+	}
+	if f.MinTotal != nil && f.MaxTotal != nil {
+		if f.MinTotal.Amount > f.MaxTotal.Amount {
+			return fmt.Errorf("%w: min total must not exceed max total", ErrInvalidOrderFilter)
+		}
+	}
+	return nil
+}
+
 type OrderRepository interface {
 	Get(ctx context.Context, id string) (*entity.Order, error)
-	List(ctx context.Context) ([]*entity.Order, error)
+	List(ctx context.Context, filter OrderFilter, page Page) ([]*entity.Order, PageInfo, error)
 	Create(ctx context.Context, order entity.Order) error
+	UpdateStatus(ctx context.Context, history entity.OrderStatusHistory) error
 	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,19 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+type SagaRepository interface {
+	Create(ctx context.Context, saga entity.Saga) error
+	Update(ctx context.Context, saga entity.Saga) error
+	// ListStale returns the running and compensating sagas that have not been updated since before.
+	ListStale(ctx context.Context, before time.Time) ([]entity.Saga, error)
+	// Claim takes over a stale saga by setting its update time to now. It reports false if the saga
+	// has been updated since it was listed, e.g. because another replica claimed it first.
+	Claim(ctx context.Context, saga entity.Saga, now time.Time) (bool, error)
+}
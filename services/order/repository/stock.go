@@ -0,0 +1,16 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package repository
+
+import (
+	"context"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+// StockRepository holds catalog stock for orders. A reservation is identified by the order ID,
+// so that it can be released or committed from the order alone.
+type StockRepository interface {
+	Reserve(ctx context.Context, reservationID string, orderLines []*entity.OrderLine) error
+	Release(ctx context.Context, reservationID string) error
+	Commit(ctx context.Context, reservationID string) error
+}
@@ -3,3 +3,65 @@
 
 // Package mock is a generated GoMock package.
 package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+)
+
+// MockOrderService is a mock of OrderService interface.
+type MockOrderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderServiceMockRecorder
+}
+
+// MockOrderServiceMockRecorder is the mock recorder for MockOrderService.
+type MockOrderServiceMockRecorder struct {
+	mock *MockOrderService
+}
+
+// NewMockOrderService creates a new mock instance.
+func NewMockOrderService(ctrl *gomock.Controller) *MockOrderService {
+	mock := &MockOrderService{ctrl: ctrl}
+	mock.recorder = &MockOrderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
+	return m.recorder
+}
+
+// PlaceOrder mocks base method.
+func (m *MockOrderService) PlaceOrder(ctx context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceOrder", ctx, order)
+	ret0, _ := ret[0].(*entity.Order)
+	ret1, _ := ret[1].(*entity.Customer)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PlaceOrder indicates an expected call of PlaceOrder.
+func (mr *MockOrderServiceMockRecorder) PlaceOrder(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockOrderService)(nil).PlaceOrder), ctx, order)
+}
+
+// ResumeSagas mocks base method.
+func (m *MockOrderService) ResumeSagas(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSagas", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSagas indicates an expected call of ResumeSagas.
+func (mr *MockOrderServiceMockRecorder) ResumeSagas(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSagas", reflect.TypeOf((*MockOrderService)(nil).ResumeSagas), ctx, now)
+}
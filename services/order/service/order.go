@@ -1,77 +1,310 @@
 //go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
 package service
 
-// import (
-// 	"context"
-
-// 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
-// 	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
-// )
-
-// type OrderService interface {
-// 	CreateOrder(ctx context.Context, order entity.Order) error
-// 	DeleteOrder(ctx context.Context, id string) error
-// }
-
-// type orderService struct {
-// 	or  repository.OrderRepository
-// 	olr repository.OrderLineRepository
-// 	tr  repository.TransactionRepository
-// }
-
-// func NewOrderService(
-// 	or repository.OrderRepository,
-// 	olr repository.OrderLineRepository,
-// 	tr repository.TransactionRepository,
-// ) OrderService {
-// 	return &orderService{
-// 		or:  or,
-// 		olr: olr,
-// 		tr:  tr,
-// 	}
-// }
-
-// func (os *orderService) CreateOrder(ctx context.Context, order entity.Order) error {
-// 	if err := os.tr.Transaction(ctx, func(ctx context.Context) error {
-// 		if err := os.or.Create(ctx, entity.OrderModel{
-// 			ID:         order.ID,
-// 			CustomerID: order.Customer.ID,
-// 			OrderDate:  order.OrderDate,
-// 		}); err != nil {
-// 			return err
-// 		}
-
-// 		var orderLiensModel []entity.OrderLineModel
-// 		for _, ol := range order.OrderLines {
-// 			orderLiensModel = append(orderLiensModel, entity.OrderLineModel{
-// 				OrderID:       order.ID,
-// 				CatalogItemID: ol.CatalogItem.ID,
-// 				Count:         ol.Count,
-// 			})
-// 		}
-
-// 		if err := os.olr.BatchCreate(ctx, orderLiensModel); err != nil {
-// 			return err
-// 		}
-// 		return nil
-// 	}); err != nil {
-// 		return err
-// 	}
-
-// 	return nil
-// }
-
-// func (os *orderService) DeleteOrder(ctx context.Context, id string) error {
-// 	if err := os.tr.Transaction(ctx, func(ctx context.Context) error {
-// 		if err := os.olr.BatchDelete(ctx, id); err != nil {
-// 			return err
-// 		}
-// 		if err := os.or.Delete(ctx, id); err != nil {
-// 			return err
-// 		}
-// 		return nil
-// 	}); err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+)
+
+var (
+	ErrCustomerNotFound = entity.NewError(entity.ErrNotFound, "customer not found")
+	ErrCustomerDeleted  = entity.NewError(entity.ErrFailedPrecondition, "customer is in the trash")
+)
+
+// OrderService places orders across the customer, catalog and order services. Placement runs as a saga
+// whose state is stored after every step, so that it can be resumed or compensated after a restart.
+type OrderService interface {
+	// PlaceOrder runs the placement steps for order and returns the confirmed order and its customer.
+	// If a step fails, the steps already run are compensated and the error of the failed step is returned.
+	PlaceOrder(ctx context.Context, order entity.Order) (*entity.Order, *entity.Customer, error)
+	// ResumeSagas takes over the sagas that have not made progress for a while and drives them to an end.
+	ResumeSagas(ctx context.Context, now time.Time) error
+}
+
+type orderService struct {
+	cr         repository.CustomerRepository
+	sr         repository.StockRepository
+	or         repository.OrderRepository
+	sgr        repository.SagaRepository
+	tr         repository.TransactionRepository
+	obr        repository.OutboxRepository
+	staleAfter time.Duration
+	steps      map[entity.SagaStep]sagaStep
+}
+
+func NewOrderService(
+	cr repository.CustomerRepository,
+	sr repository.StockRepository,
+	or repository.OrderRepository,
+	sgr repository.SagaRepository,
+	tr repository.TransactionRepository,
+	obr repository.OutboxRepository,
+	conf *config.SagaConfig,
+) OrderService {
+	os := &orderService{
+		cr:         cr,
+		sr:         sr,
+		or:         or,
+		sgr:        sgr,
+		tr:         tr,
+		obr:        obr,
+		staleAfter: conf.StaleAfter,
+	}
+	os.steps = map[entity.SagaStep]sagaStep{
+		entity.SagaStepValidateCustomer: {run: os.validateCustomer, compensate: noCompensation},
+		entity.SagaStepReserveStock:     {run: os.reserveStock, compensate: os.releaseStock},
+		entity.SagaStepPersistOrder:     {run: os.persistOrder, compensate: os.cancelOrder},
+		entity.SagaStepConfirmOrder:     {run: os.confirmOrder, compensate: noCompensation},
+	}
+	return os
+}
+
+// sagaStep is an action of the saga and the action undoing it. Both may run again for the
+// same saga after a restart, so they must succeed when their effect is already in place.
+type sagaStep struct {
+	run        func(ctx context.Context, p *placement) error
+	compensate func(ctx context.Context, p *placement) error
+}
+
+// placement is a saga driven by this process.
+type placement struct {
+	saga     *entity.Saga
+	customer *entity.Customer
+}
+
+func (os *orderService) PlaceOrder(ctx context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
+	saga := entity.NewOrderPlacementSaga(order, time.Now())
+	if err := os.sgr.Create(ctx, *saga); err != nil {
+		log.Error("Failed to create saga", log.Fstring("sagaID", saga.ID), log.Ferror(err))
+		return nil, nil, err
+	}
+
+	p := &placement{saga: saga}
+	if err := os.drive(ctx, p); err != nil {
+		return nil, nil, err
+	}
+	return &p.saga.Order, p.customer, nil
+}
+
+func (os *orderService) ResumeSagas(ctx context.Context, now time.Time) error {
+	sagas, err := os.sgr.ListStale(ctx, now.Add(-os.staleAfter))
+	if err != nil {
+		log.Error("Failed to list stale sagas", log.Ferror(err))
+		return err
+	}
+
+	for i := range sagas {
+		saga := sagas[i]
+		claimed, cerr := os.sgr.Claim(ctx, saga, now)
+		if cerr != nil {
+			log.Error("Failed to claim saga", log.Fstring("sagaID", saga.ID), log.Ferror(cerr))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		saga.UpdatedAt = now
+
+		log.Info("Resuming saga", log.Fstring("sagaID", saga.ID), log.Fstring("status", string(saga.Status)), log.Fstring("step", string(saga.Step)))
+		if err = os.drive(ctx, &placement{saga: &saga}); err != nil {
+			log.Warn("Resumed saga failed", log.Fstring("sagaID", saga.ID), log.Ferror(err))
+		}
+	}
+	return nil
+}
+
+// drive runs the remaining steps of a running saga and undoes the steps already run if one fails,
+// returning the error of the failed step. A compensating saga is only compensated.
+func (os *orderService) drive(ctx context.Context, p *placement) error {
+	var err error
+	for p.saga.Status == entity.SagaStatusRunning {
+		step := p.saga.Step
+		if err = os.steps[step].run(ctx, p); err != nil {
+			log.Warn("Saga step failed", log.Fstring("sagaID", p.saga.ID), log.Fstring("step", string(step)), log.Ferror(err))
+			p.saga.Fail(err.Error(), time.Now())
+		} else {
+			p.saga.Advance(time.Now())
+		}
+
+		if uerr := os.sgr.Update(ctx, *p.saga); uerr != nil {
+			log.Error("Failed to update saga", log.Fstring("sagaID", p.saga.ID), log.Ferror(uerr))
+			// Progress that cannot be recorded would not be resumed after a restart, so it is undone instead.
+			if err == nil {
+				err = uerr
+				p.saga.Fail(uerr.Error(), time.Now())
+			}
+		}
+	}
+
+	if p.saga.Status == entity.SagaStatusCompensating {
+		if cerr := os.compensate(ctx, p); cerr != nil {
+			// The saga stays compensating and is retried once it is stale.
+			log.Error("Failed to compensate saga", log.Fstring("sagaID", p.saga.ID), log.Fstring("step", string(p.saga.Step)), log.Ferror(cerr))
+		}
+	}
+	return err
+}
+
+func (os *orderService) compensate(ctx context.Context, p *placement) error {
+	for p.saga.Status == entity.SagaStatusCompensating {
+		if err := os.steps[p.saga.Step].compensate(ctx, p); err != nil {
+			return err
+		}
+		p.saga.Compensated(time.Now())
+		if err := os.sgr.Update(ctx, *p.saga); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func noCompensation(context.Context, *placement) error {
+	return nil
+}
+
+func (os *orderService) validateCustomer(ctx context.Context, p *placement) error {
+	customerID := p.saga.Order.CustomerID
+	customer, err := os.cr.Get(ctx, customerID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrCustomerNotFound, customerID)
+	}
+	if err != nil {
+		return err
+	}
+	// The customer is read including the trash, and those in it cannot place orders.
+	if customer.DeletedAt != nil {
+		return fmt.Errorf("%w: %s", ErrCustomerDeleted, customerID)
+	}
+	p.customer = customer
+	return nil
+}
+
+// reserveStock reserves the stock under the order ID.
+func (os *orderService) reserveStock(ctx context.Context, p *placement) error {
+	err := os.sr.Reserve(ctx, p.saga.ID, p.saga.Order.OrderLines)
+	if errors.Is(err, entity.ErrAlreadyExists) {
+		// Reserved before a restart.
+		return nil
+	}
+	return err
+}
+
+func (os *orderService) releaseStock(ctx context.Context, p *placement) error {
+	err := os.sr.Release(ctx, p.saga.ID)
+	if errors.Is(err, entity.ErrNotFound) {
+		// The reservation was never made.
+		return nil
+	}
+	return err
+}
+
+func (os *orderService) persistOrder(ctx context.Context, p *placement) error {
+	err := os.or.Create(ctx, p.saga.Order)
+	if errors.Is(err, entity.ErrAlreadyExists) {
+		// Persisted before a restart.
+		return nil
+	}
+	return err
+}
+
+// cancelOrder cancels the persisted order rather than deleting it, so that the failed placement
+// stays visible in the order history. No event is published, as the order was never placed.
+func (os *orderService) cancelOrder(ctx context.Context, p *placement) error {
+	order, err := os.or.Get(ctx, p.saga.ID)
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if order.Status == entity.OrderStatusCancelled {
+		return nil
+	}
+
+	history, err := order.TransitionTo(entity.OrderStatusCancelled, time.Now())
+	if err != nil {
+		return err
+	}
+	if err = os.or.UpdateStatus(ctx, *history); err != nil {
+		return err
+	}
+	p.saga.Order.Status = order.Status
+	return nil
+}
+
+func (os *orderService) confirmOrder(ctx context.Context, p *placement) error {
+	order := p.saga.Order
+	history, err := order.TransitionTo(entity.OrderStatusConfirmed, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// The order is placed once it is confirmed, so the event is stored with the confirmation.
+	err = os.tr.Transaction(ctx, func(ctx context.Context) error {
+		if uerr := os.or.UpdateStatus(ctx, *history); uerr != nil {
+			return uerr
+		}
+		event, eerr := entity.NewEvent(entity.EventTypeOrderPlaced, order.ID, order, history.ChangedAt)
+		if eerr != nil {
+			return eerr
+		}
+		return os.obr.Add(ctx, *event)
+	})
+	if errors.Is(err, repository.ErrStatusConflict) {
+		// The order may have been confirmed before a restart.
+		current, gerr := os.or.Get(ctx, p.saga.ID)
+		if gerr == nil && current.Status == entity.OrderStatusConfirmed {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	p.saga.Order.Status = entity.OrderStatusConfirmed
+	return nil
+}
+
+// SagaRecoverer resumes the sagas left unfinished by a stopped process, once at start
+// and then periodically.
+type SagaRecoverer struct {
+	os       OrderService
+	interval time.Duration
+}
+
+func NewSagaRecoverer(os OrderService, conf *config.SagaConfig) *SagaRecoverer {
+	return &SagaRecoverer{
+		os:       os,
+		interval: conf.RecoveryInterval,
+	}
+}
+
+// Run resumes stale sagas until ctx is cancelled.
+func (r *SagaRecoverer) Run(ctx context.Context) {
+	r.Recover(ctx, time.Now())
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.Recover(ctx, now)
+		}
+	}
+}
+
+func (r *SagaRecoverer) Recover(ctx context.Context, now time.Time) {
+	if err := r.os.ResumeSagas(ctx, now); err != nil {
+		log.Error("Failed to resume sagas", log.Ferror(err))
+	}
+}
@@ -1,197 +1,362 @@
 package service
 
-// import (
-// 	"context"
-// 	"testing"
-// 	"time"
-
-// 	"github.com/golang/mock/gomock"
-// 	"github.com/google/uuid"
-// 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
-// 	"github.com/tusmasoma/go-microservice-k8s/services/order/repository/mock"
-// )
-
-// func TestOrderService_CreateOrder(t *testing.T) {
-// 	t.Parallel()
-
-// 	orderID := uuid.New().String()
-
-// 	customerID := uuid.New().String()
-// 	customer := entity.Customer{
-// 		ID: customerID,
-// 	}
-
-// 	catalogItemID := uuid.New().String()
-// 	catalogItem := entity.CatalogItem{
-// 		ID:    catalogItemID,
-// 		Name:  "item1",
-// 		Price: 1000,
-// 	}
-
-// 	orderLines := []entity.OrderLine{
-// 		{
-// 			Count:       1,
-// 			CatalogItem: catalogItem,
-// 		},
-// 	}
-
-// 	date := time.Now()
-// 	order := entity.Order{
-// 		ID:         orderID,
-// 		Customer:   customer,
-// 		OrderDate:  date,
-// 		OrderLines: orderLines,
-// 	}
-
-// 	patterns := []struct {
-// 		name  string
-// 		setup func(
-// 			m *mock.MockOrderRepository,
-// 			m1 *mock.MockOrderLineRepository,
-// 			m2 *mock.MockTransactionRepository,
-// 		)
-// 		arg struct {
-// 			ctx   context.Context
-// 			order entity.Order
-// 		}
-// 		wantErr error
-// 	}{
-// 		{
-// 			name: "success",
-// 			setup: func(
-// 				m *mock.MockOrderRepository,
-// 				m1 *mock.MockOrderLineRepository,
-// 				m2 *mock.MockTransactionRepository,
-// 			) {
-// 				m2.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-// 					return fn(ctx)
-// 				})
-// 				m.EXPECT().Create(
-// 					gomock.Any(),
-// 					entity.OrderModel{
-// 						ID:         orderID,
-// 						CustomerID: customerID,
-// 						OrderDate:  date,
-// 					},
-// 				).Return(nil)
-// 				m1.EXPECT().BatchCreate(
-// 					gomock.Any(),
-// 					[]entity.OrderLineModel{
-// 						{
-// 							OrderID:       orderID,
-// 							CatalogItemID: catalogItemID,
-// 							Count:         1,
-// 						},
-// 					},
-// 				).Return(nil)
-// 			},
-// 			arg: struct {
-// 				ctx   context.Context
-// 				order entity.Order
-// 			}{
-// 				ctx:   context.Background(),
-// 				order: order,
-// 			},
-// 			wantErr: nil,
-// 		},
-// 	}
-// 	for _, tt := range patterns {
-// 		tt := tt
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			t.Parallel()
-
-// 			ctrl := gomock.NewController(t)
-// 			or := mock.NewMockOrderRepository(ctrl)
-// 			olr := mock.NewMockOrderLineRepository(ctrl)
-// 			tr := mock.NewMockTransactionRepository(ctrl)
-
-// 			if tt.setup != nil {
-// 				tt.setup(or, olr, tr)
-// 			}
-
-// 			service := NewOrderService(or, olr, tr)
-
-// 			err := service.CreateOrder(tt.arg.ctx, tt.arg.order)
-
-// 			if (err != nil) != (tt.wantErr != nil) {
-// 				t.Errorf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
-// 			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
-// 				t.Errorf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
-// 			}
-// 		})
-// 	}
-// }
-
-// func TestOrderService_DeleteOrder(t *testing.T) {
-// 	t.Parallel()
-
-// 	orderID := uuid.New().String()
-
-// 	patterns := []struct {
-// 		name  string
-// 		setup func(
-// 			m *mock.MockOrderRepository,
-// 			m1 *mock.MockOrderLineRepository,
-// 			m2 *mock.MockTransactionRepository,
-// 		)
-// 		arg struct {
-// 			ctx context.Context
-// 			id  string
-// 		}
-// 		wantErr error
-// 	}{
-// 		{
-// 			name: "success",
-// 			setup: func(
-// 				m *mock.MockOrderRepository,
-// 				m1 *mock.MockOrderLineRepository,
-// 				m2 *mock.MockTransactionRepository,
-// 			) {
-// 				m2.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
-// 					return fn(ctx)
-// 				})
-// 				m1.EXPECT().BatchDelete(
-// 					gomock.Any(),
-// 					orderID,
-// 				).Return(nil)
-// 				m.EXPECT().Delete(
-// 					gomock.Any(),
-// 					orderID,
-// 				).Return(nil)
-// 			},
-// 			arg: struct {
-// 				ctx context.Context
-// 				id  string
-// 			}{
-// 				ctx: context.Background(),
-// 				id:  orderID,
-// 			},
-// 			wantErr: nil,
-// 		},
-// 	}
-// 	for _, tt := range patterns {
-// 		tt := tt
-// 		t.Run(tt.name, func(t *testing.T) {
-// 			t.Parallel()
-
-// 			ctrl := gomock.NewController(t)
-// 			or := mock.NewMockOrderRepository(ctrl)
-// 			olr := mock.NewMockOrderLineRepository(ctrl)
-// 			tr := mock.NewMockTransactionRepository(ctrl)
-
-// 			if tt.setup != nil {
-// 				tt.setup(or, olr, tr)
-// 			}
-
-// 			service := NewOrderService(or, olr, tr)
-
-// 			err := service.DeleteOrder(tt.arg.ctx, tt.arg.id)
-
-// 			if (err != nil) != (tt.wantErr != nil) {
-// 				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
-// 			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
-// 				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
-// 			}
-// 		})
-// 	}
-// }
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository/mock"
+)
+
+func newTestOrder(t *testing.T) *entity.Order {
+	t.Helper()
+
+	orderDate := time.Now()
+	order, err := entity.NewOrder("", uuid.New().String(), &orderDate, []*entity.OrderLine{
+		{Count: 2, CatalogItemID: uuid.New().String(), ItemName: "item1", UnitPrice: entity.Money{Amount: 1000, Currency: "USD"}},
+	})
+	if err != nil {
+		t.Fatalf("NewOrder() error = %v", err)
+	}
+	return order
+}
+
+func TestOrderService_PlaceOrder(t *testing.T) { //nolint:gocognit // This is a test function
+	t.Parallel()
+
+	order := newTestOrder(t)
+	customer := &entity.Customer{ID: order.CustomerID, Name: "customer1"}
+	errUnavailable := errors.New("connection refused")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerRepository,
+			m1 *mock.MockStockRepository,
+			m2 *mock.MockOrderRepository,
+			m3 *mock.MockSagaRepository,
+		)
+		wantErr     error
+		wantUpdates int
+		wantStatus  entity.SagaStatus
+		wantPlaced  bool
+	}{
+		{
+			name: "success",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), order.CustomerID).Return(customer, nil)
+				sr.EXPECT().Reserve(gomock.Any(), order.ID, order.OrderLines).Return(nil)
+				or.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Do(func(_ context.Context, history entity.OrderStatusHistory) {
+					if history.ToStatus != entity.OrderStatusConfirmed {
+						t.Errorf("unexpected ToStatus: got %v, want %v", history.ToStatus, entity.OrderStatusConfirmed)
+					}
+				}).Return(nil)
+			},
+			wantErr:     nil,
+			wantUpdates: 4,
+			wantStatus:  entity.SagaStatusCompleted,
+			wantPlaced:  true,
+		},
+		{
+			name: "Fail: customer not found",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), order.CustomerID).Return(nil, entity.NewError(entity.ErrNotFound, "customer not found"))
+			},
+			wantErr:     ErrCustomerNotFound,
+			wantUpdates: 1,
+			wantStatus:  entity.SagaStatusCompensated,
+		},
+		{
+			name: "Fail: customer is in the trash",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				deletedAt := time.Now()
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), order.CustomerID).Return(&entity.Customer{ID: order.CustomerID, Name: "customer1", DeletedAt: &deletedAt}, nil)
+			},
+			wantErr:     ErrCustomerDeleted,
+			wantUpdates: 1,
+			wantStatus:  entity.SagaStatusCompensated,
+		},
+		{
+			name: "Fail: insufficient stock",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), order.CustomerID).Return(customer, nil)
+				sr.EXPECT().Reserve(gomock.Any(), order.ID, order.OrderLines).Return(
+					entity.NewError(entity.ErrFailedPrecondition, "insufficient stock"))
+			},
+			wantErr:     entity.ErrFailedPrecondition,
+			wantUpdates: 3,
+			wantStatus:  entity.SagaStatusCompensated,
+		},
+		{
+			name: "Fail: failed confirmation cancels the order and releases the stock",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), order.CustomerID).Return(customer, nil)
+				sr.EXPECT().Reserve(gomock.Any(), order.ID, order.OrderLines).Return(nil)
+				or.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				gomock.InOrder(
+					or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(errUnavailable),
+					or.EXPECT().Get(gomock.Any(), order.ID).Return(&entity.Order{
+						ID:         order.ID,
+						CustomerID: order.CustomerID,
+						Status:     entity.OrderStatusPending,
+					}, nil),
+					or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Do(func(_ context.Context, history entity.OrderStatusHistory) {
+						if history.ToStatus != entity.OrderStatusCancelled {
+							t.Errorf("unexpected ToStatus: got %v, want %v", history.ToStatus, entity.OrderStatusCancelled)
+						}
+					}).Return(nil),
+					sr.EXPECT().Release(gomock.Any(), order.ID).Return(nil),
+				)
+			},
+			wantErr:     errUnavailable,
+			wantUpdates: 7,
+			wantStatus:  entity.SagaStatusCompensated,
+		},
+		{
+			name: "Fail: saga cannot be stored",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errUnavailable)
+			},
+			wantErr: errUnavailable,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCustomerRepository(ctrl)
+			sr := mock.NewMockStockRepository(ctrl)
+			or := mock.NewMockOrderRepository(ctrl)
+			sgr := mock.NewMockSagaRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr, sr, or, sgr)
+			}
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			wantEvents := 0
+			if tt.wantPlaced {
+				wantEvents = 1
+			}
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				if len(events) != 1 || events[0].Type != entity.EventTypeOrderPlaced || events[0].AggregateID != order.ID {
+					t.Errorf("unexpected events: %v", events)
+				}
+			}).Return(nil).Times(wantEvents)
+
+			var last entity.Saga
+			sgr.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, saga entity.Saga) {
+				last = saga
+			}).Return(nil).Times(tt.wantUpdates)
+
+			os := NewOrderService(cr, sr, or, sgr, tr, obr, &config.SagaConfig{StaleAfter: time.Minute})
+
+			placed, gotCustomer, err := os.PlaceOrder(context.Background(), *order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PlaceOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if placed.ID != order.ID || placed.Status != entity.OrderStatusConfirmed {
+					t.Errorf("PlaceOrder() order = %v, want %v confirmed", placed, order.ID)
+				}
+				if gotCustomer != customer {
+					t.Errorf("PlaceOrder() customer = %v, want %v", gotCustomer, customer)
+				}
+			}
+			if tt.wantUpdates > 0 && last.Status != tt.wantStatus {
+				t.Errorf("saga status = %v, want %v", last.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestOrderService_ResumeSagas(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	order := newTestOrder(t)
+	errUnavailable := errors.New("connection refused")
+
+	running := *entity.NewOrderPlacementSaga(*order, now.Add(-time.Hour))
+	running.Step = entity.SagaStepPersistOrder
+
+	compensating := *entity.NewOrderPlacementSaga(*order, now.Add(-time.Hour))
+	compensating.Status = entity.SagaStatusCompensating
+	compensating.Step = entity.SagaStepReserveStock
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *mock.MockCustomerRepository,
+			m1 *mock.MockStockRepository,
+			m2 *mock.MockOrderRepository,
+			m3 *mock.MockSagaRepository,
+		)
+		wantErr    error
+		wantPlaced bool
+	}{
+		{
+			name: "success: a running saga is resumed",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().ListStale(gomock.Any(), now.Add(-time.Minute)).Return([]entity.Saga{running}, nil)
+				sgr.EXPECT().Claim(gomock.Any(), running, now).Return(true, nil)
+				or.EXPECT().Create(gomock.Any(), gomock.Any()).Return(entity.NewError(entity.ErrAlreadyExists, "order already exists"))
+				or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(nil)
+				gomock.InOrder(
+					sgr.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, saga entity.Saga) {
+						if saga.Step != entity.SagaStepConfirmOrder {
+							t.Errorf("unexpected Step: got %v, want %v", saga.Step, entity.SagaStepConfirmOrder)
+						}
+					}).Return(nil),
+					sgr.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, saga entity.Saga) {
+						if saga.Status != entity.SagaStatusCompleted {
+							t.Errorf("unexpected Status: got %v, want %v", saga.Status, entity.SagaStatusCompleted)
+						}
+					}).Return(nil),
+				)
+			},
+			wantErr:    nil,
+			wantPlaced: true,
+		},
+		{
+			name: "success: a compensating saga is compensated",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().ListStale(gomock.Any(), now.Add(-time.Minute)).Return([]entity.Saga{compensating}, nil)
+				sgr.EXPECT().Claim(gomock.Any(), compensating, now).Return(true, nil)
+				sr.EXPECT().Release(gomock.Any(), order.ID).Return(nil)
+				gomock.InOrder(
+					sgr.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil),
+					sgr.EXPECT().Update(gomock.Any(), gomock.Any()).Do(func(_ context.Context, saga entity.Saga) {
+						if saga.Status != entity.SagaStatusCompensated {
+							t.Errorf("unexpected Status: got %v, want %v", saga.Status, entity.SagaStatusCompensated)
+						}
+					}).Return(nil),
+				)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success: a saga claimed by another replica is skipped",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().ListStale(gomock.Any(), now.Add(-time.Minute)).Return([]entity.Saga{running}, nil)
+				sgr.EXPECT().Claim(gomock.Any(), running, now).Return(false, nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: stale sagas cannot be listed",
+			setup: func(
+				cr *mock.MockCustomerRepository,
+				sr *mock.MockStockRepository,
+				or *mock.MockOrderRepository,
+				sgr *mock.MockSagaRepository,
+			) {
+				sgr.EXPECT().ListStale(gomock.Any(), now.Add(-time.Minute)).Return(nil, errUnavailable)
+			},
+			wantErr: errUnavailable,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := mock.NewMockCustomerRepository(ctrl)
+			sr := mock.NewMockStockRepository(ctrl)
+			or := mock.NewMockOrderRepository(ctrl)
+			sgr := mock.NewMockSagaRepository(ctrl)
+			tr := mock.NewMockTransactionRepository(ctrl)
+			obr := mock.NewMockOutboxRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr, sr, or, sgr)
+			}
+
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			wantEvents := 0
+			if tt.wantPlaced {
+				wantEvents = 1
+			}
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				if len(events) != 1 || events[0].Type != entity.EventTypeOrderPlaced || events[0].AggregateID != order.ID {
+					t.Errorf("unexpected events: %v", events)
+				}
+			}).Return(nil).Times(wantEvents)
+
+			os := NewOrderService(cr, sr, or, sgr, tr, obr, &config.SagaConfig{StaleAfter: time.Minute})
+
+			err := os.ResumeSagas(context.Background(), now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResumeSagas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	entity "github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	repository "github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 	usecase "github.com/tusmasoma/go-microservice-k8s/services/order/usecase"
 )
 
@@ -36,12 +37,28 @@ func (m *MockOrderUseCase) EXPECT() *MockOrderUseCaseMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderUseCase) CancelOrder(ctx context.Context, id string) (*usecase.OrderDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, id)
+	ret0, _ := ret[0].(*usecase.OrderDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderUseCaseMockRecorder) CancelOrder(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderUseCase)(nil).CancelOrder), ctx, id)
+}
+
 // CreateOrder mocks base method.
-func (m *MockOrderUseCase) CreateOrder(ctx context.Context, params *usecase.CreateOrderParams) error {
+func (m *MockOrderUseCase) CreateOrder(ctx context.Context, params *usecase.CreateOrderParams) (*usecase.OrderDetails, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateOrder", ctx, params)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*usecase.OrderDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateOrder indicates an expected call of CreateOrder.
@@ -96,16 +113,46 @@ func (mr *MockOrderUseCaseMockRecorder) GetOrderCreationResources(ctx interface{
 }
 
 // ListOrders mocks base method.
-func (m *MockOrderUseCase) ListOrders(ctx context.Context) ([]*usecase.OrderDetails, error) {
+func (m *MockOrderUseCase) ListOrders(ctx context.Context, filter repository.OrderFilter, page repository.Page) ([]*usecase.OrderDetails, repository.PageInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListOrders", ctx)
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter, page)
 	ret0, _ := ret[0].([]*usecase.OrderDetails)
+	ret1, _ := ret[1].(repository.PageInfo)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderUseCaseMockRecorder) ListOrders(ctx, filter, page interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderUseCase)(nil).ListOrders), ctx, filter, page)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderUseCase) UpdateOrderStatus(ctx context.Context, id string, status entity.OrderStatus) (*usecase.OrderDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, id, status)
+	ret0, _ := ret[0].(*usecase.OrderDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListOrders indicates an expected call of ListOrders.
-func (mr *MockOrderUseCaseMockRecorder) ListOrders(ctx interface{}) *gomock.Call {
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderUseCaseMockRecorder) UpdateOrderStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderUseCase)(nil).UpdateOrderStatus), ctx, id, status)
+}
+
+// WatchOrders mocks base method.
+func (m *MockOrderUseCase) WatchOrders(ctx context.Context, after int64, send func(usecase.OrderChange) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchOrders", ctx, after, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchOrders indicates an expected call of WatchOrders.
+func (mr *MockOrderUseCaseMockRecorder) WatchOrders(ctx, after, send interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderUseCase)(nil).ListOrders), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchOrders", reflect.TypeOf((*MockOrderUseCase)(nil).WatchOrders), ctx, after, send)
 }
@@ -3,36 +3,83 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/service"
 	"github.com/tusmasoma/go-tech-dojo/pkg/log"
 )
 
+var (
+	ErrInvalidOrderRequest = entity.NewError(entity.ErrInvalidArgument, "invalid order request")
+	ErrCustomerNotFound    = service.ErrCustomerNotFound
+	ErrCustomerDeleted     = service.ErrCustomerDeleted
+	ErrCatalogItemNotFound = entity.NewError(entity.ErrNotFound, "catalog item not found")
+)
+
 type OrderUseCase interface {
 	GetOrderCreationResources(ctx context.Context) ([]entity.Customer, []entity.CatalogItem, error)
 	GetOrder(ctx context.Context, id string) (*OrderDetails, error)
-	ListOrders(ctx context.Context) ([]*OrderDetails, error)
-	CreateOrder(ctx context.Context, params *CreateOrderParams) error
+	ListOrders(ctx context.Context, filter repository.OrderFilter, page repository.Page) ([]*OrderDetails, repository.PageInfo, error)
+	CreateOrder(ctx context.Context, params *CreateOrderParams) (*OrderDetails, error)
+	UpdateOrderStatus(ctx context.Context, id string, status entity.OrderStatus) (*OrderDetails, error)
+	CancelOrder(ctx context.Context, id string) (*OrderDetails, error)
 	DeleteOrder(ctx context.Context, id string) error
+	// WatchOrders calls send with the changes of the orders made after the change with sequence after,
+	// in order, until ctx is cancelled or send fails. A negative after watches the changes made from now on.
+	WatchOrders(ctx context.Context, after int64, send func(OrderChange) error) error
+}
+
+type ChangeType string
+
+const (
+	ChangeTypeCreated ChangeType = "created"
+	ChangeTypeUpdated ChangeType = "updated"
+	ChangeTypeDeleted ChangeType = "deleted"
+)
+
+// OrderChange is a change of an order. Order is the order as it is when the change is sent, and is nil when it is deleted.
+// Sequence orders the changes, and is where watching resumes from.
+type OrderChange struct {
+	Type     ChangeType
+	ID       string
+	Order    *OrderDetails
+	Sequence int64
 }
 
 type orderUseCase struct {
 	cr  repository.CustomerRepository
 	cir repository.CatalogItemRepository
 	or  repository.OrderRepository
+	sr  repository.StockRepository
+	os  service.OrderService
+	tr  repository.TransactionRepository
+	obr repository.OutboxRepository
+	cw  ChangeWatcher
 }
 
 func NewOrderUseCase(
 	cr repository.CustomerRepository,
 	cir repository.CatalogItemRepository,
 	or repository.OrderRepository,
+	sr repository.StockRepository,
+	os service.OrderService,
+	tr repository.TransactionRepository,
+	obr repository.OutboxRepository,
+	cw ChangeWatcher,
 ) OrderUseCase {
 	return &orderUseCase{
 		cr:  cr,
 		cir: cir,
 		or:  or,
+		sr:  sr,
+		os:  os,
+		tr:  tr,
+		obr: obr,
+		cw:  cw,
 	}
 }
 
@@ -56,8 +103,10 @@ type OrderDetails struct {
 	OrderLines []*OrderLineDetails
 }
 
+// OrderLineDetails is a line of an order. SKU is the variant of CatalogItem ordered, if any.
 type OrderLineDetails struct {
 	Count       int
+	SKU         string
 	CatalogItem *entity.CatalogItem
 }
 
@@ -74,136 +123,332 @@ func (ouc *orderUseCase) GetOrder(ctx context.Context, id string) (*OrderDetails
 		return nil, err
 	}
 
-	var itemIDs []string
-	for _, ol := range order.OrderLines {
-		itemIDs = append(itemIDs, ol.CatalogItemID)
-	}
-
-	items, err := ouc.cir.ListByIDs(ctx, itemIDs)
-	if err != nil {
-		log.Error("Failed to list catalog items", log.Ferror(err))
-		return nil, err
-	}
-
-	itemMap := make(map[string]entity.CatalogItem)
-	for _, item := range items {
-		itemMap[item.ID] = item
-	}
-
-	var orderLineDetails []*OrderLineDetails
-	for _, ol := range order.OrderLines {
-		item, ok := itemMap[ol.CatalogItemID]
-		if !ok {
-			log.Error("Catalog item not found", log.Fstring("itemID", ol.CatalogItemID))
-			return nil, fmt.Errorf("catalog item not found: %s", ol.CatalogItemID)
-		}
-		orderLineDetails = append(orderLineDetails, &OrderLineDetails{
-			Count:       ol.Count,
-			CatalogItem: &item,
-		})
-
-		order.TotalPrice += item.Price * float64(ol.Count)
-	}
-
 	return &OrderDetails{
 		Order:      order,
 		Customer:   customer,
-		OrderLines: orderLineDetails,
+		OrderLines: newOrderLineDetails(order.OrderLines),
 	}, nil
 }
 
-func (ouc *orderUseCase) ListOrders(ctx context.Context) ([]*OrderDetails, error) {
+func (ouc *orderUseCase) ListOrders(ctx context.Context, filter repository.OrderFilter, page repository.Page) ([]*OrderDetails, repository.PageInfo, error) {
 	var orderDetails []*OrderDetails
 
-	orders, err := ouc.or.List(ctx)
+	if err := filter.Validate(); err != nil {
+		log.Warn("Invalid order filter", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
+	}
+
+	orders, info, err := ouc.or.List(ctx, filter, page)
 	if err != nil {
 		log.Error("Failed to get orders", log.Ferror(err))
-		return nil, err
+		return nil, repository.PageInfo{}, err
+	}
+
+	customers, err := ouc.listOrderCustomers(ctx, orders)
+	if err != nil {
+		log.Error("Failed to list customers", log.Ferror(err))
+		return nil, repository.PageInfo{}, err
 	}
 
 	for _, order := range orders {
-		customer, err := ouc.cr.Get(ctx, order.CustomerID)
-		if err != nil {
-			log.Error("Failed to get customer", log.Ferror(err))
-			return nil, err
+		customer, ok := customers[order.CustomerID]
+		if !ok {
+			// An order of a customer that no longer exists is still listed, with only the customer ID known.
+			log.Warn("Customer not found", log.Fstring("customerID", order.CustomerID))
+			customer = &entity.Customer{ID: order.CustomerID}
 		}
 
-		var itemIDs []string
-		for _, ol := range order.OrderLines {
-			itemIDs = append(itemIDs, ol.CatalogItemID)
-		}
+		orderDetails = append(orderDetails, &OrderDetails{
+			Order:      order,
+			Customer:   customer,
+			OrderLines: newOrderLineDetails(order.OrderLines),
+		})
+	}
 
-		items, err := ouc.cir.ListByIDs(ctx, itemIDs)
-		if err != nil {
-			log.Error("Failed to list catalog items", log.Ferror(err))
-			return nil, err
-		}
+	return orderDetails, info, nil
+}
 
-		itemMap := make(map[string]entity.CatalogItem)
-		for _, item := range items {
-			itemMap[item.ID] = item
-		}
+// listOrderCustomers fetches the customers of orders in a single call, keyed by customer ID,
+// so that listing orders costs the same number of calls to the customer service regardless of the page size.
+func (ouc *orderUseCase) listOrderCustomers(ctx context.Context, orders []*entity.Order) (map[string]*entity.Customer, error) {
+	customerMap := make(map[string]*entity.Customer, len(orders))
 
-		var orderLineDetails []*OrderLineDetails
-		for _, ol := range order.OrderLines {
-			item, ok := itemMap[ol.CatalogItemID]
-			if !ok {
-				log.Error("Catalog item not found", log.Fstring("itemID", ol.CatalogItemID))
-				return nil, fmt.Errorf("catalog item not found: %s", ol.CatalogItemID)
-			}
-			orderLineDetails = append(orderLineDetails, &OrderLineDetails{
-				Count:       ol.Count,
-				CatalogItem: &item,
-			})
-
-			order.TotalPrice += item.Price * float64(ol.Count)
+	ids := make([]string, 0, len(orders))
+	seen := make(map[string]struct{}, len(orders))
+	for _, order := range orders {
+		if _, ok := seen[order.CustomerID]; ok {
+			continue
 		}
+		seen[order.CustomerID] = struct{}{}
+		ids = append(ids, order.CustomerID)
+	}
+	if len(ids) == 0 {
+		return customerMap, nil
+	}
 
-		orderDetails = append(orderDetails, &OrderDetails{
-			Order:      order,
-			Customer:   customer,
-			OrderLines: orderLineDetails,
-		})
+	customers, err := ouc.cr.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
 	}
+	for i := range customers {
+		customerMap[customers[i].ID] = &customers[i]
+	}
+	return customerMap, nil
+}
 
-	return orderDetails, nil
+// newOrderLineDetails builds the line details from the item snapshots stored with the order,
+// so that reads do not depend on the current state of the catalog.
+func newOrderLineDetails(orderLines []*entity.OrderLine) []*OrderLineDetails {
+	orderLineDetails := make([]*OrderLineDetails, 0, len(orderLines))
+	for _, ol := range orderLines {
+		orderLineDetails = append(orderLineDetails, &OrderLineDetails{
+			Count: ol.Count,
+			SKU:   ol.SKU,
+			CatalogItem: &entity.CatalogItem{
+				ID:         ol.CatalogItemID,
+				Name:       ol.ItemName,
+				Price:      ol.UnitPrice,
+				Conversion: ol.Conversion,
+			},
+		})
+	}
+	return orderLineDetails
 }
 
+// CreateOrderParams are the parameters of a new order. Currency is the currency the items are
+// priced in, and the items are ordered at their base prices when it is empty. The SKU of a line
+// is the variant of the item ordered, and is left empty for items without variants.
 type CreateOrderParams struct {
 	CustomerID string
+	Currency   string
 	OrderLine  []struct {
 		CatalogItemID string
 		Count         int
+		SKU           string
 	}
 }
 
-func (ouc *orderUseCase) CreateOrder(ctx context.Context, params *CreateOrderParams) error {
+func (ouc *orderUseCase) CreateOrder(ctx context.Context, params *CreateOrderParams) (*OrderDetails, error) {
+	if len(params.OrderLine) == 0 {
+		log.Warn("Order has no order lines")
+		return nil, fmt.Errorf("%w: at least one order line is required", ErrInvalidOrderRequest)
+	}
+	if params.Currency != "" {
+		if err := entity.ValidateCurrency(params.Currency); err != nil {
+			log.Warn("Invalid currency", log.Fstring("currency", params.Currency), log.Ferror(err))
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOrderRequest, err)
+		}
+	}
+
 	var orderLiens []*entity.OrderLine
 	for _, ol := range params.OrderLine {
-		orderLine, err := entity.NewOrderLine(ol.Count, ol.CatalogItemID)
+		orderLine, err := entity.NewOrderLine(ol.Count, ol.CatalogItemID, ol.SKU)
 		if err != nil {
-			log.Error("Failed to create order line", log.Ferror(err))
-			return err
+			log.Warn("Failed to create order line", log.Ferror(err))
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOrderRequest, err)
 		}
 		orderLiens = append(orderLiens, orderLine)
 	}
+	orderLiens = mergeOrderLines(orderLiens)
+
+	now := time.Now()
+	order, err := entity.NewOrder("", params.CustomerID, &now, orderLiens)
+	if err != nil {
+		log.Warn("Failed to create order", log.Ferror(err))
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOrderRequest, err)
+	}
+
+	// Lines of different variants of an item share the item.
+	itemIDs := make([]string, 0, len(orderLiens))
+	seen := make(map[string]bool, len(orderLiens))
+	for _, ol := range orderLiens {
+		if !seen[ol.CatalogItemID] {
+			seen[ol.CatalogItemID] = true
+			itemIDs = append(itemIDs, ol.CatalogItemID)
+		}
+	}
+	items, err := ouc.cir.ListByIDs(ctx, itemIDs, params.Currency)
+	if err != nil {
+		log.Error("Failed to list catalog items", log.Ferror(err))
+		return nil, err
+	}
+	itemMap := make(map[string]entity.CatalogItem, len(items))
+	for _, item := range items {
+		itemMap[item.ID] = item
+	}
+	for _, ol := range orderLiens {
+		item, ok := itemMap[ol.CatalogItemID]
+		if !ok {
+			log.Warn("Catalog item not found", log.Fstring("itemID", ol.CatalogItemID))
+			return nil, fmt.Errorf("%w: %s", ErrCatalogItemNotFound, ol.CatalogItemID)
+		}
+		if err = ol.SetItemSnapshot(item); err != nil {
+			log.Warn("Invalid order line", log.Fstring("itemID", ol.CatalogItemID), log.Ferror(err))
+			return nil, err
+		}
+	}
+	// Items priced in different currencies cannot be ordered together, as their total has no currency.
+	if err = order.UpdateTotalPrice(); err != nil {
+		log.Warn("Failed to compute total price", log.Fstring("orderID", order.ID), log.Ferror(err))
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOrderRequest, err)
+	}
 
-	order, err := entity.NewOrder("", params.CustomerID, nil, orderLiens)
+	// The customer is validated, stock reserved and the order stored and confirmed by the placement saga,
+	// which undoes the steps already taken if a later one fails.
+	placed, customer, err := ouc.os.PlaceOrder(ctx, *order)
 	if err != nil {
-		log.Error("Failed to create order", log.Ferror(err))
+		log.Warn("Failed to place order", log.Fstring("orderID", order.ID), log.Ferror(err))
+		return nil, err
+	}
+
+	return &OrderDetails{
+		Order:      placed,
+		Customer:   customer,
+		OrderLines: newOrderLineDetails(placed.OrderLines),
+	}, nil
+}
+
+// mergeOrderLines combines lines for the same catalog item and variant into one line,
+// keeping the position of the first occurrence.
+func mergeOrderLines(orderLines []*entity.OrderLine) []*entity.OrderLine {
+	type lineKey struct {
+		catalogItemID string
+		sku           string
+	}
+	merged := make([]*entity.OrderLine, 0, len(orderLines))
+	lineMap := make(map[lineKey]*entity.OrderLine, len(orderLines))
+	for _, ol := range orderLines {
+		key := lineKey{catalogItemID: ol.CatalogItemID, sku: ol.SKU}
+		if line, ok := lineMap[key]; ok {
+			line.Count += ol.Count
+			continue
+		}
+		lineMap[key] = ol
+		merged = append(merged, ol)
+	}
+	return merged
+}
+
+func (ouc *orderUseCase) UpdateOrderStatus(ctx context.Context, id string, status entity.OrderStatus) (*OrderDetails, error) {
+	order, err := ouc.or.Get(ctx, id)
+	if err != nil {
+		log.Error("Failed to get order", log.Ferror(err))
+		return nil, err
+	}
+
+	history, err := order.TransitionTo(status, time.Now())
+	if err != nil {
+		log.Warn("Invalid order status transition", log.Fstring("orderID", id), log.Ferror(err))
+		return nil, err
+	}
+
+	eventType := entity.EventTypeOrderStatusChanged
+	if status == entity.OrderStatusCancelled {
+		eventType = entity.EventTypeOrderCancelled
+	}
+	if err = ouc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := ouc.or.UpdateStatus(ctx, *history); err != nil {
+			return err
+		}
+		return ouc.addEvent(ctx, eventType, order.ID, history)
+	}); err != nil {
+		log.Error("Failed to update order status", log.Ferror(err))
+		return nil, err
+	}
+	// The stock is settled by the StockSettler when the event is relayed. The status change is stored
+	// at this point, so the order is returned with only the customer ID if the customer cannot be read.
+	customer, err := ouc.cr.Get(ctx, order.CustomerID)
+	if err != nil {
+		log.Warn("Failed to get customer", log.Fstring("customerID", order.CustomerID), log.Ferror(err))
+		customer = &entity.Customer{ID: order.CustomerID}
+	}
+
+	return &OrderDetails{
+		Order:      order,
+		Customer:   customer,
+		OrderLines: newOrderLineDetails(order.OrderLines),
+	}, nil
+}
+
+func (ouc *orderUseCase) CancelOrder(ctx context.Context, id string) (*OrderDetails, error) {
+	return ouc.UpdateOrderStatus(ctx, id, entity.OrderStatusCancelled)
+}
+
+func (ouc *orderUseCase) DeleteOrder(ctx context.Context, id string) error {
+	order, err := ouc.or.Get(ctx, id)
+	if err != nil {
+		log.Error("Failed to get order", log.Ferror(err))
 		return err
 	}
-	if err = ouc.or.Create(ctx, *order); err != nil {
-		log.Error("Failed to create order", log.Ferror(err))
+
+	// The stock of an open order is released before the order is deleted, so that an order
+	// whose stock could not be released is left to delete again rather than holding it for good.
+	switch order.Status { //nolint:exhaustive // only these statuses hold stock
+	case entity.OrderStatusPending, entity.OrderStatusConfirmed, entity.OrderStatusPaid:
+		err = ouc.sr.Release(ctx, id)
+		if errors.Is(err, entity.ErrNotFound) {
+			// Orders created before stock was tracked, or whose stock was never reserved, have no reservation.
+			log.Warn("Stock reservation not found", log.Fstring("orderID", id))
+		} else if err != nil {
+			log.Error("Failed to release stock", log.Fstring("orderID", id), log.Ferror(err))
+			return err
+		}
+	}
+
+	if err = ouc.tr.Transaction(ctx, func(ctx context.Context) error {
+		if err := ouc.or.Delete(ctx, id); err != nil {
+			return err
+		}
+		return ouc.addEvent(ctx, entity.EventTypeOrderDeleted, id, entity.Deletion{ID: id})
+	}); err != nil {
+		log.Error("Failed to delete order", log.Ferror(err))
 		return err
 	}
 	return nil
 }
 
-func (ouc *orderUseCase) DeleteOrder(ctx context.Context, id string) error {
-	if err := ouc.or.Delete(ctx, id); err != nil {
-		log.Error("Failed to delete order", log.Ferror(err))
+func (ouc *orderUseCase) WatchOrders(ctx context.Context, after int64, send func(OrderChange) error) error {
+	err := ouc.cw.Watch(ctx, after, func(event entity.Event) error {
+		change := OrderChange{
+			ID:       event.AggregateID,
+			Sequence: event.Sequence,
+		}
+		switch event.Type {
+		case entity.EventTypeOrderPlaced:
+			change.Type = ChangeTypeCreated
+		case entity.EventTypeOrderStatusChanged, entity.EventTypeOrderCancelled:
+			change.Type = ChangeTypeUpdated
+		case entity.EventTypeOrderDeleted:
+			change.Type = ChangeTypeDeleted
+			return send(change)
+		default:
+			return nil
+		}
+
+		// The order is sent with its customer and lines, which the events do not carry.
+		details, err := ouc.GetOrder(ctx, event.AggregateID)
+		if errors.Is(err, entity.ErrNotFound) {
+			// Deleted since, which is sent with the deletion event.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		change.Order = details
+		return send(change)
+	})
+	if err != nil {
+		log.Error("Failed to watch orders", log.Ferror(err))
 		return err
 	}
 	return nil
 }
+
+// addEvent stores an event in the outbox, within the transaction of ctx.
+func (ouc *orderUseCase) addEvent(ctx context.Context, eventType entity.EventType, aggregateID string, payload interface{}) error {
+	event, err := entity.NewEvent(eventType, aggregateID, payload, time.Now())
+	if err != nil {
+		return err
+	}
+	return ouc.obr.Add(ctx, *event)
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -9,7 +11,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
 	repo_mock "github.com/tusmasoma/go-microservice-k8s/services/order/repository/mock"
+	service_mock "github.com/tusmasoma/go-microservice-k8s/services/order/service/mock"
 )
 
 func TestOrderUseCase_GetOrderCreationResources(t *testing.T) {
@@ -26,7 +30,7 @@ func TestOrderUseCase_GetOrderCreationResources(t *testing.T) {
 		{
 			ID:    uuid.New().String(),
 			Name:  "item1",
-			Price: 1000,
+			Price: entity.Money{Amount: 1000, Currency: "USD"},
 		},
 	}
 
@@ -87,7 +91,7 @@ func TestOrderUseCase_GetOrderCreationResources(t *testing.T) {
 				tt.setup(cr, cir, or)
 			}
 
-			ouc := NewOrderUseCase(cr, cir, or)
+			ouc := NewOrderUseCase(cr, cir, or, nil, nil, nil, nil, nil)
 
 			gotCustomers, gotItems, err := ouc.GetOrderCreationResources(tt.arg.ctx)
 			if (err != nil) != (tt.want.err != nil) {
@@ -122,8 +126,11 @@ func TestOrderUseCase_GetOrder(t *testing.T) {
 			{
 				Count:         1,
 				CatalogItemID: catalogItemID,
+				ItemName:      "item1",
+				UnitPrice:     entity.Money{Amount: 1000, Currency: "USD"},
 			},
 		},
+		TotalPrice: entity.Money{Amount: 1000, Currency: "USD"},
 	}
 
 	customer := entity.Customer{
@@ -134,7 +141,7 @@ func TestOrderUseCase_GetOrder(t *testing.T) {
 	item := entity.CatalogItem{
 		ID:    catalogItemID,
 		Name:  "item1",
-		Price: 1000,
+		Price: entity.Money{Amount: 1000, Currency: "USD"},
 	}
 
 	patterns := []struct {
@@ -166,8 +173,6 @@ func TestOrderUseCase_GetOrder(t *testing.T) {
 				)
 				cr.EXPECT().Get(gomock.Any(), customerID).Return(
 					&customer, nil)
-				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}).Return(
-					[]entity.CatalogItem{item}, nil)
 			},
 			arg: struct {
 				ctx context.Context
@@ -209,7 +214,7 @@ func TestOrderUseCase_GetOrder(t *testing.T) {
 				tt.setup(cr, cir, or)
 			}
 
-			ouc := NewOrderUseCase(cr, cir, or)
+			ouc := NewOrderUseCase(cr, cir, or, nil, nil, nil, nil, nil)
 
 			gotOrderDetails, err := ouc.GetOrder(tt.arg.ctx, tt.arg.id)
 			if (err != nil) != (tt.want.err != nil) {
@@ -242,8 +247,11 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 				{
 					Count:         1,
 					CatalogItemID: catalogItemID,
+					ItemName:      "item1",
+					UnitPrice:     entity.Money{Amount: 1000, Currency: "USD"},
 				},
 			},
+			TotalPrice: entity.Money{Amount: 1000, Currency: "USD"},
 		},
 	}
 
@@ -255,9 +263,11 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 	item := entity.CatalogItem{
 		ID:    catalogItemID,
 		Name:  "item1",
-		Price: 1000,
+		Price: entity.Money{Amount: 1000, Currency: "USD"},
 	}
 
+	minTotal, maxTotal := entity.Money{Amount: 500, Currency: "USD"}, entity.Money{Amount: 1500, Currency: "USD"}
+
 	patterns := []struct {
 		name  string
 		setup func(
@@ -266,10 +276,13 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 			m2 *repo_mock.MockOrderRepository,
 		)
 		arg struct {
-			ctx context.Context
+			ctx    context.Context
+			filter repository.OrderFilter
+			page   repository.Page
 		}
 		want struct {
 			orderDetails []*OrderDetails
+			info         repository.PageInfo
 			err          error
 		}
 	}{
@@ -280,22 +293,26 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 				cir *repo_mock.MockCatalogItemRepository,
 				or *repo_mock.MockOrderRepository,
 			) {
-				or.EXPECT().List(gomock.Any()).Return(
+				or.EXPECT().List(gomock.Any(), repository.OrderFilter{CustomerID: customerID}, repository.Page{Size: 1}).Return(
 					orders,
+					repository.PageInfo{NextToken: "next"},
 					nil,
 				)
-				cr.EXPECT().Get(gomock.Any(), customerID).Return(
-					&customer, nil)
-				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}).Return(
-					[]entity.CatalogItem{item}, nil)
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{customerID}).Return(
+					[]entity.Customer{customer}, nil)
 			},
 			arg: struct {
-				ctx context.Context
+				ctx    context.Context
+				filter repository.OrderFilter
+				page   repository.Page
 			}{
-				ctx: context.Background(),
+				ctx:    context.Background(),
+				filter: repository.OrderFilter{CustomerID: customerID},
+				page:   repository.Page{Size: 1},
 			},
 			want: struct {
 				orderDetails []*OrderDetails
+				info         repository.PageInfo
 				err          error
 			}{
 				orderDetails: []*OrderDetails{
@@ -310,7 +327,103 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 						},
 					},
 				},
-				err: nil,
+				info: repository.PageInfo{NextToken: "next"},
+				err:  nil,
+			},
+		},
+		{
+			name: "success: customer no longer exists",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+			) {
+				or.EXPECT().List(gomock.Any(), repository.OrderFilter{}, repository.Page{}).Return(
+					orders,
+					repository.PageInfo{},
+					nil,
+				)
+				cr.EXPECT().ListByIDs(gomock.Any(), []string{customerID}).Return(
+					nil, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				filter repository.OrderFilter
+				page   repository.Page
+			}{
+				ctx: context.Background(),
+			},
+			want: struct {
+				orderDetails []*OrderDetails
+				info         repository.PageInfo
+				err          error
+			}{
+				orderDetails: []*OrderDetails{
+					{
+						Order:    orders[0],
+						Customer: &entity.Customer{ID: customerID},
+						OrderLines: []*OrderLineDetails{
+							{
+								Count:       1,
+								CatalogItem: &item,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Fail: total range is reversed",
+			arg: struct {
+				ctx    context.Context
+				filter repository.OrderFilter
+				page   repository.Page
+			}{
+				ctx:    context.Background(),
+				filter: repository.OrderFilter{MinTotal: &maxTotal, MaxTotal: &minTotal},
+			},
+			want: struct {
+				orderDetails []*OrderDetails
+				info         repository.PageInfo
+				err          error
+			}{
+				err: fmt.Errorf("%w: min total must not exceed max total", repository.ErrInvalidOrderFilter),
+			},
+		},
+		{
+			name: "Fail: sorting by total price without a currency",
+			arg: struct {
+				ctx    context.Context
+				filter repository.OrderFilter
+				page   repository.Page
+			}{
+				ctx:    context.Background(),
+				filter: repository.OrderFilter{Sort: repository.OrderSortTotalPrice},
+			},
+			want: struct {
+				orderDetails []*OrderDetails
+				info         repository.PageInfo
+				err          error
+			}{
+				err: fmt.Errorf("%w: sorting by total price requires a currency", repository.ErrInvalidOrderFilter),
+			},
+		},
+		{
+			name: "Fail: min total in another currency than the filter",
+			arg: struct {
+				ctx    context.Context
+				filter repository.OrderFilter
+				page   repository.Page
+			}{
+				ctx:    context.Background(),
+				filter: repository.OrderFilter{MinTotal: &minTotal, Currency: "EUR"},
+			},
+			want: struct {
+				orderDetails []*OrderDetails
+				info         repository.PageInfo
+				err          error
+			}{
+				err: fmt.Errorf("%w: min total and max total must be in the currency of the filter", repository.ErrInvalidOrderFilter),
 			},
 		},
 	}
@@ -329,9 +442,9 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 				tt.setup(cr, cir, or)
 			}
 
-			ouc := NewOrderUseCase(cr, cir, or)
+			ouc := NewOrderUseCase(cr, cir, or, nil, nil, nil, nil, nil)
 
-			gotOrderDetails, err := ouc.ListOrders(tt.arg.ctx)
+			gotOrderDetails, gotInfo, err := ouc.ListOrders(tt.arg.ctx, tt.arg.filter, tt.arg.page)
 			if (err != nil) != (tt.want.err != nil) {
 				t.Errorf("ListOrder() error = %v, wantErr %v", err, tt.want.err)
 			} else if err != nil && tt.want.err != nil && err.Error() != tt.want.err.Error() {
@@ -341,22 +454,111 @@ func TestOrderUseCase_ListOrder(t *testing.T) {
 			if !reflect.DeepEqual(gotOrderDetails, tt.want.orderDetails) {
 				t.Errorf("ListOrder() got = %v, want %v", gotOrderDetails, tt.want.orderDetails)
 			}
+			if gotInfo != tt.want.info {
+				t.Errorf("ListOrder() got = %v, want %v", gotInfo, tt.want.info)
+			}
+		})
+	}
+}
+
+func BenchmarkOrderUseCase_ListOrders(b *testing.B) {
+	for _, size := range []int{10, 100, 500} {
+		size := size
+		b.Run(fmt.Sprintf("orders=%d", size), func(b *testing.B) {
+			orderDate := time.Now()
+			orders := make([]*entity.Order, 0, size)
+			customers := make([]entity.Customer, 0, size)
+			for i := 0; i < size; i++ {
+				customerID := uuid.New().String()
+				orders = append(orders, &entity.Order{
+					ID:         uuid.New().String(),
+					CustomerID: customerID,
+					OrderDate:  &orderDate,
+					OrderLines: []*entity.OrderLine{
+						{
+							Count:         1,
+							CatalogItemID: uuid.New().String(),
+							ItemName:      "item",
+							UnitPrice:     entity.Money{Amount: 1000, Currency: "USD"},
+						},
+					},
+					TotalPrice: entity.Money{Amount: 1000, Currency: "USD"},
+				})
+				customers = append(customers, entity.Customer{ID: customerID, Name: "customer"})
+			}
+
+			ctrl := gomock.NewController(b)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+
+			// Any call other than the ones below fails the benchmark, so the counts
+			// prove that the number of calls does not grow with the number of orders.
+			var orderCalls, customerCalls int
+			or.EXPECT().List(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ repository.OrderFilter, _ repository.Page) ([]*entity.Order, repository.PageInfo, error) {
+					orderCalls++
+					return orders, repository.PageInfo{}, nil
+				}).AnyTimes()
+			cr.EXPECT().ListByIDs(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ []string) ([]entity.Customer, error) {
+					customerCalls++
+					return customers, nil
+				}).AnyTimes()
+
+			ouc := NewOrderUseCase(cr, cir, or, nil, nil, nil, nil, nil)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := ouc.ListOrders(context.Background(), repository.OrderFilter{}, repository.Page{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			if orderCalls != b.N || customerCalls != b.N {
+				b.Fatalf("got %d order and %d customer calls for %d iterations, want one each per iteration", orderCalls, customerCalls, b.N)
+			}
+			b.ReportMetric(float64(orderCalls+customerCalls)/float64(b.N), "calls/op")
 		})
 	}
 }
 
-func TestOrderUseCase_CreateOrder(t *testing.T) {
+func TestOrderUseCase_CreateOrder(t *testing.T) { //nolint:gocognit // This is a test function
 	t.Parallel()
 
+	start := time.Now()
 	customerID := uuid.New().String()
 	catalogItemID := uuid.New().String()
 
+	customer := entity.Customer{
+		ID:   customerID,
+		Name: "customer1",
+	}
+
+	item := entity.CatalogItem{
+		ID:    catalogItemID,
+		Name:  "item1",
+		Price: entity.Money{Amount: 1000, Currency: "USD"},
+	}
+	convertedItem := entity.CatalogItem{
+		ID:    catalogItemID,
+		Name:  "item1",
+		Price: entity.Money{Amount: 920, Currency: "EUR"},
+		Conversion: &entity.PriceConversion{
+			BasePrice: item.Price,
+			Rate:      entity.ExchangeRate{From: "USD", To: "EUR", Rate: 92000000},
+		},
+	}
+
 	patterns := []struct {
 		name  string
 		setup func(
 			m *repo_mock.MockCustomerRepository,
 			m1 *repo_mock.MockCatalogItemRepository,
 			m2 *repo_mock.MockOrderRepository,
+			m3 *repo_mock.MockStockRepository,
+			m4 *service_mock.MockOrderService,
 		)
 		arg struct {
 			ctx    context.Context
@@ -370,18 +572,101 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 				cr *repo_mock.MockCustomerRepository,
 				cir *repo_mock.MockCatalogItemRepository,
 				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
 			) {
-				or.EXPECT().Create(
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{item}, nil)
+				os.EXPECT().PlaceOrder(
 					gomock.Any(),
 					gomock.Any(),
-				).Do(func(_ context.Context, order entity.Order) {
+				).DoAndReturn(func(_ context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
 					if order.CustomerID != customerID {
 						t.Errorf("unexpected customerID: got %v, want %v", order.CustomerID, customerID)
 					}
+					if order.OrderDate == nil || order.OrderDate.Before(start) || order.OrderDate.After(time.Now()) {
+						t.Errorf("unexpected orderDate: got %v, want the time the order was created", order.OrderDate)
+					}
+					if len(order.OrderLines) != 1 {
+						t.Fatalf("unexpected number of order lines: got %v, want %v", len(order.OrderLines), 1)
+					}
 					if order.OrderLines[0].CatalogItemID != catalogItemID {
 						t.Errorf("unexpected catalogItemID: got %v, want %v", order.OrderLines[0].CatalogItemID, catalogItemID)
 					}
-				}).Return(nil)
+					if order.OrderLines[0].Count != 2 {
+						t.Errorf("unexpected count: got %v, want %v", order.OrderLines[0].Count, 2)
+					}
+					if order.OrderLines[0].ItemName != "item1" {
+						t.Errorf("unexpected itemName: got %v, want %v", order.OrderLines[0].ItemName, "item1")
+					}
+					if order.OrderLines[0].UnitPrice != (entity.Money{Amount: 1000, Currency: "USD"}) {
+						t.Errorf("unexpected unitPrice: got %v, want %v", order.OrderLines[0].UnitPrice, entity.Money{Amount: 1000, Currency: "USD"})
+					}
+					if order.TotalPrice != (entity.Money{Amount: 2000, Currency: "USD"}) {
+						t.Errorf("unexpected totalPrice: got %v, want %v", order.TotalPrice, entity.Money{Amount: 2000, Currency: "USD"})
+					}
+					return &order, &customer, nil
+				})
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success: duplicate item lines are merged",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{item}, nil)
+				os.EXPECT().PlaceOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(_ context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
+					if order.CustomerID != customerID {
+						t.Errorf("unexpected customerID: got %v, want %v", order.CustomerID, customerID)
+					}
+					if len(order.OrderLines) != 1 {
+						t.Fatalf("unexpected number of order lines: got %v, want %v", len(order.OrderLines), 1)
+					}
+					if order.OrderLines[0].CatalogItemID != catalogItemID {
+						t.Errorf("unexpected catalogItemID: got %v, want %v", order.OrderLines[0].CatalogItemID, catalogItemID)
+					}
+					if order.OrderLines[0].Count != 3 {
+						t.Errorf("unexpected count: got %v, want %v", order.OrderLines[0].Count, 3)
+					}
+					if order.OrderLines[0].ItemName != "item1" {
+						t.Errorf("unexpected itemName: got %v, want %v", order.OrderLines[0].ItemName, "item1")
+					}
+					if order.OrderLines[0].UnitPrice != (entity.Money{Amount: 1000, Currency: "USD"}) {
+						t.Errorf("unexpected unitPrice: got %v, want %v", order.OrderLines[0].UnitPrice, entity.Money{Amount: 1000, Currency: "USD"})
+					}
+					if order.TotalPrice != (entity.Money{Amount: 3000, Currency: "USD"}) {
+						t.Errorf("unexpected totalPrice: got %v, want %v", order.TotalPrice, entity.Money{Amount: 3000, Currency: "USD"})
+					}
+					return &order, &customer, nil
+				})
 			},
 			arg: struct {
 				ctx    context.Context
@@ -393,103 +678,978 @@ func TestOrderUseCase_CreateOrder(t *testing.T) {
 					OrderLine: []struct {
 						CatalogItemID string
 						Count         int
+						SKU           string
 					}{
 						{
 							CatalogItemID: catalogItemID,
 							Count:         1,
 						},
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+						},
 					},
 				},
 			},
 			wantErr: nil,
 		},
-	}
-
-	for _, tt := range patterns {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			ctrl := gomock.NewController(t)
-			cr := repo_mock.NewMockCustomerRepository(ctrl)
-			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
-			or := repo_mock.NewMockOrderRepository(ctrl)
-
-			if tt.setup != nil {
-				tt.setup(cr, cir, or)
-			}
-
-			ouc := NewOrderUseCase(cr, cir, or)
-
-			err := ouc.CreateOrder(tt.arg.ctx, tt.arg.params)
-			if (err != nil) != (tt.wantErr != nil) {
-				t.Errorf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
-			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
-				t.Errorf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestOrderUseCase_DeleteOrder(t *testing.T) {
-	t.Parallel()
-
-	orderID := uuid.New().String()
-
-	patterns := []struct {
-		name  string
-		setup func(
-			m *repo_mock.MockCustomerRepository,
-			m1 *repo_mock.MockCatalogItemRepository,
-			m2 *repo_mock.MockOrderRepository,
-		)
-		arg struct {
-			ctx context.Context
-			id  string
-		}
-		wantErr error
-	}{
 		{
-			name: "success",
+			name: "success: variants of an item are ordered on separate lines",
 			setup: func(
 				cr *repo_mock.MockCustomerRepository,
 				cir *repo_mock.MockCatalogItemRepository,
 				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
 			) {
-				or.EXPECT().Delete(gomock.Any(), orderID).Return(nil)
+				shirt := item
+				shirt.Variants = []entity.Variant{
+					{SKU: "ITEM1-RED", Attributes: map[string]string{"color": "red"}},
+					{SKU: "ITEM1-BLUE", Attributes: map[string]string{"color": "blue"}, Price: &entity.Money{Amount: 1200, Currency: "USD"}},
+				}
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{shirt}, nil)
+				os.EXPECT().PlaceOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(_ context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
+					if len(order.OrderLines) != 2 {
+						t.Fatalf("unexpected number of order lines: got %v, want %v", len(order.OrderLines), 2)
+					}
+					if order.OrderLines[0].SKU != "ITEM1-RED" || order.OrderLines[0].ItemName != "item1 (color: red)" {
+						t.Errorf("unexpected order line: %v", order.OrderLines[0])
+					}
+					if order.OrderLines[1].SKU != "ITEM1-BLUE" || order.OrderLines[1].UnitPrice != (entity.Money{Amount: 1200, Currency: "USD"}) {
+						t.Errorf("unexpected order line: %v", order.OrderLines[1])
+					}
+					if order.TotalPrice != (entity.Money{Amount: 4400, Currency: "USD"}) {
+						t.Errorf("unexpected totalPrice: got %v, want %v", order.TotalPrice, entity.Money{Amount: 4400, Currency: "USD"})
+					}
+					return &order, &customer, nil
+				})
 			},
 			arg: struct {
-				ctx context.Context
-				id  string
+				ctx    context.Context
+				params *CreateOrderParams
 			}{
 				ctx: context.Background(),
-				id:  orderID,
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+							SKU:           "ITEM1-RED",
+						},
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+							SKU:           "ITEM1-BLUE",
+						},
+					},
+				},
 			},
 			wantErr: nil,
 		},
-	}
-
-	for _, tt := range patterns {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			ctrl := gomock.NewController(t)
-			cr := repo_mock.NewMockCustomerRepository(ctrl)
-			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
-			or := repo_mock.NewMockOrderRepository(ctrl)
-
-			if tt.setup != nil {
-				tt.setup(cr, cir, or)
-			}
-
-			ouc := NewOrderUseCase(cr, cir, or)
-
-			err := ouc.DeleteOrder(tt.arg.ctx, tt.arg.id)
-			if (err != nil) != (tt.wantErr != nil) {
-				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
-			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
-				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
+		{
+			name: "success: items are priced in the currency of the order",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "EUR").Return(
+					[]entity.CatalogItem{convertedItem}, nil)
+				os.EXPECT().PlaceOrder(
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(_ context.Context, order entity.Order) (*entity.Order, *entity.Customer, error) {
+					if !reflect.DeepEqual(order.OrderLines[0].Conversion, convertedItem.Conversion) {
+						t.Errorf("unexpected conversion: got %v, want %v", order.OrderLines[0].Conversion, convertedItem.Conversion)
+					}
+					if order.TotalPrice != (entity.Money{Amount: 1840, Currency: "EUR"}) {
+						t.Errorf("unexpected totalPrice: got %v, want %v", order.TotalPrice, entity.Money{Amount: 1840, Currency: "EUR"})
+					}
+					return &order, &customer, nil
+				})
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					Currency:   "EUR",
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+						},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Fail: unsupported currency",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					Currency:   "XXX",
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         2,
+						},
+					},
+				},
+			},
+			wantErr: ErrInvalidOrderRequest,
+		},
+		{
+			name: "Fail: count is zero",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         0,
+						},
+					},
+				},
+			},
+			wantErr: ErrInvalidOrderRequest,
+		},
+		{
+			name: "Fail: customer not found",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{item}, nil)
+				os.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(nil, nil, ErrCustomerNotFound)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         1,
+						},
+					},
+				},
+			},
+			wantErr: ErrCustomerNotFound,
+		},
+		{
+			name: "Fail: catalog item not found",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{}, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         1,
+						},
+					},
+				},
+			},
+			wantErr: ErrCatalogItemNotFound,
+		},
+		{
+			name: "Fail: insufficient stock",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+				os *service_mock.MockOrderService,
+			) {
+				cir.EXPECT().ListByIDs(gomock.Any(), []string{catalogItemID}, "").Return(
+					[]entity.CatalogItem{item}, nil)
+				os.EXPECT().PlaceOrder(gomock.Any(), gomock.Any()).Return(
+					nil, nil, entity.NewError(entity.ErrFailedPrecondition, "insufficient stock"))
+			},
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+					OrderLine: []struct {
+						CatalogItemID string
+						Count         int
+						SKU           string
+					}{
+						{
+							CatalogItemID: catalogItemID,
+							Count:         1,
+						},
+					},
+				},
+			},
+			wantErr: entity.ErrFailedPrecondition,
+		},
+		{
+			name: "Fail: no order lines",
+			arg: struct {
+				ctx    context.Context
+				params *CreateOrderParams
+			}{
+				ctx: context.Background(),
+				params: &CreateOrderParams{
+					CustomerID: customerID,
+				},
+			},
+			wantErr: ErrInvalidOrderRequest,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+			sr := repo_mock.NewMockStockRepository(ctrl)
+			os := service_mock.NewMockOrderService(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr, cir, or, sr, os)
+			}
+
+			ouc := NewOrderUseCase(cr, cir, or, sr, os, nil, nil, nil)
+
+			orderDetails, err := ouc.CreateOrder(tt.arg.ctx, tt.arg.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if orderDetails.Order.ID == "" {
+					t.Errorf("CreateOrder() returned an order without ID")
+				}
+				if orderDetails.Customer.ID != tt.arg.params.CustomerID {
+					t.Errorf("CreateOrder() customer = %v, want %v", orderDetails.Customer.ID, tt.arg.params.CustomerID)
+				}
+				if len(orderDetails.OrderLines) != len(orderDetails.Order.OrderLines) {
+					t.Errorf("CreateOrder() got %d line details, want %d", len(orderDetails.OrderLines), len(orderDetails.Order.OrderLines))
+				}
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_UpdateOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	customerID := uuid.New().String()
+	orderDate := time.Now()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *repo_mock.MockCustomerRepository,
+			m1 *repo_mock.MockCatalogItemRepository,
+			m2 *repo_mock.MockOrderRepository,
+			m3 *repo_mock.MockStockRepository,
+		)
+		arg struct {
+			ctx    context.Context
+			id     string
+			status entity.OrderStatus
+		}
+		wantErr   error
+		wantEvent entity.EventType
+	}{
+		{
+			name: "success",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusPending,
+					},
+					nil,
+				)
+				or.EXPECT().UpdateStatus(
+					gomock.Any(),
+					gomock.Any(),
+				).Do(func(_ context.Context, history entity.OrderStatusHistory) {
+					if history.OrderID != orderID {
+						t.Errorf("unexpected OrderID: got %v, want %v", history.OrderID, orderID)
+					}
+					if history.FromStatus != entity.OrderStatusPending {
+						t.Errorf("unexpected FromStatus: got %v, want %v", history.FromStatus, entity.OrderStatusPending)
+					}
+					if history.ToStatus != entity.OrderStatusConfirmed {
+						t.Errorf("unexpected ToStatus: got %v, want %v", history.ToStatus, entity.OrderStatusConfirmed)
+					}
+				}).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), customerID).Return(&entity.Customer{ID: customerID}, nil)
+			},
+			arg: struct {
+				ctx    context.Context
+				id     string
+				status entity.OrderStatus
+			}{
+				ctx:    context.Background(),
+				id:     orderID,
+				status: entity.OrderStatusConfirmed,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderStatusChanged,
+		},
+		{
+			name: "success: customer cannot be fetched after the status change",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusPaid,
+					},
+					nil,
+				)
+				or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), customerID).Return(nil, errors.New("customer service unavailable"))
+			},
+			arg: struct {
+				ctx    context.Context
+				id     string
+				status entity.OrderStatus
+			}{
+				ctx:    context.Background(),
+				id:     orderID,
+				status: entity.OrderStatusShipped,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderStatusChanged,
+		},
+		{
+			name: "Fail: invalid transition",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusDelivered,
+					},
+					nil,
+				)
+			},
+			arg: struct {
+				ctx    context.Context
+				id     string
+				status entity.OrderStatus
+			}{
+				ctx:    context.Background(),
+				id:     orderID,
+				status: entity.OrderStatusShipped,
+			},
+			wantErr: entity.ErrInvalidStatusTransition,
+		},
+		{
+			name: "Fail: status changed concurrently",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusPaid,
+					},
+					nil,
+				)
+				or.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(repository.ErrStatusConflict)
+			},
+			arg: struct {
+				ctx    context.Context
+				id     string
+				status entity.OrderStatus
+			}{
+				ctx:    context.Background(),
+				id:     orderID,
+				status: entity.OrderStatusShipped,
+			},
+			wantErr: repository.ErrStatusConflict,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+			sr := repo_mock.NewMockStockRepository(ctrl)
+
+			tr := repo_mock.NewMockTransactionRepository(ctrl)
+			obr := repo_mock.NewMockOutboxRepository(ctrl)
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			wantEvents := 0
+			if tt.wantEvent != "" {
+				wantEvents = 1
+			}
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				if len(events) != 1 || events[0].Type != tt.wantEvent {
+					t.Errorf("unexpected events: %v", events)
+				}
+			}).Return(nil).Times(wantEvents)
+
+			if tt.setup != nil {
+				tt.setup(cr, cir, or, sr)
+			}
+
+			ouc := NewOrderUseCase(cr, cir, or, sr, nil, tr, obr, nil)
+
+			orderDetails, err := ouc.UpdateOrderStatus(tt.arg.ctx, tt.arg.id, tt.arg.status)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateOrderStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && orderDetails.Customer.ID != customerID {
+				t.Errorf("UpdateOrderStatus() customer = %v, want %v", orderDetails.Customer.ID, customerID)
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_CancelOrder(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	customerID := uuid.New().String()
+	orderDate := time.Now()
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *repo_mock.MockCustomerRepository,
+			m1 *repo_mock.MockCatalogItemRepository,
+			m2 *repo_mock.MockOrderRepository,
+			m3 *repo_mock.MockStockRepository,
+		)
+		arg struct {
+			ctx context.Context
+			id  string
+		}
+		wantErr   error
+		wantEvent entity.EventType
+	}{
+		{
+			name: "success",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusConfirmed,
+					},
+					nil,
+				)
+				or.EXPECT().UpdateStatus(
+					gomock.Any(),
+					gomock.Any(),
+				).Do(func(_ context.Context, history entity.OrderStatusHistory) {
+					if history.ToStatus != entity.OrderStatusCancelled {
+						t.Errorf("unexpected ToStatus: got %v, want %v", history.ToStatus, entity.OrderStatusCancelled)
+					}
+				}).Return(nil)
+				cr.EXPECT().Get(gomock.Any(), customerID).Return(&entity.Customer{ID: customerID}, nil)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderCancelled,
+		},
+		{
+			name: "Fail: shipped order cannot be cancelled",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(
+					&entity.Order{
+						ID:         orderID,
+						CustomerID: customerID,
+						OrderDate:  &orderDate,
+						Status:     entity.OrderStatusShipped,
+					},
+					nil,
+				)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr: entity.ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+			sr := repo_mock.NewMockStockRepository(ctrl)
+
+			tr := repo_mock.NewMockTransactionRepository(ctrl)
+			obr := repo_mock.NewMockOutboxRepository(ctrl)
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			wantEvents := 0
+			if tt.wantEvent != "" {
+				wantEvents = 1
+			}
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				if len(events) != 1 || events[0].Type != tt.wantEvent {
+					t.Errorf("unexpected events: %v", events)
+				}
+			}).Return(nil).Times(wantEvents)
+
+			if tt.setup != nil {
+				tt.setup(cr, cir, or, sr)
+			}
+
+			ouc := NewOrderUseCase(cr, cir, or, sr, nil, tr, obr, nil)
+
+			orderDetails, err := ouc.CancelOrder(tt.arg.ctx, tt.arg.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CancelOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && orderDetails.Customer.ID != customerID {
+				t.Errorf("CancelOrder() customer = %v, want %v", orderDetails.Customer.ID, customerID)
+			}
+		})
+	}
+}
+
+func TestOrderUseCase_DeleteOrder(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	order := func(status entity.OrderStatus) *entity.Order {
+		return &entity.Order{ID: orderID, CustomerID: uuid.New().String(), Status: status}
+	}
+	errConn := errors.New("connection refused")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *repo_mock.MockCustomerRepository,
+			m1 *repo_mock.MockCatalogItemRepository,
+			m2 *repo_mock.MockOrderRepository,
+			m3 *repo_mock.MockStockRepository,
+		)
+		arg struct {
+			ctx context.Context
+			id  string
+		}
+		wantErr   error
+		wantEvent entity.EventType
+	}{
+		{
+			name: "success",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(order(entity.OrderStatusConfirmed), nil)
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(nil)
+				or.EXPECT().Delete(gomock.Any(), orderID).Return(nil)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderDeleted,
+		},
+		{
+			name: "success: order without reservation",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(order(entity.OrderStatusPending), nil)
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(entity.NewError(entity.ErrNotFound, "stock reservation not found"))
+				or.EXPECT().Delete(gomock.Any(), orderID).Return(nil)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderDeleted,
+		},
+		{
+			name: "success: stock of delivered order is not released",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(order(entity.OrderStatusDelivered), nil)
+				or.EXPECT().Delete(gomock.Any(), orderID).Return(nil)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr:   nil,
+			wantEvent: entity.EventTypeOrderDeleted,
+		},
+		{
+			name: "Fail: stock cannot be released",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(order(entity.OrderStatusPaid), nil)
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(errConn)
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr: errConn,
+		},
+		{
+			name: "Fail: order not found",
+			setup: func(
+				cr *repo_mock.MockCustomerRepository,
+				cir *repo_mock.MockCatalogItemRepository,
+				or *repo_mock.MockOrderRepository,
+				sr *repo_mock.MockStockRepository,
+			) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(nil, entity.NewError(entity.ErrNotFound, "order not found"))
+			},
+			arg: struct {
+				ctx context.Context
+				id  string
+			}{
+				ctx: context.Background(),
+				id:  orderID,
+			},
+			wantErr: entity.NewError(entity.ErrNotFound, "order not found"),
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			cir := repo_mock.NewMockCatalogItemRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+			sr := repo_mock.NewMockStockRepository(ctrl)
+
+			tr := repo_mock.NewMockTransactionRepository(ctrl)
+			obr := repo_mock.NewMockOutboxRepository(ctrl)
+			tr.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			}).AnyTimes()
+			wantEvents := 0
+			if tt.wantEvent != "" {
+				wantEvents = 1
+			}
+			obr.EXPECT().Add(gomock.Any(), gomock.Any()).Do(func(_ context.Context, events ...entity.Event) {
+				if len(events) != 1 || events[0].Type != tt.wantEvent {
+					t.Errorf("unexpected events: %v", events)
+				}
+			}).Return(nil).Times(wantEvents)
+
+			if tt.setup != nil {
+				tt.setup(cr, cir, or, sr)
+			}
+
+			ouc := NewOrderUseCase(cr, cir, or, sr, nil, tr, obr, nil)
+
+			err := ouc.DeleteOrder(tt.arg.ctx, tt.arg.id)
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
+			} else if err != nil && tt.wantErr != nil && err.Error() != tt.wantErr.Error() {
+				t.Errorf("DeleteOrder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeChangeWatcher sends its events and returns.
+type fakeChangeWatcher struct {
+	events []entity.Event
+}
+
+func (w *fakeChangeWatcher) Watch(_ context.Context, after int64, send func(entity.Event) error) error {
+	for _, event := range w.events {
+		if event.Sequence <= after {
+			continue
+		}
+		if err := send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestOrderUseCase_WatchOrders(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	customer := entity.Customer{
+		ID:   uuid.New().String(),
+		Name: "customer1",
+	}
+	placed := entity.Order{
+		ID:         orderID,
+		CustomerID: customer.ID,
+		Status:     entity.OrderStatusConfirmed,
+	}
+	shipped := placed
+	shipped.Status = entity.OrderStatusShipped
+
+	newEvent := func(sequence int64, eventType entity.EventType, payload interface{}) entity.Event {
+		event, err := entity.NewEvent(eventType, orderID, payload, time.Now())
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		event.Sequence = sequence
+		return *event
+	}
+	events := []entity.Event{
+		newEvent(1, entity.EventTypeOrderPlaced, placed),
+		newEvent(2, entity.EventTypeOrderStatusChanged, entity.OrderStatusHistory{OrderID: orderID, ToStatus: entity.OrderStatusShipped}),
+		newEvent(3, entity.EventTypeOrderDeleted, entity.Deletion{ID: orderID}),
+	}
+	errSend := errors.New("stream closed")
+
+	patterns := []struct {
+		name  string
+		setup func(
+			m *repo_mock.MockCustomerRepository,
+			m1 *repo_mock.MockOrderRepository,
+		)
+		after   int64
+		sendErr error
+		want    []OrderChange
+		wantErr error
+	}{
+		{
+			name: "success: changed orders are sent as they are now",
+			setup: func(cr *repo_mock.MockCustomerRepository, or *repo_mock.MockOrderRepository) {
+				gomock.InOrder(
+					or.EXPECT().Get(gomock.Any(), orderID).Return(&placed, nil),
+					or.EXPECT().Get(gomock.Any(), orderID).Return(&shipped, nil),
+				)
+				cr.EXPECT().Get(gomock.Any(), customer.ID).Return(&customer, nil).Times(2)
+			},
+			after: 0,
+			want: []OrderChange{
+				{Type: ChangeTypeCreated, ID: orderID, Order: &OrderDetails{Order: &placed, Customer: &customer, OrderLines: []*OrderLineDetails{}}, Sequence: 1},
+				{Type: ChangeTypeUpdated, ID: orderID, Order: &OrderDetails{Order: &shipped, Customer: &customer, OrderLines: []*OrderLineDetails{}}, Sequence: 2},
+				{Type: ChangeTypeDeleted, ID: orderID, Sequence: 3},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "success: orders deleted since are only sent as deleted",
+			setup: func(cr *repo_mock.MockCustomerRepository, or *repo_mock.MockOrderRepository) {
+				or.EXPECT().Get(gomock.Any(), orderID).Return(nil, entity.NewError(entity.ErrNotFound, "order not found")).Times(2)
+			},
+			after: 0,
+			want: []OrderChange{
+				{Type: ChangeTypeDeleted, ID: orderID, Sequence: 3},
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "Fail: a change cannot be sent",
+			after:   2,
+			sendErr: errSend,
+			want:    nil,
+			wantErr: errSend,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			cr := repo_mock.NewMockCustomerRepository(ctrl)
+			or := repo_mock.NewMockOrderRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(cr, or)
+			}
+
+			ouc := NewOrderUseCase(cr, nil, or, nil, nil, nil, nil, &fakeChangeWatcher{events: events})
+
+			var got []OrderChange
+			err := ouc.WatchOrders(context.Background(), tt.after, func(change OrderChange) error {
+				if tt.sendErr != nil {
+					return tt.sendErr
+				}
+				got = append(got, change)
+				return nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WatchOrders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WatchOrders() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+)
+
+// StockSettler settles the stock reserved for an order when its status change is relayed from the outbox:
+// the stock of a cancelled order is released, and that of a shipped order committed. A settlement that fails
+// is retried with the event, so that the stock is settled even if the catalog service is down when the status
+// changes. Releasing or committing a reservation again does nothing.
+type StockSettler struct {
+	sr repository.StockRepository
+}
+
+func NewStockSettler(sr repository.StockRepository) *StockSettler {
+	return &StockSettler{
+		sr: sr,
+	}
+}
+
+// Settle settles the stock for event, and returns an error if it should be retried.
+func (s *StockSettler) Settle(ctx context.Context, event entity.Event) error {
+	if event.Type != entity.EventTypeOrderStatusChanged && event.Type != entity.EventTypeOrderCancelled {
+		return nil
+	}
+	var history entity.OrderStatusHistory
+	if err := json.Unmarshal(event.Payload, &history); err != nil {
+		log.Error("Failed to decode order status change", log.Fstring("eventID", event.ID), log.Ferror(err))
+		return nil
+	}
+
+	var err error
+	switch history.ToStatus { //nolint:exhaustive // only these statuses move stock
+	case entity.OrderStatusCancelled:
+		err = s.sr.Release(ctx, history.OrderID)
+	case entity.OrderStatusShipped:
+		err = s.sr.Commit(ctx, history.OrderID)
+	default:
+		return nil
+	}
+	switch {
+	case errors.Is(err, entity.ErrNotFound):
+		// Orders created before stock was tracked have no reservation.
+		log.Warn("Stock reservation not found", log.Fstring("orderID", history.OrderID))
+		return nil
+	case errors.Is(err, entity.ErrFailedPrecondition):
+		// The reservation was closed the other way, e.g. released when the order was deleted, which retrying cannot change.
+		log.Error("Stock reservation cannot be settled", log.Fstring("orderID", history.OrderID), log.Fstring("status", string(history.ToStatus)), log.Ferror(err))
+		return nil
+	case err != nil:
+		log.Warn("Failed to settle stock", log.Fstring("orderID", history.OrderID), log.Fstring("status", string(history.ToStatus)), log.Ferror(err))
+		return err
+	}
+	return nil
+}
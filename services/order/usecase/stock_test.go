@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository/mock"
+)
+
+func TestStockSettler_Settle(t *testing.T) {
+	t.Parallel()
+
+	orderID := uuid.New().String()
+	newEvent := func(eventType entity.EventType, from, to entity.OrderStatus) entity.Event {
+		event, err := entity.NewEvent(eventType, orderID, entity.OrderStatusHistory{
+			OrderID:    orderID,
+			FromStatus: from,
+			ToStatus:   to,
+			ChangedAt:  time.Now(),
+		}, time.Now())
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		return *event
+	}
+	errUnavailable := errors.New("catalog service unavailable")
+
+	patterns := []struct {
+		name    string
+		event   entity.Event
+		setup   func(m *mock.MockStockRepository)
+		wantErr error
+	}{
+		{
+			name:  "success: the stock of a cancelled order is released",
+			event: newEvent(entity.EventTypeOrderCancelled, entity.OrderStatusConfirmed, entity.OrderStatusCancelled),
+			setup: func(sr *mock.MockStockRepository) {
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "success: the stock of a shipped order is committed",
+			event: newEvent(entity.EventTypeOrderStatusChanged, entity.OrderStatusPaid, entity.OrderStatusShipped),
+			setup: func(sr *mock.MockStockRepository) {
+				sr.EXPECT().Commit(gomock.Any(), orderID).Return(nil)
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "success: other status changes do not move stock",
+			event:   newEvent(entity.EventTypeOrderStatusChanged, entity.OrderStatusPending, entity.OrderStatusConfirmed),
+			wantErr: nil,
+		},
+		{
+			name:  "success: order without a stock reservation",
+			event: newEvent(entity.EventTypeOrderCancelled, entity.OrderStatusPending, entity.OrderStatusCancelled),
+			setup: func(sr *mock.MockStockRepository) {
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(
+					entity.NewError(entity.ErrNotFound, "stock reservation not found"))
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "success: reservation already closed the other way",
+			event: newEvent(entity.EventTypeOrderStatusChanged, entity.OrderStatusPaid, entity.OrderStatusShipped),
+			setup: func(sr *mock.MockStockRepository) {
+				sr.EXPECT().Commit(gomock.Any(), orderID).Return(
+					entity.NewError(entity.ErrFailedPrecondition, "stock reservation already released"))
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "Fail: catalog service unavailable",
+			event: newEvent(entity.EventTypeOrderCancelled, entity.OrderStatusConfirmed, entity.OrderStatusCancelled),
+			setup: func(sr *mock.MockStockRepository) {
+				sr.EXPECT().Release(gomock.Any(), orderID).Return(errUnavailable)
+			},
+			wantErr: errUnavailable,
+		},
+	}
+
+	for _, tt := range patterns {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			sr := mock.NewMockStockRepository(ctrl)
+
+			if tt.setup != nil {
+				tt.setup(sr)
+			}
+
+			ss := NewStockSettler(sr)
+
+			if err := ss.Settle(context.Background(), tt.event); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Settle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+//go:generate mockgen -source=$GOFILE -package=mock -destination=./mock/$GOFILE
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tusmasoma/go-tech-dojo/pkg/log"
+
+	"github.com/tusmasoma/go-microservice-k8s/services/order/config"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/entity"
+	"github.com/tusmasoma/go-microservice-k8s/services/order/repository"
+)
+
+// ChangeFeed tells the watchers of this process that the outbox relay has published events,
+// so that they do not wait for their next poll.
+type ChangeFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+}
+
+func NewChangeFeed() *ChangeFeed {
+	return &ChangeFeed{
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving a value after events are published, and a function to stop receiving.
+// Notifications are coalesced: a subscriber that has not received the last one misses the next.
+func (f *ChangeFeed) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	f.mu.Lock()
+	f.subscribers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		delete(f.subscribers, ch)
+		f.mu.Unlock()
+	}
+}
+
+func (f *ChangeFeed) Notify() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ChangeWatcher follows the events stored in the outbox.
+type ChangeWatcher interface {
+	// Watch calls send with the events stored after the sequence after, in order, until ctx is cancelled
+	// or send fails. A negative after starts from the events stored from now on.
+	Watch(ctx context.Context, after int64, send func(entity.Event) error) error
+}
+
+type changeWatcher struct {
+	obr          repository.OutboxRepository
+	feed         *ChangeFeed
+	pollInterval time.Duration
+	gapTimeout   time.Duration
+	batchSize    int
+}
+
+func NewChangeWatcher(obr repository.OutboxRepository, feed *ChangeFeed, conf *config.EventConfig) ChangeWatcher {
+	return &changeWatcher{
+		obr:          obr,
+		feed:         feed,
+		pollInterval: conf.WatchPollInterval,
+		gapTimeout:   conf.WatchGapTimeout,
+		batchSize:    conf.RelayBatchSize,
+	}
+}
+
+func (w *changeWatcher) Watch(ctx context.Context, after int64, send func(entity.Event) error) error {
+	notified, unsubscribe := w.feed.Subscribe()
+	defer unsubscribe()
+
+	if after < 0 {
+		last, err := w.obr.LastSequence(ctx)
+		if err != nil {
+			return err
+		}
+		after = last
+	}
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	// gapSince is when an event was first read past a sequence missing right after after,
+	// and is zero while no sequence is missing.
+	var gapSince time.Time
+	for {
+	read:
+		for {
+			events, err := w.obr.ListAfter(ctx, after, w.batchSize)
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				if event.Sequence > after+1 {
+					// Sequences are assigned before the transactions commit, so a missing sequence may be
+					// of a transaction still running, whose events are waited for. The sequences of the
+					// transactions rolled back are never filled, so a gap is skipped after gapTimeout.
+					if gapSince.IsZero() {
+						gapSince = time.Now()
+					}
+					if time.Since(gapSince) < w.gapTimeout {
+						break read
+					}
+					log.Warn(
+						"Skipped missing events",
+						log.Fint64("from", after+1),
+						log.Fint64("to", event.Sequence-1),
+						log.Fduration("waited", time.Since(gapSince)),
+					)
+				}
+				gapSince = time.Time{}
+				if err = send(event); err != nil {
+					return err
+				}
+				after = event.Sequence
+			}
+			if len(events) < w.batchSize {
+				break
+			}
+		}
+
+		// While a sequence is missing, the outbox is read again once the gap times out at the latest.
+		var gapTimedOut <-chan time.Time
+		if !gapSince.IsZero() {
+			gapTimedOut = time.After(time.Until(gapSince.Add(w.gapTimeout)))
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notified:
+		case <-ticker.C:
+		case <-gapTimedOut:
+		}
+	}
+}